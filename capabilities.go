@@ -0,0 +1,225 @@
+package mcpgrafana
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// ToolCategory is a bitmask of the kinds of effect a tool can have,
+// generalizing the simple Read/Write split into finer-grained
+// authorization buckets: an admin-only tool (user management, datasource
+// creation), a destructive one (delete dashboard, silence alert), or an
+// experimental one a deployment may not want exposed yet.
+type ToolCategory uint8
+
+const (
+	ToolCategoryRead ToolCategory = 1 << iota
+	ToolCategoryWrite
+	ToolCategoryAdmin
+	ToolCategoryDestructive
+	ToolCategoryExperimental
+)
+
+// toolCategoryNames maps each ToolCategory bit to the name used in
+// --enable-categories and ToolScope policy files.
+var toolCategoryNames = map[ToolCategory]string{
+	ToolCategoryRead:         "read",
+	ToolCategoryWrite:        "write",
+	ToolCategoryAdmin:        "admin",
+	ToolCategoryDestructive:  "destructive",
+	ToolCategoryExperimental: "experimental",
+}
+
+// Has reports whether c includes every bit set in other.
+func (c ToolCategory) Has(other ToolCategory) bool {
+	return c&other == other
+}
+
+// String renders c as a comma-separated list of its set category names,
+// in a stable Read, Write, Admin, Destructive, Experimental order.
+func (c ToolCategory) String() string {
+	var names []string
+	for _, bit := range []ToolCategory{ToolCategoryRead, ToolCategoryWrite, ToolCategoryAdmin, ToolCategoryDestructive, ToolCategoryExperimental} {
+		if c.Has(bit) {
+			names = append(names, toolCategoryNames[bit])
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseToolCategories parses a comma-separated list of category names
+// (e.g. a --enable-categories flag value) into a ToolCategory bitmask.
+func ParseToolCategories(csv string) (ToolCategory, error) {
+	var categories ToolCategory
+	for _, name := range strings.Split(csv, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		bit, ok := toolCategoryByName(name)
+		if !ok {
+			return 0, fmt.Errorf("unknown tool category %q", name)
+		}
+		categories |= bit
+	}
+	return categories, nil
+}
+
+func toolCategoryByName(name string) (ToolCategory, bool) {
+	for bit, n := range toolCategoryNames {
+		if n == name {
+			return bit, true
+		}
+	}
+	return 0, false
+}
+
+// UnmarshalYAML parses a comma-separated category list (e.g.
+// "read,write,admin") into c, matching the flag syntax of
+// ParseToolCategories so a policy file and a --enable-categories flag
+// use the same format.
+func (c *ToolCategory) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw string
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	categories, err := ParseToolCategories(raw)
+	if err != nil {
+		return err
+	}
+	*c = categories
+	return nil
+}
+
+// ToolScope restricts a tool's capabilities to a subset of datasources or
+// resources. A nil or empty slice means "no restriction" (all allowed);
+// a scope is a subset of another when every entry it names is also named
+// by the other, or the other has no restriction.
+type ToolScope struct {
+	// DatasourceTypes, if non-empty, lists the datasource type names (e.g.
+	// "prometheus", "loki") a tool is scoped to.
+	DatasourceTypes []string `yaml:"datasourceTypes,omitempty"`
+	// Resources, if non-empty, lists the resource kinds (e.g.
+	// "dashboard", "alert-rule") a tool is scoped to.
+	Resources []string `yaml:"resources,omitempty"`
+}
+
+// subsetOf reports whether every entry in s is also present in other, or
+// other imposes no restriction (nil/empty).
+func subsetOf(s, other []string) bool {
+	if len(other) == 0 {
+		return true
+	}
+	allowed := make(map[string]bool, len(other))
+	for _, v := range other {
+		allowed[v] = true
+	}
+	for _, v := range s {
+		if !allowed[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubsetOf reports whether s is at least as restrictive as other: every
+// datasource type and resource s names is also named by other (or other
+// names none, meaning unrestricted).
+func (s ToolScope) IsSubsetOf(other ToolScope) bool {
+	return subsetOf(s.DatasourceTypes, other.DatasourceTypes) && subsetOf(s.Resources, other.Resources)
+}
+
+// ToolCapabilities is what a tool declares about itself (its
+// ToolCategory bits and ToolScope) and what a caller grants (the same
+// shape, naming what's allowed). A tool may register only if its
+// declared capabilities are a subset of what's allowed.
+type ToolCapabilities struct {
+	Categories ToolCategory `yaml:"categories,omitempty"`
+	Scope      ToolScope    `yaml:"scope,omitempty"`
+}
+
+// IsSubsetOf reports whether c's categories and scope are both covered
+// by allowed: every category bit c sets is also set in allowed, and c's
+// scope is at least as restrictive as allowed's.
+func (c ToolCapabilities) IsSubsetOf(allowed ToolCapabilities) bool {
+	return allowed.Categories.Has(c.Categories) && c.Scope.IsSubsetOf(allowed.Scope)
+}
+
+// Policy maps MCP client identities to the ToolCapabilities granted to
+// them, so a single server process can serve clients at different
+// privilege levels. An identity not listed falls back to Default.
+type Policy struct {
+	Default ToolCapabilities            `yaml:"default"`
+	Clients map[string]ToolCapabilities `yaml:"clients"`
+}
+
+// CapabilitiesFor returns the ToolCapabilities granted to identity, or
+// p.Default if identity has no specific entry.
+func (p *Policy) CapabilitiesFor(identity string) ToolCapabilities {
+	if p == nil {
+		return ToolCapabilities{}
+	}
+	if caps, ok := p.Clients[identity]; ok {
+		return caps
+	}
+	return p.Default
+}
+
+// LoadPolicyFile reads and parses an RBAC policy file from path. The
+// file maps client identities (as extracted from request context, e.g.
+// an auth header) to the ToolCapabilities granted to them, plus a
+// default for identities with no specific entry:
+//
+//	default:
+//	  categories: read
+//	clients:
+//	  ops-team:
+//	    categories: read,write,admin
+//	    scope:
+//	      datasourceTypes: [prometheus, loki]
+func LoadPolicyFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parse policy file %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// AllowAllCapabilities grants every category with no scope restriction, the
+// default an AddXxxTools function should fall back to when the caller
+// doesn't otherwise restrict registration (e.g. no --enable-categories or
+// --disable-destructive flag was set), so existing deployments keep seeing
+// every tool they did before ToolCapabilities existed.
+var AllowAllCapabilities = ToolCapabilities{
+	Categories: ToolCategoryRead | ToolCategoryWrite | ToolCategoryAdmin | ToolCategoryDestructive | ToolCategoryExperimental,
+}
+
+// Registerable is anything MustTool produces: a Tool[T] value, identified
+// here by its one method rather than imported, since capabilities.go only
+// needs to call it.
+type Registerable interface {
+	Register(s *server.MCPServer)
+}
+
+// RegisterTool registers tool with mcp only if caps -- what tool declares
+// about its own effect and scope -- is a subset of allowed, the capability
+// filter a deployment configured via --enable-categories/--disable-destructive
+// flags or an RBAC Policy. AddXxxTools functions call this in place of
+// tool.Register(mcp) directly, the same way they already gate individual
+// tools on the narrower enableWriteTools bool, so a single flag can restrict
+// every tool in the server by category or scope rather than just read/write.
+func RegisterTool(mcp *server.MCPServer, allowed ToolCapabilities, caps ToolCapabilities, tool Registerable) {
+	if !caps.IsSubsetOf(allowed) {
+		return
+	}
+	tool.Register(mcp)
+}
@@ -0,0 +1,106 @@
+package mcpgrafana
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolCategories(t *testing.T) {
+	t.Run("parses a comma-separated list", func(t *testing.T) {
+		categories, err := ParseToolCategories("read, admin")
+		require.NoError(t, err)
+		assert.True(t, categories.Has(ToolCategoryRead))
+		assert.True(t, categories.Has(ToolCategoryAdmin))
+		assert.False(t, categories.Has(ToolCategoryWrite))
+	})
+
+	t.Run("empty string is no categories", func(t *testing.T) {
+		categories, err := ParseToolCategories("")
+		require.NoError(t, err)
+		assert.Equal(t, ToolCategory(0), categories)
+	})
+
+	t.Run("unknown category is an error", func(t *testing.T) {
+		_, err := ParseToolCategories("read,bogus")
+		assert.Error(t, err)
+	})
+}
+
+func TestToolCategory_String(t *testing.T) {
+	categories := ToolCategoryWrite | ToolCategoryRead | ToolCategoryDestructive
+	assert.Equal(t, "read,write,destructive", categories.String())
+}
+
+func TestToolScope_IsSubsetOf(t *testing.T) {
+	t.Run("unrestricted other allows anything", func(t *testing.T) {
+		s := ToolScope{DatasourceTypes: []string{"prometheus"}}
+		assert.True(t, s.IsSubsetOf(ToolScope{}))
+	})
+
+	t.Run("subset of named types is allowed", func(t *testing.T) {
+		s := ToolScope{DatasourceTypes: []string{"loki"}}
+		allowed := ToolScope{DatasourceTypes: []string{"loki", "prometheus"}}
+		assert.True(t, s.IsSubsetOf(allowed))
+	})
+
+	t.Run("type outside allowed set is rejected", func(t *testing.T) {
+		s := ToolScope{DatasourceTypes: []string{"prometheus"}}
+		allowed := ToolScope{DatasourceTypes: []string{"loki"}}
+		assert.False(t, s.IsSubsetOf(allowed))
+	})
+}
+
+func TestToolCapabilities_IsSubsetOf(t *testing.T) {
+	allowed := ToolCapabilities{
+		Categories: ToolCategoryRead | ToolCategoryWrite,
+		Scope:      ToolScope{DatasourceTypes: []string{"prometheus", "loki"}},
+	}
+
+	t.Run("allowed category and scope", func(t *testing.T) {
+		c := ToolCapabilities{Categories: ToolCategoryRead, Scope: ToolScope{DatasourceTypes: []string{"loki"}}}
+		assert.True(t, c.IsSubsetOf(allowed))
+	})
+
+	t.Run("disallowed category", func(t *testing.T) {
+		c := ToolCapabilities{Categories: ToolCategoryAdmin}
+		assert.False(t, c.IsSubsetOf(allowed))
+	})
+
+	t.Run("disallowed scope", func(t *testing.T) {
+		c := ToolCapabilities{Categories: ToolCategoryRead, Scope: ToolScope{DatasourceTypes: []string{"tempo"}}}
+		assert.False(t, c.IsSubsetOf(allowed))
+	})
+}
+
+func TestLoadPolicyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	contents := `
+default:
+  categories: read
+clients:
+  ops-team:
+    categories: read,write,admin
+    scope:
+      datasourceTypes: [prometheus, loki]
+`
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+
+	policy, err := LoadPolicyFile(path)
+	require.NoError(t, err)
+
+	opsCaps := policy.CapabilitiesFor("ops-team")
+	assert.True(t, opsCaps.Categories.Has(ToolCategoryAdmin))
+	assert.Equal(t, []string{"prometheus", "loki"}, opsCaps.Scope.DatasourceTypes)
+
+	defaultCaps := policy.CapabilitiesFor("unknown-client")
+	assert.Equal(t, ToolCategoryRead, defaultCaps.Categories)
+}
+
+func TestLoadPolicyFile_MissingFile(t *testing.T) {
+	_, err := LoadPolicyFile(filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+	assert.Error(t, err)
+}
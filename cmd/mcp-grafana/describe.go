@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/grafana/mcp-grafana/internal/proxy"
+)
+
+// runDescribeRecording implements the `mcp-grafana describe-recording`
+// subcommand: it summarizes a proxy session recording (written by
+// proxy.Recorder when TEMPO_PROXY_RECORD_FILE is set) so an operator can
+// triage a captured session without replaying it.
+func runDescribeRecording(args []string) int {
+	fs := flag.NewFlagSet("describe-recording", flag.ExitOnError)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: mcp-grafana describe-recording <file>")
+		return exitWaitError
+	}
+	path := fs.Arg(0)
+
+	summary, err := proxy.Describe(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error describing recording %s: %v\n", path, err)
+		return exitWaitError
+	}
+
+	errorRate := 0.0
+	if summary.Records > 0 {
+		errorRate = float64(summary.Errors) / float64(summary.Records) * 100
+	}
+
+	fmt.Printf("records: %d (errors: %d, %.1f%%)\n", summary.Records, summary.Errors, errorRate)
+
+	fmt.Printf("datasources: %d\n", len(summary.Datasources))
+	for _, uid := range sortedKeys(summary.Datasources) {
+		fmt.Printf("  %s: %d calls, last response %s\n", uid, summary.Datasources[uid], summary.LastHash[uid])
+	}
+
+	fmt.Printf("tools: %d\n", len(summary.Tools))
+	for _, name := range sortedKeys(summary.Tools) {
+		fmt.Printf("  %s: %d calls\n", name, summary.Tools[name])
+	}
+
+	return 0
+}
+
+// sortedKeys returns m's keys sorted alphabetically, so describe-recording
+// output is stable between runs.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -53,9 +53,44 @@ type disabledTools struct {
 	search, datasource, incident,
 	prometheus, loki, alerting,
 	dashboard, oncall, asserts, sift, admin,
-	pyroscope, navigation, proxied bool
+	pyroscope, navigation, proxied, provisioning, backup bool
+
+	// enableWriteTools gates registration of tools that mutate state:
+	// provisioning repository file writes, OnCall alert group actions
+	// (acknowledge/resolve/silence/unresolve/escalate), and OnCall schedule
+	// override creation/deletion, so read-only deployments don't
+	// accidentally expose write access to GitOps-managed repositories or
+	// live incidents.
+	enableWriteTools bool
+
+	// oncallProvider selects which vendor backs the oncall_* tools (see
+	// tools.NewOnCallProvider). Empty keeps the default Grafana OnCall backend.
+	oncallProvider string
+
+	// noBrowser disables manual_submit_pull_request's browser launch, returning
+	// the pre-filled PR URL in the tool response instead. Needed for headless
+	// or remote deployments (SSE transport, containers) that have nowhere to
+	// open a browser.
+	noBrowser bool
+
+	// enableCategories restricts every registered tool to this comma
+	// separated list of mcpgrafana.ToolCategory names (e.g. "read,write").
+	// Empty means mcpgrafana.AllowAllCapabilities, preserving pre-existing
+	// behavior for deployments that don't opt in.
+	enableCategories string
+
+	// disableDestructive withholds ToolCategoryDestructive even when
+	// enableCategories grants it, so a deployment can opt into write
+	// access without exposing irreversible operations like delete or
+	// restore.
+	disableDestructive bool
 }
 
+// noBrowserEnvVar is the environment variable fallback for --no-browser, for
+// deployments that set server configuration via environment rather than
+// command-line flags.
+const noBrowserEnvVar = "MCP_GRAFANA_NO_BROWSER"
+
 // Configuration for the Grafana client.
 type grafanaConfig struct {
 	// Whether to enable debug mode for the Grafana transport.
@@ -69,7 +104,7 @@ type grafanaConfig struct {
 }
 
 func (dt *disabledTools) addFlags() {
-	flag.StringVar(&dt.enabledTools, "enabled-tools", "search,datasource,incident,prometheus,loki,alerting,dashboard,oncall,asserts,sift,admin,pyroscope,navigation,proxied", "A comma separated list of tools enabled for this server. Can be overwritten entirely or by disabling specific components, e.g. --disable-search.")
+	flag.StringVar(&dt.enabledTools, "enabled-tools", "search,datasource,incident,prometheus,loki,alerting,dashboard,oncall,asserts,sift,admin,pyroscope,navigation,proxied,provisioning,backup", "A comma separated list of tools enabled for this server. Can be overwritten entirely or by disabling specific components, e.g. --disable-search.")
 	flag.BoolVar(&dt.search, "disable-search", false, "Disable search tools")
 	flag.BoolVar(&dt.datasource, "disable-datasource", false, "Disable datasource tools")
 	flag.BoolVar(&dt.incident, "disable-incident", false, "Disable incident tools")
@@ -84,6 +119,34 @@ func (dt *disabledTools) addFlags() {
 	flag.BoolVar(&dt.pyroscope, "disable-pyroscope", false, "Disable pyroscope tools")
 	flag.BoolVar(&dt.navigation, "disable-navigation", false, "Disable navigation tools")
 	flag.BoolVar(&dt.proxied, "disable-proxied", false, "Disable proxied tools (tools from external MCP servers)")
+	flag.BoolVar(&dt.provisioning, "disable-provisioning", false, "Disable provisioning tools")
+	flag.BoolVar(&dt.backup, "disable-backup", false, "Disable backup/restore tools")
+	flag.BoolVar(&dt.enableWriteTools, "enable-write-tools", false, "Enable tools that mutate state: provisioning repository file writes, OnCall alert group actions, and OnCall schedule overrides (disabled by default for read-only deployments)")
+	flag.StringVar(&dt.oncallProvider, "oncall-provider", "grafana", "The on-call vendor backing the oncall_* tools. One of: grafana, pagerduty")
+	flag.BoolVar(&dt.noBrowser, "no-browser", os.Getenv(noBrowserEnvVar) != "", "Don't launch a browser from manual_submit_pull_request; return the pre-filled PR URL in the response instead. Also settable via "+noBrowserEnvVar+".")
+	flag.StringVar(&dt.enableCategories, "enable-categories", "", "A comma separated list of tool categories to allow (read,write,admin,destructive,experimental). Empty allows every category, matching pre-existing behavior.")
+	flag.BoolVar(&dt.disableDestructive, "disable-destructive", false, "Withhold destructive tools (e.g. delete, restore) even if --enable-categories allows them")
+}
+
+// capabilities resolves the --enable-categories/--disable-destructive flags
+// into the mcpgrafana.ToolCapabilities passed to every tools.AddXxxTools
+// call, falling back to mcpgrafana.AllowAllCapabilities so deployments that
+// don't set either flag keep seeing every tool they did before
+// ToolCapabilities existed.
+func (dt *disabledTools) capabilities() mcpgrafana.ToolCapabilities {
+	allowed := mcpgrafana.AllowAllCapabilities
+	if dt.enableCategories != "" {
+		categories, err := mcpgrafana.ParseToolCategories(dt.enableCategories)
+		if err != nil {
+			slog.Error("Invalid --enable-categories value, allowing every category", "value", dt.enableCategories, "error", err)
+		} else {
+			allowed.Categories = categories
+		}
+	}
+	if dt.disableDestructive {
+		allowed.Categories &^= mcpgrafana.ToolCategoryDestructive
+	}
+	return allowed
 }
 
 func (gc *grafanaConfig) addFlags() {
@@ -97,21 +160,44 @@ func (gc *grafanaConfig) addFlags() {
 }
 
 func (dt *disabledTools) addTools(s *server.MCPServer) {
+	tools.SetNoBrowserMode(dt.noBrowser)
+
+	allowed := dt.capabilities()
 	enabledTools := strings.Split(dt.enabledTools, ",")
-	maybeAddTools(s, tools.AddSearchTools, enabledTools, dt.search, "search")
+	maybeAddTools(s, func(s *server.MCPServer) {
+		tools.AddSearchTools(s, allowed)
+	}, enabledTools, dt.search, "search")
 	maybeAddTools(s, tools.AddDatasourceTools, enabledTools, dt.datasource, "datasource")
 	maybeAddTools(s, tools.AddIncidentTools, enabledTools, dt.incident, "incident")
 	maybeAddTools(s, tools.AddPrometheusTools, enabledTools, dt.prometheus, "prometheus")
 	maybeAddTools(s, tools.AddLokiTools, enabledTools, dt.loki, "loki")
 	maybeAddTools(s, tools.AddAlertingTools, enabledTools, dt.alerting, "alerting")
-	maybeAddTools(s, tools.AddDashboardTools, enabledTools, dt.dashboard, "dashboard")
-	maybeAddTools(s, tools.AddOnCallTools, enabledTools, dt.oncall, "oncall")
+	maybeAddTools(s, func(s *server.MCPServer) {
+		tools.AddDashboardTools(s, allowed)
+	}, enabledTools, dt.dashboard, "dashboard")
+	maybeAddTools(s, func(s *server.MCPServer) {
+		tools.AddOnCallTools(s, dt.enableWriteTools, dt.oncallProvider, allowed)
+	}, enabledTools, dt.oncall, "oncall")
 	maybeAddTools(s, tools.AddAssertsTools, enabledTools, dt.asserts, "asserts")
 	maybeAddTools(s, tools.AddSiftTools, enabledTools, dt.sift, "sift")
-	maybeAddTools(s, tools.AddAdminTools, enabledTools, dt.admin, "admin")
+	maybeAddTools(s, func(s *server.MCPServer) {
+		tools.AddAdminTools(s, allowed)
+	}, enabledTools, dt.admin, "admin")
 	maybeAddTools(s, tools.AddPyroscopeTools, enabledTools, dt.pyroscope, "pyroscope")
-	maybeAddTools(s, tools.AddNavigationTools, enabledTools, dt.navigation, "navigation")
-	maybeAddTools(s, tools.AddProxiedTools, enabledTools, dt.proxied, "proxied")
+	maybeAddTools(s, func(s *server.MCPServer) {
+		tools.AddNavigationTools(s, allowed)
+	}, enabledTools, dt.navigation, "navigation")
+	maybeAddTools(s, func(s *server.MCPServer) {
+		tools.AddProxiedTools(s, allowed)
+	}, enabledTools, dt.proxied, "proxied")
+	maybeAddTools(s, func(s *server.MCPServer) {
+		tools.AddProvisioningRepositoriesTool(s, allowed)
+		tools.AddProvisioningRepositoryFilesTool(s, dt.enableWriteTools, allowed)
+		tools.AddOrphanResourcesTool(s, allowed)
+	}, enabledTools, dt.provisioning, "provisioning")
+	maybeAddTools(s, func(s *server.MCPServer) {
+		tools.AddBackupTools(s, dt.enableWriteTools, allowed)
+	}, enabledTools, dt.backup, "backup")
 }
 
 func newServer(dt disabledTools) *server.MCPServer {
@@ -130,6 +216,8 @@ func newServer(dt disabledTools) *server.MCPServer {
 	- Pyroscope: Profile applications and fetch profiling data.
 	- Navigation: Generate deeplink URLs for Grafana resources like dashboards, panels, and Explore queries.
 	- Proxied Tools: Access tools from external MCP servers (like Tempo) through dynamic discovery.
+	- Provisioning: List and inspect Git-managed (GitOps) repositories, their files, and sync status. File writes require --enable-write-tools.
+	- Backup/Restore: Back up dashboards, folders, and datasources to a single file, and restore them into another instance. Restore requires --enable-write-tools.
 	`))
 	dt.addTools(s)
 	return s
@@ -196,6 +284,13 @@ func run(transport, addr, basePath, endpointPath string, logLevel slog.Level, dt
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "wait" {
+		os.Exit(runWait(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "describe-recording" {
+		os.Exit(runDescribeRecording(os.Args[2:]))
+	}
+
 	var transport string
 	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio, sse or streamable-http)")
 	flag.StringVar(
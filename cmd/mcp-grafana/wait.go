@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grafana/mcp-grafana/internal/health"
+)
+
+// Exit codes for the `wait` subcommand. 0 is reserved for success.
+const (
+	exitWaitTimeout = 1
+	exitWaitError   = 2
+)
+
+// runWait implements the `mcp-grafana wait` subcommand, which blocks until a
+// health endpoint reports ready or a retry timeout elapses. It's meant for
+// init containers and CI jobs that need to block on the MCP server coming
+// up without an ad-hoc bash polling loop.
+func runWait(args []string) int {
+	fs := flag.NewFlagSet("wait", flag.ExitOnError)
+	sleep := fs.Duration("sleep", time.Second, "How long to wait between polling attempts")
+	retryTimeout := fs.Duration("retry-timeout", 60*time.Second, "How long to keep polling before giving up")
+	initialDelay := fs.Duration("initial-delay", 0, "How long to wait once before the first attempt")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: mcp-grafana wait [flags] <url>")
+		return exitWaitError
+	}
+	url := fs.Arg(0)
+
+	opts := health.WaitOptions{
+		Sleep:        *sleep,
+		RetryTimeout: *retryTimeout,
+		InitialDelay: *initialDelay,
+	}
+
+	err := health.WaitReady(context.Background(), url, opts, func(r health.AttemptResult) {
+		ratio := float64(r.Elapsed) / float64(r.Timeout)
+		if r.Err != nil {
+			fmt.Printf("attempt %d: error: %v (elapsed %s/%s, %.0f%%)\n", r.Attempt, r.Err, r.Elapsed.Round(time.Millisecond), r.Timeout, ratio*100)
+			return
+		}
+		fmt.Printf("attempt %d: status %d (elapsed %s/%s, %.0f%%)\n", r.Attempt, r.Status, r.Elapsed.Round(time.Millisecond), r.Timeout, ratio*100)
+	})
+
+	if err == nil {
+		fmt.Println("ready")
+		return 0
+	}
+
+	if errors.Is(err, health.ErrWaitTimeout) {
+		fmt.Fprintf(os.Stderr, "timed out after %s waiting for %s to become ready\n", *retryTimeout, url)
+		return exitWaitTimeout
+	}
+
+	fmt.Fprintf(os.Stderr, "error waiting for %s: %v\n", url, err)
+	return exitWaitError
+}
@@ -0,0 +1,61 @@
+// Package browser opens URLs in the user's default web browser, working
+// around the fact that WSL's runtime.GOOS reports "linux" even though
+// there's no X11/Wayland session (or xdg-open) available to reach the
+// Windows host's browser.
+package browser
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// isWSL caches the result of the /proc/version check: it never changes for
+// the lifetime of the process, and every Open call would otherwise re-read
+// the file.
+var isWSL = sync.OnceValue(func() bool {
+	version, err := os.ReadFile("/proc/version")
+	if err != nil {
+		return false
+	}
+	return detectWSL(string(version))
+})
+
+// detectWSL reports whether /proc/version's contents indicate a WSL
+// environment, split out from isWSL so it can be tested without a real
+// /proc/version file.
+func detectWSL(procVersion string) bool {
+	return strings.Contains(procVersion, "Microsoft") || strings.Contains(procVersion, "WSL")
+}
+
+// Open launches url in the default browser. On native Linux this shells out
+// to xdg-open, which under WSL has no desktop session to talk to and fails
+// silently -- so on WSL (detected via /proc/version) it instead asks the
+// Windows host to open the URL via cmd.exe.
+func Open(url string) error {
+	var cmd string
+	var args []string
+
+	switch {
+	case isWSL():
+		cmd = "cmd.exe"
+		args = []string{"/c", "start", "", url}
+	case runtime.GOOS == "windows":
+		cmd = "rundll32"
+		args = []string{"url.dll,FileProtocolHandler", url}
+	case runtime.GOOS == "darwin":
+		cmd = "open"
+		args = []string{url}
+	default: // "linux", "freebsd", "openbsd", "netbsd"
+		cmd = "xdg-open"
+		args = []string{url}
+	}
+
+	if err := exec.Command(cmd, args...).Start(); err != nil {
+		return fmt.Errorf("opening browser: %w", err)
+	}
+	return nil
+}
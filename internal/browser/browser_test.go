@@ -0,0 +1,24 @@
+package browser
+
+import "testing"
+
+func TestDetectWSL(t *testing.T) {
+	cases := []struct {
+		name        string
+		procVersion string
+		want        bool
+	}{
+		{"wsl2", "Linux version 5.15.90.1-microsoft-standard-WSL2 (Microsoft@Microsoft.com)", true},
+		{"wsl1", "Linux version 4.4.0-19041-Microsoft", true},
+		{"native linux", "Linux version 6.5.0-generic (buildd@lcy02-amd64-076)", false},
+		{"empty", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := detectWSL(c.procVersion); got != c.want {
+				t.Errorf("detectWSL(%q) = %v, want %v", c.procVersion, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Package compress provides a content-negotiating gzip/deflate compression
+// middleware for outgoing HTTP responses, plus a RoundTripper that requests
+// and transparently decodes compressed responses from an upstream server.
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultMinSize is the response body size, in bytes, below which
+// compression is skipped because the gzip/deflate framing overhead would
+// outweigh the benefit.
+const DefaultMinSize = 1024
+
+// defaultSkipContentTypePrefixes lists Content-Type prefixes that are
+// already compressed (or otherwise not worth compressing again).
+var defaultSkipContentTypePrefixes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-bzip2",
+	"application/x-7z-compressed",
+	"application/pdf",
+}
+
+// Config controls how Middleware decides whether and how to compress a
+// response.
+type Config struct {
+	// MinSize is the minimum response body size, in bytes, required before
+	// compression kicks in. Defaults to DefaultMinSize.
+	MinSize int
+
+	// SkipContentTypePrefixes lists Content-Type prefixes to never compress.
+	// Defaults to defaultSkipContentTypePrefixes.
+	SkipContentTypePrefixes []string
+}
+
+func (c Config) withDefaults() Config {
+	if c.MinSize <= 0 {
+		c.MinSize = DefaultMinSize
+	}
+	if c.SkipContentTypePrefixes == nil {
+		c.SkipContentTypePrefixes = defaultSkipContentTypePrefixes
+	}
+	return c
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+var flateWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := flate.NewWriter(nil, flate.DefaultCompression)
+		return w
+	},
+}
+
+// Middleware returns an http.Handler wrapper that compresses responses with
+// gzip or deflate, whichever the client's Accept-Encoding header prefers,
+// skipping already-compressed content types and bodies under config.MinSize.
+func Middleware(config Config) func(http.Handler) http.Handler {
+	config = config.withDefaults()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, config: config, encoding: encoding}
+			next.ServeHTTP(cw, r)
+			cw.Close()
+		})
+	}
+}
+
+// negotiateEncoding picks gzip over deflate when both are accepted, since
+// gzip support is effectively universal and tends to compress slightly
+// better for JSON payloads.
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	hasGzip, hasDeflate := false, false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "gzip":
+			hasGzip = true
+		case "deflate":
+			hasDeflate = true
+		}
+	}
+
+	switch {
+	case hasGzip:
+		return "gzip"
+	case hasDeflate:
+		return "deflate"
+	default:
+		return ""
+	}
+}
+
+// compressWriter wraps an http.ResponseWriter, buffering the response until
+// either config.MinSize bytes have been written (at which point it switches
+// to streaming through a pooled gzip/deflate writer) or the handler
+// finishes without reaching that threshold (in which case the buffered body
+// is flushed uncompressed).
+type compressWriter struct {
+	http.ResponseWriter
+	config   Config
+	encoding string
+
+	buf        bytes.Buffer
+	compressor io.WriteCloser
+
+	headerWritten bool
+	statusCode    int
+	headerFlushed bool
+	bypass        bool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.headerWritten {
+		return
+	}
+	cw.headerWritten = true
+	cw.statusCode = status
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.bypass {
+		return cw.passthroughWrite(p)
+	}
+
+	if cw.compressor != nil {
+		return cw.compressor.Write(p)
+	}
+
+	if cw.shouldSkip() {
+		cw.bypass = true
+		return cw.passthroughWrite(p)
+	}
+
+	n, _ := cw.buf.Write(p)
+
+	if cw.buf.Len() >= cw.config.MinSize {
+		if err := cw.startCompressing(); err != nil {
+			return 0, err
+		}
+	}
+
+	return n, nil
+}
+
+func (cw *compressWriter) shouldSkip() bool {
+	if cw.Header().Get("Content-Encoding") != "" {
+		return true
+	}
+
+	ct := cw.Header().Get("Content-Type")
+	for _, prefix := range cw.config.SkipContentTypePrefixes {
+		if strings.HasPrefix(ct, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cw *compressWriter) startCompressing() error {
+	cw.Header().Set("Content-Encoding", cw.encoding)
+	cw.Header().Del("Content-Length")
+	cw.flushHeader()
+
+	switch cw.encoding {
+	case "gzip":
+		gz := gzipWriterPool.Get().(*gzip.Writer)
+		gz.Reset(cw.ResponseWriter)
+		cw.compressor = gz
+	case "deflate":
+		fw := flateWriterPool.Get().(*flate.Writer)
+		fw.Reset(cw.ResponseWriter)
+		cw.compressor = fw
+	default:
+		return fmt.Errorf("compress: unsupported encoding %q", cw.encoding)
+	}
+
+	if cw.buf.Len() > 0 {
+		_, err := cw.compressor.Write(cw.buf.Bytes())
+		cw.buf.Reset()
+		return err
+	}
+	return nil
+}
+
+func (cw *compressWriter) passthroughWrite(p []byte) (int, error) {
+	cw.flushHeader()
+	if cw.buf.Len() > 0 {
+		if _, err := cw.ResponseWriter.Write(cw.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		cw.buf.Reset()
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+func (cw *compressWriter) flushHeader() {
+	if cw.headerFlushed {
+		return
+	}
+	cw.headerFlushed = true
+	if cw.headerWritten {
+		cw.ResponseWriter.WriteHeader(cw.statusCode)
+	}
+}
+
+// Close flushes any buffered-but-never-compressed body and releases the
+// pooled compressor, if one was used. It must be called once the handler
+// has finished writing the response.
+func (cw *compressWriter) Close() error {
+	if cw.compressor == nil {
+		cw.flushHeader()
+		if cw.buf.Len() > 0 {
+			_, err := cw.ResponseWriter.Write(cw.buf.Bytes())
+			cw.buf.Reset()
+			return err
+		}
+		return nil
+	}
+
+	err := cw.compressor.Close()
+	switch c := cw.compressor.(type) {
+	case *gzip.Writer:
+		gzipWriterPool.Put(c)
+	case *flate.Writer:
+		flateWriterPool.Put(c)
+	}
+	cw.compressor = nil
+	return err
+}
+
+// Flush implements http.Flusher so streamed responses (e.g. SSE) keep
+// working through the middleware.
+func (cw *compressWriter) Flush() {
+	if f, ok := cw.compressor.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker so the middleware doesn't break transports
+// (like some SSE or websocket setups) that need to take over the
+// connection.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("compress: underlying ResponseWriter does not support hijacking")
+	}
+	return h.Hijack()
+}
@@ -0,0 +1,181 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware_CompressesWhenAcceptedAndAboveMinSize(t *testing.T) {
+	body := strings.Repeat("x", DefaultMinSize+1)
+
+	handler := Middleware(Config{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	resp := w.Result()
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := resp.Header.Get("Vary"); got != "Accept-Encoding" {
+		t.Fatalf("Vary = %q, want Accept-Encoding", got)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	decoded, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("reading decompressed body: %v", err)
+	}
+	if string(decoded) != body {
+		t.Fatalf("decompressed body mismatch")
+	}
+}
+
+func TestMiddleware_SkipsSmallBodies(t *testing.T) {
+	handler := Middleware(Config{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("tiny"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	resp := w.Result()
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want empty for small body", got)
+	}
+	if w.Body.String() != "tiny" {
+		t.Fatalf("body = %q, want %q", w.Body.String(), "tiny")
+	}
+}
+
+func TestMiddleware_SkipsAlreadyCompressedContentTypes(t *testing.T) {
+	body := strings.Repeat("x", DefaultMinSize+1)
+
+	handler := Middleware(Config{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte(body))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got := w.Result().Header.Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want empty for skipped content type", got)
+	}
+	if w.Body.String() != body {
+		t.Fatalf("body mismatch for skipped content type")
+	}
+}
+
+func TestMiddleware_NoAcceptEncodingPassesThrough(t *testing.T) {
+	handler := Middleware(Config{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", DefaultMinSize+1)))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got := w.Result().Header.Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want empty without Accept-Encoding", got)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"gzip, deflate", "gzip"},
+		{"deflate, gzip;q=0.5", "gzip"},
+		{"br", ""},
+	}
+
+	for _, tc := range cases {
+		if got := negotiateEncoding(tc.acceptEncoding); got != tc.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tc.acceptEncoding, got, tc.want)
+		}
+	}
+}
+
+func TestCompressingTransport_DecodesGzipResponse(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); !strings.Contains(got, "gzip") {
+			t.Errorf("Accept-Encoding = %q, want it to request gzip", got)
+		}
+
+		gz := gzip.NewWriter(w)
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = gz.Write([]byte("hello from upstream"))
+		_ = gz.Close()
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: NewCompressingTransport(nil)}
+	resp, err := client.Get(upstream.URL)
+	if err != nil {
+		t.Fatalf("client.Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if string(body) != "hello from upstream" {
+		t.Fatalf("body = %q, want %q", body, "hello from upstream")
+	}
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Fatalf("Content-Encoding should be stripped after decoding")
+	}
+}
+
+func TestCompressingTransport_LeavesCallerEncodingAlone(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != "identity" {
+			t.Errorf("Accept-Encoding = %q, want caller-supplied value preserved", got)
+		}
+		_, _ = w.Write([]byte("plain"))
+	}))
+	defer upstream.Close()
+
+	req, err := http.NewRequest(http.MethodGet, upstream.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Accept-Encoding", "identity")
+
+	client := &http.Client{Transport: NewCompressingTransport(nil)}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if string(body) != "plain" {
+		t.Fatalf("body = %q, want %q", body, "plain")
+	}
+}
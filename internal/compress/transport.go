@@ -0,0 +1,84 @@
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+)
+
+// compressingTransport wraps a RoundTripper, asking the upstream server for
+// a compressed response and transparently decoding it before returning it to
+// the caller. Go's net/http.Transport already does this for gzip as long as
+// the caller never sets Accept-Encoding itself, but it doesn't support
+// deflate and that behavior disappears the moment a caller supplies its own
+// RoundTripper (e.g. for custom TLS config) that doesn't replicate it.
+type compressingTransport struct {
+	base http.RoundTripper
+}
+
+// NewCompressingTransport wraps base so outgoing requests negotiate gzip or
+// deflate compression and incoming responses are decoded transparently. If
+// base is nil, http.DefaultTransport is used. If a caller has already set
+// Accept-Encoding on the request, this transport leaves it and the response
+// body alone.
+func NewCompressingTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &compressingTransport{base: base}
+}
+
+func (t *compressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	callerSetEncoding := req.Header.Get("Accept-Encoding") != ""
+
+	if !callerSetEncoding {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if callerSetEncoding {
+		return resp, nil
+	}
+
+	switch resp.Header.Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			// Not actually gzip despite the header; hand the body back
+			// untouched rather than failing the whole request.
+			return resp, nil
+		}
+		resp.Body = &decodingBody{Reader: gz, underlying: resp.Body}
+	case "deflate":
+		resp.Body = &decodingBody{Reader: flate.NewReader(resp.Body), underlying: resp.Body}
+	default:
+		return resp, nil
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return resp, nil
+}
+
+// decodingBody closes both the decompressing reader and the underlying
+// network response body.
+type decodingBody struct {
+	io.Reader
+	underlying io.ReadCloser
+}
+
+func (d *decodingBody) Close() error {
+	if closer, ok := d.Reader.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	return d.underlying.Close()
+}
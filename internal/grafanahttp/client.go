@@ -0,0 +1,155 @@
+// Package grafanahttp provides a shared HTTP client for talking to a
+// Grafana instance's API, so individual tools don't each re-implement
+// auth-header injection, TLS transport setup, retries, and JSON decoding.
+package grafanahttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/grafana/mcp-grafana/internal/retry"
+)
+
+// ErrNotFound is returned by GetJSON and DoJSON for a 404 response, so
+// callers can check for it with errors.Is instead of matching status codes
+// or response text.
+var ErrNotFound = errors.New("grafanahttp: not found")
+
+// Client issues authenticated requests against a Grafana instance's API.
+type Client struct {
+	cfg       mcpgrafana.GrafanaConfig
+	transport http.RoundTripper
+}
+
+// transportCache holds one retrying, TLS-configured http.RoundTripper per
+// distinct Grafana URL + TLS configuration, so repeated New calls for the
+// same instance -- which happens on every tool invocation -- reuse a
+// single underlying *http.Transport and its connection pool instead of
+// building (and leaking) a fresh one each time.
+var (
+	transportCacheMu sync.Mutex
+	transportCache   = map[string]http.RoundTripper{}
+)
+
+// New returns a Client for cfg, reusing a cached transport for cfg's URL
+// and TLS configuration if one was already built.
+func New(cfg mcpgrafana.GrafanaConfig) (*Client, error) {
+	transport, err := transportFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{cfg: cfg, transport: transport}, nil
+}
+
+// transportFor builds (or reuses) the retrying, TLS-configured transport
+// for cfg. The cache key only distinguishes "has a TLS config" from "does
+// not", rather than hashing the TLS config itself -- in practice a single
+// process only ever talks to one Grafana instance under one TLS
+// configuration, so this is enough to stop each tool call from paying for
+// its own transport and connection pool.
+func transportFor(cfg mcpgrafana.GrafanaConfig) (http.RoundTripper, error) {
+	key := cfg.URL
+	if cfg.TLSConfig != nil {
+		key += "|tls"
+	}
+
+	transportCacheMu.Lock()
+	defer transportCacheMu.Unlock()
+	if cached, ok := transportCache[key]; ok {
+		return cached, nil
+	}
+
+	base := http.DefaultTransport
+	if cfg.TLSConfig != nil {
+		tlsTransport, err := cfg.TLSConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
+		if err != nil {
+			return nil, fmt.Errorf("creating TLS transport: %w", err)
+		}
+		base = tlsTransport
+	}
+
+	transport := retry.NewTransport(base, retry.DefaultConfig())
+	transportCache[key] = transport
+	return transport, nil
+}
+
+// Do sends req with Grafana's auth headers applied, via the client's
+// cached, retrying, TLS-configured transport.
+func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	req = req.WithContext(ctx)
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	} else if c.cfg.AccessToken != "" && c.cfg.IDToken != "" {
+		req.Header.Set("X-Access-Token", c.cfg.AccessToken)
+		req.Header.Set("X-Grafana-Id", c.cfg.IDToken)
+	}
+
+	client := &http.Client{Transport: c.transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making request to %s: %w", req.URL, err)
+	}
+	return resp, nil
+}
+
+// DoJSON makes an authenticated method request against path (resolved
+// against the Grafana instance's base URL), encoding body as JSON when
+// non-nil and decoding the response into out when non-nil. It returns
+// ErrNotFound for a 404 response rather than a generic status-code error,
+// since callers frequently need to handle "doesn't exist" as a value, and
+// wraps any other non-2xx response with a snippet of the response body to
+// help diagnose it.
+func (c *Client) DoJSON(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	requestURL := strings.TrimRight(c.cfg.URL, "/") + path
+	req, err := http.NewRequest(method, requestURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, requestURL, strings.TrimSpace(string(snippet)))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decoding response from %s: %w", requestURL, err)
+		}
+	}
+	return nil
+}
+
+// GetJSON is a convenience wrapper for DoJSON(ctx, http.MethodGet, path, nil, out).
+func (c *Client) GetJSON(ctx context.Context, path string, out any) error {
+	return c.DoJSON(ctx, http.MethodGet, path, nil, out)
+}
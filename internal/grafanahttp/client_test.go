@@ -0,0 +1,112 @@
+package grafanahttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+func TestClientGetJSONDecodesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/health" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(mcpgrafana.GrafanaConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var out struct {
+		Status string `json:"status"`
+	}
+	if err := client.GetJSON(context.Background(), "/api/health", &out); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if out.Status != "ok" {
+		t.Fatalf("expected status=ok, got %q", out.Status)
+	}
+}
+
+func TestClientGetJSONReturnsErrNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := New(mcpgrafana.GrafanaConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	err = client.GetJSON(context.Background(), "/api/missing", nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClientDoJSONSendsAuthHeaders(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := New(mcpgrafana.GrafanaConfig{URL: server.URL, APIKey: "secret-token"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := client.DoJSON(context.Background(), http.MethodGet, "/api/health", nil, nil); err != nil {
+		t.Fatalf("DoJSON: %v", err)
+	}
+	if gotAuth != "Bearer secret-token" {
+		t.Fatalf("expected Bearer secret-token, got %q", gotAuth)
+	}
+}
+
+func TestClientDoJSONWrapsNonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+
+	client, err := New(mcpgrafana.GrafanaConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	err = client.DoJSON(context.Background(), http.MethodGet, "/api/health", nil, nil)
+	if err == nil {
+		t.Fatal("expected an error for a 500 response")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Fatal("a 500 response should not be reported as ErrNotFound")
+	}
+}
+
+func TestNewReusesCachedTransportForSameURL(t *testing.T) {
+	cfg := mcpgrafana.GrafanaConfig{URL: "http://example.invalid"}
+
+	a, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	b, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if a.transport != b.transport {
+		t.Fatal("expected New to reuse the cached transport for the same URL")
+	}
+}
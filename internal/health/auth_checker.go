@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// grafanaOrgPath is a Grafana API endpoint that requires authentication, so
+// a successful response confirms the configured credentials actually work,
+// as opposed to merely being non-empty.
+const grafanaOrgPath = "/api/org"
+
+// AuthChecker is a readiness Checker that confirms the configured Grafana
+// credentials (API key or basic auth) are accepted by Grafana, rather than
+// just present. It reuses the Grafana URL, credentials, and TLS
+// configuration found on the context it's constructed with, the same way
+// tools in the tools package do.
+type AuthChecker struct {
+	client   *http.Client
+	url      string
+	apiKey   string
+	username string
+	password string
+}
+
+// NewAuthChecker builds an AuthChecker from the GrafanaConfig found on ctx.
+func NewAuthChecker(ctx context.Context) (*AuthChecker, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	client := &http.Client{}
+	if cfg.TLSConfig != nil {
+		transport, err := cfg.TLSConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TLS transport for Grafana auth check: %w", err)
+		}
+		client.Transport = transport
+	}
+
+	return &AuthChecker{
+		client:   client,
+		url:      cfg.URL,
+		apiKey:   cfg.APIKey,
+		username: cfg.Username,
+		password: cfg.Password,
+	}, nil
+}
+
+// Name identifies this checker in readiness output.
+func (c *AuthChecker) Name() string {
+	return "auth_valid"
+}
+
+// Check hits a Grafana endpoint that requires authentication and fails if
+// the configured credentials are rejected or missing entirely.
+func (c *AuthChecker) Check(ctx context.Context) error {
+	if c.apiKey == "" && c.password == "" {
+		return fmt.Errorf("no Grafana API key or basic auth credentials configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+grafanaOrgPath, nil)
+	if err != nil {
+		return fmt.Errorf("creating Grafana auth request: %w", err)
+	}
+	switch {
+	case c.apiKey != "":
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	case c.password != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("reaching Grafana at %s: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("Grafana rejected the configured credentials with status %d", resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Grafana org endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+)
+
+// HTTPChecker is a Checker that reports healthy when an HTTP(S) endpoint
+// responds with one of ExpectedStatus (2xx if unset), for wiring up
+// Grafana/Loki/Tempo/Mimir probes as readiness or liveness checks without
+// writing a one-off Checker for each.
+type HTTPChecker struct {
+	// CheckerName identifies this checker in readiness/liveness output.
+	CheckerName string
+	// URL is the full URL to probe.
+	URL string
+	// Method defaults to http.MethodGet if empty.
+	Method string
+	// ExpectedStatus, if non-empty, lists the status codes considered
+	// healthy. If empty, any 2xx response is considered healthy.
+	ExpectedStatus []int
+	// Client is the http.Client used to make the request, defaulting to
+	// http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// NewHTTPChecker builds an HTTPChecker named name that expects a 2xx
+// response from a GET to url.
+func NewHTTPChecker(name, url string) *HTTPChecker {
+	return &HTTPChecker{CheckerName: name, URL: url}
+}
+
+// Name identifies this checker in readiness/liveness output.
+func (c *HTTPChecker) Name() string { return c.CheckerName }
+
+// Check makes a single request to c.URL and fails unless the response
+// status matches c.ExpectedStatus (or is 2xx, if unset).
+func (c *HTTPChecker) Check(ctx context.Context) error {
+	method := c.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.URL, nil)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("reaching %s: %w", c.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if len(c.ExpectedStatus) > 0 {
+		if !slices.Contains(c.ExpectedStatus, resp.StatusCode) {
+			return fmt.Errorf("%s returned status %d, expected one of %v", c.URL, resp.StatusCode, c.ExpectedStatus)
+		}
+		return nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", c.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// TCPChecker is a Checker that reports healthy when a TCP connection to
+// Address can be established, for backends with no HTTP health endpoint to
+// poll.
+type TCPChecker struct {
+	// CheckerName identifies this checker in readiness/liveness output.
+	CheckerName string
+	// Address is the host:port to dial.
+	Address string
+}
+
+// NewTCPChecker builds a TCPChecker named name that dials address.
+func NewTCPChecker(name, address string) *TCPChecker {
+	return &TCPChecker{CheckerName: name, Address: address}
+}
+
+// Name identifies this checker in readiness/liveness output.
+func (c *TCPChecker) Name() string { return c.CheckerName }
+
+// Check dials c.Address over TCP and fails if the connection can't be
+// established before ctx's deadline.
+func (c *TCPChecker) Check(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.Address)
+	if err != nil {
+		return fmt.Errorf("dialing %s: %w", c.Address, err)
+	}
+	return conn.Close()
+}
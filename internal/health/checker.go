@@ -0,0 +1,188 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Checker is a single named dependency check that a readiness or liveness
+// probe can aggregate. Check should return quickly and respect ctx's
+// deadline; RunChecks enforces a per-check timeout regardless.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a plain function to the Checker interface, so callers
+// can register a one-off check without declaring a named type.
+type CheckerFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewCheckerFunc builds a Checker named name that runs fn.
+func NewCheckerFunc(name string, fn func(ctx context.Context) error) Checker {
+	return CheckerFunc{name: name, fn: fn}
+}
+
+// Name identifies this checker in readiness/liveness output.
+func (c CheckerFunc) Name() string { return c.name }
+
+// Check runs the wrapped function.
+func (c CheckerFunc) Check(ctx context.Context) error { return c.fn(ctx) }
+
+// CheckResult is the outcome of running a single Checker.
+type CheckResult struct {
+	Name    string        `json:"name"`
+	Status  Status        `json:"status"`
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error,omitempty"`
+	// Skipped is set when the check failed but was registered with
+	// CheckOptions.SkipOnErr, so its failure is visible in the breakdown
+	// without flipping the group's overall status.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// CheckOptions customizes how a single Checker is run within a checkerGroup,
+// via RegisterReadinessCheckWithOptions/RegisterLivenessCheckWithOptions.
+type CheckOptions struct {
+	// Timeout bounds how long this check's Check call may run before it's
+	// reported as failed. Zero uses the group's defaultPerCheckTimeout.
+	Timeout time.Duration
+
+	// SkipOnErr marks this check as non-critical: it still runs and its
+	// result is still reported, but a failure doesn't flip the group's
+	// overall status to unhealthy or the endpoint's response code to 503.
+	SkipOnErr bool
+}
+
+// checkerEntry pairs a Checker with the CheckOptions it was registered with.
+type checkerEntry struct {
+	checker Checker
+	opts    CheckOptions
+}
+
+// AggregateResponse is the JSON body returned by a checker-backed readiness
+// or liveness endpoint.
+type AggregateResponse struct {
+	Status    Status        `json:"status"`
+	Checks    []CheckResult `json:"checks"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// checkerGroup runs a set of Checkers concurrently, bounded by a semaphore,
+// and aggregates their results.
+type checkerGroup struct {
+	mu       sync.RWMutex
+	checkers []checkerEntry
+
+	// maxConcurrency bounds how many checks run at once; zero means
+	// unbounded.
+	maxConcurrency int
+
+	// perCheckTimeout bounds how long any single Check call may run before
+	// it's reported as failed, for checks registered without an explicit
+	// CheckOptions.Timeout.
+	perCheckTimeout time.Duration
+}
+
+const (
+	defaultMaxConcurrentChecks = 8
+	defaultPerCheckTimeout     = 5 * time.Second
+)
+
+func newCheckerGroup() *checkerGroup {
+	return &checkerGroup{
+		maxConcurrency:  defaultMaxConcurrentChecks,
+		perCheckTimeout: defaultPerCheckTimeout,
+	}
+}
+
+func (g *checkerGroup) register(c Checker) {
+	g.registerWithOptions(c, CheckOptions{})
+}
+
+func (g *checkerGroup) registerWithOptions(c Checker, opts CheckOptions) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.checkers = append(g.checkers, checkerEntry{checker: c, opts: opts})
+}
+
+func (g *checkerGroup) empty() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.checkers) == 0
+}
+
+// run executes all registered checkers (other than those named in exclude)
+// in parallel, bounded by a semaphore, and returns their results alongside
+// the overall status. A checker registered with CheckOptions.SkipOnErr still
+// contributes its result but never flips the overall status to unhealthy.
+func (g *checkerGroup) run(ctx context.Context, exclude ...string) (Status, []CheckResult) {
+	excludeSet := make(map[string]struct{}, len(exclude))
+	for _, name := range exclude {
+		excludeSet[name] = struct{}{}
+	}
+
+	g.mu.RLock()
+	entries := make([]checkerEntry, 0, len(g.checkers))
+	for _, e := range g.checkers {
+		if _, skip := excludeSet[e.checker.Name()]; skip {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	g.mu.RUnlock()
+
+	results := make([]CheckResult, len(entries))
+	sem := make(chan struct{}, g.maxConcurrency)
+
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, e checkerEntry) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = g.runOne(ctx, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	overall := StatusHealthy
+	for _, r := range results {
+		if r.Status != StatusHealthy && !r.Skipped {
+			overall = StatusUnhealthy
+			break
+		}
+	}
+
+	return overall, results
+}
+
+func (g *checkerGroup) runOne(ctx context.Context, e checkerEntry) CheckResult {
+	timeout := e.opts.Timeout
+	if timeout <= 0 {
+		timeout = g.perCheckTimeout
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := e.checker.Check(checkCtx)
+	latency := time.Since(start)
+
+	result := CheckResult{
+		Name:    e.checker.Name(),
+		Status:  StatusHealthy,
+		Latency: latency,
+	}
+	if err != nil {
+		result.Status = StatusUnhealthy
+		result.Error = err.Error()
+		result.Skipped = e.opts.SkipOnErr
+	}
+
+	return result
+}
@@ -0,0 +1,214 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeChecker struct {
+	name string
+	err  error
+	wait time.Duration
+}
+
+func (f fakeChecker) Name() string { return f.name }
+
+func (f fakeChecker) Check(ctx context.Context) error {
+	if f.wait > 0 {
+		select {
+		case <-time.After(f.wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return f.err
+}
+
+func TestRegisterReadinessCheck_FallsBackWhenEmpty(t *testing.T) {
+	server := NewServer(Config{ServiceName: "test-service", Version: "1.0.0"})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/readiness", nil)
+	w := httptest.NewRecorder()
+	server.readinessHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 with no checkers registered, got %d", w.Code)
+	}
+}
+
+func TestRegisterReadinessCheck_AggregatesResults(t *testing.T) {
+	server := NewServer(Config{ServiceName: "test-service", Version: "1.0.0"})
+	server.RegisterReadinessCheck(fakeChecker{name: "ok-check"})
+	server.RegisterReadinessCheck(fakeChecker{name: "failing-check", err: errors.New("boom")})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/readiness", nil)
+	w := httptest.NewRecorder()
+	server.readinessHandler(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 when a check fails, got %d", w.Code)
+	}
+
+	var resp AggregateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if resp.Status != StatusUnhealthy {
+		t.Errorf("expected overall status unhealthy, got %s", resp.Status)
+	}
+	if len(resp.Checks) != 2 {
+		t.Fatalf("expected 2 check results, got %d", len(resp.Checks))
+	}
+}
+
+func TestRegisterReadinessCheck_AllHealthy(t *testing.T) {
+	server := NewServer(Config{ServiceName: "test-service", Version: "1.0.0"})
+	server.RegisterReadinessCheck(fakeChecker{name: "db"})
+	server.RegisterReadinessCheck(fakeChecker{name: "cache"})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/readiness", nil)
+	w := httptest.NewRecorder()
+	server.readinessHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 when all checks pass, got %d", w.Code)
+	}
+
+	var resp AggregateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Status != StatusHealthy {
+		t.Errorf("expected overall status healthy, got %s", resp.Status)
+	}
+}
+
+func TestRegisterLivenessCheck_AggregatesResults(t *testing.T) {
+	server := NewServer(Config{ServiceName: "test-service", Version: "1.0.0"})
+	server.RegisterLivenessCheck(fakeChecker{name: "event-loop", err: errors.New("stuck")})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/liveness", nil)
+	w := httptest.NewRecorder()
+	server.livenessHandler(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+}
+
+func TestReadinessHandler_ExcludeSkipsNamedCheck(t *testing.T) {
+	server := NewServer(Config{ServiceName: "test-service", Version: "1.0.0"})
+	server.RegisterReadinessCheck(fakeChecker{name: "ok-check"})
+	server.RegisterReadinessCheck(fakeChecker{name: "failing-check", err: errors.New("boom")})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/readiness?exclude=failing-check", nil)
+	w := httptest.NewRecorder()
+	server.readinessHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 with failing check excluded, got %d", w.Code)
+	}
+
+	var resp AggregateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Checks) != 1 {
+		t.Fatalf("expected 1 check result with one excluded, got %d", len(resp.Checks))
+	}
+	if resp.Checks[0].Name != "ok-check" {
+		t.Errorf("expected the excluded check to be omitted, got %q", resp.Checks[0].Name)
+	}
+}
+
+func TestReadinessHandler_VerboseReturnsPlainTextBreakdown(t *testing.T) {
+	server := NewServer(Config{ServiceName: "test-service", Version: "1.0.0"})
+	server.RegisterReadinessCheck(fakeChecker{name: "ping"})
+	server.RegisterReadinessCheck(fakeChecker{name: "serializable_read", err: errors.New("boom")})
+
+	req := httptest.NewRequest(http.MethodGet, "/health/readiness?verbose=1", nil)
+	w := httptest.NewRecorder()
+	server.readinessHandler(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 with a failing check, got %d", w.Code)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "[+]ping ok\n") {
+		t.Errorf("expected verbose output to report the passing check, got %q", body)
+	}
+	if !strings.Contains(body, "[-]serializable_read failed: boom\n") {
+		t.Errorf("expected verbose output to report the failing check, got %q", body)
+	}
+}
+
+func TestCheckerGroup_RunsConcurrentlyBoundedBySemaphore(t *testing.T) {
+	group := newCheckerGroup()
+	group.maxConcurrency = 2
+
+	const numCheckers = 6
+	var active, maxActive int
+	var mu = make(chan struct{}, 1)
+	mu <- struct{}{}
+
+	for i := 0; i < numCheckers; i++ {
+		group.register(fakeCheckerFunc(fmt.Sprintf("c%d", i), func() error {
+			<-mu
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mu <- struct{}{}
+
+			time.Sleep(20 * time.Millisecond)
+
+			<-mu
+			active--
+			mu <- struct{}{}
+			return nil
+		}))
+	}
+
+	status, results := group.run(context.Background())
+	if status != StatusHealthy {
+		t.Errorf("expected overall status healthy, got %s", status)
+	}
+	if len(results) != numCheckers {
+		t.Fatalf("expected %d results, got %d", numCheckers, len(results))
+	}
+	if maxActive > group.maxConcurrency {
+		t.Errorf("expected at most %d concurrent checks, saw %d", group.maxConcurrency, maxActive)
+	}
+}
+
+func TestCheckerGroup_PerCheckTimeout(t *testing.T) {
+	group := newCheckerGroup()
+	group.perCheckTimeout = 10 * time.Millisecond
+	group.register(fakeChecker{name: "slow", wait: 100 * time.Millisecond})
+
+	status, results := group.run(context.Background())
+	if status != StatusUnhealthy {
+		t.Errorf("expected overall status unhealthy after timeout, got %s", status)
+	}
+	if results[0].Error == "" {
+		t.Error("expected a timeout error to be recorded")
+	}
+}
+
+type funcChecker struct {
+	name string
+	fn   func() error
+}
+
+func (f funcChecker) Name() string                        { return f.name }
+func (f funcChecker) Check(ctx context.Context) error      { return f.fn() }
+func fakeCheckerFunc(name string, fn func() error) Checker { return funcChecker{name: name, fn: fn} }
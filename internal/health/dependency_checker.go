@@ -0,0 +1,202 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultDependencyCheckInterval is how often a DependencyChecker
+	// re-probes its configured backends when Interval is unset.
+	defaultDependencyCheckInterval = 15 * time.Second
+
+	// defaultDependencyProbeTimeout bounds how long a single probe may run,
+	// so one slow backend can't hold up the whole polling round - the
+	// probe is simply recorded as failed once it's exceeded.
+	defaultDependencyProbeTimeout = 3 * time.Second
+)
+
+// DependencyProbe describes how to reach a single backend dependency: an
+// HTTP request that's expected to succeed (2xx) when the dependency is
+// healthy.
+type DependencyProbe struct {
+	// Name identifies the dependency in DependencyStatus snapshots and the
+	// /healthz "dependencies" map, e.g. "grafana" or "loki".
+	Name string
+	// URL is the full URL to probe.
+	URL string
+	// Method defaults to http.MethodGet if empty.
+	Method string
+}
+
+// DependencyStatus is the cached outcome of the most recent probe of a
+// single dependency.
+type DependencyStatus struct {
+	Status    Status    `json:"status"`
+	LatencyMs int64     `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// DependencyChecker periodically probes a fixed set of backend dependencies
+// in the background and caches the latest result for each, so request
+// handlers can report on downstream connectivity by reading the cache
+// instead of making a live network call on every request - a single slow
+// or wedged backend can only ever cost one probe's worth of delay, not a
+// request's.
+type DependencyChecker struct {
+	probes  []DependencyProbe
+	client  *http.Client
+	interval time.Duration
+	timeout  time.Duration
+
+	mu      sync.RWMutex
+	results map[string]DependencyStatus
+}
+
+// NewDependencyChecker builds a DependencyChecker that probes probes every
+// interval (defaulting to 15s if <= 0). It does not start probing until
+// Start is called.
+func NewDependencyChecker(interval time.Duration, probes ...DependencyProbe) *DependencyChecker {
+	if interval <= 0 {
+		interval = defaultDependencyCheckInterval
+	}
+	return &DependencyChecker{
+		probes:   probes,
+		client:   &http.Client{},
+		interval: interval,
+		timeout:  defaultDependencyProbeTimeout,
+		results:  make(map[string]DependencyStatus, len(probes)),
+	}
+}
+
+// Start runs one probe round immediately, so Snapshot has data as soon as
+// Start returns, then continues probing every interval in the background
+// until ctx is cancelled.
+func (d *DependencyChecker) Start(ctx context.Context) {
+	d.probeAll(ctx)
+
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.probeAll(ctx)
+			}
+		}
+	}()
+}
+
+// probeAll probes every configured dependency in parallel and updates the
+// cache with the results.
+func (d *DependencyChecker) probeAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, p := range d.probes {
+		wg.Add(1)
+		go func(p DependencyProbe) {
+			defer wg.Done()
+			status := d.probeOne(ctx, p)
+			d.mu.Lock()
+			d.results[p.Name] = status
+			d.mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+}
+
+// probeOne runs a single probe, bounded by d.timeout regardless of ctx's
+// own deadline.
+func (d *DependencyChecker) probeOne(ctx context.Context, p DependencyProbe) DependencyStatus {
+	probeCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	method := p.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	start := time.Now()
+	status := DependencyStatus{CheckedAt: start.UTC()}
+
+	req, err := http.NewRequestWithContext(probeCtx, method, p.URL, nil)
+	if err != nil {
+		status.Status = StatusUnhealthy
+		status.Error = fmt.Sprintf("building request: %v", err)
+		return status
+	}
+
+	resp, err := d.client.Do(req)
+	status.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		status.Status = StatusUnhealthy
+		status.Error = err.Error()
+		return status
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		status.Status = StatusUnhealthy
+		status.Error = fmt.Sprintf("returned status %d", resp.StatusCode)
+		return status
+	}
+
+	status.Status = StatusHealthy
+	return status
+}
+
+// Snapshot returns a point-in-time copy of the most recently cached result
+// for every probed dependency.
+func (d *DependencyChecker) Snapshot() map[string]DependencyStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	snapshot := make(map[string]DependencyStatus, len(d.results))
+	for name, status := range d.results {
+		snapshot[name] = status
+	}
+	return snapshot
+}
+
+// Unhealthy reports whether any probed dependency's cached result is
+// unhealthy, or hasn't been probed yet.
+func (d *DependencyChecker) Unhealthy() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.results) < len(d.probes) {
+		return true
+	}
+	for _, status := range d.results {
+		if status.Status != StatusHealthy {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadinessCheckers adapts each probed dependency into a Checker that
+// reports the cached DependencyStatus instead of making a live request, for
+// registration with Server.RegisterReadinessCheck.
+func (d *DependencyChecker) ReadinessCheckers() []Checker {
+	checkers := make([]Checker, 0, len(d.probes))
+	for _, p := range d.probes {
+		name := p.Name
+		checkers = append(checkers, NewCheckerFunc(name, func(ctx context.Context) error {
+			d.mu.RLock()
+			status, ok := d.results[name]
+			d.mu.RUnlock()
+			if !ok {
+				return fmt.Errorf("dependency %s has not been probed yet", name)
+			}
+			if status.Status != StatusHealthy {
+				return fmt.Errorf("%s", status.Error)
+			}
+			return nil
+		}))
+	}
+	return checkers
+}
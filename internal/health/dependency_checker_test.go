@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDependencyChecker_ProbesAllAndCachesResults(t *testing.T) {
+	okServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer okServer.Close()
+
+	failServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failServer.Close()
+
+	checker := NewDependencyChecker(time.Hour,
+		DependencyProbe{Name: "ok-dep", URL: okServer.URL},
+		DependencyProbe{Name: "failing-dep", URL: failServer.URL},
+	)
+	checker.Start(context.Background())
+
+	snapshot := checker.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 cached results, got %d", len(snapshot))
+	}
+	if snapshot["ok-dep"].Status != StatusHealthy {
+		t.Errorf("expected ok-dep to be healthy, got %s", snapshot["ok-dep"].Status)
+	}
+	if snapshot["failing-dep"].Status != StatusUnhealthy {
+		t.Errorf("expected failing-dep to be unhealthy, got %s", snapshot["failing-dep"].Status)
+	}
+	if snapshot["failing-dep"].Error == "" {
+		t.Error("expected an error message for the failing dependency")
+	}
+	if !checker.Unhealthy() {
+		t.Error("expected checker to report unhealthy when one dependency fails")
+	}
+}
+
+func TestDependencyChecker_ProbeTimeoutDoesNotBlockRound(t *testing.T) {
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slowServer.Close()
+
+	checker := NewDependencyChecker(time.Hour, DependencyProbe{Name: "slow-dep", URL: slowServer.URL})
+	checker.timeout = 10 * time.Millisecond
+
+	start := time.Now()
+	checker.Start(context.Background())
+	elapsed := time.Since(start)
+
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("expected probe to be bounded by its own timeout, took %s", elapsed)
+	}
+
+	snapshot := checker.Snapshot()
+	if snapshot["slow-dep"].Status != StatusUnhealthy {
+		t.Errorf("expected slow-dep to be recorded as unhealthy, got %s", snapshot["slow-dep"].Status)
+	}
+}
+
+func TestDependencyChecker_ReadinessCheckersReflectCachedResults(t *testing.T) {
+	failServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failServer.Close()
+
+	checker := NewDependencyChecker(time.Hour, DependencyProbe{Name: "loki", URL: failServer.URL})
+	checker.Start(context.Background())
+
+	checkers := checker.ReadinessCheckers()
+	if len(checkers) != 1 {
+		t.Fatalf("expected 1 readiness checker, got %d", len(checkers))
+	}
+	if err := checkers[0].Check(context.Background()); err == nil {
+		t.Error("expected the readiness checker to surface the cached failure")
+	}
+}
@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+const (
+	// defaultAlloyHost and alloyHostEnvVar mirror the constants in
+	// tools/alloy.go, since Alloy's address is discovered the same way
+	// there and here.
+	defaultAlloyHost = "localhost:12345"
+	alloyHostEnvVar  = "ALLOY_HOST"
+
+	alloyComponentsPath = "/api/v0/web/components"
+	prometheusReadyPath = "/-/ready"
+	lokiReadyPath       = "/ready"
+)
+
+// NewGrafanaDependencyProbe builds a DependencyProbe for the Grafana
+// instance found on ctx, using the same /api/health endpoint as
+// GrafanaChecker.
+func NewGrafanaDependencyProbe(ctx context.Context) DependencyProbe {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	return DependencyProbe{
+		Name:   "grafana",
+		URL:    strings.TrimRight(cfg.URL, "/") + grafanaHealthPath,
+		Method: http.MethodGet,
+	}
+}
+
+// NewAlloyDependencyProbe builds a DependencyProbe for the Alloy instance
+// configured via the ALLOY_HOST environment variable (defaulting to
+// localhost:12345, as in tools/alloy.go). It uses a HEAD request against
+// the components API, since a successful response there only confirms
+// Alloy is serving its API, not that any particular pipeline is healthy.
+func NewAlloyDependencyProbe() DependencyProbe {
+	host := os.Getenv(alloyHostEnvVar)
+	if host == "" {
+		host = defaultAlloyHost
+	}
+	return DependencyProbe{
+		Name:   "alloy",
+		URL:    "http://" + host + alloyComponentsPath,
+		Method: http.MethodHead,
+	}
+}
+
+// NewPrometheusDependencyProbe builds a DependencyProbe for a Prometheus-
+// compatible datasource reachable at baseURL, named name so multiple
+// Prometheus datasources can be probed side by side in the same
+// DependencyChecker.
+func NewPrometheusDependencyProbe(name, baseURL string) DependencyProbe {
+	return DependencyProbe{
+		Name:   name,
+		URL:    strings.TrimRight(baseURL, "/") + prometheusReadyPath,
+		Method: http.MethodGet,
+	}
+}
+
+// NewLokiDependencyProbe builds a DependencyProbe for a Loki datasource
+// reachable at baseURL, named name so multiple Loki datasources can be
+// probed side by side in the same DependencyChecker.
+func NewLokiDependencyProbe(name, baseURL string) DependencyProbe {
+	return DependencyProbe{
+		Name:   name,
+		URL:    strings.TrimRight(baseURL, "/") + lokiReadyPath,
+		Method: http.MethodGet,
+	}
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// grafanaHealthPath is the Grafana API endpoint used to probe connectivity.
+const grafanaHealthPath = "/api/health"
+
+// GrafanaChecker is a readiness Checker that confirms the configured Grafana
+// instance is actually reachable, as opposed to just "the process is alive".
+// It reuses the Grafana URL, credentials, and TLS configuration found on the
+// context it's constructed with, the same way tools in the tools package do.
+type GrafanaChecker struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+// NewGrafanaChecker builds a GrafanaChecker from the GrafanaConfig found on
+// ctx.
+func NewGrafanaChecker(ctx context.Context) (*GrafanaChecker, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	client := &http.Client{}
+	if cfg.TLSConfig != nil {
+		transport, err := cfg.TLSConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TLS transport for Grafana health check: %w", err)
+		}
+		client.Transport = transport
+	}
+
+	return &GrafanaChecker{
+		client: client,
+		url:    cfg.URL,
+		apiKey: cfg.APIKey,
+	}, nil
+}
+
+// Name identifies this checker in readiness output.
+func (c *GrafanaChecker) Name() string {
+	return "grafana_reachable"
+}
+
+// Check hits Grafana's /api/health endpoint and fails if it doesn't return a
+// 2xx response.
+func (c *GrafanaChecker) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+grafanaHealthPath, nil)
+	if err != nil {
+		return fmt.Errorf("creating Grafana health request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("reaching Grafana at %s: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Grafana health endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
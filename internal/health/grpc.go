@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Service names recognized by grpcHealthServer.Check/Watch. The empty
+// string is the standard grpc.health.v1 convention for "the server as a
+// whole" and is treated the same as "liveness", since that's the weaker of
+// the two checks and matches what most health-checking load balancers
+// expect from an unqualified probe.
+const (
+	grpcServiceReadiness = "readiness"
+	grpcServiceLiveness  = "liveness"
+)
+
+// defaultGRPCWatchPollInterval bounds how often an open Watch stream
+// re-evaluates its service's status.
+const defaultGRPCWatchPollInterval = time.Second
+
+// grpcHealthServer implements the standard grpc.health.v1.Health service
+// (Check and Watch), delegating to the same readiness/liveness
+// checkerGroups that back the HTTP /readyz and /livez endpoints. This
+// keeps gRPC-based service meshes and load balancers looking at exactly
+// the same status as anything polling over HTTP.
+type grpcHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	readiness *checkerGroup
+	liveness  *checkerGroup
+
+	watchPollInterval time.Duration
+}
+
+// newGRPCHealthServer builds a grpcHealthServer backed by readiness and
+// liveness.
+func newGRPCHealthServer(readiness, liveness *checkerGroup) *grpcHealthServer {
+	return &grpcHealthServer{
+		readiness:         readiness,
+		liveness:          liveness,
+		watchPollInterval: defaultGRPCWatchPollInterval,
+	}
+}
+
+// groupFor resolves a grpc.health.v1 service name to the checkerGroup that
+// answers for it.
+func (s *grpcHealthServer) groupFor(service string) (*checkerGroup, bool) {
+	switch service {
+	case "", grpcServiceLiveness:
+		return s.liveness, true
+	case grpcServiceReadiness:
+		return s.readiness, true
+	default:
+		return nil, false
+	}
+}
+
+// servingStatus runs service's checkerGroup (or treats an empty group as
+// always serving, mirroring runCheckerGroupHandler's HTTP fallback
+// behavior) and translates the result to a grpc_health_v1 serving status.
+func (s *grpcHealthServer) servingStatus(ctx context.Context, service string) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	group, ok := s.groupFor(service)
+	if !ok {
+		return 0, status.Errorf(codes.NotFound, "unknown service %q", service)
+	}
+	if group.empty() {
+		return grpc_health_v1.HealthCheckResponse_SERVING, nil
+	}
+
+	overall, _ := group.run(ctx)
+	if overall == StatusHealthy {
+		return grpc_health_v1.HealthCheckResponse_SERVING, nil
+	}
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING, nil
+}
+
+// Check implements grpc_health_v1.HealthServer.
+func (s *grpcHealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	servingStatus, err := s.servingStatus(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+// Watch implements grpc_health_v1.HealthServer, streaming a new
+// HealthCheckResponse every time the requested service's serving status
+// changes, polling at watchPollInterval, until the client disconnects.
+func (s *grpcHealthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ticker := time.NewTicker(s.watchPollInterval)
+	defer ticker.Stop()
+
+	last := grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	for {
+		servingStatus, err := s.servingStatus(stream.Context(), req.GetService())
+		if err != nil {
+			return err
+		}
+		if servingStatus != last {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return err
+			}
+			last = servingStatus
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-ticker.C:
+		}
+	}
+}
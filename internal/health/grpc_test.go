@@ -0,0 +1,44 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCHealthServer_CheckReflectsCheckerGroups(t *testing.T) {
+	readiness := newCheckerGroup()
+	liveness := newCheckerGroup()
+	readiness.register(fakeChecker{name: "failing-check", err: errors.New("boom")})
+
+	server := newGRPCHealthServer(readiness, liveness)
+
+	resp, err := server.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: grpcServiceReadiness})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING for a failing readiness check, got %s", resp.Status)
+	}
+
+	resp, err = server.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: grpcServiceLiveness})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING for an empty liveness group, got %s", resp.Status)
+	}
+}
+
+func TestGRPCHealthServer_CheckUnknownServiceReturnsNotFound(t *testing.T) {
+	server := newGRPCHealthServer(newCheckerGroup(), newCheckerGroup())
+
+	_, err := server.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "nonexistent"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an unknown service, got %v", err)
+	}
+}
@@ -17,16 +17,102 @@ const (
 
 // Response represents the health check response
 type Response struct {
-	Status    Status    `json:"status"`
-	Service   string    `json:"service"`
-	Version   string    `json:"version"`
-	Timestamp time.Time `json:"timestamp"`
+	Status       Status                      `json:"status"`
+	Service      string                      `json:"service"`
+	Version      string                      `json:"version"`
+	Timestamp    time.Time                   `json:"timestamp"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+	Stats        *StatsSnapshot              `json:"stats,omitempty"`
 }
 
 // Config holds the configuration for health checks
 type Config struct {
 	ServiceName string
 	Version     string
+
+	// TLSCertFile and TLSKeyFile, if both set, make Server.Start and
+	// Server.StartAsync serve over HTTPS (via ListenAndServeTLS) instead of
+	// plain HTTP.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// ClientCAFile, if set, enables mutual TLS: client certificates are
+	// verified against this CA when presented, but are not required, since
+	// /health/readiness also accepts a bearer token. Has no effect unless
+	// TLSCertFile/TLSKeyFile are also set.
+	ClientCAFile string
+
+	// ReadinessAuthToken, if set, requires /health/readiness requests to
+	// present a matching `Authorization: Bearer <token>` header, compared
+	// in constant time. /health/liveness is never gated on this, so
+	// kubelet's unauthenticated HTTP probes keep working.
+	ReadinessAuthToken string
+
+	// ReadinessAllowedClientCNs, if non-empty, additionally authorizes
+	// /health/readiness requests whose TLS client certificate (verified
+	// against ClientCAFile) has a Subject CommonName in this list, without
+	// requiring ReadinessAuthToken.
+	ReadinessAllowedClientCNs []string
+
+	// Dependencies, if set, is surfaced in the /healthz response as a
+	// "dependencies" map of cached per-backend status, and flips the
+	// response's overall status to unhealthy if any dependency is
+	// unhealthy.
+	Dependencies *DependencyChecker
+
+	// StartupTimeout bounds how long /startupz (and therefore /readyz) will
+	// wait for Server.MarkStartupComplete before failing startup outright,
+	// so a pod that never finishes starting gets restarted rather than
+	// left stuck. Defaults to DefaultStartupTimeout if zero.
+	StartupTimeout time.Duration
+
+	// Stats, if set (via Server.WithStats), is surfaced in the /healthz
+	// response as a "stats" snapshot of rolling request counters, alongside
+	// the existing liveness/dependency information.
+	Stats *StatsRecorder
+
+	// ManagementToken, if set, gates how much detail Handler's /healthz
+	// response includes. An unauthenticated GET (no matching bearer token,
+	// and no matching ?token= query parameter) still gets a minimal
+	// {"status": ...} response at the correct status code, so an
+	// unauthenticated prober can still tell healthy from unhealthy without
+	// learning anything about dependency topology. A request presenting
+	// ManagementToken - via `Authorization: Bearer <token>` or a `?token=`
+	// query parameter - gets the full breakdown, same as when
+	// ManagementToken is unset. ?verbose=1 is accepted as an alias for the
+	// same full-detail request, gated by the same token.
+	ManagementToken string
+}
+
+// DefaultStartupTimeout is used when Config.StartupTimeout is unset.
+const DefaultStartupTimeout = 60 * time.Second
+
+// buildResponse evaluates config's Dependencies and Stats (if set) into a
+// Response and the HTTP status code it should be served with. It's the
+// shared core of Handler and StreamHandler, so both report the same
+// status/dependencies/stats snapshot from a single code path.
+func buildResponse(config Config) (Response, int) {
+	response := Response{
+		Status:    StatusHealthy,
+		Service:   config.ServiceName,
+		Version:   config.Version,
+		Timestamp: time.Now().UTC(),
+	}
+
+	statusCode := http.StatusOK
+	if config.Dependencies != nil {
+		response.Dependencies = config.Dependencies.Snapshot()
+		if config.Dependencies.Unhealthy() {
+			response.Status = StatusUnhealthy
+			statusCode = http.StatusServiceUnavailable
+		}
+	}
+	if config.Stats != nil {
+		snapshot := config.Stats.Snapshot()
+		response.Stats = &snapshot
+	}
+
+	return response, statusCode
 }
 
 // Handler creates an HTTP handler for health checks
@@ -37,15 +123,13 @@ func Handler(config Config) http.HandlerFunc {
 			return
 		}
 
-		response := Response{
-			Status:    StatusHealthy,
-			Service:   config.ServiceName,
-			Version:   config.Version,
-			Timestamp: time.Now().UTC(),
+		response, statusCode := buildResponse(config)
+		if config.ManagementToken != "" && !managementTokenAuthorized(r, config.ManagementToken) {
+			response = Response{Status: response.Status}
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(statusCode)
 
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			slog.Error("Failed to encode health check response", "error", err)
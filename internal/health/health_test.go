@@ -1,6 +1,7 @@
 package health
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -181,6 +182,37 @@ func TestResponseStructure(t *testing.T) {
 	}
 }
 
+func TestHandler_UnhealthyDependencyFlipsStatus(t *testing.T) {
+	failServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failServer.Close()
+
+	checker := NewDependencyChecker(time.Hour, DependencyProbe{Name: "tempo", URL: failServer.URL})
+	checker.Start(context.Background())
+
+	config := Config{ServiceName: "test-service", Version: "1.0.0", Dependencies: checker}
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	Handler(config)(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 with an unhealthy dependency, got %d", w.Code)
+	}
+
+	var response Response
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response.Status != StatusUnhealthy {
+		t.Errorf("expected overall status unhealthy, got %s", response.Status)
+	}
+	if response.Dependencies["tempo"].Status != StatusUnhealthy {
+		t.Errorf("expected tempo dependency to be reported unhealthy, got %+v", response.Dependencies["tempo"])
+	}
+}
+
 func TestStatus(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,177 @@
+package health
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The metrics below are registered against the default Prometheus registry
+// so they show up alongside any other process/Go runtime collectors an
+// operator already scrapes, without requiring callers to thread a custom
+// registry through the health subsystem.
+var (
+	// toolInvocationsTotal counts every dynamically dispatched tool call,
+	// labeled by outcome so operators can alert on rising failure rates per
+	// tool.
+	toolInvocationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_tool_invocations_total",
+		Help: "Total number of MCP tool invocations, labeled by tool name and outcome status.",
+	}, []string{"tool", "status"})
+
+	// toolDurationSeconds tracks how long tool handlers take to run,
+	// independent of outcome.
+	toolDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mcp_tool_duration_seconds",
+		Help:    "Duration of MCP tool invocations in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"tool"})
+
+	// grafanaAPIRequestDurationSeconds tracks latency of outbound calls to
+	// the Grafana API (and datasources proxied through it), labeled by a
+	// coarse endpoint name and response status so slow backends are visible
+	// without scraping logs.
+	grafanaAPIRequestDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mcp_grafana_api_request_duration_seconds",
+		Help:    "Duration of requests to the Grafana API in seconds, labeled by endpoint and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint", "status"})
+
+	// grafanaAPIRequestsTotal counts the same outbound Grafana API calls as
+	// grafanaAPIRequestDurationSeconds, for operators who want a simple rate()
+	// without deriving it from the histogram's _count series.
+	grafanaAPIRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_grafana_api_requests_total",
+		Help: "Total number of requests to the Grafana API, labeled by endpoint and status.",
+	}, []string{"endpoint", "status"})
+
+	// healthChecksPassed and healthChecksTotal expose the last run's
+	// passed/total check counts as gauges, labeled by type ("readiness" or
+	// "liveness"), so a single `mcp_health_checks_passed /
+	// mcp_health_checks_total` query gives the same "checks passed / total"
+	// summary the /readyz and /livez JSON responses report individually per
+	// check.
+	healthChecksPassed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mcp_health_checks_passed",
+		Help: "Number of registered health checks that passed on their last run, labeled by type (readiness or liveness).",
+	}, []string{"type"})
+	healthChecksTotal = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mcp_health_checks_total",
+		Help: "Total number of registered health checks run, labeled by type (readiness or liveness).",
+	}, []string{"type"})
+
+	// healthCheckStatus mirrors the last result of every registered
+	// liveness/readiness check as a gauge, so dashboards and alerts can
+	// track individual check flapping over time rather than just the
+	// aggregated /readyz or /livez response.
+	healthCheckStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mcp_health_check_status",
+		Help: "Last result of a registered health check (1 = healthy, 0 = unhealthy), labeled by check name and type.",
+	}, []string{"name", "type"})
+
+	// onCallSettingsCacheTotal counts lookups of the cached OnCall API URL
+	// (resolved from Grafana's settings endpoint), labeled by result: hit,
+	// miss, or refresh_error, so the settings round trip being amortized
+	// away is visible on a dashboard rather than just in logs.
+	onCallSettingsCacheTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_oncall_settings_cache_total",
+		Help: "Total lookups of the cached OnCall API URL, labeled by result (hit, miss, refresh_error).",
+	}, []string{"result"})
+)
+
+// ToolOutcomeStatus returns the "status" label value for err, following the
+// same success/error split used throughout the tools package's error
+// handling.
+func ToolOutcomeStatus(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// RecordToolCall implements tools.MetricsRecorder, updating
+// mcp_tool_invocations_total and mcp_tool_duration_seconds for a single tool
+// call. It's the concrete recorder wired into tools.WithMetrics when
+// EnableMetrics is used. If s.stats is also enabled (via WithStats), the
+// call is recorded there too, under an HTTP-equivalent status of 200/500 so
+// it folds into the same "by_name"/status-class breakdown as HTTP requests.
+func (s *Server) RecordToolCall(toolName string, duration time.Duration, err error) {
+	toolInvocationsTotal.WithLabelValues(toolName, ToolOutcomeStatus(err)).Inc()
+	toolDurationSeconds.WithLabelValues(toolName).Observe(duration.Seconds())
+
+	if s.stats != nil {
+		statusCode := http.StatusOK
+		if err != nil {
+			statusCode = http.StatusInternalServerError
+		}
+		s.stats.Record(toolName, statusCode, duration, 0, 0)
+	}
+}
+
+// RecordGrafanaAPIRequest records the duration of a single call to the
+// Grafana API (or a datasource proxied through it), labeled by a coarse
+// endpoint name and outcome.
+func RecordGrafanaAPIRequest(endpoint string, duration time.Duration, err error) {
+	status := ToolOutcomeStatus(err)
+	grafanaAPIRequestDurationSeconds.WithLabelValues(endpoint, status).Observe(duration.Seconds())
+	grafanaAPIRequestsTotal.WithLabelValues(endpoint, status).Inc()
+}
+
+// instrumentedTransport wraps an http.RoundTripper, recording every request
+// it makes to mcp_grafana_api_request_duration_seconds under a fixed
+// endpoint label.
+type instrumentedTransport struct {
+	base     http.RoundTripper
+	endpoint string
+}
+
+// NewInstrumentedTransport wraps base so every request made through it is
+// recorded against mcp_grafana_api_request_duration_seconds, labeled with
+// endpoint and the response's status class (or "error" if the round trip
+// itself failed). A nil base wraps http.DefaultTransport, matching
+// net/http's own convention for RoundTripper fields left unset.
+func NewInstrumentedTransport(base http.RoundTripper, endpoint string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &instrumentedTransport{base: base, endpoint: endpoint}
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	grafanaAPIRequestDurationSeconds.WithLabelValues(t.endpoint, status).Observe(time.Since(start).Seconds())
+	grafanaAPIRequestsTotal.WithLabelValues(t.endpoint, status).Inc()
+	return resp, err
+}
+
+// RecordOnCallSettingsCacheResult increments mcp_oncall_settings_cache_total
+// for a single cache lookup or background refresh attempt.
+func RecordOnCallSettingsCacheResult(result string) {
+	onCallSettingsCacheTotal.WithLabelValues(result).Inc()
+}
+
+// recordCheckResults updates the mcp_health_check_status gauge for every
+// result in results, plus the aggregate mcp_health_checks_passed /
+// mcp_health_checks_total gauges, all labeled with checkType ("readiness" or
+// "liveness").
+func recordCheckResults(checkType string, results []CheckResult) {
+	passed := 0.0
+	for _, result := range results {
+		value := 0.0
+		if result.Status == StatusHealthy {
+			value = 1.0
+			passed++
+		}
+		healthCheckStatus.WithLabelValues(result.Name, checkType).Set(value)
+	}
+	healthChecksPassed.WithLabelValues(checkType).Set(passed)
+	healthChecksTotal.WithLabelValues(checkType).Set(float64(len(results)))
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestServer_RecordToolCall(t *testing.T) {
+	s := &Server{}
+
+	s.RecordToolCall("list_teams_test", 10*time.Millisecond, nil)
+	s.RecordToolCall("list_teams_test", 10*time.Millisecond, errors.New("boom"))
+
+	if got := testutil.ToFloat64(toolInvocationsTotal.WithLabelValues("list_teams_test", "success")); got != 1 {
+		t.Errorf("expected 1 success invocation, got %v", got)
+	}
+	if got := testutil.ToFloat64(toolInvocationsTotal.WithLabelValues("list_teams_test", "error")); got != 1 {
+		t.Errorf("expected 1 error invocation, got %v", got)
+	}
+}
+
+func TestInstrumentedTransport_RecordsStatusLabel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	before := testutil.CollectAndCount(grafanaAPIRequestDurationSeconds)
+
+	client := &http.Client{Transport: NewInstrumentedTransport(nil, "test_endpoint")}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if after := testutil.CollectAndCount(grafanaAPIRequestDurationSeconds); after != before+1 {
+		t.Errorf("expected one new histogram series, got %d (before %d)", after, before)
+	}
+}
+
+func TestRecordOnCallSettingsCacheResult_IncrementsCounter(t *testing.T) {
+	before := testutil.ToFloat64(onCallSettingsCacheTotal.WithLabelValues("hit"))
+
+	RecordOnCallSettingsCacheResult("hit")
+
+	if after := testutil.ToFloat64(onCallSettingsCacheTotal.WithLabelValues("hit")); after != before+1 {
+		t.Errorf("expected hit counter to increase by 1, got %v (before %v)", after, before)
+	}
+}
+
+func TestRecordCheckResults_UpdatesGauge(t *testing.T) {
+	recordCheckResults("readiness", []CheckResult{
+		{Name: "test-check", Status: StatusHealthy},
+	})
+	if got := testutil.ToFloat64(healthCheckStatus.WithLabelValues("test-check", "readiness")); got != 1 {
+		t.Errorf("expected healthy check to report 1, got %v", got)
+	}
+
+	recordCheckResults("readiness", []CheckResult{
+		{Name: "test-check", Status: StatusUnhealthy},
+	})
+	if got := testutil.ToFloat64(healthCheckStatus.WithLabelValues("test-check", "readiness")); got != 0 {
+		t.Errorf("expected unhealthy check to report 0, got %v", got)
+	}
+}
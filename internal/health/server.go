@@ -2,12 +2,26 @@ package health
 
 import (
 	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/grafana/mcp-grafana/internal/compress"
 )
 
 // Server represents a health check server
@@ -15,24 +29,409 @@ type Server struct {
 	config     Config
 	httpServer *http.Server
 	mux        *http.ServeMux
+	handler    http.Handler
 	mu         sync.RWMutex
 	started    bool
+
+	readiness *checkerGroup
+	liveness  *checkerGroup
+
+	// stats, when non-nil (via WithStats), records rolling request counters
+	// for every request handled by s.handler and for every tool call
+	// recorded through RecordToolCall, surfaced at /stats and embedded in
+	// /healthz.
+	stats *StatsRecorder
+
+	// startup gates /readyz (but not /livez) until MarkStartupComplete is
+	// called, or fails it outright if that doesn't happen within
+	// config.StartupTimeout. See startup.go.
+	startup *startupChecker
+
+	// grpcServer, when non-nil (via EnableGRPCHealth), is served alongside
+	// httpServer on the same addr using cmux, so service meshes and load
+	// balancers using gRPC health checks see the same readiness/liveness
+	// state as the HTTP endpoints.
+	grpcServer *grpc.Server
 }
 
 // NewServer creates a new health check server
 func NewServer(config Config) *Server {
+	startupTimeout := config.StartupTimeout
+	if startupTimeout <= 0 {
+		startupTimeout = DefaultStartupTimeout
+	}
+
+	s := &Server{
+		config:    config,
+		readiness: newCheckerGroup(),
+		liveness:  newCheckerGroup(),
+		startup:   newStartupChecker(startupTimeout),
+	}
+
 	mux := http.NewServeMux()
 
-	// Add health check endpoints
-	mux.HandleFunc("/healthz", Handler(config))
-	mux.HandleFunc("/health", Handler(config))
-	mux.HandleFunc("/health/readiness", Handler(config))
-	mux.HandleFunc("/health/liveness", SimpleHandler())
+	// Add health check endpoints. /healthz and /health read s.config at
+	// request time (rather than binding the config passed to NewServer)
+	// so a dependency checker registered afterwards via
+	// RegisterDependencyChecker is picked up immediately.
+	mux.HandleFunc("/healthz", s.healthzHandler)
+	mux.HandleFunc("/health", s.healthzHandler)
+	mux.HandleFunc("/health/readiness", s.readinessHandler)
+	mux.HandleFunc("/health/liveness", s.livenessHandler)
+	// /readyz and /livez are Kubernetes-idiomatic aliases for the same
+	// handlers, following the etcd/kube-apiserver naming convention.
+	mux.HandleFunc("/readyz", s.readinessHandler)
+	mux.HandleFunc("/livez", s.livenessHandler)
+	// /startupz is the Kubernetes startup-probe convention: distinct from
+	// /readyz/livez so a slow-starting pod isn't killed by liveness while
+	// it's still coming up, but also isn't sent traffic by readiness.
+	mux.HandleFunc("/startupz", s.startupHandler)
+
+	s.mux = mux
+	s.handler = compress.Middleware(compress.Config{})(mux)
+	return s
+}
+
+// RegisterReadinessCheck adds a Checker that /health/readiness runs on every
+// request. Once at least one checker is registered, the endpoint stops
+// returning the hard-coded healthy Handler response and instead reports the
+// actual aggregate result of all registered checks.
+func (s *Server) RegisterReadinessCheck(c Checker) {
+	s.readiness.register(c)
+}
+
+// RegisterLivenessCheck adds a Checker that /health/liveness runs on every
+// request. Once at least one checker is registered, the endpoint stops
+// returning the hard-coded SimpleHandler response and instead reports the
+// actual aggregate result of all registered checks.
+func (s *Server) RegisterLivenessCheck(c Checker) {
+	s.liveness.register(c)
+}
+
+// RegisterReadinessCheckWithOptions adds a Checker that /health/readiness
+// runs on every request, customized with opts (a per-check timeout and/or
+// SkipOnErr so the check's failure is reported without flipping the
+// endpoint's overall status).
+func (s *Server) RegisterReadinessCheckWithOptions(c Checker, opts CheckOptions) {
+	s.readiness.registerWithOptions(c, opts)
+}
+
+// RegisterLivenessCheckWithOptions adds a Checker that /health/liveness runs
+// on every request, customized with opts.
+func (s *Server) RegisterLivenessCheckWithOptions(c Checker, opts CheckOptions) {
+	s.liveness.registerWithOptions(c, opts)
+}
+
+// RegisterReadinessCheckFunc is a convenience wrapper around
+// RegisterReadinessCheck for callers that don't want to declare a named
+// Checker type.
+func (s *Server) RegisterReadinessCheckFunc(name string, fn func(ctx context.Context) error) {
+	s.RegisterReadinessCheck(NewCheckerFunc(name, fn))
+}
+
+// RegisterLivenessCheckFunc is a convenience wrapper around
+// RegisterLivenessCheck for callers that don't want to declare a named
+// Checker type.
+func (s *Server) RegisterLivenessCheckFunc(name string, fn func(ctx context.Context) error) {
+	s.RegisterLivenessCheck(NewCheckerFunc(name, fn))
+}
+
+// RegisterDependencyChecker wires d into the server: its cached results are
+// surfaced in the /healthz "dependencies" map, and each of its probed
+// dependencies is also registered as a readiness check, so a failing
+// dependency flips /health/readiness (and /readyz) to 503 while leaving
+// /health/liveness (and /livez) unaffected. It does not call d.Start; the
+// caller is expected to start the checker's background polling loop
+// separately.
+func (s *Server) RegisterDependencyChecker(d *DependencyChecker) {
+	s.config.Dependencies = d
+	for _, c := range d.ReadinessCheckers() {
+		s.RegisterReadinessCheck(c)
+	}
+}
+
+// EnableGRPCHealth registers the standard grpc.health.v1.Health service
+// (Check and Watch), backed by the same readiness/liveness checks as
+// /readyz and /livez, to be served alongside the HTTP health endpoints so
+// service meshes and load balancers using gRPC health probes see the same
+// status. It must be called before Start/StartAsync to take effect; once
+// enabled, the health server multiplexes gRPC and HTTP traffic on its
+// single configured address via cmux.
+func (s *Server) EnableGRPCHealth() {
+	s.grpcServer = grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(s.grpcServer, newGRPCHealthServer(s.readiness, s.liveness))
+}
+
+// EnableMetrics registers a /metrics endpoint, in Prometheus text format,
+// on the same mux as /healthz. It also returns s so it can be used as the
+// tools.MetricsRecorder passed to tools.WithMetrics, wiring per-tool
+// invocation counts and durations into the same scrape.
+func (s *Server) EnableMetrics() *Server {
+	s.mux.Handle("/metrics", promhttp.Handler())
+	return s
+}
+
+// EnableStream registers a Server-Sent Events endpoint at /health/stream,
+// pushing config's Response snapshot (see StreamHandler) to subscribed
+// clients at least once every interval. It also returns s for chaining with
+// EnableMetrics/WithStats/EnableGRPCHealth.
+func (s *Server) EnableStream(interval time.Duration) *Server {
+	s.mux.HandleFunc("/health/stream", func(w http.ResponseWriter, r *http.Request) {
+		StreamHandler(s.config, interval)(w, r)
+	})
+	return s
+}
+
+// WithStats enables rolling request-count/latency-percentile tracking:
+// every request served by s's mux is recorded, a snapshot is registered on
+// s.config so /healthz embeds it under "stats", and a new /stats endpoint
+// serves the same snapshot on its own. Like EnableMetrics, it returns s so
+// it can be used as the tools.MetricsRecorder passed to tools.WithMetrics,
+// letting tool-call counts feed the same "by_name" breakdown as HTTP
+// requests. Must be called before Start/StartAsync to wrap the handler.
+func (s *Server) WithStats() *Server {
+	s.stats = NewStatsRecorder()
+	s.config.Stats = s.stats
+	s.mux.HandleFunc("/stats", statsHandler(s.config, s.stats))
+	s.handler = statsMiddleware(s.stats, s.handler)
+	return s
+}
+
+func (s *Server) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	Handler(s.config)(w, r)
+}
+
+func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeReadiness(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if complete, _ := s.startup.done(); !complete {
+		http.Error(w, "starting up", http.StatusServiceUnavailable)
+		return
+	}
+	runCheckerGroupHandler("readiness", s.readiness, Handler(s.config))(w, r)
+}
+
+// livenessHandler is intentionally unauthenticated: kubelet's HTTP probes
+// don't send credentials, and liveness failures should restart the
+// container regardless of who's asking.
+func (s *Server) livenessHandler(w http.ResponseWriter, r *http.Request) {
+	runCheckerGroupHandler("liveness", s.liveness, SimpleHandler())(w, r)
+}
+
+// authorizeReadiness reports whether r is allowed to hit /health/readiness.
+// If neither ReadinessAuthToken nor ReadinessAllowedClientCNs is configured,
+// readiness stays open (existing deployments keep their current behavior).
+// Otherwise the request is authorized by either a matching bearer token or
+// a client certificate whose Subject CN is on the allowlist.
+func (s *Server) authorizeReadiness(r *http.Request) bool {
+	cfg := s.config
+	if cfg.ReadinessAuthToken == "" && len(cfg.ReadinessAllowedClientCNs) == 0 {
+		return true
+	}
+
+	if cfg.ReadinessAuthToken != "" && bearerTokenMatches(r, cfg.ReadinessAuthToken) {
+		return true
+	}
+
+	if len(cfg.ReadinessAllowedClientCNs) > 0 && r.TLS != nil {
+		for _, cert := range r.TLS.PeerCertificates {
+			if slices.Contains(cfg.ReadinessAllowedClientCNs, cert.Subject.CommonName) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// bearerTokenMatches reports whether r carries an `Authorization: Bearer
+// <token>` header matching token, compared in constant time to avoid
+// leaking the token's length or contents through timing.
+func bearerTokenMatches(r *http.Request, token string) bool {
+	const prefix = "Bearer "
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, prefix) {
+		return false
+	}
+	supplied := strings.TrimPrefix(h, prefix)
+	return subtle.ConstantTimeCompare([]byte(supplied), []byte(token)) == 1
+}
+
+// managementTokenAuthorized reports whether r presents token, either via an
+// `Authorization: Bearer <token>` header or a `?token=` query parameter (for
+// callers, like a browser dashboard, that can't easily set a custom header),
+// compared in constant time either way.
+func managementTokenAuthorized(r *http.Request, token string) bool {
+	if bearerTokenMatches(r, token) {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(token)) == 1
+}
+
+// runCheckerGroupHandler aggregates group's checkers into an HTTP handler. If
+// group has no registered checkers yet, it falls back to fallback so
+// existing deployments keep their current behavior until they opt in.
+//
+// Two query parameters, mirroring etcd's /livez and /readyz, let callers
+// customize the response: repeating ?exclude=<name> skips those checks
+// entirely, and ?verbose=1 returns a plain-text per-check breakdown instead
+// of the JSON AggregateResponse.
+//
+// checkType labels the mcp_health_check_status gauge updated for every run
+// ("readiness" or "liveness"), so a check registered on both groups doesn't
+// clobber the other group's last result.
+func runCheckerGroupHandler(checkType string, group *checkerGroup, fallback http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if group.empty() {
+			fallback(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		status, results := group.run(r.Context(), query["exclude"]...)
+		recordCheckResults(checkType, results)
+
+		if query.Get("verbose") == "1" {
+			writeVerboseCheckResults(w, status, results)
+			return
+		}
+
+		response := AggregateResponse{
+			Status:    status,
+			Checks:    results,
+			Timestamp: time.Now().UTC(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if status != StatusHealthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			slog.Error("Failed to encode aggregate health check response", "error", err)
+		}
+	}
+}
+
+// writeVerboseCheckResults writes one "[+]name ok" or "[-]name failed: ..."
+// line per check, the same format etcd's ?verbose=1 uses, so existing
+// tooling built around etcd's health endpoints works unmodified here.
+func writeVerboseCheckResults(w http.ResponseWriter, status Status, results []CheckResult) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if status != StatusHealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	for _, result := range results {
+		switch {
+		case result.Status == StatusHealthy:
+			fmt.Fprintf(w, "[+]%s ok\n", result.Name)
+		case result.Skipped:
+			fmt.Fprintf(w, "[!]%s failed (skipped): %s\n", result.Name, result.Error)
+		default:
+			fmt.Fprintf(w, "[-]%s failed: %s\n", result.Name, result.Error)
+		}
+	}
+}
+
+// buildTLSConfig builds the *tls.Config used to serve the health server, or
+// nil if no server certificate is configured (plain HTTP). When
+// ClientCAFile is set, client certificates are verified against it if
+// presented, but are not required, since readiness also accepts a bearer
+// token.
+func (s *Server) buildTLSConfig() (*tls.Config, error) {
+	if s.config.TLSCertFile == "" || s.config.TLSKeyFile == "" {
+		return nil, nil
+	}
+
+	if s.config.ClientCAFile == "" {
+		return &tls.Config{}, nil
+	}
+
+	caCert, err := os.ReadFile(s.config.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("parsing client CA file %s", s.config.ClientCAFile)
+	}
+
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}, nil
+}
+
+// newHTTPServer builds the *http.Server used by Start/StartAsync, applying
+// TLS configuration if a server certificate is configured.
+func (s *Server) newHTTPServer(addr string) (*http.Server, error) {
+	tlsConfig, err := s.buildTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Server{
+		Addr:         addr,
+		Handler:      s.handler,
+		TLSConfig:    tlsConfig,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}, nil
+}
 
-	return &Server{
-		config: config,
-		mux:    mux,
+// serve runs httpServer, choosing ListenAndServeTLS over ListenAndServe
+// when a server certificate is configured. If a gRPC health server has
+// been registered via EnableGRPCHealth, it's served on the same address
+// instead, multiplexed with the HTTP server over a single listener.
+func (s *Server) serve(httpServer *http.Server) error {
+	if s.grpcServer != nil {
+		return s.serveMuxed(httpServer)
 	}
+	if s.config.TLSCertFile != "" && s.config.TLSKeyFile != "" {
+		return httpServer.ListenAndServeTLS(s.config.TLSCertFile, s.config.TLSKeyFile)
+	}
+	return httpServer.ListenAndServe()
+}
+
+// serveMuxed listens on httpServer.Addr once and splits incoming
+// connections between s.grpcServer and httpServer using cmux, so the gRPC
+// grpc.health.v1.Health service and the HTTP health endpoints can both be
+// served on the single address the health subsystem was configured with.
+func (s *Server) serveMuxed(httpServer *http.Server) error {
+	listener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", httpServer.Addr, err)
+	}
+	if httpServer.TLSConfig != nil {
+		listener = tls.NewListener(listener, httpServer.TLSConfig)
+	}
+
+	m := cmux.New(listener)
+	// gRPC requests are HTTP/2 with a "content-type: application/grpc"
+	// header; everything else (HTTP/1.1 and plain HTTP/2) falls through to
+	// the HTTP health endpoints.
+	grpcListener := m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+	httpListener := m.Match(cmux.Any())
+
+	errCh := make(chan error, 3)
+	go func() { errCh <- s.grpcServer.Serve(grpcListener) }()
+	go func() { errCh <- httpServer.Serve(httpListener) }()
+	go func() { errCh <- m.Serve() }()
+
+	return <-errCh
 }
 
 // Start starts the health check server on the specified address
@@ -44,18 +443,16 @@ func (s *Server) Start(addr string) error {
 		return fmt.Errorf("health server already started")
 	}
 
-	s.httpServer = &http.Server{
-		Addr:         addr,
-		Handler:      s.mux,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	httpServer, err := s.newHTTPServer(addr)
+	if err != nil {
+		return fmt.Errorf("health server failed to start: %w", err)
 	}
+	s.httpServer = httpServer
 
 	s.started = true
 	slog.Info("Starting health check server", "address", addr)
 
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := s.serve(s.httpServer); err != nil && err != http.ErrServerClosed {
 		s.started = false
 		return fmt.Errorf("health server failed to start: %w", err)
 	}
@@ -72,19 +469,17 @@ func (s *Server) StartAsync(addr string) error {
 		return fmt.Errorf("health server already started")
 	}
 
-	s.httpServer = &http.Server{
-		Addr:         addr,
-		Handler:      s.mux,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	httpServer, err := s.newHTTPServer(addr)
+	if err != nil {
+		return fmt.Errorf("health server failed to start: %w", err)
 	}
+	s.httpServer = httpServer
 
 	go func() {
 		s.started = true
 		slog.Info("Starting health check server", "address", addr)
 
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.serve(s.httpServer); err != nil && err != http.ErrServerClosed {
 			slog.Error("Health server failed", "error", err)
 			s.mu.Lock()
 			s.started = false
@@ -105,6 +500,9 @@ func (s *Server) Stop(ctx context.Context) error {
 	}
 
 	slog.Info("Stopping health check server")
+	if s.grpcServer != nil {
+		s.grpcServer.GracefulStop()
+	}
 	err := s.httpServer.Shutdown(ctx)
 	s.started = false
 	return err
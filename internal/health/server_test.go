@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -69,6 +70,8 @@ func TestServerStartStop(t *testing.T) {
 		"/health",
 		"/health/readiness",
 		"/health/liveness",
+		"/readyz",
+		"/livez",
 	}
 
 	for _, endpoint := range testEndpoints {
@@ -399,6 +402,161 @@ func TestServerConcurrentRequests(t *testing.T) {
 	}
 }
 
+func TestServerReadinessAuthToken(t *testing.T) {
+	config := Config{
+		ServiceName:        "test-service",
+		Version:            "1.0.0",
+		ReadinessAuthToken: "s3cr3t",
+	}
+
+	server := NewServer(config)
+
+	port, err := GetAvailablePort()
+	if err != nil {
+		t.Fatalf("failed to get available port: %v", err)
+	}
+	addr := fmt.Sprintf("localhost:%d", port)
+
+	if err := server.StartAsync(addr); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	baseURL := fmt.Sprintf("http://%s", addr)
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	// No token: readiness rejects, liveness stays open.
+	resp, err := client.Get(baseURL + "/health/readiness")
+	if err != nil {
+		t.Fatalf("failed to GET readiness: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", resp.StatusCode)
+	}
+
+	resp, err = client.Get(baseURL + "/health/liveness")
+	if err != nil {
+		t.Fatalf("failed to GET liveness: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected liveness to stay unauthenticated, got %d", resp.StatusCode)
+	}
+
+	// Wrong token: rejected.
+	req, _ := http.NewRequest(http.MethodGet, baseURL+"/health/readiness", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to GET readiness: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 with wrong token, got %d", resp.StatusCode)
+	}
+
+	// Correct token: allowed.
+	req, _ = http.NewRequest(http.MethodGet, baseURL+"/health/readiness", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to GET readiness: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 with correct token, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerStatsManagementToken(t *testing.T) {
+	config := Config{
+		ServiceName:     "test-service",
+		Version:         "1.0.0",
+		ManagementToken: "s3cr3t",
+	}
+
+	server := NewServer(config).WithStats()
+
+	port, err := GetAvailablePort()
+	if err != nil {
+		t.Fatalf("failed to get available port: %v", err)
+	}
+	addr := fmt.Sprintf("localhost:%d", port)
+
+	if err := server.StartAsync(addr); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}()
+
+	baseURL := fmt.Sprintf("http://%s", addr)
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	// No token: /stats rejects rather than leaking the request/latency
+	// breakdown to an unauthenticated caller.
+	resp, err := client.Get(baseURL + "/stats")
+	if err != nil {
+		t.Fatalf("failed to GET stats: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", resp.StatusCode)
+	}
+
+	// Wrong token: rejected.
+	req, _ := http.NewRequest(http.MethodGet, baseURL+"/stats", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to GET stats: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 with wrong token, got %d", resp.StatusCode)
+	}
+
+	// Correct token: allowed.
+	req, _ = http.NewRequest(http.MethodGet, baseURL+"/stats", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to GET stats: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 with correct token, got %d", resp.StatusCode)
+	}
+}
+
+func TestBearerTokenMatches(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/health/readiness", nil)
+	req.Header.Set("Authorization", "Bearer abc123")
+
+	if !bearerTokenMatches(req, "abc123") {
+		t.Error("expected matching bearer token to be accepted")
+	}
+	if bearerTokenMatches(req, "other") {
+		t.Error("expected mismatched bearer token to be rejected")
+	}
+
+	noAuth := httptest.NewRequest(http.MethodGet, "/health/readiness", nil)
+	if bearerTokenMatches(noAuth, "abc123") {
+		t.Error("expected request without Authorization header to be rejected")
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr ||
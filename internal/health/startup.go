@@ -0,0 +1,74 @@
+package health
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// startupChecker tracks the server's startup sequence as a small state
+// machine: MarkComplete flips it to done once, a timeout (started counting
+// from newStartupChecker) independently flips it to failed if MarkComplete
+// hasn't been called in time, and whichever happens first sticks.
+//
+// This is deliberately separate from readiness/liveness: a slow-starting
+// pod should not be killed by liveness (it hasn't hung, it's just not done
+// yet), but it also shouldn't be sent traffic by readiness until startup
+// finishes, regardless of what individual dependency checks say.
+type startupChecker struct {
+	complete atomic.Bool
+	timedOut atomic.Bool
+	deadline time.Time
+}
+
+// newStartupChecker returns a startupChecker that fails itself if
+// MarkComplete isn't called within timeout.
+func newStartupChecker(timeout time.Duration) *startupChecker {
+	return &startupChecker{deadline: time.Now().Add(timeout)}
+}
+
+// MarkComplete flips the checker to done. It's idempotent and safe to call
+// from any goroutine; once the timeout has already fired, it has no effect.
+func (c *startupChecker) MarkComplete() {
+	c.complete.Store(true)
+}
+
+// done reports whether startup has finished successfully, timed out, or is
+// still in progress, checking the deadline lazily so no background
+// goroutine is needed to drive the state machine.
+func (c *startupChecker) done() (complete bool, failed bool) {
+	if c.complete.Load() {
+		return true, false
+	}
+	if time.Now().After(c.deadline) {
+		c.timedOut.Store(true)
+		return false, true
+	}
+	return false, false
+}
+
+// startupHandler serves /startupz: 200 once MarkComplete has been called,
+// 503 while startup is still in progress or after it has timed out.
+func (s *Server) startupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	complete, _ := s.startup.done()
+	if !complete {
+		http.Error(w, "starting up", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// MarkStartupComplete signals that the server has finished starting up
+// (authenticated against Grafana at least once, enumerated its tool set,
+// and begun accepting connections), so /readyz can start reflecting actual
+// dependency/readiness checks instead of unconditionally returning 503.
+func (s *Server) MarkStartupComplete() {
+	s.startup.MarkComplete()
+}
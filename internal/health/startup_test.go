@@ -0,0 +1,80 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStartupChecker_DoneAfterMarkComplete(t *testing.T) {
+	c := newStartupChecker(time.Hour)
+
+	if complete, failed := c.done(); complete || failed {
+		t.Fatalf("expected startup to be neither complete nor failed yet, got complete=%v failed=%v", complete, failed)
+	}
+
+	c.MarkComplete()
+
+	if complete, failed := c.done(); !complete || failed {
+		t.Errorf("expected startup to be complete after MarkComplete, got complete=%v failed=%v", complete, failed)
+	}
+}
+
+func TestStartupChecker_FailsAfterTimeout(t *testing.T) {
+	c := newStartupChecker(10 * time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	if complete, failed := c.done(); complete || !failed {
+		t.Errorf("expected startup to be failed after its timeout elapsed, got complete=%v failed=%v", complete, failed)
+	}
+}
+
+func TestServer_StartupzReflectsStartupState(t *testing.T) {
+	s := NewServer(Config{ServiceName: "test", StartupTimeout: time.Hour})
+
+	req := httptest.NewRequest(http.MethodGet, "/startupz", nil)
+	w := httptest.NewRecorder()
+	s.startupHandler(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before startup completes, got %d", w.Code)
+	}
+
+	s.MarkStartupComplete()
+
+	w = httptest.NewRecorder()
+	s.startupHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 after MarkStartupComplete, got %d", w.Code)
+	}
+}
+
+func TestServer_ReadinessBlockedUntilStartupComplete(t *testing.T) {
+	s := NewServer(Config{ServiceName: "test", StartupTimeout: time.Hour})
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	w := httptest.NewRecorder()
+	s.readinessHandler(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected /readyz to be 503 before startup completes, got %d", w.Code)
+	}
+
+	s.MarkStartupComplete()
+
+	w = httptest.NewRecorder()
+	s.readinessHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected /readyz to be 200 after startup completes with no registered checks, got %d", w.Code)
+	}
+}
+
+func TestServer_LivenessUnaffectedByStartupState(t *testing.T) {
+	s := NewServer(Config{ServiceName: "test", StartupTimeout: time.Hour})
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	w := httptest.NewRecorder()
+	s.livenessHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected /livez to stay 200 before startup completes, got %d", w.Code)
+	}
+}
@@ -0,0 +1,200 @@
+package health
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultStatsReservoirSize bounds how many recent request latencies
+// StatsRecorder keeps for percentile calculation. Older samples are
+// overwritten in place (a ring buffer) rather than kept forever, so memory
+// use stays flat regardless of how long the process has been running.
+const defaultStatsReservoirSize = 1024
+
+// StatsRecorder tracks rolling request counters -- totals, status-code
+// class, per-name (HTTP path or MCP tool) breakdowns, bytes transferred,
+// and a bounded reservoir of recent latencies for percentile estimation --
+// so operators can debug tool-call latency regressions from a single JSON
+// poll instead of wiring in a full Prometheus stack.
+type StatsRecorder struct {
+	startTime time.Time
+
+	mu            sync.Mutex
+	total         uint64
+	byStatusClass map[string]uint64
+	byName        map[string]uint64
+	bytesIn       uint64
+	bytesOut      uint64
+	latencies     []time.Duration
+	latencyNext   int
+	latencyFull   bool
+}
+
+// NewStatsRecorder returns an empty StatsRecorder with its uptime clock
+// starting now.
+func NewStatsRecorder() *StatsRecorder {
+	return &StatsRecorder{
+		startTime:     time.Now(),
+		byStatusClass: make(map[string]uint64),
+		byName:        make(map[string]uint64),
+		latencies:     make([]time.Duration, defaultStatsReservoirSize),
+	}
+}
+
+// Record updates the rolling counters for a single request: name is an HTTP
+// path or MCP tool/method name, statusCode is the response's HTTP status (or
+// 200 for a successful non-HTTP call), and bytesIn/bytesOut are the
+// request/response body sizes.
+func (s *StatsRecorder) Record(name string, statusCode int, duration time.Duration, bytesIn, bytesOut int64) {
+	class := strconv.Itoa(statusCode/100) + "xx"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.total++
+	s.byStatusClass[class]++
+	if name != "" {
+		s.byName[name]++
+	}
+	if bytesIn > 0 {
+		s.bytesIn += uint64(bytesIn)
+	}
+	if bytesOut > 0 {
+		s.bytesOut += uint64(bytesOut)
+	}
+
+	s.latencies[s.latencyNext] = duration
+	s.latencyNext++
+	if s.latencyNext == len(s.latencies) {
+		s.latencyNext = 0
+		s.latencyFull = true
+	}
+}
+
+// StatsSnapshot is a point-in-time copy of a StatsRecorder's counters,
+// suitable for JSON encoding.
+type StatsSnapshot struct {
+	UptimeSeconds float64           `json:"uptime_seconds"`
+	TotalRequests uint64            `json:"total_requests"`
+	ByStatusClass map[string]uint64 `json:"by_status_class,omitempty"`
+	ByName        map[string]uint64 `json:"by_name,omitempty"`
+	BytesIn       uint64            `json:"bytes_in"`
+	BytesOut      uint64            `json:"bytes_out"`
+	LatencyP50Ms  float64           `json:"latency_p50_ms"`
+	LatencyP90Ms  float64           `json:"latency_p90_ms"`
+	LatencyP99Ms  float64           `json:"latency_p99_ms"`
+}
+
+// Snapshot returns the recorder's current counters and estimates latency
+// percentiles from the reservoir's current contents.
+func (s *StatsRecorder) Snapshot() StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.latencyNext
+	if s.latencyFull {
+		n = len(s.latencies)
+	}
+	samples := make([]time.Duration, n)
+	copy(samples, s.latencies[:n])
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	snapshot := StatsSnapshot{
+		UptimeSeconds: time.Since(s.startTime).Seconds(),
+		TotalRequests: s.total,
+		ByStatusClass: copyCounterMap(s.byStatusClass),
+		ByName:        copyCounterMap(s.byName),
+		BytesIn:       s.bytesIn,
+		BytesOut:      s.bytesOut,
+		LatencyP50Ms:  percentileMs(samples, 0.50),
+		LatencyP90Ms:  percentileMs(samples, 0.90),
+		LatencyP99Ms:  percentileMs(samples, 0.99),
+	}
+	return snapshot
+}
+
+func copyCounterMap(m map[string]uint64) map[string]uint64 {
+	out := make(map[string]uint64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// percentileMs returns the p-th percentile (0 < p <= 1) of sorted, in
+// milliseconds, or 0 if sorted is empty.
+func percentileMs(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return float64(sorted[idx]) / float64(time.Millisecond)
+}
+
+// statsResponseWriter wraps http.ResponseWriter to capture the status code
+// and byte count written, for statsMiddleware.
+type statsResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	bytesOut   int64
+}
+
+func (w *statsResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statsResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytesOut += int64(n)
+	return n, err
+}
+
+// statsMiddleware records every request handled by next into recorder,
+// named by the request's URL path.
+func statsMiddleware(recorder *StatsRecorder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statsResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+
+		statusCode := sw.statusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		recorder.Record(r.URL.Path, statusCode, time.Since(start), r.ContentLength, sw.bytesOut)
+	})
+}
+
+// statsHandler serves recorder's current snapshot as JSON at /stats, gated
+// by config.ManagementToken the same way Handler and StreamHandler are:
+// the per-path request/latency/byte-count breakdown is internal topology,
+// not something an unauthenticated caller should see.
+func statsHandler(config Config, recorder *StatsRecorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if config.ManagementToken != "" && !managementTokenAuthorized(r, config.ManagementToken) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(recorder.Snapshot()); err != nil {
+			slog.Error("Failed to encode stats response", "error", err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultStreamInterval is used when StreamHandler is called with interval
+// <= 0.
+const defaultStreamInterval = 10 * time.Second
+
+// streamMinPollInterval bounds how often StreamHandler re-evaluates config's
+// status between scheduled pushes, so a status transition between two
+// `interval` ticks is still noticed promptly rather than only at the next
+// scheduled push.
+const streamMinPollInterval = time.Second
+
+// StreamHandler upgrades GET requests with `Accept: text/event-stream` to a
+// Server-Sent Events stream, pushing a Response JSON payload built from
+// config's Dependencies/Stats (the same pluggable checker registry Handler
+// reports from) at least once every interval (or DefaultStreamInterval if
+// interval <= 0), and immediately on a healthy/unhealthy transition.
+// Redundant pushes are coalesced: if nothing has changed since the last push,
+// the connection stays open but idle until the next transition or the
+// interval elapses. Last-Event-ID is honored so a client resuming a dropped
+// connection continues its event ID sequence instead of restarting it. The
+// stream terminates cleanly when the client disconnects.
+func StreamHandler(config Config, interval time.Duration) http.HandlerFunc {
+	if interval <= 0 {
+		interval = defaultStreamInterval
+	}
+	pollInterval := interval / 5
+	if pollInterval < streamMinPollInterval {
+		pollInterval = streamMinPollInterval
+	}
+	if pollInterval > interval {
+		pollInterval = interval
+	}
+	ticksPerPush := int(interval / pollInterval)
+	if ticksPerPush < 1 {
+		ticksPerPush = 1
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			http.Error(w, "this endpoint requires Accept: text/event-stream", http.StatusNotAcceptable)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		eventID := parseLastEventID(r.Header.Get("Last-Event-ID"))
+		send := func(response Response) bool {
+			payload, err := json.Marshal(response)
+			if err != nil {
+				slog.Error("Failed to encode health stream event", "error", err)
+				return false
+			}
+			eventID++
+			if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", eventID, payload); err != nil {
+				return false
+			}
+			flusher.Flush()
+			return true
+		}
+		// redact mirrors Handler's ManagementToken gating (health.go), so an
+		// unauthenticated stream client sees only the status, not the
+		// dependency/stats detail buildResponse fills in.
+		redact := func(response Response) Response {
+			if config.ManagementToken != "" && !managementTokenAuthorized(r, config.ManagementToken) {
+				return Response{Status: response.Status}
+			}
+			return response
+		}
+
+		response, _ := buildResponse(config)
+		if !send(redact(response)) {
+			return
+		}
+		lastStatus := response.Status
+		ticksSinceSend := 0
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				response, _ := buildResponse(config)
+				ticksSinceSend++
+
+				transitioned := response.Status != lastStatus
+				heartbeatDue := ticksSinceSend >= ticksPerPush
+				if !transitioned && !heartbeatDue {
+					continue
+				}
+
+				if !send(redact(response)) {
+					return
+				}
+				lastStatus = response.Status
+				ticksSinceSend = 0
+			}
+		}
+	}
+}
+
+// parseLastEventID parses the Last-Event-ID request header so a resumed
+// stream's event IDs continue from where the client left off, defaulting to
+// 0 for a first connection or an unparseable header.
+func parseLastEventID(header string) int {
+	if header == "" {
+		return 0
+	}
+	id, err := strconv.Atoi(header)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// ErrWaitTimeout is returned by WaitReady when RetryTimeout elapses before
+// the target ever returns an expected status.
+var ErrWaitTimeout = errors.New("timed out waiting for health endpoint to become ready")
+
+// WaitOptions configures WaitReady.
+type WaitOptions struct {
+	// Sleep is how long to wait between polling attempts. Defaults to 1s.
+	Sleep time.Duration
+
+	// RetryTimeout bounds the total time spent polling before giving up.
+	// Defaults to 60s.
+	RetryTimeout time.Duration
+
+	// InitialDelay is waited once, before the first attempt, to give a
+	// freshly started process a head start before it's probed.
+	InitialDelay time.Duration
+
+	// ExpectStatuses lists the HTTP status codes that count as "ready".
+	// Defaults to []int{http.StatusOK}.
+	ExpectStatuses []int
+}
+
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.Sleep <= 0 {
+		o.Sleep = time.Second
+	}
+	if o.RetryTimeout <= 0 {
+		o.RetryTimeout = 60 * time.Second
+	}
+	if len(o.ExpectStatuses) == 0 {
+		o.ExpectStatuses = []int{http.StatusOK}
+	}
+	return o
+}
+
+// AttemptResult describes the outcome of a single WaitReady polling attempt.
+type AttemptResult struct {
+	Attempt int
+	Elapsed time.Duration
+	Timeout time.Duration
+	Status  int
+	Err     error
+}
+
+// WaitReady polls url on a fixed interval until it returns one of
+// opts.ExpectStatuses or opts.RetryTimeout elapses. onAttempt, if non-nil, is
+// called after every attempt so callers can print progress. Each attempt
+// uses a fresh http.Transport so stale keep-alive connections from a
+// previous (possibly now-dead) listener don't bias the probe.
+func WaitReady(ctx context.Context, url string, opts WaitOptions, onAttempt func(AttemptResult)) error {
+	opts = opts.withDefaults()
+
+	if opts.InitialDelay > 0 {
+		select {
+		case <-time.After(opts.InitialDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	deadline := time.Now().Add(opts.RetryTimeout)
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		status, err := probeOnce(ctx, url)
+
+		result := AttemptResult{
+			Attempt: attempt,
+			Elapsed: time.Since(start),
+			Timeout: opts.RetryTimeout,
+			Status:  status,
+			Err:     err,
+		}
+		if onAttempt != nil {
+			onAttempt(result)
+		}
+
+		if err == nil && slices.Contains(opts.ExpectStatuses, status) {
+			return nil
+		}
+
+		if time.Now().Add(opts.Sleep).After(deadline) {
+			return ErrWaitTimeout
+		}
+
+		select {
+		case <-time.After(opts.Sleep):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// probeOnce makes a single GET request against url using a dedicated
+// transport so no connection is reused across attempts.
+func probeOnce(ctx context.Context, url string) (int, error) {
+	client := &http.Client{
+		Transport: &http.Transport{DisableKeepAlives: true},
+		Timeout:   10 * time.Second,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
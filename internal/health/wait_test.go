@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitReady_SucceedsImmediately(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	err := WaitReady(context.Background(), srv.URL, WaitOptions{Sleep: 10 * time.Millisecond, RetryTimeout: time.Second}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestWaitReady_SucceedsAfterRetries(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var seen []AttemptResult
+	err := WaitReady(context.Background(), srv.URL, WaitOptions{
+		Sleep:        10 * time.Millisecond,
+		RetryTimeout: time.Second,
+	}, func(r AttemptResult) {
+		seen = append(seen, r)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(seen) != 3 {
+		t.Errorf("expected 3 attempts to be reported, got %d", len(seen))
+	}
+}
+
+func TestWaitReady_TimesOut(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := WaitReady(context.Background(), srv.URL, WaitOptions{
+		Sleep:        10 * time.Millisecond,
+		RetryTimeout: 50 * time.Millisecond,
+	}, nil)
+	if err != ErrWaitTimeout {
+		t.Errorf("expected ErrWaitTimeout, got %v", err)
+	}
+}
+
+func TestWaitReady_HonorsExpectStatuses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := WaitReady(context.Background(), srv.URL, WaitOptions{
+		Sleep:          10 * time.Millisecond,
+		RetryTimeout:   time.Second,
+		ExpectStatuses: []int{http.StatusNoContent},
+	}, nil)
+	if err != nil {
+		t.Fatalf("expected no error with custom ExpectStatuses, got %v", err)
+	}
+}
+
+func TestWaitReady_ReturnsNetworkErrorOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := WaitReady(ctx, "http://127.0.0.1:0", WaitOptions{
+		Sleep:        10 * time.Millisecond,
+		RetryTimeout: time.Second,
+	}, nil)
+	if err == nil {
+		t.Fatal("expected an error when context is already cancelled")
+	}
+}
+
+func TestWaitReady_InitialDelay(t *testing.T) {
+	var firstAttempt time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if firstAttempt.IsZero() {
+			firstAttempt = time.Now()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	err := WaitReady(context.Background(), srv.URL, WaitOptions{
+		Sleep:        10 * time.Millisecond,
+		RetryTimeout: time.Second,
+		InitialDelay: 50 * time.Millisecond,
+	}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if firstAttempt.Sub(start) < 50*time.Millisecond {
+		t.Errorf("expected first attempt to be delayed by at least 50ms, took %s", firstAttempt.Sub(start))
+	}
+}
@@ -0,0 +1,285 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Coordinator arbitrates which of potentially many replicas of a proxy is
+// responsible for discovery and session creation against upstream
+// datasources, so a multi-replica deployment doesn't have every replica
+// independently polling the same datasources. It's modeled on Raft-style
+// leadership: at most one replica holds leadership at a time, and an
+// orderly TransferLeadership lets a leader hand off before it shuts down
+// rather than leaving a gap until a lease expires.
+type Coordinator interface {
+	// Campaign starts competing for leadership in the background. It
+	// returns once campaigning has started, not once leadership is won;
+	// OnBecomeLeader is how callers learn they've won.
+	Campaign(ctx context.Context) error
+	// Resign gives up leadership immediately, without waiting for a peer
+	// to take over first.
+	Resign(ctx context.Context) error
+	// IsLeader reports whether this replica currently holds leadership.
+	IsLeader() bool
+	// OnBecomeLeader registers fn to run every time this replica wins
+	// leadership.
+	OnBecomeLeader(fn func())
+	// OnLoseLeadership registers fn to run every time this replica loses
+	// leadership, including as a result of TransferLeadership.
+	OnLoseLeadership(fn func())
+	// TransferLeadership gives up leadership gracefully, retrying within
+	// ctx's deadline so a rolling deploy doesn't leave discovery paused
+	// longer than necessary. With no peer to hand off to (SingleCoordinator)
+	// it behaves like Resign.
+	TransferLeadership(ctx context.Context) error
+}
+
+// singleCoordinator is the default Coordinator for a lone replica: it's
+// leader from the moment Campaign is called until Resign or
+// TransferLeadership, with no external coordination at all. This preserves
+// the proxy's original single-process behavior.
+type singleCoordinator struct {
+	mu       sync.Mutex
+	isLeader bool
+	onBecome []func()
+	onLose   []func()
+}
+
+// NewSingleCoordinator returns the zero-configuration Coordinator used
+// when a deployment has (or is known to have) only one replica.
+func NewSingleCoordinator() Coordinator {
+	return &singleCoordinator{}
+}
+
+func (c *singleCoordinator) Campaign(ctx context.Context) error {
+	c.mu.Lock()
+	already := c.isLeader
+	c.isLeader = true
+	callbacks := append([]func(){}, c.onBecome...)
+	c.mu.Unlock()
+
+	if !already {
+		for _, fn := range callbacks {
+			fn()
+		}
+	}
+	return nil
+}
+
+func (c *singleCoordinator) Resign(ctx context.Context) error {
+	c.mu.Lock()
+	was := c.isLeader
+	c.isLeader = false
+	callbacks := append([]func(){}, c.onLose...)
+	c.mu.Unlock()
+
+	if was {
+		for _, fn := range callbacks {
+			fn()
+		}
+	}
+	return nil
+}
+
+func (c *singleCoordinator) IsLeader() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.isLeader
+}
+
+func (c *singleCoordinator) OnBecomeLeader(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onBecome = append(c.onBecome, fn)
+}
+
+func (c *singleCoordinator) OnLoseLeadership(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onLose = append(c.onLose, fn)
+}
+
+func (c *singleCoordinator) TransferLeadership(ctx context.Context) error {
+	return c.Resign(ctx)
+}
+
+// LeaseStore is the minimal distributed primitive a lease-based
+// Coordinator needs: an atomic "acquire, or renew if still held by
+// holder" operation, and a release. It's intentionally small so it can be
+// implemented against Redis (SET key holder NX PX ttl, renewed with a
+// compare-and-set script), etcd (a lease bound to a key), or any other
+// store with compare-and-swap semantics, without this package depending on
+// a specific client library.
+type LeaseStore interface {
+	// TryAcquire attempts to acquire or renew key for holder with the
+	// given ttl, returning true if holder now holds the lease.
+	TryAcquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+	// Release gives up key if it's currently held by holder.
+	Release(ctx context.Context, key, holder string) error
+}
+
+// SnapshotStore is the minimal distributed primitive a follower replica
+// needs to mirror the leader's discovered tools without performing
+// discovery itself: the leader publishes its current tool snapshot after
+// every discovery pass, and any replica can fetch the most recently
+// published snapshot. Like LeaseStore, it's intentionally small so it can
+// be implemented against Redis (SET/GET), etcd (a single key), or any
+// other store with simple put/get semantics, without this package
+// depending on a specific client library.
+type SnapshotStore interface {
+	// PutSnapshot stores data under key, replacing any previous value.
+	PutSnapshot(ctx context.Context, key string, data []byte) error
+	// GetSnapshot returns the most recently stored data under key, or
+	// found=false if nothing has been published under key yet.
+	GetSnapshot(ctx context.Context, key string) (data []byte, found bool, err error)
+}
+
+// leaseCoordinator implements Coordinator by periodically acquiring (or
+// renewing) a lease in a LeaseStore shared across replicas. It's the
+// backend for both the "redis" and "etcd" deployment modes: the
+// coordination logic is identical, and only the LeaseStore implementation
+// differs by which store it talks to.
+type leaseCoordinator struct {
+	store      LeaseStore
+	key        string
+	holder     string
+	ttl        time.Duration
+	renewEvery time.Duration
+
+	mu       sync.Mutex
+	isLeader bool
+	onBecome []func()
+	onLose   []func()
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewLeaseCoordinator returns a Coordinator that competes for leadership
+// by acquiring key in store under holder's name, renewing every ttl/3 so a
+// single missed renewal doesn't immediately cost it the lease. store is
+// supplied by the caller: neither a Redis nor an etcd client is vendored
+// in this repository, so wiring the "redis" or "etcd" backend means
+// implementing LeaseStore against whichever client library the deployment
+// already uses and passing it here.
+func NewLeaseCoordinator(store LeaseStore, key, holder string, ttl time.Duration) Coordinator {
+	return &leaseCoordinator{
+		store:      store,
+		key:        key,
+		holder:     holder,
+		ttl:        ttl,
+		renewEvery: ttl / 3,
+		stop:       make(chan struct{}),
+	}
+}
+
+func (c *leaseCoordinator) Campaign(ctx context.Context) error {
+	go func() {
+		ticker := time.NewTicker(c.renewEvery)
+		defer ticker.Stop()
+
+		c.tryAcquire(ctx)
+
+		for {
+			select {
+			case <-ticker.C:
+				c.tryAcquire(ctx)
+			case <-c.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// tryAcquire attempts to acquire or renew the lease and fires
+// OnBecomeLeader/OnLoseLeadership on a transition.
+func (c *leaseCoordinator) tryAcquire(ctx context.Context) {
+	acquired, err := c.store.TryAcquire(ctx, c.key, c.holder, c.ttl)
+	if err != nil {
+		slog.Warn("lease coordinator: acquire failed", "key", c.key, "error", err)
+		acquired = false
+	}
+
+	c.mu.Lock()
+	was := c.isLeader
+	c.isLeader = acquired
+	var callbacks []func()
+	switch {
+	case acquired && !was:
+		callbacks = append([]func(){}, c.onBecome...)
+	case !acquired && was:
+		callbacks = append([]func(){}, c.onLose...)
+	}
+	c.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+}
+
+func (c *leaseCoordinator) Resign(ctx context.Context) error {
+	c.stopOnce.Do(func() { close(c.stop) })
+
+	c.mu.Lock()
+	was := c.isLeader
+	c.isLeader = false
+	callbacks := append([]func(){}, c.onLose...)
+	c.mu.Unlock()
+
+	err := c.store.Release(ctx, c.key, c.holder)
+
+	if was {
+		for _, fn := range callbacks {
+			fn()
+		}
+	}
+	return err
+}
+
+func (c *leaseCoordinator) IsLeader() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.isLeader
+}
+
+func (c *leaseCoordinator) OnBecomeLeader(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onBecome = append(c.onBecome, fn)
+}
+
+func (c *leaseCoordinator) OnLoseLeadership(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onLose = append(c.onLose, fn)
+}
+
+// TransferLeadership releases the lease so a peer can immediately acquire
+// it, retrying the release within ctx's deadline so a transient store
+// error doesn't leave this replica holding the lease well past a graceful
+// shutdown.
+func (c *leaseCoordinator) TransferLeadership(ctx context.Context) error {
+	const maxAttempts = 3
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err := c.Resign(ctx); err != nil {
+			lastErr = err
+			select {
+			case <-time.After(200 * time.Millisecond):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("transfer leadership for %s after %d attempts: %w", c.key, maxAttempts, lastErr)
+}
@@ -0,0 +1,230 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Discoverer reports the datasources of a single source of truth, e.g.
+// Grafana itself, a static file, DNS, or a service-discovery system. A
+// Registry's Discover hook is typically backed by one Discoverer, or by
+// several combined with MergeDiscoverers.
+type Discoverer interface {
+	Discover(ctx context.Context) (map[string]Datasource, error)
+}
+
+// DiscovererFunc adapts a plain discovery function to the Discoverer
+// interface, mirroring http.HandlerFunc.
+type DiscovererFunc func(ctx context.Context) (map[string]Datasource, error)
+
+// Discover implements Discoverer.
+func (f DiscovererFunc) Discover(ctx context.Context) (map[string]Datasource, error) {
+	return f(ctx)
+}
+
+// MergeDiscoverers combines multiple Discoverers into one. All are queried
+// on every call; entries are merged by UID, with later Discoverers in the
+// argument list overriding earlier ones when both report the same UID. An
+// error from one Discoverer doesn't fail the merge unless every Discoverer
+// fails, so e.g. a temporarily unreachable Consul shouldn't take down
+// datasources Grafana already knows about.
+func MergeDiscoverers(discoverers ...Discoverer) Discoverer {
+	return DiscovererFunc(func(ctx context.Context) (map[string]Datasource, error) {
+		merged := make(map[string]Datasource)
+		var errs []error
+
+		for _, d := range discoverers {
+			found, err := d.Discover(ctx)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for uid, ds := range found {
+				merged[uid] = ds
+			}
+		}
+
+		if len(merged) == 0 && len(errs) > 0 {
+			return nil, fmt.Errorf("all discovery backends failed: %w", errs[0])
+		}
+
+		return merged, nil
+	})
+}
+
+// fileDiscoverer reads datasource entries from a JSON file, each shaped
+// like Datasource, and hot-reloads them whenever the file's modification
+// time changes. It's the escape hatch for environments where Grafana isn't
+// the source of truth for which Tempo (or other) datasources exist.
+type fileDiscoverer struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	cached  map[string]Datasource
+}
+
+// NewFileDiscoverer returns a Discoverer that reads a JSON array of
+// datasource entries (the same fields as Datasource) from path. The file
+// is re-read whenever its mtime changes, so operators can edit it in place
+// without restarting the proxy.
+func NewFileDiscoverer(path string) Discoverer {
+	return &fileDiscoverer{path: path}
+}
+
+func (f *fileDiscoverer) Discover(ctx context.Context) (map[string]Datasource, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("stat discovery file %s: %w", f.path, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.cached != nil && info.ModTime().Equal(f.modTime) {
+		return f.cached, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("read discovery file %s: %w", f.path, err)
+	}
+
+	var entries []Datasource
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse discovery file %s: %w", f.path, err)
+	}
+
+	found := make(map[string]Datasource, len(entries))
+	for _, ds := range entries {
+		found[ds.UID] = ds
+	}
+
+	f.modTime = info.ModTime()
+	f.cached = found
+
+	return found, nil
+}
+
+// dnsSRVDiscoverer synthesizes Datasource entries from the targets of a DNS
+// SRV record, in the spirit of Prometheus's dns_sd_config.
+type dnsSRVDiscoverer struct {
+	service        string // e.g. "tempo"
+	proto          string // "tcp" or "udp"
+	name           string // domain to query, e.g. "example.com"
+	scheme         string // "http" or "https", used to build each target's URL
+	datasourceType string
+}
+
+// NewDNSSRVDiscoverer returns a Discoverer that looks up the
+// _service._proto.name SRV record and synthesizes one Datasource per
+// target, addressed as scheme://target:port. Each entry's UID is the
+// target:port pair, so repeated lookups produce stable identities as long
+// as the underlying records don't change.
+func NewDNSSRVDiscoverer(service, proto, name, scheme, datasourceType string) Discoverer {
+	return &dnsSRVDiscoverer{service: service, proto: proto, name: name, scheme: scheme, datasourceType: datasourceType}
+}
+
+func (d *dnsSRVDiscoverer) Discover(ctx context.Context) (map[string]Datasource, error) {
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, d.service, d.proto, d.name)
+	if err != nil {
+		return nil, fmt.Errorf("lookup SRV _%s._%s.%s: %w", d.service, d.proto, d.name, err)
+	}
+
+	found := make(map[string]Datasource, len(records))
+	for _, record := range records {
+		target := strings.TrimSuffix(record.Target, ".")
+		uid := fmt.Sprintf("%s:%d", target, record.Port)
+		found[uid] = Datasource{
+			UID:  uid,
+			Name: target,
+			URL:  fmt.Sprintf("%s://%s:%d", d.scheme, target, record.Port),
+			Type: d.datasourceType,
+		}
+	}
+
+	return found, nil
+}
+
+// consulCatalogEntry is the subset of a Consul catalog service entry this
+// package needs. See https://developer.hashicorp.com/consul/api-docs/catalog.
+type consulCatalogEntry struct {
+	ServiceID      string `json:"ServiceID"`
+	ServiceAddress string `json:"ServiceAddress"`
+	ServicePort    int    `json:"ServicePort"`
+	Node           string `json:"Node"`
+}
+
+// consulDiscoverer queries a Consul catalog service endpoint directly over
+// HTTP, avoiding a dependency on the full Consul API client for what's a
+// single read-only call.
+type consulDiscoverer struct {
+	addr           string // e.g. "http://consul:8500"
+	service        string
+	tag            string
+	scheme         string
+	datasourceType string
+	client         *http.Client
+}
+
+// NewConsulDiscoverer returns a Discoverer that queries addr's catalog for
+// service, optionally filtered by tag, and synthesizes one Datasource per
+// healthy-looking catalog entry. client defaults to http.DefaultClient if
+// nil.
+func NewConsulDiscoverer(addr, service, tag, scheme, datasourceType string, client *http.Client) Discoverer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &consulDiscoverer{addr: strings.TrimSuffix(addr, "/"), service: service, tag: tag, scheme: scheme, datasourceType: datasourceType, client: client}
+}
+
+func (c *consulDiscoverer) Discover(ctx context.Context) (map[string]Datasource, error) {
+	url := fmt.Sprintf("%s/v1/catalog/service/%s", c.addr, c.service)
+	if c.tag != "" {
+		url += "?tag=" + c.tag
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build consul catalog request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("query consul catalog for service %s: %w", c.service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul catalog for service %s: unexpected status %s", c.service, resp.Status)
+	}
+
+	var entries []consulCatalogEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode consul catalog response for service %s: %w", c.service, err)
+	}
+
+	found := make(map[string]Datasource, len(entries))
+	for _, entry := range entries {
+		uid := entry.ServiceID
+		if uid == "" {
+			uid = fmt.Sprintf("%s-%s-%d", c.service, entry.Node, entry.ServicePort)
+		}
+		found[uid] = Datasource{
+			UID:  uid,
+			Name: entry.Node,
+			URL:  fmt.Sprintf("%s://%s:%s", c.scheme, entry.ServiceAddress, strconv.Itoa(entry.ServicePort)),
+			Type: c.datasourceType,
+		}
+	}
+
+	return found, nil
+}
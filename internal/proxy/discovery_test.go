@@ -0,0 +1,84 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDiscoverers_LaterOverridesEarlier(t *testing.T) {
+	a := DiscovererFunc(func(ctx context.Context) (map[string]Datasource, error) {
+		return map[string]Datasource{
+			"ds1": {UID: "ds1", Name: "from-a"},
+			"ds2": {UID: "ds2", Name: "only-in-a"},
+		}, nil
+	})
+	b := DiscovererFunc(func(ctx context.Context) (map[string]Datasource, error) {
+		return map[string]Datasource{
+			"ds1": {UID: "ds1", Name: "from-b"},
+		}, nil
+	})
+
+	merged, err := MergeDiscoverers(a, b).Discover(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "from-b", merged["ds1"].Name, "later discoverer should win on UID conflicts")
+	assert.Equal(t, "only-in-a", merged["ds2"].Name)
+}
+
+func TestMergeDiscoverers_PartialFailureStillReturnsOthers(t *testing.T) {
+	ok := DiscovererFunc(func(ctx context.Context) (map[string]Datasource, error) {
+		return map[string]Datasource{"ds1": {UID: "ds1"}}, nil
+	})
+	failing := DiscovererFunc(func(ctx context.Context) (map[string]Datasource, error) {
+		return nil, assert.AnError
+	})
+
+	merged, err := MergeDiscoverers(ok, failing).Discover(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, merged, "ds1")
+}
+
+func TestMergeDiscoverers_AllFail(t *testing.T) {
+	failing := DiscovererFunc(func(ctx context.Context) (map[string]Datasource, error) {
+		return nil, assert.AnError
+	})
+
+	_, err := MergeDiscoverers(failing, failing).Discover(context.Background())
+	require.Error(t, err)
+}
+
+func TestFileDiscoverer_ReadsAndHotReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "datasources.json")
+	entries := []Datasource{{UID: "ds1", Name: "Tempo 1", Type: "tempo"}}
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	d := NewFileDiscoverer(path)
+
+	found, err := d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, found, "ds1")
+	assert.Equal(t, "Tempo 1", found["ds1"].Name)
+
+	entries[0].Name = "Tempo 1 Renamed"
+	data, err = json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	found, err = d.Discover(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Tempo 1 Renamed", found["ds1"].Name)
+}
+
+func TestFileDiscoverer_MissingFile(t *testing.T) {
+	d := NewFileDiscoverer(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	_, err := d.Discover(context.Background())
+	require.Error(t, err)
+}
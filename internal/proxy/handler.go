@@ -0,0 +1,179 @@
+package proxy
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultPollingInterval is used when a HandlerConfig doesn't supply
+// PollingInterval.
+const DefaultPollingInterval = 5 * time.Minute
+
+// HandlerConfig configures a Handler.
+type HandlerConfig[P Params] struct {
+	// Hooks wires the Handler's Registry to the backend.
+	Hooks Hooks[P]
+	// Enabled reports whether the proxy should run at all, e.g. reading
+	// an env var or the process-wide ProxyConfig. A nil Enabled always
+	// runs.
+	Enabled func(ctx context.Context) bool
+	// PollingInterval returns the discovery polling interval. A nil
+	// PollingInterval (or one returning zero) uses DefaultPollingInterval.
+	PollingInterval func(ctx context.Context) time.Duration
+	// Coordinator arbitrates which replica of a multi-replica deployment
+	// performs discovery and session creation. A nil Coordinator (the
+	// default) means this replica is always responsible, matching
+	// single-replica behavior.
+	Coordinator Coordinator
+	// SnapshotStore, if set alongside Coordinator, lets non-leader
+	// replicas mirror the leader's registered tools instead of serving
+	// none for this datasource type until they win leadership: the
+	// leader publishes its tool snapshot after every discovery pass, and
+	// every other replica polls and applies the latest one at
+	// PollingInterval. A nil SnapshotStore leaves non-leader replicas
+	// registering no tools for this datasource type until they win
+	// leadership, same as before this field existed.
+	SnapshotStore SnapshotStore
+}
+
+// snapshotKeyFor returns the SnapshotStore key a datasource type's tool
+// snapshot is published and fetched under.
+func snapshotKeyFor(datasourceType string) string {
+	return "mcp-grafana:proxy-tools:" + datasourceType
+}
+
+// Handler adapts a Registry to the tools.ProxyHandler contract
+// (Initialize(ctx, *server.MCPServer) / Shutdown()) expected by
+// tools.RegisterProxyHandler, without this package needing to depend on
+// the tools package.
+type Handler[P Params] struct {
+	config   HandlerConfig[P]
+	registry *Registry[P]
+}
+
+// NewHandler constructs a Handler and its backing Registry. The registry is
+// usable immediately (e.g. BuildHandler, for tests that build dynamic tool
+// handlers without a running server); discovery and MCP registration only
+// start once Initialize is called.
+func NewHandler[P Params](config HandlerConfig[P]) *Handler[P] {
+	return &Handler[P]{
+		config:   config,
+		registry: NewRegistry(config.Hooks),
+	}
+}
+
+// Initialize discovers the backend's datasources, registers their tools,
+// and starts periodic re-discovery. If a Coordinator is configured,
+// discovery and polling only run while this replica holds leadership; a
+// SnapshotStore configured alongside it lets this replica mirror the
+// leader's tools via follower sync the rest of the time instead of
+// registering none.
+func (h *Handler[P]) Initialize(ctx context.Context, mcpServer *server.MCPServer) {
+	if h.config.Enabled != nil && !h.config.Enabled(ctx) {
+		slog.Info("proxy disabled", "datasource_type", h.config.Hooks.DatasourceType)
+		return
+	}
+
+	h.registry.mcp = mcpServer
+
+	grafanaConfig := mcpgrafana.GrafanaConfigFromContext(ctx)
+	if grafanaConfig.URL == "" {
+		slog.Info("GRAFANA_URL not set - skipping tool discovery", "datasource_type", h.config.Hooks.DatasourceType)
+		return
+	}
+
+	interval := DefaultPollingInterval
+	if h.config.PollingInterval != nil {
+		if configured := h.config.PollingInterval(ctx); configured > 0 {
+			interval = configured
+		}
+	}
+
+	if h.config.Coordinator != nil {
+		h.registry.snapshotStore = h.config.SnapshotStore
+		h.registry.snapshotKey = snapshotKeyFor(h.config.Hooks.DatasourceType)
+
+		h.config.Coordinator.OnBecomeLeader(func() {
+			slog.Info("won leadership; starting discovery", "datasource_type", h.config.Hooks.DatasourceType)
+			h.registry.stopFollowerSyncing()
+			if err := h.registry.discoverAndUpdateTools(ctx); err != nil {
+				slog.Error("error discovering tools", "datasource_type", h.config.Hooks.DatasourceType, "error", err)
+			}
+			h.registry.startPolling(ctx, interval)
+		})
+		h.config.Coordinator.OnLoseLeadership(func() {
+			slog.Info("lost leadership; stopping discovery", "datasource_type", h.config.Hooks.DatasourceType)
+			h.registry.stopPolling()
+			h.registry.startFollowerSync(ctx, interval)
+		})
+		// Start following immediately too: a replica that never wins
+		// leadership (or hasn't yet) would otherwise register no tools
+		// for this datasource type for as long as it isn't leader.
+		h.registry.startFollowerSync(ctx, interval)
+		if err := h.config.Coordinator.Campaign(ctx); err != nil {
+			slog.Error("error starting leadership campaign", "datasource_type", h.config.Hooks.DatasourceType, "error", err)
+		}
+		return
+	}
+
+	if err := h.registry.discoverAndUpdateTools(ctx); err != nil {
+		slog.Error("error discovering tools", "datasource_type", h.config.Hooks.DatasourceType, "error", err)
+	}
+	h.registry.startPolling(ctx, interval)
+
+	slog.Info("proxy initialized", "datasource_type", h.config.Hooks.DatasourceType, "polling_interval", interval)
+}
+
+// Shutdown gives up leadership (if coordinated) with retries so a peer can
+// take over before this replica stops polling, then stops polling and
+// unregisters every tool this handler owns.
+func (h *Handler[P]) Shutdown() {
+	if h.config.Coordinator != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := h.config.Coordinator.TransferLeadership(ctx); err != nil {
+			slog.Warn("leadership transfer failed during shutdown", "datasource_type", h.config.Hooks.DatasourceType, "error", err)
+		}
+	}
+	h.registry.shutdown()
+}
+
+// OnDatasourceAdded implements tools.ProxyHandler: it invalidates
+// datasourceUID's checked-state, if it's already tracked from an earlier
+// failed attempt, and triggers an immediate discovery pass so the Handler
+// doesn't wait out its own next poll to pick up a datasource the caller's
+// reconciler just saw appear.
+func (h *Handler[P]) OnDatasourceAdded(ctx context.Context, datasourceUID string, datasourceID int64) {
+	h.registry.invalidate(datasourceUID)
+	if err := h.registry.discoverAndUpdateTools(ctx); err != nil {
+		slog.Error("error discovering tools after datasource added", "datasource_type", h.config.Hooks.DatasourceType, "datasource_uid", datasourceUID, "error", err)
+	}
+}
+
+// OnDatasourceRemoved implements tools.ProxyHandler. A fresh discovery
+// pass reflects the backend's current datasource list, which by now no
+// longer includes datasourceUID, so it's cleanupRemovedDatasources that
+// actually unregisters whatever tools it solely backed.
+func (h *Handler[P]) OnDatasourceRemoved(ctx context.Context, datasourceUID string) {
+	if err := h.registry.discoverAndUpdateTools(ctx); err != nil {
+		slog.Error("error discovering tools after datasource removed", "datasource_type", h.config.Hooks.DatasourceType, "datasource_uid", datasourceUID, "error", err)
+	}
+}
+
+// BuildHandler builds the MCP handler for toolName, restricted to
+// allowedDatasources, using the handler's registry. It works whether or
+// not Initialize has run, so adapters can build handlers (e.g. for tests)
+// without a live MCP server.
+func (h *Handler[P]) BuildHandler(toolName string, allowedDatasources []string) func(context.Context, P) (string, error) {
+	return h.registry.buildHandler(toolName, allowedDatasources)
+}
+
+// HealthSnapshot returns the health state of every datasource the
+// handler's registry has tracked, for metrics and testing.
+func (h *Handler[P]) HealthSnapshot() map[string]DatasourceHealthState {
+	return h.registry.HealthSnapshot()
+}
@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// healthDownThreshold is the number of consecutive failures after
+	// which a datasource is considered "down" rather than merely
+	// "degraded".
+	healthDownThreshold = 3
+
+	// healthBackoffCap bounds how long a failing datasource waits before
+	// the next health check, regardless of how many times it has failed.
+	healthBackoffCap = 5 * time.Minute
+)
+
+// DatasourceHealthState describes the current standing of a datasource in a
+// Registry's health tracker.
+type DatasourceHealthState string
+
+const (
+	// DatasourceHealthUp means the last discovery attempt against this
+	// datasource succeeded; it's checked on the normal polling cadence.
+	DatasourceHealthUp DatasourceHealthState = "up"
+	// DatasourceHealthDegraded means recent discovery attempts have
+	// failed, but not enough in a row to take the datasource fully
+	// offline yet.
+	DatasourceHealthDegraded DatasourceHealthState = "degraded"
+	// DatasourceHealthDown means the datasource has failed enough
+	// consecutive checks that its tools are treated as unavailable until
+	// it recovers. Its tools stay registered so they reappear instantly
+	// once discovery succeeds again.
+	DatasourceHealthDown DatasourceHealthState = "down"
+)
+
+// datasourceHealth tracks the health of a single datasource across
+// discovery polls, similar in spirit to a cluster member's health state: a
+// successful check resets it to "up", a failed check moves it towards
+// "down" with jittered exponential backoff before the next check.
+type datasourceHealth struct {
+	state               DatasourceHealthState
+	consecutiveFailures int
+	nextCheck           time.Time
+	lastError           error
+}
+
+// markHealthy resets uid's health state to "up" and clears any backoff, so
+// it's eligible for a check on the next poll.
+func (r *Registry[P]) markHealthy(uid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.health[uid] = &datasourceHealth{state: DatasourceHealthUp}
+}
+
+// markUnhealthy records a failed check for uid, advancing it towards "down"
+// and scheduling the next check with jittered exponential backoff.
+func (r *Registry[P]) markUnhealthy(uid string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, exists := r.health[uid]
+	if !exists {
+		h = &datasourceHealth{}
+		r.health[uid] = h
+	}
+
+	h.consecutiveFailures++
+	h.lastError = err
+	if h.consecutiveFailures >= healthDownThreshold {
+		h.state = DatasourceHealthDown
+	} else {
+		h.state = DatasourceHealthDegraded
+	}
+	h.nextCheck = time.Now().Add(backoffWithJitter(h.consecutiveFailures))
+}
+
+// backoffWithJitter computes a jittered exponential backoff (base 2x,
+// capped at healthBackoffCap) for the given number of consecutive failures.
+func backoffWithJitter(consecutiveFailures int) time.Duration {
+	backoff := time.Duration(float64(time.Second) * math.Pow(2, float64(consecutiveFailures)))
+	if backoff <= 0 || backoff > healthBackoffCap {
+		backoff = healthBackoffCap
+	}
+	// Jitter within the second half of the window, so retries spread out
+	// instead of all datasources recovering in lockstep.
+	half := backoff / 2
+	jitter := time.Duration(0)
+	if half > 0 {
+		jitter = time.Duration(rand.Int63n(int64(half)))
+	}
+	return half + jitter
+}
+
+// healthState returns the current health state for uid, defaulting to
+// DatasourceHealthUp for datasources that haven't failed a check yet.
+func (r *Registry[P]) healthState(uid string) DatasourceHealthState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, exists := r.health[uid]
+	if !exists {
+		return DatasourceHealthUp
+	}
+	return h.state
+}
+
+// dueForCheck reports whether uid's backoff window (if any) has elapsed.
+func (r *Registry[P]) dueForCheck(uid string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, exists := r.health[uid]
+	if !exists {
+		return true
+	}
+	return !time.Now().Before(h.nextCheck)
+}
+
+// HealthSnapshot returns a point-in-time copy of the health state of every
+// datasource the registry has tracked, for metrics and testing.
+func (r *Registry[P]) HealthSnapshot() map[string]DatasourceHealthState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snapshot := make(map[string]DatasourceHealthState, len(r.health))
+	for uid, h := range r.health {
+		snapshot[uid] = h.state
+	}
+	return snapshot
+}
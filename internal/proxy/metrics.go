@@ -0,0 +1,87 @@
+package proxy
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The metrics below are registered against the default Prometheus
+// registry, the same one internal/health.Server.EnableMetrics exposes at
+// /metrics, so they show up on that scrape without this package needing
+// to depend on internal/health.
+var (
+	// proxyRequestsTotal counts every JSON-RPC call callMCP makes to a
+	// proxied datasource, labeled by outcome so operators can alert on a
+	// datasource type or individual UID whose error rate is climbing.
+	proxyRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_proxy_requests_total",
+		Help: "Total number of JSON-RPC calls made through the datasource proxy, labeled by datasource type, UID, method, and outcome status.",
+	}, []string{"datasource_type", "uid", "method", "status"})
+
+	// proxyRequestDurationSeconds tracks how long a proxied JSON-RPC call
+	// takes end to end, including any retries callMCP performed for it.
+	proxyRequestDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mcp_proxy_request_duration_seconds",
+		Help:    "Duration of JSON-RPC calls made through the datasource proxy in seconds, labeled by datasource type and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"datasource_type", "method"})
+
+	// proxySessionsActive tracks how many datasource sessions are
+	// currently held open by the process's SessionManager.
+	proxySessionsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mcp_proxy_sessions_active",
+		Help: "Number of proxied datasource sessions currently tracked by the session manager.",
+	})
+
+	// proxyDiscoveryErrorsTotal counts failed discovery passes, labeled by
+	// datasource type, so a backend whose Grafana API calls are failing
+	// shows up distinctly from one that simply has no datasources.
+	proxyDiscoveryErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_proxy_discovery_errors_total",
+		Help: "Total number of failed datasource discovery passes, labeled by datasource type.",
+	}, []string{"datasource_type"})
+)
+
+// RecordRequest updates mcp_proxy_requests_total and
+// mcp_proxy_request_duration_seconds for a single callMCP invocation
+// (covering every retry attempt it made).
+func RecordRequest(datasourceType, uid, method string, duration time.Duration, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	proxyRequestsTotal.WithLabelValues(datasourceType, uid, method, status).Inc()
+	proxyRequestDurationSeconds.WithLabelValues(datasourceType, method).Observe(duration.Seconds())
+}
+
+// IncActiveSessions increments mcp_proxy_sessions_active, e.g. when the
+// SessionManager creates a session for a datasource it hasn't seen before.
+func IncActiveSessions() {
+	proxySessionsActive.Inc()
+}
+
+// DecActiveSessions decrements mcp_proxy_sessions_active, e.g. when the
+// SessionManager evicts a stale or removed-datasource session.
+func DecActiveSessions() {
+	proxySessionsActive.Dec()
+}
+
+// RecordDiscoveryError increments mcp_proxy_discovery_errors_total for a
+// single failed discovery pass against datasourceType.
+func RecordDiscoveryError(datasourceType string) {
+	proxyDiscoveryErrorsTotal.WithLabelValues(datasourceType).Inc()
+}
+
+// MetricsHandler returns an http.Handler serving this process's
+// Prometheus metrics (including the ones above) in text exposition
+// format. A caller already running internal/health.Server with
+// EnableMetrics gets these for free at its /metrics endpoint, since both
+// packages register against the default registry; this is for a caller
+// that embeds the proxy subsystem without the rest of the health server.
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// RecordEnvVar is the environment variable a backend checks to decide
+// whether session recording is enabled, and where to write the segment
+// file. It's read by the tools package, not by this package directly,
+// since NewRecorder takes the path explicitly.
+const RecordEnvVar = "TEMPO_PROXY_RECORD_FILE"
+
+// Record captures a single callMCP invocation: enough to replay it
+// deterministically later via Replayer, and enough to triage a captured
+// session without a live backend. One Record is written per discovery
+// ("tools/list") or tool-call ("tools/call") round trip.
+type Record struct {
+	Timestamp     time.Time       `json:"timestamp"`
+	DatasourceUID string          `json:"datasource_uid"`
+	Method        string          `json:"method"`
+	Request       json.RawMessage `json:"request"`
+	Response      json.RawMessage `json:"response,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	// ResponseHash is the sha256 hex digest of Response, so a recording can
+	// be eyeballed or diffed without printing the full (potentially large)
+	// body. Empty when the call errored instead of responding.
+	ResponseHash string `json:"response_hash,omitempty"`
+}
+
+// Short returns the first 8 hex characters of ResponseHash, for compact
+// display (e.g. in `describe` output), matching the short-hash convention
+// used elsewhere in this codebase.
+func (r Record) Short() string {
+	if len(r.ResponseHash) > 8 {
+		return r.ResponseHash[:8]
+	}
+	return r.ResponseHash
+}
+
+// Recorder writes a sequence of Records to a segment file as
+// newline-delimited JSON, one record per callMCP invocation. It's safe
+// for concurrent use: performParallelDiscovery calls its backend's
+// EnsureSession (and so callMCP) from multiple goroutines at once.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewRecorder opens (creating or appending to) the segment file at path
+// and returns a Recorder that writes to it.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open recording segment %s: %w", path, err)
+	}
+	return &Recorder{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Record appends a Record for one callMCP(uid, method, request) round
+// trip. Pass callErr (non-nil) instead of a response when the call
+// itself failed; request and response are marshaled as-is, so callers
+// typically pass the same params/result values callMCP already handles.
+func (r *Recorder) Record(uid, method string, request, response interface{}, callErr error) error {
+	reqJSON, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshal recorded request: %w", err)
+	}
+
+	rec := Record{
+		Timestamp:     time.Now(),
+		DatasourceUID: uid,
+		Method:        method,
+		Request:       reqJSON,
+	}
+
+	if callErr != nil {
+		rec.Error = callErr.Error()
+	} else {
+		respJSON, err := json.Marshal(response)
+		if err != nil {
+			return fmt.Errorf("marshal recorded response: %w", err)
+		}
+		sum := sha256.Sum256(respJSON)
+		rec.Response = respJSON
+		rec.ResponseHash = fmt.Sprintf("%x", sum)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(rec)
+}
+
+// Close closes the underlying segment file.
+func (r *Recorder) Close() error {
+	return r.file.Close()
+}
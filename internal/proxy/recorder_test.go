@@ -0,0 +1,41 @@
+package proxy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_WritesReadableRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	rec, err := NewRecorder(path)
+	require.NoError(t, err)
+
+	require.NoError(t, rec.Record("ds1", "tools/list", nil, map[string]string{"ok": "true"}, nil))
+	require.NoError(t, rec.Record("ds1", "tools/call", map[string]string{"name": "trace-search"}, nil, assert.AnError))
+	require.NoError(t, rec.Close())
+
+	records, err := readRecords(path)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "ds1", records[0].DatasourceUID)
+	assert.Equal(t, "tools/list", records[0].Method)
+	assert.Empty(t, records[0].Error)
+	assert.NotEmpty(t, records[0].ResponseHash)
+
+	assert.Equal(t, "tools/call", records[1].Method)
+	assert.Equal(t, assert.AnError.Error(), records[1].Error)
+	assert.Empty(t, records[1].ResponseHash)
+}
+
+func TestRecord_Short(t *testing.T) {
+	rec := Record{ResponseHash: "abcdef0123456789"}
+	assert.Equal(t, "abcdef01", rec.Short())
+
+	short := Record{ResponseHash: "abcd"}
+	assert.Equal(t, "abcd", short.Short())
+}
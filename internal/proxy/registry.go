@@ -0,0 +1,881 @@
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// skipRecentCheckThreshold is how recently a datasource must have been
+	// checked for a tool it provides to be skipped on this poll.
+	skipRecentCheckThreshold = 4 * time.Minute
+
+	// watchDebounceWindow coalesces a burst of Watcher events (e.g. several
+	// datasources changing together) into a single discoverAndUpdateTools
+	// call rather than one per event.
+	watchDebounceWindow = 2 * time.Second
+)
+
+// Registry manages the discovery, deduplication, and MCP registration
+// lifecycle of the dynamic tools proxied from datasources of a single type.
+// It's generic over P, the backend's typed dynamic tool params struct.
+type Registry[P Params] struct {
+	hooks Hooks[P]
+
+	mu                sync.RWMutex
+	registeredTools   map[string]*registeredTool   // tool name -> registration info
+	datasourceTools   map[string][]string          // datasource UID -> tool names
+	toolToDatasources map[string][]string          // tool name -> datasource UIDs that provide it
+	health            map[string]*datasourceHealth // datasource UID -> health tracking state
+	mcp               *server.MCPServer
+	stopPoller        chan struct{}
+	pollerRunning     bool
+
+	// snapshotStore and snapshotKey, if set, let this registry publish
+	// its tools (when it's the leader of a coordinated deployment) and/or
+	// sync from a peer's published tools (when it isn't). Both are set by
+	// Handler.Initialize; a nil snapshotStore makes publishSnapshot and
+	// startFollowerSync no-ops.
+	snapshotStore       SnapshotStore
+	snapshotKey         string
+	stopFollowerSync    chan struct{}
+	followerSyncRunning bool
+}
+
+// NewRegistry constructs a Registry driven by hooks. The MCP server used to
+// register tools is set separately (via Handler.Initialize) so a Registry
+// can be built, and its handlers exercised, before a server is available.
+func NewRegistry[P Params](hooks Hooks[P]) *Registry[P] {
+	return &Registry[P]{
+		hooks:             hooks,
+		registeredTools:   make(map[string]*registeredTool),
+		datasourceTools:   make(map[string][]string),
+		toolToDatasources: make(map[string][]string),
+		health:            make(map[string]*datasourceHealth),
+		stopPoller:        make(chan struct{}),
+	}
+}
+
+// discoveryResult holds the result of discovering tools from a datasource.
+type discoveryResult struct {
+	uid   string
+	ds    Datasource
+	tools []mcp.Tool
+	err   error
+}
+
+// toolDiscovery represents a tool discovered from a specific datasource.
+type toolDiscovery struct {
+	tool           mcp.Tool
+	datasourceUID  string
+	datasourceName string
+}
+
+// registeredTool tracks information about a registered tool.
+type registeredTool struct {
+	name         string // the registered name
+	originalName string // the original tool name from the backend
+	description  string
+	schemaHash   string   // hash of the tool schema for deduplication
+	datasources  []string // UIDs of datasources that provide this tool
+	handler      interface{}
+	lastChecked  map[string]time.Time // datasource UID -> last successful check time
+}
+
+// computeToolHash generates a hash of the tool's schema for comparison.
+func computeToolHash(tool mcp.Tool) string {
+	normalized := map[string]interface{}{
+		"description": tool.Description,
+		"inputSchema": tool.InputSchema,
+	}
+	data, _ := json.Marshal(normalized)
+	hash := sha256.Sum256(data)
+	return fmt.Sprintf("%x", hash)
+}
+
+// buildHandler builds the MCP handler for toolName, restricted to
+// allowedDatasources. The handler validates the requested datasource_uid
+// before delegating to callTool, and is wrapped with the backend's
+// middleware chain (panic recovery, at minimum) so a misbehaving dynamic
+// handler can't take down the MCP server goroutine.
+func (r *Registry[P]) buildHandler(toolName string, allowedDatasources []string) func(context.Context, P) (string, error) {
+	core := func(ctx context.Context, args map[string]interface{}) (string, error) {
+		datasourceUID, _ := args["datasource_uid"].(string)
+		if datasourceUID == "" {
+			return "", fmt.Errorf("datasource_uid is required")
+		}
+
+		allowed := false
+		for _, uid := range allowedDatasources {
+			if uid == datasourceUID {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "", fmt.Errorf("datasource %s does not provide tool %s", datasourceUID, toolName)
+		}
+
+		if r.healthState(datasourceUID) == DatasourceHealthDown {
+			return "", fmt.Errorf("datasource %s is currently unavailable (marked down after repeated discovery failures)", datasourceUID)
+		}
+
+		arguments, _ := args["arguments"].(map[string]interface{})
+		if arguments == nil {
+			arguments = make(map[string]interface{})
+		}
+
+		return r.callTool(ctx, toolName, datasourceUID, arguments)
+	}
+
+	wrapped := r.hooks.Middleware(toolName)(core)
+
+	return func(ctx context.Context, params P) (string, error) {
+		return wrapped(ctx, map[string]interface{}{
+			"datasource_uid": params.GetDatasourceUID(),
+			"arguments":      params.GetArguments(),
+		})
+	}
+}
+
+// callTool ensures datasourceUID's session is ready and forwards the call
+// to its original (pre-namespacing) tool name via hooks.Call.
+func (r *Registry[P]) callTool(ctx context.Context, toolName, datasourceUID string, arguments map[string]interface{}) (string, error) {
+	// Mark this datasource as used so it's re-checked on the next poll,
+	// rather than waiting out the rest of the skip-recent-check window.
+	r.mu.Lock()
+	if tool, exists := r.registeredTools[toolName]; exists && tool.lastChecked != nil {
+		tool.lastChecked[datasourceUID] = time.Time{}
+	}
+	r.mu.Unlock()
+
+	if err := r.hooks.EnsureSession(ctx, datasourceUID); err != nil {
+		return "", fmt.Errorf("failed to ensure %s session: %w", r.hooks.DatasourceType, err)
+	}
+
+	r.mu.RLock()
+	tool, exists := r.registeredTools[toolName]
+	r.mu.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("tool %s not found in registry", toolName)
+	}
+
+	response, err := r.hooks.Call(ctx, datasourceUID, tool.originalName, arguments)
+	if err != nil {
+		return "", fmt.Errorf("failed to call %s tool %s: %w", r.hooks.DatasourceType, tool.originalName, err)
+	}
+
+	if r.hooks.WrapResponse != nil {
+		return r.hooks.WrapResponse(tool.originalName, datasourceUID, response), nil
+	}
+	return response, nil
+}
+
+// startPolling starts the background discovery polling goroutine. When
+// hooks.Watcher is set, it also starts a watch goroutine that triggers
+// discovery immediately (debounced) on reported changes; the ticker then
+// serves as a safety-net reconciliation pass rather than the sole trigger.
+func (r *Registry[P]) startPolling(ctx context.Context, interval time.Duration) {
+	r.mu.Lock()
+	if r.pollerRunning {
+		r.mu.Unlock()
+		return
+	}
+	r.pollerRunning = true
+	r.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.discoverAndUpdateTools(ctx); err != nil {
+					slog.Error("error during periodic tool discovery", "datasource_type", r.hooks.DatasourceType, "error", err)
+				}
+			case <-r.stopPoller:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	r.startWatching(ctx)
+}
+
+// startWatching consumes hooks.Watcher's event stream, if any, and
+// triggers a debounced discoverAndUpdateTools call when events arrive. A
+// nil Watcher is a no-op: discovery stays purely ticker-driven.
+func (r *Registry[P]) startWatching(ctx context.Context) {
+	if r.hooks.Watcher == nil {
+		return
+	}
+
+	events, err := r.hooks.Watcher.Watch(ctx)
+	if err != nil {
+		slog.Warn("failed to start watcher; falling back to ticker-only polling", "datasource_type", r.hooks.DatasourceType, "error", err)
+		return
+	}
+
+	go func() {
+		var debounce *time.Timer
+		var debounceC <-chan time.Time
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				r.invalidate(event.DatasourceUID)
+				if debounce == nil {
+					debounce = time.NewTimer(watchDebounceWindow)
+					debounceC = debounce.C
+				} else {
+					debounce.Reset(watchDebounceWindow)
+				}
+			case <-debounceC:
+				debounceC = nil
+				debounce = nil
+				if err := r.discoverAndUpdateTools(ctx); err != nil {
+					slog.Error("error during event-triggered tool discovery", "datasource_type", r.hooks.DatasourceType, "error", err)
+				}
+			case <-r.stopPoller:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// invalidate clears a datasource's last-checked timestamps and backoff
+// deadline so it's picked up on the very next discovery pass instead of
+// waiting out skipRecentCheckThreshold or its health backoff window.
+func (r *Registry[P]) invalidate(uid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tool := range r.registeredTools {
+		delete(tool.lastChecked, uid)
+	}
+	if h, ok := r.health[uid]; ok {
+		h.nextCheck = time.Time{}
+	}
+}
+
+// stopPolling stops the background polling goroutine.
+func (r *Registry[P]) stopPolling() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.pollerRunning {
+		return
+	}
+
+	if r.stopPoller != nil {
+		close(r.stopPoller)
+		r.stopPoller = nil
+	}
+	r.pollerRunning = false
+}
+
+// discoveryMetrics holds metrics from a discovery run.
+type discoveryMetrics struct {
+	checked    int
+	skipped    int
+	failed     int
+	successful int
+}
+
+// discoverAndUpdateTools discovers tools and updates registrations.
+func (r *Registry[P]) discoverAndUpdateTools(ctx context.Context) error {
+	startTime := time.Now()
+
+	datasources, err := r.hooks.Discover(ctx)
+	if err != nil {
+		slog.Warn("no datasources available", "datasource_type", r.hooks.DatasourceType, "error", err)
+		return nil
+	}
+
+	if len(datasources) == 0 {
+		slog.Info("no datasources found", "datasource_type", r.hooks.DatasourceType)
+		r.unregisterAllTools()
+		return nil
+	}
+
+	slog.Info("starting tool discovery",
+		"datasource_type", r.hooks.DatasourceType,
+		"datasource_count", len(datasources),
+		"parallel", true)
+
+	results, metrics := r.performParallelDiscovery(ctx, datasources)
+
+	toolsByHash, successfulDatasources := r.processDiscoveryResults(results)
+
+	// Datasources that failed this round are degraded/down, not gone:
+	// their tools must survive in the registry (just unavailable) so they
+	// can be re-advertised instantly once the datasource recovers.
+	failedDatasources := make(map[string]bool)
+	for _, result := range results {
+		if result.err != nil {
+			failedDatasources[result.uid] = true
+		}
+	}
+
+	r.updateToolRegistrations(toolsByHash, successfulDatasources, failedDatasources)
+
+	seenDatasources := make(map[string]bool)
+	for uid := range datasources {
+		seenDatasources[uid] = true
+	}
+	r.cleanupRemovedDatasources(seenDatasources)
+
+	slog.Info("tool discovery completed",
+		"datasource_type", r.hooks.DatasourceType,
+		"duration", time.Since(startTime),
+		"total_datasources", len(datasources),
+		"checked", metrics.checked,
+		"skipped", metrics.skipped,
+		"failed", metrics.failed,
+		"successful", metrics.successful)
+
+	r.publishSnapshot(ctx)
+
+	return nil
+}
+
+// performParallelDiscovery discovers tools from datasources in parallel.
+func (r *Registry[P]) performParallelDiscovery(ctx context.Context, datasources map[string]Datasource) ([]discoveryResult, discoveryMetrics) {
+	metrics := discoveryMetrics{}
+
+	resultChan := make(chan discoveryResult, len(datasources))
+	var wg sync.WaitGroup
+
+	for uid, ds := range datasources {
+		if !r.shouldRediscover(uid) {
+			slog.Debug("skipping recently checked datasource", "datasource_uid", uid)
+			metrics.skipped++
+			continue
+		}
+
+		metrics.checked++
+		wg.Add(1)
+		go func(uid string, ds Datasource) {
+			defer wg.Done()
+
+			result := discoveryResult{uid: uid, ds: ds}
+
+			if err := r.hooks.EnsureSession(ctx, uid); err != nil {
+				result.err = err
+				r.markUnhealthy(uid, err)
+				resultChan <- result
+				return
+			}
+
+			result.tools = r.hooks.SessionTools(uid, ds.ID)
+			r.markHealthy(uid)
+
+			resultChan <- result
+		}(uid, ds)
+	}
+
+	wg.Wait()
+	close(resultChan)
+
+	results := make([]discoveryResult, 0, metrics.checked)
+	for result := range resultChan {
+		if result.err != nil {
+			metrics.failed++
+			slog.Warn("failed to initialize session for datasource",
+				"datasource_uid", result.uid,
+				"error", result.err)
+		} else {
+			metrics.successful++
+		}
+		results = append(results, result)
+	}
+
+	return results, metrics
+}
+
+// shouldRediscover checks if a datasource needs re-discovery. A datasource
+// that's currently backing off after failed checks (degraded or down) is
+// skipped until its backoff window elapses, regardless of how long ago it
+// was last checked.
+func (r *Registry[P]) shouldRediscover(uid string) bool {
+	if !r.dueForCheck(uid) {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, tool := range r.registeredTools {
+		if lastCheck, exists := tool.lastChecked[uid]; exists {
+			if time.Since(lastCheck) < skipRecentCheckThreshold {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// processDiscoveryResults processes discovery results into tool mappings.
+func (r *Registry[P]) processDiscoveryResults(results []discoveryResult) (map[string][]toolDiscovery, map[string]time.Time) {
+	toolsByHash := make(map[string][]toolDiscovery)
+	successfulDatasources := make(map[string]time.Time)
+
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+
+		successfulDatasources[result.uid] = time.Now()
+
+		for _, tool := range result.tools {
+			hash := computeToolHash(tool)
+			toolsByHash[hash] = append(toolsByHash[hash], toolDiscovery{
+				tool:           tool,
+				datasourceUID:  result.uid,
+				datasourceName: result.ds.Name,
+			})
+		}
+	}
+
+	return toolsByHash, successfulDatasources
+}
+
+// updateToolRegistrations updates the tool registry based on discovered
+// tools. failedDatasources lists datasources whose discovery failed this
+// round; tools solely backed by a failed datasource are left registered
+// (merely unavailable, per their health state) rather than torn down, so a
+// flaky datasource doesn't cause its tools to disappear and reappear.
+func (r *Registry[P]) updateToolRegistrations(toolsByHash map[string][]toolDiscovery, successfulDatasources map[string]time.Time, failedDatasources map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	processedTools := make(map[string]bool)
+
+	for hash, discoveries := range toolsByHash {
+		if len(discoveries) == 0 {
+			continue
+		}
+
+		representative := discoveries[0]
+		normalizedName := r.hooks.NormalizeName(representative.tool.Name)
+
+		if len(discoveries) > 1 {
+			sameName := true
+			for _, d := range discoveries[1:] {
+				if d.tool.Name != representative.tool.Name {
+					sameName = false
+					break
+				}
+			}
+
+			if sameName {
+				r.registerOrUpdateTool(normalizedName, representative.tool, discoveries, hash, successfulDatasources)
+				processedTools[normalizedName] = true
+			} else {
+				for _, discovery := range discoveries {
+					uniqueName := r.hooks.UniqueName(
+						r.hooks.NormalizeName(discovery.tool.Name),
+						discovery.datasourceName,
+					)
+					r.registerOrUpdateTool(uniqueName, discovery.tool, []toolDiscovery{discovery}, hash, successfulDatasources)
+					processedTools[uniqueName] = true
+				}
+			}
+		} else {
+			r.registerOrUpdateTool(normalizedName, representative.tool, discoveries, hash, successfulDatasources)
+			processedTools[normalizedName] = true
+		}
+	}
+
+	// Unregister tools that are no longer provided by any datasource. A
+	// tool whose datasources all just failed discovery is left alone:
+	// it's unavailable, not gone.
+	for toolName, tool := range r.registeredTools {
+		if processedTools[toolName] {
+			continue
+		}
+		if allDatasourcesFailed(tool.datasources, failedDatasources) {
+			continue
+		}
+		r.unregisterTool(toolName)
+	}
+}
+
+// allDatasourcesFailed reports whether every datasource backing a tool
+// failed discovery this round (and there was at least one).
+func allDatasourcesFailed(datasources []string, failedDatasources map[string]bool) bool {
+	if len(datasources) == 0 {
+		return false
+	}
+	for _, uid := range datasources {
+		if !failedDatasources[uid] {
+			return false
+		}
+	}
+	return true
+}
+
+// registerOrUpdateTool registers a new tool or updates an existing one.
+func (r *Registry[P]) registerOrUpdateTool(toolName string, tool mcp.Tool, discoveries []toolDiscovery, hash string, successfulDatasources map[string]time.Time) {
+	datasourceUIDs := make([]string, len(discoveries))
+	datasourceNames := make([]string, len(discoveries))
+	for i, d := range discoveries {
+		datasourceUIDs[i] = d.datasourceUID
+		datasourceNames[i] = d.datasourceName
+	}
+
+	existing, exists := r.registeredTools[toolName]
+	if exists {
+		existing.datasources = datasourceUIDs
+		existing.schemaHash = hash
+		existing.originalName = tool.Name // update in case it changed
+
+		if existing.lastChecked == nil {
+			existing.lastChecked = make(map[string]time.Time)
+		}
+		for uid, checkTime := range successfulDatasources {
+			for _, dsUID := range datasourceUIDs {
+				if dsUID == uid {
+					existing.lastChecked[uid] = checkTime
+					break
+				}
+			}
+		}
+
+		r.updateMappings(toolName, datasourceUIDs)
+		return
+	}
+
+	var description string
+	if len(datasourceUIDs) > 1 {
+		description = fmt.Sprintf("%s (via %s datasources: %s)",
+			tool.Description, r.hooks.DisplayName, strings.Join(datasourceNames, ", "))
+	} else {
+		description = fmt.Sprintf("%s (via %s datasource: %s)",
+			tool.Description, r.hooks.DisplayName, datasourceNames[0])
+	}
+
+	handler := r.buildHandler(toolName, datasourceUIDs)
+
+	convertedTool := mcpgrafana.MustTool(
+		toolName,
+		description,
+		handler,
+	)
+	convertedTool.Register(r.mcp)
+
+	lastChecked := make(map[string]time.Time)
+	for _, uid := range datasourceUIDs {
+		if checkTime, ok := successfulDatasources[uid]; ok {
+			lastChecked[uid] = checkTime
+		}
+	}
+
+	r.registeredTools[toolName] = &registeredTool{
+		name:         toolName,
+		originalName: tool.Name,
+		description:  description,
+		schemaHash:   hash,
+		datasources:  datasourceUIDs,
+		handler:      handler,
+		lastChecked:  lastChecked,
+	}
+
+	r.updateMappings(toolName, datasourceUIDs)
+
+	slog.Info("registered tool", "tool_name", toolName, "datasource_type", r.hooks.DatasourceType)
+}
+
+// updateMappings updates the datasource-to-tool mappings.
+func (r *Registry[P]) updateMappings(toolName string, datasourceUIDs []string) {
+	if oldUIDs, exists := r.toolToDatasources[toolName]; exists {
+		for _, uid := range oldUIDs {
+			r.removeToolFromDatasource(uid, toolName)
+		}
+	}
+
+	r.toolToDatasources[toolName] = datasourceUIDs
+	for _, uid := range datasourceUIDs {
+		if r.datasourceTools[uid] == nil {
+			r.datasourceTools[uid] = []string{}
+		}
+		r.datasourceTools[uid] = append(r.datasourceTools[uid], toolName)
+	}
+}
+
+// removeToolFromDatasource removes a tool from a datasource's tool list.
+func (r *Registry[P]) removeToolFromDatasource(datasourceUID, toolName string) {
+	tools := r.datasourceTools[datasourceUID]
+	filtered := make([]string, 0, len(tools))
+	for _, t := range tools {
+		if t != toolName {
+			filtered = append(filtered, t)
+		}
+	}
+	if len(filtered) > 0 {
+		r.datasourceTools[datasourceUID] = filtered
+	} else {
+		delete(r.datasourceTools, datasourceUID)
+	}
+}
+
+// unregisterTool removes a tool from the registry.
+func (r *Registry[P]) unregisterTool(toolName string) {
+	tool, exists := r.registeredTools[toolName]
+	if !exists {
+		return
+	}
+
+	r.mcp.DeleteTools(toolName)
+
+	delete(r.registeredTools, toolName)
+	delete(r.toolToDatasources, toolName)
+
+	for _, uid := range tool.datasources {
+		r.removeToolFromDatasource(uid, toolName)
+	}
+
+	slog.Info("unregistered tool", "tool_name", toolName, "datasource_type", r.hooks.DatasourceType)
+}
+
+// cleanupRemovedDatasources removes tools from datasources that no longer
+// exist.
+func (r *Registry[P]) cleanupRemovedDatasources(seenDatasources map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removedDatasources := []string{}
+	for uid := range r.datasourceTools {
+		if !seenDatasources[uid] {
+			removedDatasources = append(removedDatasources, uid)
+		}
+	}
+
+	for _, uid := range removedDatasources {
+		tools := r.datasourceTools[uid]
+		for _, toolName := range tools {
+			if otherUIDs := r.toolToDatasources[toolName]; len(otherUIDs) > 1 {
+				filtered := make([]string, 0, len(otherUIDs)-1)
+				for _, otherUID := range otherUIDs {
+					if otherUID != uid {
+						filtered = append(filtered, otherUID)
+					}
+				}
+				r.toolToDatasources[toolName] = filtered
+
+				if tool := r.registeredTools[toolName]; tool != nil {
+					tool.datasources = filtered
+				}
+			} else {
+				r.unregisterTool(toolName)
+			}
+		}
+		delete(r.datasourceTools, uid)
+	}
+}
+
+// unregisterAllTools removes all registered tools.
+func (r *Registry[P]) unregisterAllTools() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for toolName := range r.registeredTools {
+		r.unregisterTool(toolName)
+	}
+}
+
+// shutdown performs a graceful shutdown of the registry.
+func (r *Registry[P]) shutdown() {
+	r.stopPolling()
+	r.stopFollowerSyncing()
+	r.unregisterAllTools()
+	slog.Info("proxy shutdown complete", "datasource_type", r.hooks.DatasourceType)
+}
+
+// ToolSnapshotEntry is one registered tool's publishable state -- enough
+// for a follower replica to recreate the same MCP registration and
+// datasource routing the leader holds, without performing discovery
+// itself. The registry's tools all share one generic
+// (datasource_uid, arguments) handler signature (see buildHandler), so the
+// snapshot carries no per-tool JSON schema, only what's needed to rebuild
+// the registration and route calls to the right datasources.
+type ToolSnapshotEntry struct {
+	Name         string   `json:"name"`
+	OriginalName string   `json:"original_name"`
+	Description  string   `json:"description"`
+	Datasources  []string `json:"datasources"`
+}
+
+// Snapshot returns the registry's current tools in publishable form, for a
+// leader replica to hand to its SnapshotStore so follower replicas can
+// mirror these registrations without discovering datasources themselves.
+func (r *Registry[P]) Snapshot() []ToolSnapshotEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]ToolSnapshotEntry, 0, len(r.registeredTools))
+	for _, tool := range r.registeredTools {
+		entries = append(entries, ToolSnapshotEntry{
+			Name:         tool.name,
+			OriginalName: tool.originalName,
+			Description:  tool.description,
+			Datasources:  append([]string{}, tool.datasources...),
+		})
+	}
+	return entries
+}
+
+// publishSnapshot serializes the registry's current tools and publishes
+// them via snapshotStore, if configured, so follower replicas can sync
+// without performing discovery themselves. A publish failure is logged and
+// otherwise ignored: followers simply keep serving their last
+// successfully synced snapshot until the next leader publish succeeds.
+func (r *Registry[P]) publishSnapshot(ctx context.Context) {
+	if r.snapshotStore == nil {
+		return
+	}
+
+	data, err := json.Marshal(r.Snapshot())
+	if err != nil {
+		slog.Error("failed to marshal tool snapshot", "datasource_type", r.hooks.DatasourceType, "error", err)
+		return
+	}
+	if err := r.snapshotStore.PutSnapshot(ctx, r.snapshotKey, data); err != nil {
+		slog.Warn("failed to publish tool snapshot", "datasource_type", r.hooks.DatasourceType, "error", err)
+	}
+}
+
+// applySnapshot reconciles the registry's MCP registrations to match
+// entries exactly: it registers tools this replica hasn't seen yet,
+// updates the datasource routing of ones it has, and unregisters any tool
+// no longer present. It's the follower-side counterpart to
+// updateToolRegistrations, driven by a published snapshot instead of a
+// live discovery pass.
+func (r *Registry[P]) applySnapshot(entries []ToolSnapshotEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		seen[entry.Name] = true
+
+		if existing, exists := r.registeredTools[entry.Name]; exists {
+			existing.originalName = entry.OriginalName
+			existing.description = entry.Description
+			existing.datasources = entry.Datasources
+			r.updateMappings(entry.Name, entry.Datasources)
+			continue
+		}
+
+		handler := r.buildHandler(entry.Name, entry.Datasources)
+		convertedTool := mcpgrafana.MustTool(entry.Name, entry.Description, handler)
+		convertedTool.Register(r.mcp)
+
+		r.registeredTools[entry.Name] = &registeredTool{
+			name:         entry.Name,
+			originalName: entry.OriginalName,
+			description:  entry.Description,
+			datasources:  entry.Datasources,
+			handler:      handler,
+		}
+		r.updateMappings(entry.Name, entry.Datasources)
+
+		slog.Info("registered tool from follower sync", "tool_name", entry.Name, "datasource_type", r.hooks.DatasourceType)
+	}
+
+	for toolName := range r.registeredTools {
+		if !seen[toolName] {
+			r.unregisterTool(toolName)
+		}
+	}
+}
+
+// startFollowerSync starts a goroutine that periodically fetches the
+// leader's published tool snapshot and applies it, so a non-leader replica
+// keeps serving this datasource type's tools instead of registering none
+// until it wins leadership. A nil snapshotStore makes this a no-op, and an
+// already-running sync is left alone, so Handler.Initialize and every
+// OnLoseLeadership callback can call it unconditionally.
+func (r *Registry[P]) startFollowerSync(ctx context.Context, interval time.Duration) {
+	if r.snapshotStore == nil {
+		return
+	}
+
+	r.mu.Lock()
+	if r.followerSyncRunning {
+		r.mu.Unlock()
+		return
+	}
+	r.followerSyncRunning = true
+	r.stopFollowerSync = make(chan struct{})
+	stop := r.stopFollowerSync
+	r.mu.Unlock()
+
+	sync := func() {
+		data, found, err := r.snapshotStore.GetSnapshot(ctx, r.snapshotKey)
+		if err != nil {
+			slog.Warn("failed to fetch tool snapshot", "datasource_type", r.hooks.DatasourceType, "error", err)
+			return
+		}
+		if !found {
+			return
+		}
+		var entries []ToolSnapshotEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			slog.Warn("failed to unmarshal tool snapshot", "datasource_type", r.hooks.DatasourceType, "error", err)
+			return
+		}
+		r.applySnapshot(entries)
+	}
+
+	sync()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				sync()
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopFollowerSyncing stops the goroutine started by startFollowerSync, if
+// running. It's named with the -ing suffix to avoid colliding with the
+// stopFollowerSync channel field.
+func (r *Registry[P]) stopFollowerSyncing() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.followerSyncRunning {
+		return
+	}
+	close(r.stopFollowerSync)
+	r.followerSyncRunning = false
+}
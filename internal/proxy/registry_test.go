@@ -0,0 +1,250 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testParams is a minimal Params implementation for exercising Registry
+// without a real backend.
+type testParams struct {
+	DatasourceUID string
+	Arguments     map[string]interface{}
+}
+
+func (p testParams) GetDatasourceUID() string             { return p.DatasourceUID }
+func (p testParams) GetArguments() map[string]interface{} { return p.Arguments }
+
+func newTestRegistry() *Registry[testParams] {
+	r := NewRegistry(Hooks[testParams]{
+		DatasourceType: "test",
+		DisplayName:    "Test",
+		Middleware: func(name string) Middleware {
+			return func(next ToolHandlerFunc) ToolHandlerFunc { return next }
+		},
+	})
+	r.mcp = server.NewMCPServer("test", "test description")
+	return r
+}
+
+func TestComputeToolHash(t *testing.T) {
+	t.Run("identical tools produce same hash", func(t *testing.T) {
+		tool1 := mcp.Tool{
+			Name:        "test-tool",
+			Description: "A test tool",
+			InputSchema: mcp.ToolInputSchema{Type: "object"},
+		}
+		tool2 := mcp.Tool{
+			Name:        "test-tool",
+			Description: "A test tool",
+			InputSchema: mcp.ToolInputSchema{Type: "object"},
+		}
+
+		assert.Equal(t, computeToolHash(tool1), computeToolHash(tool2))
+	})
+
+	t.Run("different descriptions produce different hashes", func(t *testing.T) {
+		tool1 := mcp.Tool{Name: "test-tool", Description: "A test tool"}
+		tool2 := mcp.Tool{Name: "test-tool", Description: "A different test tool"}
+
+		assert.NotEqual(t, computeToolHash(tool1), computeToolHash(tool2))
+	})
+}
+
+func TestRegistry_BuildHandler_RequiresDatasourceUID(t *testing.T) {
+	r := newTestRegistry()
+	handler := r.buildHandler("test_tool", []string{"ds1", "ds2"})
+
+	_, err := handler(context.Background(), testParams{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "datasource_uid is required")
+}
+
+func TestRegistry_BuildHandler_ValidatesAllowedDatasources(t *testing.T) {
+	r := newTestRegistry()
+	handler := r.buildHandler("test_tool", []string{"ds1", "ds2"})
+
+	_, err := handler(context.Background(), testParams{DatasourceUID: "ds3"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "datasource ds3 does not provide tool test_tool")
+}
+
+func TestRegistry_PollingLifecycle(t *testing.T) {
+	t.Run("start and stop polling", func(t *testing.T) {
+		r := newTestRegistry()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		r.startPolling(ctx, 100*time.Millisecond)
+		assert.True(t, r.pollerRunning)
+
+		r.stopPolling()
+		assert.False(t, r.pollerRunning)
+	})
+
+	t.Run("multiple stop calls are safe", func(t *testing.T) {
+		r := newTestRegistry()
+
+		r.startPolling(context.Background(), 100*time.Millisecond)
+		r.stopPolling()
+		r.stopPolling()
+		r.stopPolling()
+
+		assert.False(t, r.pollerRunning)
+	})
+}
+
+func TestDatasourceHealth_FailRecoverTransitions(t *testing.T) {
+	r := newTestRegistry()
+
+	assert.Equal(t, DatasourceHealthUp, r.healthState("ds1"))
+
+	r.markUnhealthy("ds1", assert.AnError)
+	assert.Equal(t, DatasourceHealthDegraded, r.healthState("ds1"))
+
+	r.markUnhealthy("ds1", assert.AnError)
+	assert.Equal(t, DatasourceHealthDegraded, r.healthState("ds1"))
+
+	r.markUnhealthy("ds1", assert.AnError)
+	assert.Equal(t, DatasourceHealthDown, r.healthState("ds1"))
+
+	snapshot := r.HealthSnapshot()
+	assert.Equal(t, DatasourceHealthDown, snapshot["ds1"])
+
+	r.markHealthy("ds1")
+	assert.Equal(t, DatasourceHealthUp, r.healthState("ds1"))
+}
+
+func TestBackoffWithJitter_Bounds(t *testing.T) {
+	t.Run("first failure backs off less than the cap", func(t *testing.T) {
+		d := backoffWithJitter(1)
+		assert.Greater(t, d, time.Duration(0))
+		assert.Less(t, d, healthBackoffCap)
+	})
+
+	t.Run("many consecutive failures are capped", func(t *testing.T) {
+		d := backoffWithJitter(20)
+		assert.LessOrEqual(t, d, healthBackoffCap)
+		assert.Greater(t, d, time.Duration(0))
+	})
+}
+
+func TestShouldRediscover_RespectsBackoff(t *testing.T) {
+	r := newTestRegistry()
+
+	r.markUnhealthy("ds1", assert.AnError)
+	r.mu.Lock()
+	r.health["ds1"].nextCheck = time.Now().Add(time.Hour)
+	r.mu.Unlock()
+
+	assert.False(t, r.shouldRediscover("ds1"), "datasource still backing off should not be rediscovered")
+
+	r.mu.Lock()
+	r.health["ds1"].nextCheck = time.Now().Add(-time.Second)
+	r.mu.Unlock()
+
+	assert.True(t, r.shouldRediscover("ds1"), "datasource past its backoff window should be eligible again")
+}
+
+func TestUpdateToolRegistrations_SurvivesTransientFailureThenRecovers(t *testing.T) {
+	r := newTestRegistry()
+	r.hooks.NormalizeName = func(name string) string { return "test_" + name }
+
+	tool := mcp.Tool{Name: "trace-search", Description: "search traces"}
+	hash := computeToolHash(tool)
+	discoveries := []toolDiscovery{{tool: tool, datasourceUID: "ds1", datasourceName: "Test"}}
+
+	// Initial discovery registers the tool normally.
+	r.updateToolRegistrations(map[string][]toolDiscovery{hash: discoveries}, map[string]time.Time{"ds1": time.Now()}, nil)
+	require.Contains(t, r.registeredTools, "test_trace-search")
+
+	// ds1 fails on the next poll: the tool must stay registered (merely
+	// unavailable via its health state) rather than being torn down.
+	r.markUnhealthy("ds1", assert.AnError)
+	r.updateToolRegistrations(map[string][]toolDiscovery{}, map[string]time.Time{}, map[string]bool{"ds1": true})
+	assert.Contains(t, r.registeredTools, "test_trace-search", "tool backed solely by a failing datasource should not be unregistered")
+
+	// ds1 recovers: discovery succeeds again and the tool is re-confirmed.
+	r.markHealthy("ds1")
+	r.updateToolRegistrations(map[string][]toolDiscovery{hash: discoveries}, map[string]time.Time{"ds1": time.Now()}, nil)
+	assert.Contains(t, r.registeredTools, "test_trace-search")
+	assert.Equal(t, DatasourceHealthUp, r.healthState("ds1"))
+}
+
+func TestUpdateToolRegistrations_RemovesToolNoLongerOffered(t *testing.T) {
+	r := newTestRegistry()
+	r.hooks.NormalizeName = func(name string) string { return "test_" + name }
+
+	tool := mcp.Tool{Name: "trace-search", Description: "search traces"}
+	hash := computeToolHash(tool)
+	discoveries := []toolDiscovery{{tool: tool, datasourceUID: "ds1", datasourceName: "Test"}}
+
+	r.updateToolRegistrations(map[string][]toolDiscovery{hash: discoveries}, map[string]time.Time{"ds1": time.Now()}, nil)
+	require.Contains(t, r.registeredTools, "test_trace-search")
+
+	// ds1 succeeded but simply no longer offers this tool (not a failure).
+	r.updateToolRegistrations(map[string][]toolDiscovery{}, map[string]time.Time{"ds1": time.Now()}, nil)
+	assert.NotContains(t, r.registeredTools, "test_trace-search")
+}
+
+func TestRegistry_SnapshotRoundTrip(t *testing.T) {
+	leader := newTestRegistry()
+	leader.hooks.NormalizeName = func(name string) string { return "test_" + name }
+
+	tool := mcp.Tool{Name: "trace-search", Description: "search traces"}
+	hash := computeToolHash(tool)
+	discoveries := []toolDiscovery{{tool: tool, datasourceUID: "ds1", datasourceName: "Test"}}
+	leader.updateToolRegistrations(map[string][]toolDiscovery{hash: discoveries}, map[string]time.Time{"ds1": time.Now()}, nil)
+
+	snapshot := leader.Snapshot()
+	require.Len(t, snapshot, 1)
+	assert.Equal(t, "test_trace-search", snapshot[0].Name)
+	assert.Equal(t, []string{"ds1"}, snapshot[0].Datasources)
+
+	follower := newTestRegistry()
+	follower.applySnapshot(snapshot)
+	require.Contains(t, follower.registeredTools, "test_trace-search")
+	assert.Equal(t, []string{"ds1"}, follower.registeredTools["test_trace-search"].datasources)
+}
+
+func TestRegistry_ApplySnapshot_RemovesToolNoLongerPresent(t *testing.T) {
+	follower := newTestRegistry()
+	follower.applySnapshot([]ToolSnapshotEntry{{Name: "test_trace-search", OriginalName: "trace-search", Datasources: []string{"ds1"}}})
+	require.Contains(t, follower.registeredTools, "test_trace-search")
+
+	follower.applySnapshot(nil)
+	assert.NotContains(t, follower.registeredTools, "test_trace-search")
+}
+
+func TestRegistry_FollowerSync_AppliesPublishedSnapshot(t *testing.T) {
+	store := &fakeSnapshotStore{}
+
+	leader := newTestRegistry()
+	leader.snapshotStore = store
+	leader.snapshotKey = "test-key"
+	leader.hooks.NormalizeName = func(name string) string { return "test_" + name }
+
+	tool := mcp.Tool{Name: "trace-search", Description: "search traces"}
+	hash := computeToolHash(tool)
+	discoveries := []toolDiscovery{{tool: tool, datasourceUID: "ds1", datasourceName: "Test"}}
+	leader.updateToolRegistrations(map[string][]toolDiscovery{hash: discoveries}, map[string]time.Time{"ds1": time.Now()}, nil)
+	leader.publishSnapshot(context.Background())
+
+	follower := newTestRegistry()
+	follower.snapshotStore = store
+	follower.snapshotKey = "test-key"
+	follower.startFollowerSync(context.Background(), time.Hour)
+	defer follower.stopFollowerSyncing()
+
+	require.Eventually(t, func() bool {
+		_, ok := follower.registeredTools["test_trace-search"]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+}
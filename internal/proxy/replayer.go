@@ -0,0 +1,181 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// replayKey identifies a recorded call for matching during replay.
+// argsHash is the sha256 hex digest of the request after canonicalizing
+// it through an unmarshal/marshal round trip, so requests that differ
+// only in JSON key order or whitespace still match.
+type replayKey struct {
+	uid, method, argsHash string
+}
+
+// canonicalHash hashes raw after normalizing it via a decode/re-encode
+// round trip (Go's json.Marshal sorts map keys), so two requests that are
+// semantically identical hash the same even if they weren't marshaled
+// byte-for-byte the same way.
+func canonicalHash(raw json.RawMessage) string {
+	var v interface{}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &v)
+	}
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%x", sum)
+}
+
+// readRecords decodes every Record from a newline-delimited JSON segment
+// file at path, in the order Recorder wrote them.
+func readRecords(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open recording %s: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	var records []Record
+	dec := json.NewDecoder(f)
+	for {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode recording %s: %w", path, err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// Replayer loads a recording written by a Recorder and serves as a
+// drop-in replacement for the real callMCP: it matches requests by
+// (datasource UID, JSON-RPC method, canonicalized-args-hash) and returns
+// the response captured for that call, so Registry's discovery loop,
+// hash-based dedup, and conflict-rename paths can be exercised against a
+// recorded session in unit tests without any live Tempo.
+type Replayer struct {
+	mu   sync.Mutex
+	recs map[replayKey][]Record
+	next map[replayKey]int
+}
+
+// LoadReplayer reads the segment file at path and returns a Replayer
+// ready to serve its recorded calls.
+func LoadReplayer(path string) (*Replayer, error) {
+	records, err := readRecords(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &Replayer{
+		recs: make(map[replayKey][]Record),
+		next: make(map[replayKey]int),
+	}
+	for _, rec := range records {
+		key := replayKey{uid: rec.DatasourceUID, method: rec.Method, argsHash: canonicalHash(rec.Request)}
+		rep.recs[key] = append(rep.recs[key], rec)
+	}
+
+	return rep, nil
+}
+
+// Call returns the response recorded for (uid, method, request). If the
+// same call was recorded more than once (e.g. repeated polling), each
+// invocation of Call returns the next one in recorded order; once
+// exhausted, it keeps returning the last one, so a replayed poll loop
+// doesn't error out just because it outlasted the recording.
+func (r *Replayer) Call(uid, method string, request interface{}) (json.RawMessage, error) {
+	reqJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal replay request: %w", err)
+	}
+	key := replayKey{uid: uid, method: method, argsHash: canonicalHash(reqJSON)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recs := r.recs[key]
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("no recorded response for datasource %s method %s", uid, method)
+	}
+
+	idx := r.next[key]
+	if idx >= len(recs) {
+		idx = len(recs) - 1
+	} else {
+		r.next[key] = idx + 1
+	}
+
+	rec := recs[idx]
+	if rec.Error != "" {
+		return nil, errors.New(rec.Error)
+	}
+	return rec.Response, nil
+}
+
+// Summary is a human-readable digest of a recording, as produced by
+// Describe and printed by the `mcp-grafana describe-recording` CLI
+// subcommand.
+type Summary struct {
+	Records     int
+	Errors      int
+	Datasources map[string]int // datasource UID -> record count
+	Tools       map[string]int // tool name -> call count, from tools/call requests
+	LastHash    map[string]string
+}
+
+// mcpCallToolParams mirrors tools.MCPCallToolParams' wire shape, just
+// enough to recover the tool name from a recorded "tools/call" request
+// without internal/proxy depending on the tools package.
+type mcpCallToolParams struct {
+	Name string `json:"name"`
+}
+
+// Describe reads the segment file at path and summarizes it: how many
+// datasources and tools it covers, its error rate, and a short hash
+// prefix of each datasource's most recently recorded response, so an
+// operator can eyeball whether a recording still matches what's live
+// before trusting it for replay-based triage.
+func Describe(path string) (Summary, error) {
+	records, err := readRecords(path)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary := Summary{
+		Datasources: make(map[string]int),
+		Tools:       make(map[string]int),
+		LastHash:    make(map[string]string),
+	}
+
+	for _, rec := range records {
+		summary.Records++
+		summary.Datasources[rec.DatasourceUID]++
+
+		if rec.Error != "" {
+			summary.Errors++
+			continue
+		}
+
+		summary.LastHash[rec.DatasourceUID] = rec.Short()
+
+		if rec.Method == "tools/call" {
+			var params mcpCallToolParams
+			if err := json.Unmarshal(rec.Request, &params); err == nil && params.Name != "" {
+				summary.Tools[params.Name]++
+			}
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,87 @@
+package proxy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordSession(t *testing.T, path string) {
+	t.Helper()
+	rec, err := NewRecorder(path)
+	require.NoError(t, err)
+
+	require.NoError(t, rec.Record("ds1", "tools/list", nil, []string{"trace-search"}, nil))
+	require.NoError(t, rec.Record("ds1", "tools/call",
+		map[string]interface{}{"name": "trace-search", "arguments": map[string]interface{}{"query": "{}"}},
+		map[string]string{"result": "ok"}, nil))
+	require.NoError(t, rec.Record("ds1", "tools/call",
+		map[string]interface{}{"name": "trace-search", "arguments": map[string]interface{}{"query": "{}"}},
+		map[string]string{"result": "ok-again"}, nil))
+	require.NoError(t, rec.Close())
+}
+
+func TestReplayer_MatchesByCanonicalizedArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recordSession(t, path)
+
+	replayer, err := LoadReplayer(path)
+	require.NoError(t, err)
+
+	// Key order differs from the recorded call but is semantically
+	// identical, so it should still match.
+	reordered := map[string]interface{}{"arguments": map[string]interface{}{"query": "{}"}, "name": "trace-search"}
+
+	resp, err := replayer.Call("ds1", "tools/call", reordered)
+	require.NoError(t, err)
+	assert.Contains(t, string(resp), "ok")
+}
+
+func TestReplayer_RepeatedCallsStepThroughThenHoldLast(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recordSession(t, path)
+
+	replayer, err := LoadReplayer(path)
+	require.NoError(t, err)
+
+	args := map[string]interface{}{"name": "trace-search", "arguments": map[string]interface{}{"query": "{}"}}
+
+	first, err := replayer.Call("ds1", "tools/call", args)
+	require.NoError(t, err)
+	assert.Contains(t, string(first), "ok")
+
+	second, err := replayer.Call("ds1", "tools/call", args)
+	require.NoError(t, err)
+	assert.Contains(t, string(second), "ok-again")
+
+	third, err := replayer.Call("ds1", "tools/call", args)
+	require.NoError(t, err)
+	assert.Equal(t, second, third, "once exhausted, replay should keep returning the last recorded response")
+}
+
+func TestReplayer_UnknownCallErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recordSession(t, path)
+
+	replayer, err := LoadReplayer(path)
+	require.NoError(t, err)
+
+	_, err = replayer.Call("ds2", "tools/call", map[string]interface{}{"name": "unknown"})
+	assert.Error(t, err)
+}
+
+func TestDescribe_SummarizesRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recordSession(t, path)
+
+	summary, err := Describe(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, summary.Records)
+	assert.Equal(t, 0, summary.Errors)
+	assert.Equal(t, 3, summary.Datasources["ds1"])
+	assert.Equal(t, 2, summary.Tools["trace-search"])
+	assert.NotEmpty(t, summary.LastHash["ds1"])
+}
@@ -0,0 +1,89 @@
+// Package proxy implements the shared registry/discovery/health-tracking
+// machinery behind the datasource-proxied MCP tools (Tempo, and any future
+// backend with the same "poll Grafana for datasources of a type, proxy
+// tools/list + tools/call through them" shape). A backend adapter supplies
+// Hooks describing how to discover its datasources, ensure a session, and
+// call a tool; Registry does the rest (dedup by schema hash, conflict
+// renaming, health-aware backoff, and registration lifecycle).
+package proxy
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Datasource is the subset of a Grafana datasource a proxy backend needs:
+// enough to address it through the Grafana datasource proxy and to label
+// tools registered on its behalf.
+type Datasource struct {
+	ID   int64
+	UID  string
+	Name string
+	URL  string
+	Type string
+}
+
+// ToolHandlerFunc is the common shape of a dynamically registered tool
+// handler: it receives the raw arguments for a proxied call (already
+// resolved to a map, regardless of the caller's typed params struct) and
+// returns the rendered tool result text or an error.
+type ToolHandlerFunc func(ctx context.Context, args map[string]interface{}) (string, error)
+
+// Middleware wraps a ToolHandlerFunc to add cross-cutting behavior (panic
+// recovery, metrics, ...) around a registry's dynamically registered tool
+// handlers. A backend adapter supplies one via Hooks.Middleware, typically
+// by adapting its own tool middleware chain to this signature.
+type Middleware func(next ToolHandlerFunc) ToolHandlerFunc
+
+// Params is implemented by the typed params struct a backend adapter
+// registers its dynamic tools with (e.g. DynamicTempoToolParams). It's how
+// Registry extracts the target datasource and call arguments from a params
+// struct it otherwise knows nothing about.
+type Params interface {
+	// GetDatasourceUID returns the UID of the datasource a call targets.
+	GetDatasourceUID() string
+	// GetArguments returns the tool-specific arguments to forward.
+	GetArguments() map[string]interface{}
+}
+
+// Hooks wires a Registry to a specific backend datasource type without the
+// registry needing to know anything about Grafana's datasource proxy, MCP
+// session transport, or the backend's own typed params struct.
+type Hooks[P Params] struct {
+	// DatasourceType is the Grafana datasource type this registry
+	// discovers and polls, e.g. "tempo".
+	DatasourceType string
+	// DisplayName is the human-readable name used in registered tools'
+	// descriptions and log messages, e.g. "Tempo".
+	DisplayName string
+	// NormalizeName converts a discovered tool's original name into the
+	// name it's registered under, e.g. adding a "tempo_" prefix.
+	NormalizeName func(originalName string) string
+	// UniqueName disambiguates a tool name when multiple datasources
+	// provide same-named-but-different tools, e.g. by suffixing the
+	// datasource's name.
+	UniqueName func(baseName, datasourceName string) string
+	// Discover returns the currently configured datasources of
+	// DatasourceType.
+	Discover func(ctx context.Context) (map[string]Datasource, error)
+	// EnsureSession initializes (or confirms) a proxied MCP session
+	// against a datasource, so its tools are known and callable.
+	EnsureSession func(ctx context.Context, datasourceUID string) error
+	// SessionTools returns the tool list of an already-ensured session.
+	SessionTools func(datasourceUID string, datasourceID int64) []mcp.Tool
+	// Call invokes originalToolName on datasourceUID with arguments,
+	// returning the tool's raw text result.
+	Call func(ctx context.Context, datasourceUID, originalToolName string, arguments map[string]interface{}) (string, error)
+	// WrapResponse optionally formats a tool's raw response before it's
+	// returned to the caller. A nil WrapResponse returns it unchanged.
+	WrapResponse func(originalToolName, datasourceUID, response string) string
+	// Middleware returns the middleware chain applied to toolName's
+	// handler, e.g. the backend's default panic-recovery/metrics chain.
+	Middleware func(toolName string) Middleware
+	// Watcher, if set, lets the Registry rediscover a single datasource as
+	// soon as it changes instead of waiting for the next ticker poll. A
+	// nil Watcher means discovery only ever runs on the ticker interval
+	// passed to Handler.Initialize.
+	Watcher Watcher
+}
@@ -0,0 +1,143 @@
+package proxy
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// EventKind identifies the kind of change a Watcher observed.
+type EventKind int
+
+const (
+	// DatasourceAdded indicates a new datasource of the watched type appeared.
+	DatasourceAdded EventKind = iota
+	// DatasourceRemoved indicates a previously known datasource disappeared.
+	DatasourceRemoved
+	// DatasourceChanged indicates a datasource's configuration (name, URL, ...) changed.
+	DatasourceChanged
+	// ToolListChanged indicates the set of tools a datasource's session offers changed.
+	ToolListChanged
+)
+
+// Event is a single change notification emitted by a Watcher.
+type Event struct {
+	Kind          EventKind
+	DatasourceUID string
+}
+
+// Watcher emits Events as it observes datasource or tool-list changes, so a
+// Registry can rediscover the affected datasource immediately instead of
+// waiting for its next ticker poll. Watch should run until ctx is
+// cancelled, closing the returned channel on exit.
+type Watcher interface {
+	Watch(ctx context.Context) (<-chan Event, error)
+}
+
+// pollingWatcher turns a Discover hook into a Watcher by polling it at a
+// short cadence and diffing successive snapshots. It's the fallback (and,
+// absent a richer backend-specific mechanism, the default) way to get
+// event-driven rediscovery: any Discover hook can be wrapped into a
+// Watcher without the backend needing its own change-notification API.
+type pollingWatcher struct {
+	discover func(ctx context.Context) (map[string]Datasource, error)
+	interval time.Duration
+}
+
+// NewPollingWatcher wraps discover (typically a Hooks.Discover) into a
+// Watcher that re-runs it every interval and emits DatasourceAdded,
+// DatasourceRemoved, and DatasourceChanged events for whatever differs
+// from the previous snapshot. Use this when a backend has no native
+// change-notification API to watch.
+func NewPollingWatcher(discover func(ctx context.Context) (map[string]Datasource, error), interval time.Duration) Watcher {
+	return &pollingWatcher{discover: discover, interval: interval}
+}
+
+func (w *pollingWatcher) Watch(ctx context.Context) (<-chan Event, error) {
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		previous := map[string]Datasource{}
+
+		for {
+			select {
+			case <-ticker.C:
+				current, err := w.discover(ctx)
+				if err != nil {
+					slog.Debug("polling watcher: discover failed, skipping this tick", "error", err)
+					continue
+				}
+
+				for uid, ds := range current {
+					prev, existed := previous[uid]
+					if !existed {
+						events <- Event{Kind: DatasourceAdded, DatasourceUID: uid}
+					} else if prev != ds {
+						events <- Event{Kind: DatasourceChanged, DatasourceUID: uid}
+					}
+				}
+				for uid := range previous {
+					if _, stillThere := current[uid]; !stillThere {
+						events <- Event{Kind: DatasourceRemoved, DatasourceUID: uid}
+					}
+				}
+
+				previous = current
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// MergeWatchers fans the events of multiple Watchers into a single
+// channel, so e.g. a Grafana-side polling watcher and an MCP-side
+// notification watcher can drive the same debounced rediscovery path.
+// The merged channel closes once every underlying Watch has started and
+// ctx is cancelled.
+func MergeWatchers(watchers ...Watcher) Watcher {
+	return &mergedWatcher{watchers: watchers}
+}
+
+type mergedWatcher struct {
+	watchers []Watcher
+}
+
+func (m *mergedWatcher) Watch(ctx context.Context) (<-chan Event, error) {
+	merged := make(chan Event)
+	var wg sync.WaitGroup
+
+	for _, w := range m.watchers {
+		events, err := w.Watch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		wg.Add(1)
+		go func(events <-chan Event) {
+			defer wg.Done()
+			for event := range events {
+				select {
+				case merged <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(events)
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	return merged, nil
+}
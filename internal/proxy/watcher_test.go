@@ -0,0 +1,98 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollingWatcher_EmitsAddedRemovedChanged(t *testing.T) {
+	var mu sync.Mutex
+	snapshot := map[string]Datasource{
+		"ds1": {UID: "ds1", Name: "Tempo 1"},
+	}
+
+	discover := func(ctx context.Context) (map[string]Datasource, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		copied := make(map[string]Datasource, len(snapshot))
+		for uid, ds := range snapshot {
+			copied[uid] = ds
+		}
+		return copied, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := NewPollingWatcher(discover, 10*time.Millisecond)
+	events, err := w.Watch(ctx)
+	require.NoError(t, err)
+
+	// ds1 starts present in the first snapshot, so it's reported added.
+	require.Equal(t, Event{Kind: DatasourceAdded, DatasourceUID: "ds1"}, mustRecvEvent(t, events))
+
+	mu.Lock()
+	snapshot["ds1"] = Datasource{UID: "ds1", Name: "Tempo 1 Renamed"}
+	mu.Unlock()
+	require.Equal(t, Event{Kind: DatasourceChanged, DatasourceUID: "ds1"}, mustRecvEvent(t, events))
+
+	mu.Lock()
+	delete(snapshot, "ds1")
+	mu.Unlock()
+	require.Equal(t, Event{Kind: DatasourceRemoved, DatasourceUID: "ds1"}, mustRecvEvent(t, events))
+}
+
+func TestMergeWatchers_FansInBothSources(t *testing.T) {
+	a := &fakeWatcher{events: []Event{{Kind: DatasourceAdded, DatasourceUID: "ds1"}}}
+	b := &fakeWatcher{events: []Event{{Kind: ToolListChanged, DatasourceUID: "ds2"}}}
+
+	merged := MergeWatchers(a, b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := merged.Watch(ctx)
+	require.NoError(t, err)
+
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		event := mustRecvEvent(t, events)
+		seen[event.DatasourceUID] = true
+	}
+	assert.True(t, seen["ds1"])
+	assert.True(t, seen["ds2"])
+}
+
+// fakeWatcher emits a fixed set of events once, then blocks until ctx is
+// cancelled.
+type fakeWatcher struct {
+	events []Event
+}
+
+func (f *fakeWatcher) Watch(ctx context.Context) (<-chan Event, error) {
+	out := make(chan Event, len(f.events))
+	for _, event := range f.events {
+		out <- event
+	}
+	go func() {
+		<-ctx.Done()
+		close(out)
+	}()
+	return out, nil
+}
+
+func mustRecvEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return Event{}
+	}
+}
@@ -0,0 +1,168 @@
+// Package retry provides an http.RoundTripper that retries transient
+// failures with jittered exponential backoff.
+package retry
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Config controls the retry policy applied by a Transport.
+type Config struct {
+	// MaxRetries is the maximum number of retry attempts after the initial
+	// request. Zero disables retries.
+	MaxRetries int
+
+	// RetryWaitMin and RetryWaitMax bound the jittered exponential backoff
+	// between attempts.
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+
+	// RetryNonIdempotent allows retrying requests whose method isn't
+	// inherently safe to repeat (anything other than GET, HEAD, OPTIONS).
+	// Left false by default so things like incident creation aren't
+	// silently retried and double-submitted.
+	RetryNonIdempotent bool
+}
+
+// DefaultConfig returns the retry policy used when no Config is supplied.
+func DefaultConfig() Config {
+	return Config{
+		MaxRetries:   3,
+		RetryWaitMin: 500 * time.Millisecond,
+		RetryWaitMax: 30 * time.Second,
+	}
+}
+
+// transport wraps a base http.RoundTripper, retrying transient failures with
+// jittered exponential backoff.
+type transport struct {
+	base   http.RoundTripper
+	config Config
+}
+
+// NewTransport wraps base so transient failures (network errors, 429, 502,
+// 503, 504) are retried with jittered exponential backoff before being
+// returned to the caller. If base is nil, http.DefaultTransport is used.
+// Requests whose method isn't idempotent (anything but GET/HEAD/OPTIONS) are
+// never retried unless config.RetryNonIdempotent is set.
+func NewTransport(base http.RoundTripper, config Config) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, config: config}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.retryable(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	var lastResp *http.Response
+	var lastErr error
+
+	for attempt := 0; attempt <= t.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			wait := backoffWithJitter(attempt, t.config.RetryWaitMin, t.config.RetryWaitMax)
+			if retryAfter, ok := retryAfterDelay(lastResp); ok {
+				wait = retryAfter
+			}
+			if err := sleep(req.Context(), wait); err != nil {
+				return nil, err
+			}
+		}
+
+		drainAndClose(lastResp)
+
+		resp, err := t.base.RoundTrip(req)
+		lastResp, lastErr = resp, err
+
+		if attempt == t.config.MaxRetries {
+			break
+		}
+		if err == nil && !shouldRetryStatus(resp.StatusCode) {
+			break
+		}
+	}
+
+	return lastResp, lastErr
+}
+
+// retryable reports whether req is eligible for retries at all: its method
+// must be idempotent unless the caller opted in to retrying everything.
+func (t *transport) retryable(req *http.Request) bool {
+	if t.config.RetryNonIdempotent {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func shouldRetryStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfterDelay extracts a Retry-After delay from resp, if present.
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	h := resp.Header.Get("Retry-After")
+	if h == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(h); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(h); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// backoffWithJitter computes a jittered exponential backoff for the given
+// attempt number, bounded by [min, max].
+func backoffWithJitter(attempt int, min, max time.Duration) time.Duration {
+	backoff := min << (attempt - 1)
+	if backoff <= 0 || backoff > max {
+		backoff = max
+	}
+	jitter := time.Duration(rand.Int63n(int64(backoff/2) + 1))
+	return backoff/2 + jitter
+}
+
+// drainAndClose reads resp's body to completion and closes it so the
+// underlying connection can be reused for the next attempt.
+func drainAndClose(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body) //nolint:errcheck
+	resp.Body.Close()              //nolint:errcheck
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
@@ -126,6 +128,91 @@ func TestExtractGrafanaInfoFromHeaders(t *testing.T) {
 		assert.Equal(t, "http://my-test-url.grafana.com", config.URL)
 		assert.Equal(t, "my-test-api-key", config.APIKey)
 	})
+
+	t.Run("basic auth via headers", func(t *testing.T) {
+		t.Setenv("GRAFANA_USERNAME", "")
+		t.Setenv("GRAFANA_PASSWORD", "")
+
+		req, err := http.NewRequest("GET", "http://example.com", nil)
+		require.NoError(t, err)
+		req.Header.Set(grafanaUsernameHeader, "my-test-user")
+		req.Header.Set(grafanaPasswordHeader, "my-test-pass")
+		ctx := ExtractGrafanaInfoFromHeaders(context.Background(), req)
+		config := GrafanaConfigFromContext(ctx)
+		assert.Equal(t, "", config.APIKey)
+		assert.Equal(t, "my-test-user", config.Username)
+		assert.Equal(t, "my-test-pass", config.Password)
+	})
+
+	t.Run("basic auth via env, headers take priority", func(t *testing.T) {
+		t.Setenv("GRAFANA_USERNAME", "will-not-be-used")
+		t.Setenv("GRAFANA_PASSWORD", "will-not-be-used")
+
+		req, err := http.NewRequest("GET", "http://example.com", nil)
+		require.NoError(t, err)
+		req.Header.Set(grafanaUsernameHeader, "my-test-user")
+		req.Header.Set(grafanaPasswordHeader, "my-test-pass")
+		ctx := ExtractGrafanaInfoFromHeaders(context.Background(), req)
+		config := GrafanaConfigFromContext(ctx)
+		assert.Equal(t, "my-test-user", config.Username)
+		assert.Equal(t, "my-test-pass", config.Password)
+	})
+
+	t.Run("password without username is rejected", func(t *testing.T) {
+		t.Setenv("GRAFANA_USERNAME", "")
+		t.Setenv("GRAFANA_PASSWORD", "")
+
+		req, err := http.NewRequest("GET", "http://example.com", nil)
+		require.NoError(t, err)
+		req.Header.Set(grafanaPasswordHeader, "my-test-pass")
+		ctx := ExtractGrafanaInfoFromHeaders(context.Background(), req)
+		config := GrafanaConfigFromContext(ctx)
+		assert.Equal(t, "", config.Username)
+		assert.Equal(t, "", config.Password)
+	})
+}
+
+func TestExtractGrafanaInfoFromHeaders_Tenant(t *testing.T) {
+	t.Run("no headers, no env", func(t *testing.T) {
+		t.Setenv("GRAFANA_TENANT_ID", "")
+
+		req, err := http.NewRequest("GET", "http://example.com", nil)
+		require.NoError(t, err)
+		ctx := ExtractGrafanaInfoFromHeaders(context.Background(), req)
+		config := GrafanaConfigFromContext(ctx)
+		assert.Equal(t, "", config.TenantID)
+	})
+
+	t.Run("no headers, with env", func(t *testing.T) {
+		t.Setenv("GRAFANA_TENANT_ID", "my-test-tenant")
+
+		req, err := http.NewRequest("GET", "http://example.com", nil)
+		require.NoError(t, err)
+		ctx := ExtractGrafanaInfoFromHeaders(context.Background(), req)
+		config := GrafanaConfigFromContext(ctx)
+		assert.Equal(t, "my-test-tenant", config.TenantID)
+	})
+
+	t.Run("with headers, with env", func(t *testing.T) {
+		// Env vars should be ignored if headers are present.
+		t.Setenv("GRAFANA_TENANT_ID", "will-not-be-used")
+
+		req, err := http.NewRequest("GET", "http://example.com", nil)
+		require.NoError(t, err)
+		req.Header.Set(grafanaTenantIDHeader, "my-test-tenant")
+		ctx := ExtractGrafanaInfoFromHeaders(context.Background(), req)
+		config := GrafanaConfigFromContext(ctx)
+		assert.Equal(t, "my-test-tenant", config.TenantID)
+	})
+
+	t.Run("multi-tenant reads via X-Grafana-Tenant-Ids", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "http://example.com", nil)
+		require.NoError(t, err)
+		req.Header.Set(grafanaTenantIDsHeader, "tenant-a,tenant-b")
+		ctx := ExtractGrafanaInfoFromHeaders(context.Background(), req)
+		config := GrafanaConfigFromContext(ctx)
+		assert.Equal(t, []string{"tenant-a", "tenant-b"}, config.TenantIDs)
+	})
 }
 
 func TestExtractGrafanaClientPath(t *testing.T) {
@@ -513,6 +600,67 @@ func TestToolTracingInstrumentation(t *testing.T) {
 		assertHasAttribute(t, attributes, "mcp.tool.description", "A tool for debugging")
 		assertHasAttribute(t, attributes, "mcp.tool.arguments", `{"safeData":"debug-value"}`)
 	})
+
+	t.Run("redacted mode masks matching fields but preserves shape", func(t *testing.T) {
+		// Clear any previous spans
+		spanRecorder.Reset()
+
+		// Define a tool with nested and array arguments
+		type Credentials struct {
+			Password string `json:"password"`
+		}
+		type TestParams struct {
+			Username    string      `json:"username"`
+			Credentials Credentials `json:"credentials"`
+			Contacts    []string    `json:"contacts"`
+		}
+
+		testHandler := func(ctx context.Context, args TestParams) (string, error) {
+			return "processed", nil
+		}
+
+		// Create tool
+		tool := MustTool("redacted_tool", "A tool with fields requiring redaction", testHandler)
+
+		// Create context with redacted-mode span arguments
+		config := GrafanaConfig{
+			SpanArgumentPolicy: SpanArgumentPolicyRedacted,
+			RedactedFields:     []string{"password"},
+		}
+		ctx := WithGrafanaConfig(context.Background(), config)
+
+		// Create a mock MCP request with nested sensitive data
+		request := mcp.CallToolRequest{
+			Params: struct {
+				Name      string    `json:"name"`
+				Arguments any       `json:"arguments,omitempty"`
+				Meta      *mcp.Meta `json:"_meta,omitempty"`
+			}{
+				Name: "redacted_tool",
+				Arguments: map[string]interface{}{
+					"username": "alice",
+					"credentials": map[string]interface{}{
+						"password": "hunter2",
+					},
+					"contacts": []interface{}{"alice@example.com", "bob@example.com"},
+				},
+			},
+		}
+
+		// Execute the tool
+		result, err := tool.Handler(ctx, request)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+
+		// Verify span was created
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+
+		span := spans[0]
+		attributes := span.Attributes()
+		assertHasAttribute(t, attributes, "mcp.tool.arguments",
+			`{"contacts":["***","***"],"credentials":{"password":"***"},"username":"alice"}`)
+	})
 }
 
 func TestHTTPTracingConfiguration(t *testing.T) {
@@ -545,6 +693,115 @@ func TestHTTPTracingConfiguration(t *testing.T) {
 	})
 }
 
+func TestToolResultSpanAndMetrics(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(spanRecorder),
+	)
+	originalTracerProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(tracerProvider)
+	defer otel.SetTracerProvider(originalTracerProvider)
+
+	t.Run("result attributes and RED metrics recorded on success", func(t *testing.T) {
+		spanRecorder.Reset()
+
+		reader := sdkmetric.NewManualReader()
+		meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+		originalMeterProvider := otel.GetMeterProvider()
+		otel.SetMeterProvider(meterProvider)
+		defer otel.SetMeterProvider(originalMeterProvider)
+
+		type TestParams struct {
+			Message string `json:"message" jsonschema:"description=Test message"`
+		}
+		testHandler := func(ctx context.Context, args TestParams) (string, error) {
+			return "Hello " + args.Message, nil
+		}
+		tool := MustTool("metrics_tool", "A tool for result/metric instrumentation", testHandler)
+
+		config := GrafanaConfig{IncludeResultInSpans: true}
+		ctx := WithGrafanaConfig(context.Background(), config)
+
+		request := mcp.CallToolRequest{
+			Params: struct {
+				Name      string    `json:"name"`
+				Arguments any       `json:"arguments,omitempty"`
+				Meta      *mcp.Meta `json:"_meta,omitempty"`
+			}{
+				Name:      "metrics_tool",
+				Arguments: map[string]interface{}{"message": "world"},
+			},
+		}
+
+		result, err := tool.Handler(ctx, request)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+		attributes := spans[0].Attributes()
+		assertHasAttribute(t, attributes, "mcp.tool.result", "Hello world")
+		assertHasAttribute(t, attributes, "mcp.tool.result.truncated", "false")
+
+		var foundBytes bool
+		for _, attr := range attributes {
+			if string(attr.Key) == "mcp.tool.result.bytes" {
+				foundBytes = true
+				assert.Greater(t, attr.Value.AsInt64(), int64(0))
+			}
+		}
+		assert.True(t, foundBytes, "expected mcp.tool.result.bytes attribute")
+
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+
+		var sawDuration, sawCalls bool
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				switch m.Name {
+				case "mcp.tool.duration":
+					sawDuration = true
+				case "mcp.tool.calls":
+					sawCalls = true
+				}
+			}
+		}
+		assert.True(t, sawDuration, "expected mcp.tool.duration histogram to be recorded")
+		assert.True(t, sawCalls, "expected mcp.tool.calls counter to be recorded")
+	})
+
+	t.Run("metrics path is a no-op without a MeterProvider configured", func(t *testing.T) {
+		spanRecorder.Reset()
+		// Deliberately leave the global MeterProvider unconfigured.
+
+		type TestParams struct {
+			Message string `json:"message" jsonschema:"description=Test message"`
+		}
+		testHandler := func(ctx context.Context, args TestParams) (string, error) {
+			return "processed", nil
+		}
+		tool := MustTool("no_meter_tool", "A tool exercised without a MeterProvider", testHandler)
+
+		config := GrafanaConfig{}
+		ctx := WithGrafanaConfig(context.Background(), config)
+
+		request := mcp.CallToolRequest{
+			Params: struct {
+				Name      string    `json:"name"`
+				Arguments any       `json:"arguments,omitempty"`
+				Meta      *mcp.Meta `json:"_meta,omitempty"`
+			}{
+				Name:      "no_meter_tool",
+				Arguments: map[string]interface{}{"message": "world"},
+			},
+		}
+
+		result, err := tool.Handler(ctx, request)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+}
+
 // Helper function to check if an attribute exists with expected value
 func assertHasAttribute(t *testing.T, attributes []attribute.KeyValue, key string, expectedValue string) {
 	for _, attr := range attributes {
@@ -0,0 +1,329 @@
+package mcpgrafana
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported content types for tool results.
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeYAML = "application/yaml"
+	ContentTypeCSV  = "text/csv"
+	ContentTypePNG  = "image/png"
+)
+
+// Transformer runs over a tool's raw handler result before it's
+// marshaled, letting cross-cutting concerns (stripping null fields,
+// redacting datasource credentials, clipping long series) apply to every
+// tool's result uniformly instead of being reimplemented per handler.
+type Transformer func(ctx context.Context, toolName string, v interface{}) (interface{}, error)
+
+// Marshaler encodes a (possibly transformed) tool result as one of its
+// ContentTypes into w.
+type Marshaler interface {
+	ContentTypes() []string
+	Marshal(ctx context.Context, w io.Writer, contentType string, v interface{}) error
+}
+
+// FormatMarshaler lets a tool result type supply its own encoding for a
+// given content type, bypassing a Registry's default Marshalers
+// entirely. This is how a result type adds support for a format the
+// registry doesn't know about, e.g. a rendered panel thumbnail's
+// image/png bytes, without the registry needing to know anything about
+// that tool.
+type FormatMarshaler interface {
+	MarshalFormat(contentType string) ([]byte, error)
+}
+
+// API is the negotiate/transform/marshal contract a tool-conversion
+// layer (ConvertTool/MustTool) uses to pick and render a result
+// representation: Negotiate resolves an Accept-style header value to a
+// supported content type, Transform runs the registered transformer
+// chain over a handler's raw result, and Marshal renders the
+// transformed result as the negotiated content type.
+type API interface {
+	Negotiate(accept string) (string, error)
+	Transform(ctx context.Context, toolName string, v interface{}) (interface{}, error)
+	Marshal(ctx context.Context, w io.Writer, contentType string, v interface{}) error
+}
+
+// Registry is the default API implementation: a set of Marshalers keyed
+// by content type, consulted in registration order during negotiation,
+// plus an ordered chain of Transformers applied before marshaling.
+//
+// NOTE: this file implements the negotiation/transform/marshal pipeline
+// on its own; threading it into ConvertTool/MustTool (so a handler's
+// result is actually run through a Registry before being returned as MCP
+// content) is a change to that file's result-handling path, done
+// separately.
+type Registry struct {
+	order        []string
+	marshalers   map[string]Marshaler
+	transformers []Transformer
+}
+
+// NewRegistry returns a Registry with JSON, YAML, and CSV Marshalers
+// registered, in that preference order, and no transformers.
+func NewRegistry() *Registry {
+	r := &Registry{marshalers: make(map[string]Marshaler)}
+	r.Register(jsonMarshaler{})
+	r.Register(yamlMarshaler{})
+	r.Register(csvMarshaler{})
+	return r
+}
+
+// Register adds m under each of its ContentTypes, appended to the
+// negotiation preference order the first time a content type is seen.
+// Registering a Marshaler for an already-registered content type
+// replaces the existing one without changing its place in that order.
+func (r *Registry) Register(m Marshaler) {
+	for _, ct := range m.ContentTypes() {
+		if _, exists := r.marshalers[ct]; !exists {
+			r.order = append(r.order, ct)
+		}
+		r.marshalers[ct] = m
+	}
+}
+
+// Use appends t to the transformer chain; transformers run in the order
+// they were added.
+func (r *Registry) Use(t Transformer) {
+	r.transformers = append(r.transformers, t)
+}
+
+// Negotiate parses accept as a comma-separated list of content types
+// (e.g. a tool call's _meta.acceptFormats, joined), in the caller's
+// preference order, and returns the first one this Registry supports. An
+// empty accept, or one naming nothing supported, falls back to the
+// Registry's first-registered content type (application/json, for the
+// default Registry).
+func (r *Registry) Negotiate(accept string) (string, error) {
+	for _, want := range strings.Split(accept, ",") {
+		want = strings.TrimSpace(want)
+		if want == "" {
+			continue
+		}
+		if _, ok := r.marshalers[want]; ok {
+			return want, nil
+		}
+	}
+
+	if len(r.order) == 0 {
+		return "", fmt.Errorf("no marshalers registered")
+	}
+	return r.order[0], nil
+}
+
+// Transform runs v through the registered transformer chain in order,
+// returning the final value. A Registry with no transformers returns v
+// unchanged, so existing handlers keep working without opting into any
+// of this.
+func (r *Registry) Transform(ctx context.Context, toolName string, v interface{}) (interface{}, error) {
+	var err error
+	for _, t := range r.transformers {
+		v, err = t(ctx, toolName, v)
+		if err != nil {
+			return nil, fmt.Errorf("transform %s result: %w", toolName, err)
+		}
+	}
+	return v, nil
+}
+
+// Marshal encodes v as contentType into w. If v implements
+// FormatMarshaler, its MarshalFormat takes precedence over this
+// Registry's own Marshaler for contentType.
+func (r *Registry) Marshal(ctx context.Context, w io.Writer, contentType string, v interface{}) error {
+	if fm, ok := v.(FormatMarshaler); ok {
+		data, err := fm.MarshalFormat(contentType)
+		if err != nil {
+			return fmt.Errorf("marshal format %s: %w", contentType, err)
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	m, ok := r.marshalers[contentType]
+	if !ok {
+		return fmt.Errorf("unsupported content type %q", contentType)
+	}
+	return m.Marshal(ctx, w, contentType, v)
+}
+
+// DefaultRegistry is the process-wide Registry a tool-conversion layer
+// would consult by default. Tools register additional Marshalers or
+// Transformers on it at init time via Register/Use.
+var DefaultRegistry = NewRegistry()
+
+type jsonMarshaler struct{}
+
+func (jsonMarshaler) ContentTypes() []string { return []string{ContentTypeJSON} }
+
+func (jsonMarshaler) Marshal(_ context.Context, w io.Writer, _ string, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+type yamlMarshaler struct{}
+
+func (yamlMarshaler) ContentTypes() []string { return []string{ContentTypeYAML} }
+
+func (yamlMarshaler) Marshal(_ context.Context, w io.Writer, _ string, v interface{}) error {
+	enc := yaml.NewEncoder(w)
+	defer enc.Close() //nolint:errcheck
+	return enc.Encode(v)
+}
+
+// TabularResult is implemented by a tool result that can be rendered as
+// CSV: a header row plus one row per record. Prometheus's query_range
+// and similarly tabular results implement this to support text/csv
+// negotiation.
+type TabularResult interface {
+	CSVHeader() []string
+	CSVRows() [][]string
+}
+
+type csvMarshaler struct{}
+
+func (csvMarshaler) ContentTypes() []string { return []string{ContentTypeCSV} }
+
+func (csvMarshaler) Marshal(_ context.Context, w io.Writer, _ string, v interface{}) error {
+	tab, ok := v.(TabularResult)
+	if !ok {
+		return fmt.Errorf("result does not support CSV rendering")
+	}
+
+	cw := csv.NewWriter(w)
+	if header := tab.CSVHeader(); len(header) > 0 {
+		if err := cw.Write(header); err != nil {
+			return fmt.Errorf("write csv header: %w", err)
+		}
+	}
+	for _, row := range tab.CSVRows() {
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("write csv row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// acceptFormatsKey is the context key for the caller's negotiated accept
+// formats.
+type acceptFormatsKey struct{}
+
+// WithAcceptFormats stores the caller's preferred output content types
+// (most-preferred first, e.g. decoded from a tool call's
+// _meta.acceptFormats) in ctx, so a handler or the tool-conversion layer
+// can later negotiate a representation via DefaultRegistry.Negotiate.
+func WithAcceptFormats(ctx context.Context, formats []string) context.Context {
+	return context.WithValue(ctx, acceptFormatsKey{}, formats)
+}
+
+// AcceptFormatsFromContext retrieves the accept formats set by
+// WithAcceptFormats, or nil if none were set.
+func AcceptFormatsFromContext(ctx context.Context) []string {
+	formats, _ := ctx.Value(acceptFormatsKey{}).([]string)
+	return formats
+}
+
+// StripNullFieldsTransformer removes nil map entries from v's JSON
+// representation, recursively, so a result isn't padded with empty
+// fields most clients immediately disregard.
+func StripNullFieldsTransformer(_ context.Context, toolName string, v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s result for null-stripping: %w", toolName, err)
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("unmarshal %s result for null-stripping: %w", toolName, err)
+	}
+
+	return stripNulls(decoded), nil
+}
+
+func stripNulls(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, fieldValue := range val {
+			if fieldValue == nil {
+				continue
+			}
+			out[k] = stripNulls(fieldValue)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = stripNulls(item)
+		}
+		return out
+	default:
+		return val
+	}
+}
+
+// RedactedFields is implemented by a result type that knows which of its
+// own JSON fields carry sensitive datasource credentials (API keys,
+// basic-auth passwords, tokens) that must never reach a client, however
+// the result ends up being rendered.
+type RedactedFields interface {
+	RedactedFields() []string
+}
+
+// RedactCredentialsTransformer replaces every field named by v's
+// RedactedFields with "[redacted]" in its JSON representation. Results
+// that don't implement RedactedFields pass through unchanged.
+func RedactCredentialsTransformer(_ context.Context, toolName string, v interface{}) (interface{}, error) {
+	redacted, ok := v.(RedactedFields)
+	if !ok {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s result for redaction: %w", toolName, err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("unmarshal %s result for redaction: %w", toolName, err)
+	}
+
+	for _, field := range redacted.RedactedFields() {
+		if _, ok := decoded[field]; ok {
+			decoded[field] = "[redacted]"
+		}
+	}
+
+	return decoded, nil
+}
+
+// ClippedSeries is implemented by a result type whose time-series
+// payload can be clipped to a maximum number of data points per series.
+type ClippedSeries interface {
+	ClipSeries(maxPoints int) interface{}
+}
+
+// ClipSeriesTransformer returns a Transformer that clips any result
+// implementing ClippedSeries to maxPoints data points per series, so a
+// large Prometheus/Loki result doesn't blow past a client's context
+// budget. Results that don't implement ClippedSeries pass through
+// unchanged.
+func ClipSeriesTransformer(maxPoints int) Transformer {
+	return func(_ context.Context, _ string, v interface{}) (interface{}, error) {
+		clippable, ok := v.(ClippedSeries)
+		if !ok {
+			return v, nil
+		}
+		return clippable.ClipSeries(maxPoints), nil
+	}
+}
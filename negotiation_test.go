@@ -0,0 +1,170 @@
+package mcpgrafana
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Negotiate(t *testing.T) {
+	r := NewRegistry()
+
+	t.Run("exact match", func(t *testing.T) {
+		ct, err := r.Negotiate("text/csv, application/json")
+		require.NoError(t, err)
+		assert.Equal(t, ContentTypeCSV, ct)
+	})
+
+	t.Run("falls back to first registered type when nothing matches", func(t *testing.T) {
+		ct, err := r.Negotiate("image/gif")
+		require.NoError(t, err)
+		assert.Equal(t, ContentTypeJSON, ct)
+	})
+
+	t.Run("falls back on empty accept", func(t *testing.T) {
+		ct, err := r.Negotiate("")
+		require.NoError(t, err)
+		assert.Equal(t, ContentTypeJSON, ct)
+	})
+}
+
+func TestRegistry_Marshal(t *testing.T) {
+	r := NewRegistry()
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, r.Marshal(context.Background(), &buf, ContentTypeJSON, map[string]string{"a": "b"}))
+		assert.JSONEq(t, `{"a":"b"}`, buf.String())
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, r.Marshal(context.Background(), &buf, ContentTypeYAML, map[string]string{"a": "b"}))
+		assert.Equal(t, "a: b\n", buf.String())
+	})
+
+	t.Run("csv requires a TabularResult", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := r.Marshal(context.Background(), &buf, ContentTypeCSV, map[string]string{"a": "b"})
+		assert.Error(t, err)
+	})
+
+	t.Run("csv renders a TabularResult", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, r.Marshal(context.Background(), &buf, ContentTypeCSV, fakeTable{}))
+		assert.Equal(t, "metric,value\nup,1\n", buf.String())
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := r.Marshal(context.Background(), &buf, "application/xml", map[string]string{"a": "b"})
+		assert.Error(t, err)
+	})
+
+	t.Run("FormatMarshaler overrides the registry's marshaler", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, r.Marshal(context.Background(), &buf, ContentTypeJSON, fakeFormatMarshaler{}))
+		assert.Equal(t, "custom bytes", buf.String())
+	})
+}
+
+type fakeTable struct{}
+
+func (fakeTable) CSVHeader() []string { return []string{"metric", "value"} }
+func (fakeTable) CSVRows() [][]string { return [][]string{{"up", "1"}} }
+
+type fakeFormatMarshaler struct{}
+
+func (fakeFormatMarshaler) MarshalFormat(contentType string) ([]byte, error) {
+	return []byte("custom bytes"), nil
+}
+
+func TestRegistry_Transform(t *testing.T) {
+	t.Run("no transformers is a no-op", func(t *testing.T) {
+		r := NewRegistry()
+		v, err := r.Transform(context.Background(), "my_tool", map[string]string{"a": "b"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "b"}, v)
+	})
+
+	t.Run("transformers run in registration order", func(t *testing.T) {
+		r := NewRegistry()
+		var order []string
+		r.Use(func(_ context.Context, _ string, v interface{}) (interface{}, error) {
+			order = append(order, "first")
+			return v, nil
+		})
+		r.Use(func(_ context.Context, _ string, v interface{}) (interface{}, error) {
+			order = append(order, "second")
+			return v, nil
+		})
+
+		_, err := r.Transform(context.Background(), "my_tool", "value")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, order)
+	})
+}
+
+func TestStripNullFieldsTransformer(t *testing.T) {
+	in := map[string]interface{}{"a": "b", "c": nil, "nested": map[string]interface{}{"d": nil, "e": "f"}}
+	out, err := StripNullFieldsTransformer(context.Background(), "my_tool", in)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "b", "nested": map[string]interface{}{"e": "f"}}, out)
+}
+
+type fakeCredentialResult struct {
+	APIKey string `json:"apiKey"`
+	Name   string `json:"name"`
+}
+
+func (fakeCredentialResult) RedactedFields() []string { return []string{"apiKey"} }
+
+func TestRedactCredentialsTransformer(t *testing.T) {
+	t.Run("redacts named fields", func(t *testing.T) {
+		out, err := RedactCredentialsTransformer(context.Background(), "my_tool", fakeCredentialResult{APIKey: "secret", Name: "ds1"})
+		require.NoError(t, err)
+		m, ok := out.(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "[redacted]", m["apiKey"])
+		assert.Equal(t, "ds1", m["name"])
+	})
+
+	t.Run("passes through results without RedactedFields", func(t *testing.T) {
+		out, err := RedactCredentialsTransformer(context.Background(), "my_tool", map[string]string{"a": "b"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "b"}, out)
+	})
+}
+
+type fakeSeriesResult struct {
+	clipped int
+}
+
+func (f fakeSeriesResult) ClipSeries(maxPoints int) interface{} {
+	return fakeSeriesResult{clipped: maxPoints}
+}
+
+func TestClipSeriesTransformer(t *testing.T) {
+	t.Run("clips a ClippedSeries result", func(t *testing.T) {
+		transform := ClipSeriesTransformer(100)
+		out, err := transform(context.Background(), "my_tool", fakeSeriesResult{})
+		require.NoError(t, err)
+		assert.Equal(t, fakeSeriesResult{clipped: 100}, out)
+	})
+
+	t.Run("passes through results without ClippedSeries", func(t *testing.T) {
+		transform := ClipSeriesTransformer(100)
+		out, err := transform(context.Background(), "my_tool", "value")
+		require.NoError(t, err)
+		assert.Equal(t, "value", out)
+	})
+}
+
+func TestAcceptFormatsContext(t *testing.T) {
+	ctx := WithAcceptFormats(context.Background(), []string{ContentTypeCSV, ContentTypeJSON})
+	assert.Equal(t, []string{ContentTypeCSV, ContentTypeJSON}, AcceptFormatsFromContext(ctx))
+	assert.Nil(t, AcceptFormatsFromContext(context.Background()))
+}
@@ -0,0 +1,62 @@
+package mcpgrafana
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProgressEmitter is the "emit" function a streaming tool handler calls
+// with each partial result it produces, one call per increment of
+// progress (e.g. one call per page of a paginated Grafana API).
+//
+// NOTE: this is designed to extend ConvertTool's handler detection with
+// a second supported shape,
+// func(ctx context.Context, params P, emit ProgressEmitter) (R, error),
+// alongside its existing string/*string/struct/*struct-returning
+// handlers: when registered, ConvertTool would wire emit to MCP
+// notifications/progress using the progressToken on the request's
+// _meta, then emit the handler's final return value as the tool result
+// once it returns. ConvertTool isn't present in this checkout, so this
+// lands as additive groundwork for that handler shape rather than a
+// change to ConvertTool's (absent) reflection-based dispatch.
+type ProgressEmitter func(partial any) error
+
+// StreamingHandler is the second handler shape ConvertTool would detect
+// via reflection, for tools (query_range, search_dashboards, Loki
+// queries, ...) whose result is naturally produced incrementally and
+// shouldn't have to buffer in full before the caller sees anything.
+type StreamingHandler[P any, R any] func(ctx context.Context, params P, emit ProgressEmitter) (R, error)
+
+// Paginate drives the common case of paging through a Grafana search API:
+// it repeatedly calls page with the current cursor, emits each page's
+// items via emit as they arrive, and accumulates them into the returned
+// slice. page returns the next cursor; an empty next cursor ends
+// pagination. Paginate stops early, returning ctx.Err(), if ctx is
+// cancelled between pages.
+func Paginate[T any](ctx context.Context, emit ProgressEmitter, page func(cursor string) ([]T, string, error)) ([]T, error) {
+	var all []T
+	cursor := ""
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return all, err
+		}
+
+		items, next, err := page(cursor)
+		if err != nil {
+			return all, fmt.Errorf("fetch page: %w", err)
+		}
+
+		all = append(all, items...)
+		if emit != nil {
+			if err := emit(items); err != nil {
+				return all, fmt.Errorf("emit page: %w", err)
+			}
+		}
+
+		if next == "" {
+			return all, nil
+		}
+		cursor = next
+	}
+}
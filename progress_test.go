@@ -0,0 +1,75 @@
+package mcpgrafana
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate_EmitsPagesInOrderAndAccumulates(t *testing.T) {
+	pages := [][]string{{"a", "b"}, {"c"}, {"d", "e"}}
+	cursors := []string{"page-2", "page-3", ""}
+
+	var emitted [][]string
+	emit := func(partial any) error {
+		emitted = append(emitted, partial.([]string))
+		return nil
+	}
+
+	call := 0
+	page := func(cursor string) ([]string, string, error) {
+		items := pages[call]
+		next := cursors[call]
+		call++
+		return items, next, nil
+	}
+
+	all, err := Paginate(context.Background(), emit, page)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, all)
+	assert.Equal(t, pages, emitted)
+}
+
+func TestPaginate_StopsOnPageError(t *testing.T) {
+	page := func(cursor string) ([]string, string, error) {
+		return nil, "", errors.New("boom")
+	}
+
+	_, err := Paginate[string](context.Background(), nil, page)
+	assert.ErrorContains(t, err, "boom")
+}
+
+func TestPaginate_StopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	call := 0
+	page := func(cursor string) ([]string, string, error) {
+		call++
+		if call == 2 {
+			cancel()
+		}
+		return []string{cursor}, "next", nil
+	}
+
+	all, err := Paginate(ctx, nil, page)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, all, 2, "should have accumulated exactly the pages fetched before cancellation was observed")
+}
+
+func TestPaginate_EmitErrorStopsPagination(t *testing.T) {
+	calls := 0
+	page := func(cursor string) ([]string, string, error) {
+		calls++
+		return []string{"x"}, "next", nil
+	}
+	emit := func(partial any) error {
+		return errors.New("emit failed")
+	}
+
+	_, err := Paginate(context.Background(), emit, page)
+	assert.ErrorContains(t, err, "emit failed")
+	assert.Equal(t, 1, calls, "should not fetch a second page once emit fails")
+}
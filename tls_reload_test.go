@@ -0,0 +1,118 @@
+package mcpgrafana
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert generates a self-signed cert/key pair for commonName
+// and writes them to certPath/keyPath.
+func writeSelfSignedCert(t *testing.T, certPath, keyPath, commonName string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certOut, err := os.Create(certPath)
+	require.NoError(t, err)
+	require.NoError(t, pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	require.NoError(t, certOut.Close())
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	keyOut, err := os.Create(keyPath)
+	require.NoError(t, err)
+	require.NoError(t, pem.Encode(keyOut, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}))
+	require.NoError(t, keyOut.Close())
+}
+
+func TestCertCache_ReloadsChangedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+
+	writeSelfSignedCert(t, certPath, keyPath, "first")
+
+	tlsConfig := &TLSConfig{CertFile: certPath, KeyFile: keyPath}
+
+	cfg, err := tlsConfig.CreateTLSConfig()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.GetClientCertificate)
+
+	first, err := cfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	firstLeaf, err := x509.ParseCertificate(first.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, "first", firstLeaf.Subject.CommonName)
+
+	// Overwrite the cert/key on disk with a different identity. Sleep a tick
+	// so the filesystem reports a distinct mtime on platforms with coarse
+	// mtime resolution.
+	time.Sleep(10 * time.Millisecond)
+	writeSelfSignedCert(t, certPath, keyPath, "second")
+
+	second, err := cfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	secondLeaf, err := x509.ParseCertificate(second.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, "second", secondLeaf.Subject.CommonName, "expected GetClientCertificate to pick up the rotated certificate")
+}
+
+func TestCertCache_RoundTripUsesRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+
+	writeSelfSignedCert(t, certPath, keyPath, "before-rotation")
+
+	tlsConfig := &TLSConfig{CertFile: certPath, KeyFile: keyPath}
+	transport, err := tlsConfig.HTTPTransport(&http.Transport{})
+	require.NoError(t, err)
+
+	httpTransport := transport.(*http.Transport)
+	require.NotNil(t, httpTransport.TLSClientConfig.GetClientCertificate)
+
+	commonName := func() string {
+		cert, err := httpTransport.TLSClientConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+
+	require.Equal(t, "before-rotation", commonName())
+
+	time.Sleep(10 * time.Millisecond)
+	writeSelfSignedCert(t, certPath, keyPath, "after-rotation")
+
+	// The same *http.Transport (no rebuild) must now present the new cert.
+	require.Equal(t, "after-rotation", commonName())
+}
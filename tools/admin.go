@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 
@@ -15,7 +16,9 @@ type ListTeamsParams struct {
 	Query string `json:"query" jsonschema:"description=The query to search for teams. Can be left empty to fetch all teams"`
 }
 
-func listTeams(ctx context.Context, args ListTeamsParams) (*models.SearchTeamQueryResult, error) {
+func listTeams(ctx context.Context, args ListTeamsParams) (result *models.SearchTeamQueryResult, err error) {
+	defer func(start time.Time) { RecordToolMetrics("list_teams", time.Since(start), err) }(time.Now())
+
 	c := mcpgrafana.GrafanaClientFromContext(ctx)
 	params := teams.NewSearchTeamsParamsWithContext(ctx)
 	if args.Query != "" {
@@ -36,7 +39,9 @@ var ListTeams = mcpgrafana.MustTool(
 
 type ListUsersByOrgParams struct{}
 
-func listUsersByOrg(ctx context.Context, args ListUsersByOrgParams) ([]*models.OrgUserDTO, error) {
+func listUsersByOrg(ctx context.Context, args ListUsersByOrgParams) (result []*models.OrgUserDTO, err error) {
+	defer func(start time.Time) { RecordToolMetrics("list_users_by_org", time.Since(start), err) }(time.Now())
+
 	c := mcpgrafana.GrafanaClientFromContext(ctx)
 
 	search, err := c.Org.GetOrgUsersForCurrentOrg()
@@ -52,7 +57,34 @@ var ListUsersByOrg = mcpgrafana.MustTool(
 	listUsersByOrg,
 )
 
-func AddAdminTools(mcp *server.MCPServer) {
-	ListTeams.Register(mcp)
-	ListUsersByOrg.Register(mcp)
+type TriggerDiscoveryParams struct{}
+
+// triggerDiscovery reconciles every registered proxy datasource type
+// against Grafana's current datasource list immediately, rather than
+// waiting out each type's next polling tick. Useful right after an
+// operator adds or removes a datasource and wants the proxied tools to
+// pick it up without restarting the server.
+func triggerDiscovery(ctx context.Context, args TriggerDiscoveryParams) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("trigger_discovery", time.Since(start), err) }(time.Now())
+
+	if err := TriggerDiscovery(ctx); err != nil {
+		return "", fmt.Errorf("trigger discovery: %w", err)
+	}
+	return "discovery triggered", nil
+}
+
+var TriggerDiscoveryTool = mcpgrafana.MustTool(
+	"trigger_discovery",
+	"Immediately re-discover datasources for every registered proxy datasource type (e.g. Tempo), instead of waiting for the next scheduled poll. Use this after adding or removing a datasource in Grafana.",
+	triggerDiscovery,
+)
+
+// AddAdminTools registers the admin tools with mcp, gating each on allowed
+// the same way the other AddXxxTools functions do: a tool registers only if
+// its declared ToolCapabilities are a subset of allowed.
+func AddAdminTools(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	admin := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryAdmin}
+	mcpgrafana.RegisterTool(mcp, allowed, admin, ListTeams)
+	mcpgrafana.RegisterTool(mcp, allowed, admin, ListUsersByOrg)
+	mcpgrafana.RegisterTool(mcp, allowed, admin, TriggerDiscoveryTool)
 }
@@ -49,15 +49,19 @@ type Health struct {
 // AlloyComponentRequest represents a request for component details
 type AlloyComponentRequest struct {
 	ComponentID string `json:"component_id" jsonschema:"required,description=The ID of the component to get details for"`
+	Format      string `json:"format,omitempty" jsonschema:"description=Output format: text (default) or json"`
 }
 
 // AlloyPipelineRequest represents a request for pipeline analysis
 type AlloyPipelineRequest struct {
 	PipelineType string `json:"pipeline_type" jsonschema:"required,description=The type of pipeline to analyze (loki, prometheus, otel)"`
+	Format       string `json:"format,omitempty" jsonschema:"description=Output format: text (default), json, or ndjson (one JSON object per component, including its connections and health)"`
 }
 
-// EmptyParams represents an empty parameter struct for tools that don't need input
-type EmptyParams struct{}
+// EmptyParams represents an empty parameter struct for tools that don't need input, beyond an optional output format
+type EmptyParams struct {
+	Format string `json:"format,omitempty" jsonschema:"description=Output format: text (default), json, or ndjson (one JSON object per line, for large component counts)"`
+}
 
 // AlloyVersion represents version information from metrics
 type AlloyVersion struct {
@@ -76,8 +80,8 @@ type AlloyDocsRequest struct {
 var ListAlloyComponents = mcpgrafana.MustTool[EmptyParams, string](
 	"alloy_list_components",
 	"List all components in the Alloy instance",
-	func(ctx context.Context, _ EmptyParams) (string, error) {
-		return MCPListAlloyComponents(ctx)
+	func(ctx context.Context, req EmptyParams) (string, error) {
+		return MCPListAlloyComponents(ctx, req.Format)
 	},
 )
 
@@ -87,7 +91,7 @@ var GetAlloyComponentDetails = mcpgrafana.MustTool[AlloyComponentRequest, string
 	"Get detailed information about a specific component",
 	func(ctx context.Context, req AlloyComponentRequest) (string, error) {
 		slog.DebugContext(ctx, "Entering GetAlloyComponentDetails tool handler lambda", "component_id", req.ComponentID)
-		result, err := MCPGetAlloyComponentDetails(ctx, req.ComponentID)
+		result, err := MCPGetAlloyComponentDetails(ctx, req.ComponentID, req.Format)
 		if err != nil {
 			slog.ErrorContext(ctx, "Error executing MCPGetAlloyComponentDetails within lambda", "error", err, "component_id", req.ComponentID)
 		} else {
@@ -102,7 +106,7 @@ var AnalyzeAlloyPipeline = mcpgrafana.MustTool[AlloyPipelineRequest, string](
 	"alloy_analyze_pipeline",
 	"Analyze components of a specific type (loki, prometheus, otel)",
 	func(ctx context.Context, req AlloyPipelineRequest) (string, error) {
-		return MCPAnalyzeAlloyPipeline(ctx, req.PipelineType)
+		return MCPAnalyzeAlloyPipeline(ctx, req.PipelineType, req.Format)
 	},
 )
 
@@ -110,8 +114,8 @@ var AnalyzeAlloyPipeline = mcpgrafana.MustTool[AlloyPipelineRequest, string](
 var GetAlloyComponentHealth = mcpgrafana.MustTool[EmptyParams, string](
 	"alloy_get_health",
 	"Get health status of all components",
-	func(ctx context.Context, _ EmptyParams) (string, error) {
-		return MCPGetAlloyComponentHealth(ctx)
+	func(ctx context.Context, req EmptyParams) (string, error) {
+		return MCPGetAlloyComponentHealth(ctx, req.Format)
 	},
 )
 
@@ -188,6 +192,12 @@ func MCPGetAlloyComponentDocs(ctx context.Context, req AlloyDocsRequest) (string
 	componentType := parts[0]
 	componentFileName := component.Name // Use the full name like discovery.relabel
 
+	if cached, ok := cachedComponentDoc(version, componentFileName); ok {
+		slog.DebugContext(ctx, "Serving component docs from local cache", "component", componentFileName, "version", version.Version)
+		return fmt.Sprintf("## Documentation for %s (Alloy %s)\n\nSource: local cache (%s)\n\n---\n\n%s",
+			component.Name, version.Version, alloyDocsVersionDir(version), cached), nil
+	}
+
 	// Construct the URL to the raw markdown file on GitHub for the specific release branch
 	// Example: https://raw.githubusercontent.com/grafana/alloy/release/v1.6/docs/sources/reference/components/discovery/discovery.relabel.md
 	githubURL := fmt.Sprintf("https://raw.githubusercontent.com/grafana/alloy/release/v%s.%s/docs/sources/reference/components/%s/%s.md",
@@ -240,6 +250,8 @@ func MCPGetAlloyComponentDocs(ctx context.Context, req AlloyDocsRequest) (string
 	markdownContent := string(markdownBytes)
 	slog.DebugContext(ctx, "Successfully fetched and read markdown content", "component", component.Name, "chars", len(markdownContent))
 
+	cacheComponentDoc(version, componentFileName, markdownContent)
+
 	// Optional: Could add some basic formatting or indicate source?
 	result := fmt.Sprintf("## Documentation for %s (Alloy %s)\n\nSource: %s\n\n---\n\n%s",
 		component.Name, version.Version, githubURL, markdownContent)
@@ -269,13 +281,25 @@ var GetAlloyComponentDocs = mcpgrafana.MustTool[AlloyDocsRequest, string](
 	MCPGetAlloyComponentDocs,
 )
 
-// AddAlloyTools registers all Alloy tools with the MCP server
-func AddAlloyTools(mcp *server.MCPServer) {
-	ListAlloyComponents.Register(mcp)
-	GetAlloyComponentDetails.Register(mcp)
-	AnalyzeAlloyPipeline.Register(mcp)
-	GetAlloyComponentHealth.Register(mcp)
-	GetAlloyComponentDocs.Register(mcp)
+// AddAlloyTools registers all Alloy tools with the MCP server, gating each
+// on allowed the same way the other AddXxxTools functions do.
+func AddAlloyTools(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	write := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryWrite}
+
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListAlloyComponents)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetAlloyComponentDetails)
+	mcpgrafana.RegisterTool(mcp, allowed, read, AnalyzeAlloyPipeline)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetAlloyComponentHealth)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetAlloyComponentDocs)
+	mcpgrafana.RegisterTool(mcp, allowed, read, AlloyStreamComponentDebug)
+	mcpgrafana.RegisterTool(mcp, allowed, write, AlloyEnableLiveDebugging)
+	mcpgrafana.RegisterTool(mcp, allowed, read, AlloyGetCPUProfile)
+	mcpgrafana.RegisterTool(mcp, allowed, read, AlloyGetHeapProfile)
+	mcpgrafana.RegisterTool(mcp, allowed, read, AlloyGetGoroutineDump)
+	mcpgrafana.RegisterTool(mcp, allowed, read, AlloySearchDocs)
+	mcpgrafana.RegisterTool(mcp, allowed, write, AlloyGeneratePipelineDashboard)
+	mcpgrafana.RegisterTool(mcp, allowed, read, AlloyExportPipelineGraph)
 }
 
 // GetAlloyComponentDetails gets detailed information about a specific component
@@ -395,14 +419,48 @@ func analyzeHealth(components []AlloyComponent) map[string]string {
 
 // MCP Tool Functions
 
-func MCPListAlloyComponents(ctx context.Context) (string, error) {
+// marshalAlloyJSON renders v as an indented JSON document, for the "json"
+// output format shared across the Alloy tools.
+func marshalAlloyJSON(v interface{}) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling JSON: %w", err)
+	}
+	return string(b), nil
+}
+
+// marshalAlloyNDJSON renders items as newline-delimited JSON, one object
+// per line, so a large fleet doesn't need to be held in memory as one
+// giant JSON array.
+func marshalAlloyNDJSON[T any](items []T) (string, error) {
+	var b strings.Builder
+	for _, item := range items {
+		line, err := json.Marshal(item)
+		if err != nil {
+			return "", fmt.Errorf("marshaling NDJSON line: %w", err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+func MCPListAlloyComponents(ctx context.Context, format string) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("list_alloy_components", time.Since(start), err) }(time.Now())
+
 	components, err := ListAlloyComponentsFunc(ctx)
 	if err != nil {
 		return "", err
 	}
 
+	switch format {
+	case "json":
+		return marshalAlloyJSON(components)
+	case "ndjson":
+		return marshalAlloyNDJSON(components)
+	}
+
 	// Format the output in a way that's useful for an LLM
-	var result string
 	result = "Alloy Components:\n\n"
 
 	for _, c := range components {
@@ -422,7 +480,9 @@ func MCPListAlloyComponents(ctx context.Context) (string, error) {
 	return result, nil
 }
 
-func MCPGetAlloyComponentDetails(ctx context.Context, componentID string) (string, error) {
+func MCPGetAlloyComponentDetails(ctx context.Context, componentID string, format string) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("get_alloy_component_details", time.Since(start), err) }(time.Now())
+
 	slog.DebugContext(ctx, "MCPGetAlloyComponentDetails called", "component_id", componentID)
 	component, err := GetAlloyComponentDetailsFunc(ctx, componentID)
 	if err != nil {
@@ -430,10 +490,14 @@ func MCPGetAlloyComponentDetails(ctx context.Context, componentID string) (strin
 		return "", err
 	}
 
+	if format == "json" || format == "ndjson" {
+		return marshalAlloyJSON(component)
+	}
+
 	slog.DebugContext(ctx, "Formatting component details", "component_id", componentID)
 
 	// Format the detailed output
-	result := fmt.Sprintf("Details for Component %s:\n\n", componentID)
+	result = fmt.Sprintf("Details for Component %s:\n\n", componentID)
 	result += fmt.Sprintf("Name: %s\n", component.Name)
 	result += fmt.Sprintf("Type: %s\n", component.Type)
 	result += fmt.Sprintf("Label: %s\n", component.Label)
@@ -476,14 +540,44 @@ func MCPGetAlloyComponentDetails(ctx context.Context, componentID string) (strin
 	return result, nil
 }
 
-func MCPAnalyzeAlloyPipeline(ctx context.Context, pipelineType string) (string, error) {
+// alloyPipelineComponentLine is one ndjson line of an alloy_analyze_pipeline
+// result: a component together with the connections and health views
+// AnalyzeAlloyPipelineFunc derives from it.
+type alloyPipelineComponentLine struct {
+	Component   AlloyComponent `json:"component"`
+	Connections []string       `json:"connections"`
+	Health      string         `json:"health"`
+}
+
+func MCPAnalyzeAlloyPipeline(ctx context.Context, pipelineType string, format string) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("analyze_alloy_pipeline", time.Since(start), err) }(time.Now())
+
 	analysis, err := AnalyzeAlloyPipelineFunc(ctx, pipelineType)
 	if err != nil {
 		return "", err
 	}
 
+	if format == "json" {
+		return marshalAlloyJSON(analysis)
+	}
+	if format == "ndjson" {
+		components, _ := analysis["components"].([]AlloyComponent)
+		connections, _ := analysis["connections"].(map[string][]string)
+		health, _ := analysis["health"].(map[string]string)
+
+		lines := make([]alloyPipelineComponentLine, 0, len(components))
+		for _, c := range components {
+			lines = append(lines, alloyPipelineComponentLine{
+				Component:   c,
+				Connections: connections[c.LocalID],
+				Health:      health[c.LocalID],
+			})
+		}
+		return marshalAlloyNDJSON(lines)
+	}
+
 	// Format the analysis in a way that's useful for understanding the pipeline
-	result := fmt.Sprintf("Analysis of %s Pipeline:\n\n", pipelineType)
+	result = fmt.Sprintf("Analysis of %s Pipeline:\n\n", pipelineType)
 
 	// Add components
 	if components, ok := analysis["components"].([]AlloyComponent); ok {
@@ -515,14 +609,33 @@ func MCPAnalyzeAlloyPipeline(ctx context.Context, pipelineType string) (string,
 	return result, nil
 }
 
-func MCPGetAlloyComponentHealth(ctx context.Context) (string, error) {
+// alloyComponentHealthLine is one ndjson line of an alloy_get_health result.
+type alloyComponentHealthLine struct {
+	ComponentID string `json:"component_id"`
+	Health      Health `json:"health"`
+}
+
+func MCPGetAlloyComponentHealth(ctx context.Context, format string) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("get_alloy_component_health", time.Since(start), err) }(time.Now())
+
 	health, err := GetAlloyComponentHealthFunc(ctx)
 	if err != nil {
 		return "", err
 	}
 
+	if format == "json" {
+		return marshalAlloyJSON(health)
+	}
+	if format == "ndjson" {
+		lines := make([]alloyComponentHealthLine, 0, len(health))
+		for id, h := range health {
+			lines = append(lines, alloyComponentHealthLine{ComponentID: id, Health: h})
+		}
+		return marshalAlloyNDJSON(lines)
+	}
+
 	// Format the health information
-	result := "Component Health Status:\n\n"
+	result = "Component Health Status:\n\n"
 
 	for id, h := range health {
 		result += fmt.Sprintf("Component: %s\n", id)
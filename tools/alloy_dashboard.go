@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// componentMetricPatterns maps a component name prefix to the Prometheus
+// metric most representative of its throughput, used to pick the
+// timeseries panel's query for alloy_generate_pipeline_dashboard. Keys are
+// matched longest-prefix-first against AlloyComponent.Name.
+var componentMetricPatterns = map[string]string{
+	"prometheus.remote_write": "prometheus_remote_write_samples_total",
+	"prometheus.scrape":       "prometheus_target_interval_length_seconds_sum",
+	"loki.write":              "loki_write_sent_entries_total",
+	"loki.source":             "loki_source_file_read_bytes_total",
+	"otelcol.receiver":        "otelcol_receiver_accepted_spans",
+	"otelcol.exporter":        "otelcol_exporter_sent_spans",
+	"otelcol.processor":       "otelcol_processor_accepted_spans",
+	"discovery":               "discovery_file_targets",
+}
+
+// defaultComponentMetric is used for any component type not covered by
+// componentMetricPatterns.
+const defaultComponentMetric = "alloy_component_evaluation_seconds_sum"
+
+// componentThroughputMetric returns the metric most representative of
+// component's throughput, falling back to a generic evaluation-time metric
+// for component types we don't have a specific mapping for.
+func componentThroughputMetric(component AlloyComponent) string {
+	var best string
+	for prefix := range componentMetricPatterns {
+		if strings.HasPrefix(component.Name, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return defaultComponentMetric
+	}
+	return componentMetricPatterns[best]
+}
+
+// AlloyGeneratePipelineDashboardRequest represents a request to build a
+// Grafana dashboard visualizing a running Alloy pipeline.
+type AlloyGeneratePipelineDashboardRequest struct {
+	PipelineType  string `json:"pipeline_type" jsonschema:"required,description=The type of pipeline to visualize (loki, prometheus, otel)"`
+	DatasourceUID string `json:"datasource_uid,omitempty" jsonschema:"description=UID of the Prometheus datasource Alloy's own metrics are scraped into. If omitted\\, panels are left with the default datasource"`
+	FolderUID     string `json:"folder_uid,omitempty" jsonschema:"description=If set\\, save the generated dashboard into this folder instead of just returning its JSON"`
+	Message       string `json:"message,omitempty" jsonschema:"description=Commit message for the version history\\, used only when folder_uid is set"`
+}
+
+// panelDatasourceRef builds the panel "datasource" field in the {type, uid}
+// object form validate_dashboard understands, or nil to leave it as the
+// dashboard's default.
+func panelDatasourceRef(datasourceUID string) interface{} {
+	if datasourceUID == "" {
+		return nil
+	}
+	return map[string]interface{}{"type": "prometheus", "uid": datasourceUID}
+}
+
+// buildComponentRowPanels builds the row panel plus the three panels
+// (running-components stat, throughput timeseries, health state-timeline)
+// for a single Alloy component, starting at grid row y and panel id id.
+func buildComponentRowPanels(component AlloyComponent, datasourceUID string, id, y int) ([]map[string]interface{}, int, int) {
+	ds := panelDatasourceRef(datasourceUID)
+	componentIDFilter := fmt.Sprintf(`component_id="%s"`, component.LocalID)
+
+	row := map[string]interface{}{
+		"id":        id,
+		"type":      "row",
+		"title":     fmt.Sprintf("%s (%s)", component.LocalID, component.Health.State),
+		"collapsed": false,
+		"gridPos":   map[string]interface{}{"h": 1, "w": 24, "x": 0, "y": y},
+	}
+	id++
+	y++
+
+	statPanel := map[string]interface{}{
+		"id":         id,
+		"type":       "stat",
+		"title":      "Running",
+		"datasource": ds,
+		"gridPos":    map[string]interface{}{"h": 8, "w": 8, "x": 0, "y": y},
+		"targets": []interface{}{
+			map[string]interface{}{
+				"expr":         fmt.Sprintf("alloy_component_controller_running_components{%s}", componentIDFilter),
+				"legendFormat": component.LocalID,
+				"refId":        "A",
+			},
+		},
+	}
+	id++
+
+	metric := componentThroughputMetric(component)
+	throughputPanel := map[string]interface{}{
+		"id":         id,
+		"type":       "timeseries",
+		"title":      fmt.Sprintf("Throughput (%s)", metric),
+		"datasource": ds,
+		"gridPos":    map[string]interface{}{"h": 8, "w": 8, "x": 8, "y": y},
+		"targets": []interface{}{
+			map[string]interface{}{
+				"expr":         fmt.Sprintf("rate(%s{%s}[5m])", metric, componentIDFilter),
+				"legendFormat": component.LocalID,
+				"refId":        "A",
+			},
+		},
+	}
+	id++
+
+	healthPanel := map[string]interface{}{
+		"id":          id,
+		"type":        "state-timeline",
+		"title":       "Health",
+		"description": fmt.Sprintf("Current state at generation time: %s (%s)", component.Health.State, component.Health.Message),
+		"datasource":  ds,
+		"gridPos":     map[string]interface{}{"h": 8, "w": 8, "x": 16, "y": y},
+		"targets": []interface{}{
+			map[string]interface{}{
+				"expr":         fmt.Sprintf("alloy_component_controller_running_components{%s}", componentIDFilter),
+				"legendFormat": component.Health.State,
+				"refId":        "A",
+			},
+		},
+	}
+	id++
+	y += 8
+
+	return []map[string]interface{}{row, statPanel, throughputPanel, healthPanel}, id, y
+}
+
+// buildPipelineDashboardModel turns an Alloy pipeline analysis into a
+// Grafana dashboard JSON model, one row per component, ordered by
+// component ID for a stable, readable layout.
+func buildPipelineDashboardModel(pipelineType string, components []AlloyComponent, datasourceUID string) map[string]interface{} {
+	sorted := make([]AlloyComponent, len(components))
+	copy(sorted, components)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].LocalID < sorted[j].LocalID })
+
+	var panels []interface{}
+	id, y := 1, 0
+	for _, component := range sorted {
+		rowPanels, nextID, nextY := buildComponentRowPanels(component, datasourceUID, id, y)
+		for _, p := range rowPanels {
+			panels = append(panels, p)
+		}
+		id, y = nextID, nextY
+	}
+
+	return map[string]interface{}{
+		"title":         fmt.Sprintf("Alloy %s pipeline", pipelineType),
+		"tags":          []interface{}{"alloy", "generated", pipelineType},
+		"schemaVersion": targetSchemaVersion,
+		"panels":        panels,
+	}
+}
+
+func alloyGeneratePipelineDashboard(ctx context.Context, req AlloyGeneratePipelineDashboardRequest) (string, error) {
+	analysis, err := AnalyzeAlloyPipelineFunc(ctx, req.PipelineType)
+	if err != nil {
+		return "", fmt.Errorf("analyzing pipeline: %w", err)
+	}
+
+	components, _ := analysis["components"].([]AlloyComponent)
+	if len(components) == 0 {
+		return fmt.Sprintf("No %s components found; nothing to generate a dashboard for.", req.PipelineType), nil
+	}
+
+	dashboard := buildPipelineDashboardModel(req.PipelineType, components, req.DatasourceUID)
+
+	if req.FolderUID == "" {
+		indented, err := json.MarshalIndent(dashboard, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("format dashboard: %w", err)
+		}
+		return fmt.Sprintf("Generated dashboard JSON for %d component(s) (not saved; set folder_uid to save it):\n\n%s", len(components), indented), nil
+	}
+
+	result, err := updateDashboard(ctx, UpdateDashboardParams{
+		Dashboard: dashboard,
+		FolderUID: req.FolderUID,
+		Message:   req.Message,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Dashboard created for %d component(s):\n- UID: %s\n- URL: %s", len(components), *result.UID, *result.URL), nil
+}
+
+// AlloyGeneratePipelineDashboard is a tool for generating a Grafana
+// dashboard from a running Alloy pipeline's component graph.
+var AlloyGeneratePipelineDashboard = mcpgrafana.MustTool(
+	"alloy_generate_pipeline_dashboard",
+	`Generate a Grafana dashboard visualizing every component of an Alloy pipeline (loki, prometheus, or otel), one row per component with:
+- a stat panel for alloy_component_controller_running_components filtered by component_id
+- a timeseries panel for the component's throughput metric (e.g. prometheus_remote_write_samples_total for prometheus.remote_write)
+- a state-timeline panel noting the component's health at generation time
+
+Without folder_uid, returns the dashboard JSON for the caller to import manually. With folder_uid set, saves it into that folder so it shows up in search_dashboards like any other dashboard.`,
+	alloyGeneratePipelineDashboard,
+	mcp.WithTitleAnnotation("Generate Alloy pipeline dashboard"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
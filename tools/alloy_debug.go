@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+const (
+	defaultDebugStreamDuration  = 5 * time.Second
+	maxDebugStreamDuration      = 60 * time.Second
+	defaultDebugMaxMessages     = 50
+	maxDebugMessageContentBytes = 4096
+)
+
+// AlloyDebugFrame is a single frame read off a component's live debugging
+// stream.
+type AlloyDebugFrame struct {
+	Timestamp time.Time `json:"timestamp"`
+	Size      int       `json:"size"`
+	Content   string    `json:"content"`
+	Truncated bool      `json:"truncated"`
+}
+
+// AlloyStreamComponentDebugRequest represents a request to stream a
+// component's live debugging output for a bounded amount of time.
+type AlloyStreamComponentDebugRequest struct {
+	ComponentID     string `json:"component_id" jsonschema:"required,description=The ID of the component to stream debug data for"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" jsonschema:"description=How long to stay connected to the debug stream\\, in seconds. Defaults to 5\\, capped at 60"`
+	MaxMessages     int    `json:"max_messages,omitempty" jsonschema:"description=Stop early once this many debug frames have been read. Defaults to 50"`
+}
+
+// AlloyEnableLiveDebuggingRequest represents a request to toggle a
+// component's liveDebuggingEnabled flag, the same way the Alloy UI does
+// before opening its debug stream view.
+type AlloyEnableLiveDebuggingRequest struct {
+	ComponentID string `json:"component_id" jsonschema:"required,description=The ID of the component to toggle live debugging for"`
+	Enabled     bool   `json:"enabled" jsonschema:"required,description=Whether live debugging should be enabled or disabled"`
+}
+
+// StreamAlloyComponentDebugFunc opens Alloy's live debugging stream for a
+// component and collects frames until the duration elapses or maxMessages
+// frames have been read, whichever comes first.
+func StreamAlloyComponentDebugFunc(ctx context.Context, componentID string, duration time.Duration, maxMessages int) ([]AlloyDebugFrame, error) {
+	host := os.Getenv(alloyHostEnvVar)
+	if host == "" {
+		host = defaultAlloyHost
+	}
+	baseURL := fmt.Sprintf("http://%s", host)
+	url := fmt.Sprintf("%s/api/v0/web/debug/%s", baseURL, componentID)
+
+	streamCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(streamCtx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection refused") {
+			return nil, fmt.Errorf("connection refused: Alloy service appears to be down or not running on %s. Please ensure the service is running and try again", baseURL)
+		}
+		// A deadline exceeded error just means we collected data for the
+		// full requested duration; that's the normal way this stream ends.
+		if streamCtx.Err() != nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening debug stream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var frames []AlloyDebugFrame
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		content := string(line)
+		truncated := false
+		if len(content) > maxDebugMessageContentBytes {
+			content = content[:maxDebugMessageContentBytes]
+			truncated = true
+		}
+
+		frames = append(frames, AlloyDebugFrame{
+			Timestamp: time.Now(),
+			Size:      len(line),
+			Content:   content,
+			Truncated: truncated,
+		})
+
+		if maxMessages > 0 && len(frames) >= maxMessages {
+			break
+		}
+
+		if streamCtx.Err() != nil {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil && streamCtx.Err() == nil {
+		return frames, fmt.Errorf("reading debug stream: %w", err)
+	}
+
+	return frames, nil
+}
+
+func streamAlloyComponentDebug(ctx context.Context, req AlloyStreamComponentDebugRequest) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("alloy_stream_component_debug", time.Since(start), err) }(time.Now())
+
+	duration := defaultDebugStreamDuration
+	if req.DurationSeconds > 0 {
+		duration = time.Duration(req.DurationSeconds) * time.Second
+	}
+	if duration > maxDebugStreamDuration {
+		duration = maxDebugStreamDuration
+	}
+
+	maxMessages := req.MaxMessages
+	if maxMessages <= 0 {
+		maxMessages = defaultDebugMaxMessages
+	}
+
+	slog.DebugContext(ctx, "streamAlloyComponentDebug called", "component_id", req.ComponentID, "duration", duration, "max_messages", maxMessages)
+
+	frames, err := StreamAlloyComponentDebugFunc(ctx, req.ComponentID, duration, maxMessages)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error streaming component debug data", "error", err, "component_id", req.ComponentID)
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Live debug transcript for component %s (%d frame(s), up to %s or %d messages):\n\n", req.ComponentID, len(frames), duration, maxMessages)
+	if len(frames) == 0 {
+		b.WriteString("No debug frames were received. The component may be idle, or live debugging may not be enabled for it (see alloy_enable_live_debugging).\n")
+		return b.String(), nil
+	}
+
+	for i, f := range frames {
+		fmt.Fprintf(&b, "[%d] %s (%d bytes)%s\n%s\n\n", i+1, f.Timestamp.Format(time.RFC3339Nano), f.Size, truncationNotice(f.Truncated), f.Content)
+	}
+
+	if len(frames) >= maxMessages {
+		fmt.Fprintf(&b, "Transcript stopped after reaching max_messages (%d); more data may still be flowing.\n", maxMessages)
+	}
+
+	return b.String(), nil
+}
+
+func truncationNotice(truncated bool) string {
+	if truncated {
+		return " [truncated]"
+	}
+	return ""
+}
+
+// AlloyStreamComponentDebug is a tool for streaming a component's live
+// debugging output for a bounded duration or message count.
+var AlloyStreamComponentDebug = mcpgrafana.MustTool(
+	"alloy_stream_component_debug",
+	`Stream a component's live debugging output (Alloy's "Debug" view), for example to observe the data actually flowing through a discovery.relabel or otelcol.processor.
+
+Connects to Alloy's /api/v0/web/debug/{componentID} endpoint and reads newline-delimited debug frames until duration_seconds elapses (default 5s, capped at 60s) or max_messages frames have been read (default 50), whichever comes first. Returns a transcript with a per-frame timestamp, size, and a truncation notice for any frame over 4KB.
+
+Live debugging must be enabled for the component first; use alloy_enable_live_debugging if this returns no frames.`,
+	streamAlloyComponentDebug,
+	mcp.WithTitleAnnotation("Stream Alloy component debug data"),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+// SetAlloyLiveDebuggingFunc PATCHes a component's liveDebuggingEnabled flag,
+// mirroring what the Alloy UI does before it opens a component's debug
+// stream view.
+func SetAlloyLiveDebuggingFunc(ctx context.Context, componentID string, enabled bool) error {
+	host := os.Getenv(alloyHostEnvVar)
+	if host == "" {
+		host = defaultAlloyHost
+	}
+	baseURL := fmt.Sprintf("http://%s", host)
+	url := fmt.Sprintf("%s/api/v0/web/components/%s", baseURL, componentID)
+
+	body, err := json.Marshal(map[string]bool{"liveDebuggingEnabled": enabled})
+	if err != nil {
+		return fmt.Errorf("marshaling request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection refused") {
+			return fmt.Errorf("connection refused: Alloy service appears to be down or not running on %s. Please ensure the service is running and try again", baseURL)
+		}
+		return fmt.Errorf("toggling live debugging: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func enableAlloyLiveDebugging(ctx context.Context, req AlloyEnableLiveDebuggingRequest) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("alloy_enable_live_debugging", time.Since(start), err) }(time.Now())
+
+	if err := SetAlloyLiveDebuggingFunc(ctx, req.ComponentID, req.Enabled); err != nil {
+		slog.ErrorContext(ctx, "Error toggling live debugging", "error", err, "component_id", req.ComponentID, "enabled", req.Enabled)
+		return "", err
+	}
+
+	state := "disabled"
+	if req.Enabled {
+		state = "enabled"
+	}
+	return fmt.Sprintf("Live debugging %s for component %s", state, req.ComponentID), nil
+}
+
+// AlloyEnableLiveDebugging is a tool for toggling a component's live
+// debugging flag before streaming its debug output.
+var AlloyEnableLiveDebugging = mcpgrafana.MustTool(
+	"alloy_enable_live_debugging",
+	`Enable or disable live debugging for an Alloy component, the same toggle the Alloy UI flips before showing a component's "Debug" view.
+
+Call this with enabled=true before alloy_stream_component_debug if a component's liveDebuggingEnabled flag (see alloy_get_component_details) is false.`,
+	enableAlloyLiveDebugging,
+	mcp.WithTitleAnnotation("Enable/disable Alloy live debugging"),
+	mcp.WithIdempotentHintAnnotation(true),
+)
@@ -0,0 +1,341 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// alloyDocsCacheEnvVar overrides where downloaded component docs are cached
+// on disk. Defaults to a directory under os.TempDir().
+const alloyDocsCacheEnvVar = "ALLOY_DOCS_CACHE"
+
+// docsCacheCompleteMarker is written to a version's cache directory once
+// every doc file has been downloaded, so a restart doesn't need to hit
+// GitHub again to know the cache is already populated.
+const docsCacheCompleteMarker = ".complete"
+
+var docsTokenPattern = regexp.MustCompile(`[a-zA-Z0-9_]+`)
+
+// alloyDocsIndex is an in-memory inverted index (token -> component names)
+// built from the cached component docs for a single Alloy minor version.
+// GetAlloyComponentDocs and alloy_search_docs share it so the docs only
+// need to be downloaded and tokenized once per process per version.
+type alloyDocsIndex struct {
+	mu      sync.Mutex
+	version string // "{major}.{minor}" this index was built for
+	tokens  map[string]map[string]bool
+}
+
+var sharedAlloyDocsIndex = &alloyDocsIndex{}
+
+// alloyDocsCacheDir returns the root cache directory, honoring
+// ALLOY_DOCS_CACHE.
+func alloyDocsCacheDir() string {
+	if dir := os.Getenv(alloyDocsCacheEnvVar); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "alloy-docs-cache")
+}
+
+// alloyDocsVersionDir returns the cache directory for a specific Alloy
+// minor version, since docs content is keyed by {major}.{minor} so a
+// version upgrade triggers a re-fetch instead of silently serving stale docs.
+func alloyDocsVersionDir(version *AlloyVersion) string {
+	return filepath.Join(alloyDocsCacheDir(), fmt.Sprintf("%s.%s", version.Major, version.Minor))
+}
+
+// githubTreeResponse is the subset of GitHub's git trees API response
+// (https://docs.github.com/en/rest/git/trees) this package needs.
+type githubTreeResponse struct {
+	Tree []githubTreeEntry `json:"tree"`
+}
+
+type githubTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// componentDocsTreePaths walks the grafana/alloy repo tree at the release
+// branch matching version and returns the repo-relative path of every
+// component reference doc (docs/sources/reference/components/**/*.md).
+func componentDocsTreePaths(ctx context.Context, version *AlloyVersion) ([]string, error) {
+	ref := fmt.Sprintf("release/v%s.%s", version.Major, version.Minor)
+	url := fmt.Sprintf("https://api.github.com/repos/grafana/alloy/git/trees/%s?recursive=1", strings.ReplaceAll(ref, "/", "%2F"))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching repo tree: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d fetching repo tree for %s", resp.StatusCode, ref)
+	}
+
+	var tree githubTreeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		return nil, fmt.Errorf("decoding repo tree: %w", err)
+	}
+
+	const prefix = "docs/sources/reference/components/"
+	var paths []string
+	for _, entry := range tree.Tree {
+		if entry.Type == "blob" && strings.HasPrefix(entry.Path, prefix) && strings.HasSuffix(entry.Path, ".md") {
+			paths = append(paths, entry.Path)
+		}
+	}
+	return paths, nil
+}
+
+// componentNameFromDocPath turns a doc path like
+// "docs/sources/reference/components/discovery/discovery.relabel.md" into
+// its component name, "discovery.relabel".
+func componentNameFromDocPath(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, ".md")
+}
+
+// downloadComponentDoc fetches a single doc file's raw markdown content
+// from GitHub at the given release branch.
+func downloadComponentDoc(ctx context.Context, version *AlloyVersion, repoPath string) ([]byte, error) {
+	url := fmt.Sprintf("https://raw.githubusercontent.com/grafana/alloy/release/v%s.%s/%s", version.Major, version.Minor, repoPath)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d fetching %s", resp.StatusCode, url)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// populateAlloyDocsCache downloads every component doc for version into its
+// cache directory, skipping the download entirely if a previous run already
+// completed it.
+func populateAlloyDocsCache(ctx context.Context, version *AlloyVersion) error {
+	versionDir := alloyDocsVersionDir(version)
+	markerPath := filepath.Join(versionDir, docsCacheCompleteMarker)
+	if _, err := os.Stat(markerPath); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(versionDir, 0o755); err != nil {
+		return fmt.Errorf("creating docs cache dir: %w", err)
+	}
+
+	paths, err := componentDocsTreePaths(ctx, version)
+	if err != nil {
+		return fmt.Errorf("listing component docs: %w", err)
+	}
+
+	for _, path := range paths {
+		content, err := downloadComponentDoc(ctx, version, path)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to download component doc, skipping", "path", path, "error", err)
+			continue
+		}
+		name := componentNameFromDocPath(path)
+		if err := os.WriteFile(filepath.Join(versionDir, name+".md"), content, 0o644); err != nil {
+			return fmt.Errorf("writing cached doc %s: %w", name, err)
+		}
+	}
+
+	return os.WriteFile(markerPath, []byte("ok"), 0o644)
+}
+
+// tokenizeDoc lowercases and splits content into word tokens for indexing.
+func tokenizeDoc(content string) []string {
+	return docsTokenPattern.FindAllString(strings.ToLower(content), -1)
+}
+
+// buildAlloyDocsIndex builds an in-memory token -> component-names index
+// from every cached doc file for version, downloading them first if the
+// cache directory isn't already populated.
+func buildAlloyDocsIndex(ctx context.Context, version *AlloyVersion) (map[string]map[string]bool, error) {
+	if err := populateAlloyDocsCache(ctx, version); err != nil {
+		return nil, err
+	}
+
+	versionDir := alloyDocsVersionDir(version)
+	entries, err := os.ReadDir(versionDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading docs cache dir: %w", err)
+	}
+
+	tokens := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		componentName := strings.TrimSuffix(entry.Name(), ".md")
+		content, err := os.ReadFile(filepath.Join(versionDir, entry.Name()))
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to read cached doc while indexing, skipping", "component", componentName, "error", err)
+			continue
+		}
+		for _, token := range tokenizeDoc(string(content)) {
+			if tokens[token] == nil {
+				tokens[token] = make(map[string]bool)
+			}
+			tokens[token][componentName] = true
+		}
+	}
+
+	return tokens, nil
+}
+
+// ensureAlloyDocsIndex returns the shared docs index, (re)building it if
+// it's unset or stale for the running Alloy's version.
+func ensureAlloyDocsIndex(ctx context.Context) (*alloyDocsIndex, *AlloyVersion, error) {
+	version, err := GetAlloyVersion(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting Alloy version: %w", err)
+	}
+	versionKey := fmt.Sprintf("%s.%s", version.Major, version.Minor)
+
+	sharedAlloyDocsIndex.mu.Lock()
+	defer sharedAlloyDocsIndex.mu.Unlock()
+
+	if sharedAlloyDocsIndex.version == versionKey && sharedAlloyDocsIndex.tokens != nil {
+		return sharedAlloyDocsIndex, version, nil
+	}
+
+	tokens, err := buildAlloyDocsIndex(ctx, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedAlloyDocsIndex.version = versionKey
+	sharedAlloyDocsIndex.tokens = tokens
+	return sharedAlloyDocsIndex, version, nil
+}
+
+// cachedComponentDoc reads a component's doc from the on-disk cache for
+// version, returning ("", false) on a cache miss.
+func cachedComponentDoc(version *AlloyVersion, componentName string) (string, bool) {
+	content, err := os.ReadFile(filepath.Join(alloyDocsVersionDir(version), componentName+".md"))
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+// cacheComponentDoc opportunistically writes a doc fetched directly from
+// GitHub (e.g. by a cache-miss fallback) into the on-disk cache, so the
+// next lookup is a cache hit.
+func cacheComponentDoc(version *AlloyVersion, componentName, content string) {
+	versionDir := alloyDocsVersionDir(version)
+	if err := os.MkdirAll(versionDir, 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(versionDir, componentName+".md"), []byte(content), 0o644)
+}
+
+// AlloySearchDocsRequest represents a free-text search over the cached
+// component documentation.
+type AlloySearchDocsRequest struct {
+	Query      string `json:"query" jsonschema:"required,description=Free-text search query\\, e.g. 'bearer_token' or 'components that export receiver'"`
+	MaxResults int    `json:"max_results,omitempty" jsonschema:"description=Maximum number of matching components to return. Defaults to 10"`
+}
+
+// docMatch is one alloy_search_docs result: a component and how many of
+// the query's tokens its doc contains.
+type docMatch struct {
+	Component string
+	Score     int
+}
+
+// searchAlloyDocsIndex ranks every component that contains at least one of
+// query's tokens by how many distinct tokens it matched.
+func searchAlloyDocsIndex(idx *alloyDocsIndex, query string, maxResults int) []docMatch {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	scores := make(map[string]int)
+	for _, token := range tokenizeDoc(query) {
+		if len(token) < 3 {
+			continue
+		}
+		for component := range idx.tokens[token] {
+			scores[component]++
+		}
+	}
+
+	matches := make([]docMatch, 0, len(scores))
+	for component, score := range scores {
+		matches = append(matches, docMatch{Component: component, Score: score})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Component < matches[j].Component
+	})
+
+	if maxResults > 0 && len(matches) > maxResults {
+		matches = matches[:maxResults]
+	}
+	return matches
+}
+
+func alloySearchDocs(ctx context.Context, req AlloySearchDocsRequest) (result string, err error) {
+	maxResults := req.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+
+	idx, _, err := ensureAlloyDocsIndex(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	matches := searchAlloyDocsIndex(idx, req.Query, maxResults)
+	if len(matches) == 0 {
+		return fmt.Sprintf("No components found matching %q.", req.Query), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Components matching %q:\n\n", req.Query)
+	for _, m := range matches {
+		fmt.Fprintf(&b, "- %s (matched %d query token(s))\n", m.Component, m.Score)
+	}
+	return b.String(), nil
+}
+
+// AlloySearchDocs is a tool for searching the cached, offline-indexed
+// component documentation.
+var AlloySearchDocs = mcpgrafana.MustTool(
+	"alloy_search_docs",
+	`Search Alloy's component reference documentation for a free-text query, e.g. "which components accept a bearer_token argument" or "components that export receiver".
+
+On first use this downloads every component doc at the running Alloy's release branch into a local cache (see ALLOY_DOCS_CACHE), keyed by major.minor so a version upgrade triggers a re-fetch, and builds an in-memory inverted index over it. Subsequent calls, and alloy_get_component_docs, read from that cache instead of hitting GitHub again.
+
+Returns the components whose docs matched the most query tokens, most relevant first.`,
+	alloySearchDocs,
+	mcp.WithTitleAnnotation("Search Alloy component docs"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
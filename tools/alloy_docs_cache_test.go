@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestComponentNameFromDocPath(t *testing.T) {
+	name := componentNameFromDocPath("docs/sources/reference/components/discovery/discovery.relabel.md")
+	if name != "discovery.relabel" {
+		t.Errorf("expected 'discovery.relabel', got %q", name)
+	}
+}
+
+func TestTokenizeDoc(t *testing.T) {
+	tokens := tokenizeDoc("The `bearer_token` argument accepts a secret.")
+	found := false
+	for _, tok := range tokens {
+		if tok == "bearer_token" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected tokens to contain 'bearer_token', got %v", tokens)
+	}
+}
+
+func TestSearchAlloyDocsIndex(t *testing.T) {
+	idx := &alloyDocsIndex{
+		version: "1.6",
+		tokens: map[string]map[string]bool{
+			"bearer_token": {"otelcol.auth.bearer": true, "remote.http": true},
+			"receiver":     {"otelcol.auth.bearer": true},
+		},
+	}
+
+	matches := searchAlloyDocsIndex(idx, "bearer_token receiver", 10)
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+	if matches[0].Component != "otelcol.auth.bearer" || matches[0].Score != 2 {
+		t.Errorf("expected otelcol.auth.bearer to rank first with score 2, got %+v", matches[0])
+	}
+}
@@ -0,0 +1,257 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+var graphIDPattern = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// AlloyExportPipelineGraphRequest represents a request to export an Alloy
+// pipeline's component graph in a renderable format.
+type AlloyExportPipelineGraphRequest struct {
+	PipelineType string `json:"pipeline_type" jsonschema:"required,description=The type of pipeline to export (loki, prometheus, otel), or 'all' to export the entire config"`
+	Format       string `json:"format" jsonschema:"required,description=One of dot, mermaid, or cytoscape-json"`
+}
+
+// collectPipelineComponents lists every component of pipelineType, or every
+// component in the config when pipelineType is "all".
+func collectPipelineComponents(ctx context.Context, pipelineType string) ([]AlloyComponent, error) {
+	components, err := ListAlloyComponentsFunc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing components: %w", err)
+	}
+	if pipelineType == "all" {
+		return components, nil
+	}
+
+	var filtered []AlloyComponent
+	for _, c := range components {
+		if isComponentOfType(c, pipelineType) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// healthColor maps an Alloy component health state to a traffic-light color
+// for graph rendering.
+func healthColor(state string) string {
+	switch strings.ToLower(state) {
+	case "healthy":
+		return "green"
+	case "unhealthy":
+		return "red"
+	default:
+		return "yellow"
+	}
+}
+
+// graphNodeID sanitizes a component's LocalID into an identifier safe to
+// use unquoted in DOT, Mermaid, or as a Cytoscape element id.
+func graphNodeID(localID string) string {
+	return graphIDPattern.ReplaceAllString(localID, "_")
+}
+
+// componentsByModule groups components by ModuleID, preserving a stable,
+// sorted module order so repeated exports produce a stable diagram.
+func componentsByModule(components []AlloyComponent) (moduleIDs []string, byModule map[string][]AlloyComponent) {
+	byModule = make(map[string][]AlloyComponent)
+	for _, c := range components {
+		byModule[c.ModuleID] = append(byModule[c.ModuleID], c)
+	}
+	for moduleID := range byModule {
+		moduleIDs = append(moduleIDs, moduleID)
+	}
+	sort.Strings(moduleIDs)
+	for _, comps := range byModule {
+		sort.Slice(comps, func(i, j int) bool { return comps[i].LocalID < comps[j].LocalID })
+	}
+	return moduleIDs, byModule
+}
+
+// buildDOTGraph renders components as a Graphviz DOT digraph, one subgraph
+// cluster per ModuleID, nodes colored by health state, edges from
+// ReferencesTo.
+func buildDOTGraph(components []AlloyComponent) string {
+	moduleIDs, byModule := componentsByModule(components)
+
+	var b strings.Builder
+	b.WriteString("digraph alloy {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=filled];\n\n")
+
+	for i, moduleID := range moduleIDs {
+		comps := byModule[moduleID]
+		if moduleID != "" {
+			fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=%q;\n", i, moduleID)
+		}
+		for _, c := range comps {
+			fmt.Fprintf(&b, "    %q [label=%q, fillcolor=%s];\n", c.LocalID, fmt.Sprintf("%s\\n%s", c.Name, c.Health.State), healthColor(c.Health.State))
+		}
+		if moduleID != "" {
+			b.WriteString("  }\n")
+		}
+		b.WriteString("\n")
+	}
+
+	for _, c := range components {
+		for _, ref := range c.ReferencesTo {
+			fmt.Fprintf(&b, "  %q -> %q;\n", c.LocalID, ref)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// buildMermaidGraph renders components as a Mermaid flowchart, one
+// subgraph per ModuleID, nodes classed by health state.
+func buildMermaidGraph(components []AlloyComponent) string {
+	moduleIDs, byModule := componentsByModule(components)
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	b.WriteString("  classDef healthy fill:#2ecc71,color:#000\n")
+	b.WriteString("  classDef unhealthy fill:#e74c3c,color:#000\n")
+	b.WriteString("  classDef unknown fill:#f1c40f,color:#000\n\n")
+
+	classFor := func(state string) string {
+		switch strings.ToLower(state) {
+		case "healthy":
+			return "healthy"
+		case "unhealthy":
+			return "unhealthy"
+		default:
+			return "unknown"
+		}
+	}
+
+	for i, moduleID := range moduleIDs {
+		comps := byModule[moduleID]
+		indent := "  "
+		if moduleID != "" {
+			fmt.Fprintf(&b, "  subgraph module_%d[%s]\n", i, moduleID)
+			indent = "    "
+		}
+		for _, c := range comps {
+			fmt.Fprintf(&b, "%s%s[\"%s (%s)\"]:::%s\n", indent, graphNodeID(c.LocalID), c.Name, c.Health.State, classFor(c.Health.State))
+		}
+		if moduleID != "" {
+			b.WriteString("  end\n")
+		}
+		b.WriteString("\n")
+	}
+
+	for _, c := range components {
+		for _, ref := range c.ReferencesTo {
+			fmt.Fprintf(&b, "  %s --> %s\n", graphNodeID(c.LocalID), graphNodeID(ref))
+		}
+	}
+
+	return b.String()
+}
+
+// cytoscapeElements is the {elements: {nodes, edges}} shape Cytoscape.js
+// expects as graph input.
+type cytoscapeElements struct {
+	Nodes []cytoscapeNode `json:"nodes"`
+	Edges []cytoscapeEdge `json:"edges"`
+}
+
+type cytoscapeNode struct {
+	Data cytoscapeNodeData `json:"data"`
+}
+
+type cytoscapeNodeData struct {
+	ID     string `json:"id"`
+	Label  string `json:"label"`
+	State  string `json:"state"`
+	Color  string `json:"color"`
+	Module string `json:"module,omitempty"`
+}
+
+type cytoscapeEdge struct {
+	Data cytoscapeEdgeData `json:"data"`
+}
+
+type cytoscapeEdgeData struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// buildCytoscapeGraph renders components as Cytoscape.js-compatible
+// {elements: {nodes, edges}} JSON.
+func buildCytoscapeGraph(components []AlloyComponent) (string, error) {
+	elements := cytoscapeElements{}
+	for _, c := range components {
+		elements.Nodes = append(elements.Nodes, cytoscapeNode{Data: cytoscapeNodeData{
+			ID:     c.LocalID,
+			Label:  c.Name,
+			State:  c.Health.State,
+			Color:  healthColor(c.Health.State),
+			Module: c.ModuleID,
+		}})
+		for _, ref := range c.ReferencesTo {
+			elements.Edges = append(elements.Edges, cytoscapeEdge{Data: cytoscapeEdgeData{
+				ID:     fmt.Sprintf("%s->%s", c.LocalID, ref),
+				Source: c.LocalID,
+				Target: ref,
+			}})
+		}
+	}
+
+	b, err := json.MarshalIndent(map[string]cytoscapeElements{"elements": elements}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling cytoscape graph: %w", err)
+	}
+	return string(b), nil
+}
+
+func alloyExportPipelineGraph(ctx context.Context, req AlloyExportPipelineGraphRequest) (string, error) {
+	components, err := collectPipelineComponents(ctx, req.PipelineType)
+	if err != nil {
+		return "", err
+	}
+	if len(components) == 0 {
+		return fmt.Sprintf("No components found for pipeline_type %q; nothing to export.", req.PipelineType), nil
+	}
+
+	switch req.Format {
+	case "dot":
+		return buildDOTGraph(components), nil
+	case "mermaid":
+		return buildMermaidGraph(components), nil
+	case "cytoscape-json":
+		return buildCytoscapeGraph(components)
+	default:
+		return "", fmt.Errorf("unsupported format %q: must be one of dot, mermaid, cytoscape-json", req.Format)
+	}
+}
+
+// AlloyExportPipelineGraph is a tool for exporting an Alloy pipeline's
+// component graph as a renderable diagram.
+var AlloyExportPipelineGraph = mcpgrafana.MustTool(
+	"alloy_export_pipeline_graph",
+	`Export an Alloy pipeline's component graph (nodes labeled with their name and health state, colored red/yellow/green, edges from ReferencesTo, clustered by ModuleID) as a paste-ready diagram.
+
+format must be one of:
+- dot: a Graphviz digraph
+- mermaid: a Mermaid flowchart
+- cytoscape-json: a Cytoscape.js {elements: {nodes, edges}} document
+
+Set pipeline_type to loki, prometheus, or otel to scope to one pipeline, or to "all" to render the entire running config.`,
+	alloyExportPipelineGraph,
+	mcp.WithTitleAnnotation("Export Alloy pipeline graph"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
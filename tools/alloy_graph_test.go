@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func testGraphComponents() []AlloyComponent {
+	return []AlloyComponent{
+		{
+			Name: "discovery.relabel", LocalID: "discovery.relabel.default", ModuleID: "",
+			ReferencesTo: []string{"prometheus.remote_write.default"},
+			Health:       Health{State: "healthy"},
+		},
+		{
+			Name: "prometheus.remote_write", LocalID: "prometheus.remote_write.default", ModuleID: "",
+			Health: Health{State: "unhealthy"},
+		},
+	}
+}
+
+func TestBuildDOTGraph(t *testing.T) {
+	dot := buildDOTGraph(testGraphComponents())
+	if !strings.Contains(dot, `"discovery.relabel.default" -> "prometheus.remote_write.default"`) {
+		t.Errorf("expected an edge between the two components, got:\n%s", dot)
+	}
+	if !strings.Contains(dot, "fillcolor=red") {
+		t.Errorf("expected the unhealthy component to be colored red, got:\n%s", dot)
+	}
+}
+
+func TestBuildMermaidGraph(t *testing.T) {
+	mermaid := buildMermaidGraph(testGraphComponents())
+	if !strings.Contains(mermaid, "discovery_relabel_default --> prometheus_remote_write_default") {
+		t.Errorf("expected a sanitized edge, got:\n%s", mermaid)
+	}
+}
+
+func TestBuildCytoscapeGraph(t *testing.T) {
+	out, err := buildCytoscapeGraph(testGraphComponents())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(out, `"source": "discovery.relabel.default"`) {
+		t.Errorf("expected an edge in the cytoscape output, got:\n%s", out)
+	}
+}
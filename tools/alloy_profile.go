@@ -0,0 +1,280 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/pprof/profile"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+const (
+	defaultProfileSeconds = 10
+	maxProfileSeconds     = 60
+	defaultProfileTopN    = 15
+)
+
+// AlloyProfileRequest represents a request for a pprof profile from the
+// running Alloy process.
+type AlloyProfileRequest struct {
+	Seconds int `json:"seconds,omitempty" jsonschema:"description=How long to collect the profile for. Only applies to the CPU profile; ignored for heap and goroutine. Defaults to 10\\, capped at 60"`
+	TopN    int `json:"top_n,omitempty" jsonschema:"description=Number of functions to include in the flat/cumulative table. Defaults to 15"`
+}
+
+// profileFuncStat is one row of a profile's flat/cumulative function table.
+type profileFuncStat struct {
+	Name       string
+	Flat       int64
+	Cumulative int64
+}
+
+// componentHotness is the aggregated profile value attributed to a single
+// Alloy component, via the "component_id" pprof sample label Alloy attaches
+// to the goroutines it runs components on.
+type componentHotness struct {
+	ComponentID string
+	Value       int64
+}
+
+// fetchAlloyProfile fetches a pprof profile from Alloy's debug server. The
+// profile format is gzip'd protobuf; profile.Parse decompresses and decodes
+// it directly.
+func fetchAlloyProfile(ctx context.Context, endpoint string, seconds int) (*profile.Profile, error) {
+	host := os.Getenv(alloyHostEnvVar)
+	if host == "" {
+		host = defaultAlloyHost
+	}
+	url := fmt.Sprintf("http://%s/debug/pprof/%s", host, endpoint)
+	if seconds > 0 {
+		url = fmt.Sprintf("%s?seconds=%d", url, seconds)
+	}
+
+	reqCtx := ctx
+	if seconds > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, time.Duration(seconds+10)*time.Second)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection refused") {
+			return nil, fmt.Errorf("connection refused: Alloy service appears to be down or not running on %s. Please ensure the service is running and try again", host)
+		}
+		return nil, fmt.Errorf("fetching profile: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	prof, err := profile.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing profile: %w", err)
+	}
+	return prof, nil
+}
+
+// aggregateProfileFuncs sums each function's flat value (time/space
+// attributed to it directly, at the top of the stack) and cumulative value
+// (attributed to it anywhere in the stack) across every sample, using the
+// profile's first sample type as the value of interest.
+func aggregateProfileFuncs(prof *profile.Profile) []profileFuncStat {
+	flat := make(map[string]int64)
+	cum := make(map[string]int64)
+
+	for _, sample := range prof.Sample {
+		if len(sample.Value) == 0 {
+			continue
+		}
+		value := sample.Value[0]
+
+		seen := make(map[string]bool)
+		for i, loc := range sample.Location {
+			for _, line := range loc.Line {
+				if line.Function == nil {
+					continue
+				}
+				name := line.Function.Name
+				if i == 0 {
+					flat[name] += value
+				}
+				if !seen[name] {
+					cum[name] += value
+					seen[name] = true
+				}
+			}
+		}
+	}
+
+	stats := make([]profileFuncStat, 0, len(cum))
+	for name, c := range cum {
+		stats = append(stats, profileFuncStat{Name: name, Flat: flat[name], Cumulative: c})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Cumulative > stats[j].Cumulative })
+	return stats
+}
+
+// hotComponents aggregates each sample's value by the "component_id" pprof
+// label Alloy attaches to component goroutines, so callers can see which
+// component is responsible for the load without reading function names.
+func hotComponents(prof *profile.Profile) []componentHotness {
+	totals := make(map[string]int64)
+	for _, sample := range prof.Sample {
+		if len(sample.Value) == 0 {
+			continue
+		}
+		ids := sample.Label["component_id"]
+		for _, id := range ids {
+			totals[id] += sample.Value[0]
+		}
+	}
+
+	hot := make([]componentHotness, 0, len(totals))
+	for id, v := range totals {
+		hot = append(hot, componentHotness{ComponentID: id, Value: v})
+	}
+	sort.Slice(hot, func(i, j int) bool { return hot[i].Value > hot[j].Value })
+	return hot
+}
+
+func formatProfileSummary(title string, prof *profile.Profile, topN int) string {
+	var unit string
+	if len(prof.SampleType) > 0 {
+		unit = prof.SampleType[0].Unit
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%d sample(s), value unit: %s):\n\n", title, len(prof.Sample), unit)
+
+	stats := aggregateProfileFuncs(prof)
+	if len(stats) == 0 {
+		b.WriteString("No samples were collected.\n")
+		return b.String()
+	}
+	if topN > len(stats) {
+		topN = len(stats)
+	}
+
+	b.WriteString("Top functions by cumulative value:\n")
+	fmt.Fprintf(&b, "%-8s %-8s %s\n", "FLAT", "CUM", "FUNCTION")
+	for _, s := range stats[:topN] {
+		fmt.Fprintf(&b, "%-8d %-8d %s\n", s.Flat, s.Cumulative, s.Name)
+	}
+
+	if hot := hotComponents(prof); len(hot) > 0 {
+		hotN := topN
+		if hotN > len(hot) {
+			hotN = len(hot)
+		}
+		b.WriteString("\nHot components:\n")
+		for _, h := range hot[:hotN] {
+			fmt.Fprintf(&b, "- %s: %d\n", h.ComponentID, h.Value)
+		}
+	} else {
+		b.WriteString("\nNo component_id labels were present in this profile; components couldn't be attributed.\n")
+	}
+
+	return b.String()
+}
+
+func normalizeProfileRequest(req AlloyProfileRequest) (seconds, topN int) {
+	seconds = req.Seconds
+	if seconds <= 0 {
+		seconds = defaultProfileSeconds
+	}
+	if seconds > maxProfileSeconds {
+		seconds = maxProfileSeconds
+	}
+	topN = req.TopN
+	if topN <= 0 {
+		topN = defaultProfileTopN
+	}
+	return seconds, topN
+}
+
+func getAlloyCPUProfile(ctx context.Context, req AlloyProfileRequest) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("alloy_get_cpu_profile", time.Since(start), err) }(time.Now())
+
+	seconds, topN := normalizeProfileRequest(req)
+	slog.DebugContext(ctx, "getAlloyCPUProfile called", "seconds", seconds)
+
+	prof, err := fetchAlloyProfile(ctx, "profile", seconds)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching CPU profile", "error", err)
+		return "", err
+	}
+	return formatProfileSummary(fmt.Sprintf("CPU profile (%ds)", seconds), prof, topN), nil
+}
+
+func getAlloyHeapProfile(ctx context.Context, req AlloyProfileRequest) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("alloy_get_heap_profile", time.Since(start), err) }(time.Now())
+
+	_, topN := normalizeProfileRequest(req)
+	slog.DebugContext(ctx, "getAlloyHeapProfile called")
+
+	prof, err := fetchAlloyProfile(ctx, "heap", 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching heap profile", "error", err)
+		return "", err
+	}
+	return formatProfileSummary("Heap profile", prof, topN), nil
+}
+
+func getAlloyGoroutineDump(ctx context.Context, req AlloyProfileRequest) (result string, err error) {
+	defer func(start time.Time) { RecordToolMetrics("alloy_get_goroutine_dump", time.Since(start), err) }(time.Now())
+
+	_, topN := normalizeProfileRequest(req)
+	slog.DebugContext(ctx, "getAlloyGoroutineDump called")
+
+	prof, err := fetchAlloyProfile(ctx, "goroutine", 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching goroutine dump", "error", err)
+		return "", err
+	}
+	return formatProfileSummary("Goroutine dump", prof, topN), nil
+}
+
+// AlloyGetCPUProfile is a tool for capturing and summarizing a CPU profile
+// from the running Alloy process.
+var AlloyGetCPUProfile = mcpgrafana.MustTool(
+	"alloy_get_cpu_profile",
+	`Capture a CPU profile from the running Alloy process (GET /debug/pprof/profile?seconds=N) and summarize it as a top-N flat/cumulative function table plus the hottest components by their "component_id" pprof label, so you can answer "which component is burning CPU right now" without running go tool pprof by hand.`,
+	getAlloyCPUProfile,
+	mcp.WithTitleAnnotation("Get Alloy CPU profile"),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+// AlloyGetHeapProfile is a tool for capturing and summarizing a heap
+// profile from the running Alloy process.
+var AlloyGetHeapProfile = mcpgrafana.MustTool(
+	"alloy_get_heap_profile",
+	`Capture the current heap profile from the running Alloy process (GET /debug/pprof/heap) and summarize it as a top-N flat/cumulative function table plus the hottest components by their "component_id" pprof label, so you can answer "which component is allocating the most memory right now" without running go tool pprof by hand.`,
+	getAlloyHeapProfile,
+	mcp.WithTitleAnnotation("Get Alloy heap profile"),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+// AlloyGetGoroutineDump is a tool for capturing and summarizing a goroutine
+// profile from the running Alloy process.
+var AlloyGetGoroutineDump = mcpgrafana.MustTool(
+	"alloy_get_goroutine_dump",
+	`Capture the current goroutine profile from the running Alloy process (GET /debug/pprof/goroutine) and summarize it as a top-N flat/cumulative function table plus the hottest components by their "component_id" pprof label, useful for spotting a component stuck or leaking goroutines.`,
+	getAlloyGoroutineDump,
+	mcp.WithTitleAnnotation("Get Alloy goroutine dump"),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -35,7 +36,7 @@ func TestMCPListAlloyComponents(t *testing.T) {
 
 	// Test the function
 	ctx := context.Background()
-	result, err := MCPListAlloyComponents(ctx)
+	result, err := MCPListAlloyComponents(ctx, "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -46,6 +47,45 @@ func TestMCPListAlloyComponents(t *testing.T) {
 	}
 }
 
+func TestMCPListAlloyComponentsJSONFormats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		components := []AlloyComponent{
+			{Name: "loki.write", LocalID: "loki.write.default"},
+			{Name: "loki.process", LocalID: "loki.process.default"},
+		}
+		json.NewEncoder(w).Encode(components)
+	}))
+	defer server.Close()
+	t.Setenv(alloyHostEnvVar, server.URL[7:])
+
+	ctx := context.Background()
+
+	jsonResult, err := MCPListAlloyComponents(ctx, "json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var decoded []AlloyComponent
+	if err := json.Unmarshal([]byte(jsonResult), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON array, got error: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Errorf("Expected 2 components, got %d", len(decoded))
+	}
+
+	ndjsonResult, err := MCPListAlloyComponents(ctx, "ndjson")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lines := bytes.Split(bytes.TrimRight([]byte(ndjsonResult), "\n"), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 ndjson lines, got %d", len(lines))
+	}
+	var line AlloyComponent
+	if err := json.Unmarshal(lines[0], &line); err != nil {
+		t.Errorf("Expected each ndjson line to be a valid component, got error: %v", err)
+	}
+}
+
 func TestMCPGetAlloyComponentDetails(t *testing.T) {
 	// Mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -72,7 +112,7 @@ func TestMCPGetAlloyComponentDetails(t *testing.T) {
 
 	// Test the function
 	ctx := context.Background()
-	result, err := MCPGetAlloyComponentDetails(ctx, "loki.write.default")
+	result, err := MCPGetAlloyComponentDetails(ctx, "loki.write.default", "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -120,7 +160,7 @@ func TestMCPAnalyzeAlloyPipeline(t *testing.T) {
 
 	// Test the function
 	ctx := context.Background()
-	result, err := MCPAnalyzeAlloyPipeline(ctx, "loki")
+	result, err := MCPAnalyzeAlloyPipeline(ctx, "loki", "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -158,7 +198,7 @@ func TestMCPGetAlloyComponentHealth(t *testing.T) {
 
 	// Test the function
 	ctx := context.Background()
-	result, err := MCPGetAlloyComponentHealth(ctx)
+	result, err := MCPGetAlloyComponentHealth(ctx, "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
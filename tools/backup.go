@@ -0,0 +1,732 @@
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/grafana/grafana-openapi-client-go/client/folders"
+	"github.com/grafana/grafana-openapi-client-go/client/search"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+const backupManifestVersion = "1"
+
+// backupFolder is a folder entry in a backup manifest. ParentFolderUID is
+// empty for a top-level folder.
+type backupFolder struct {
+	UID             string `json:"uid"`
+	Title           string `json:"title"`
+	ParentFolderUID string `json:"parentFolderUid,omitempty"`
+}
+
+// backupDashboard is a dashboard entry in a backup manifest. Dashboard is
+// the full dashboard JSON, the same shape update_dashboard accepts.
+type backupDashboard struct {
+	UID       string                 `json:"uid"`
+	FolderUID string                 `json:"folderUid,omitempty"`
+	Dashboard map[string]interface{} `json:"dashboard"`
+}
+
+// backupDatasource is a datasource entry in a backup manifest. Grafana's
+// datasource list API never returns secret values, so this never holds
+// any; SecureJSONFields instead records which secrets a restore needs to
+// have re-entered by hand, since there is nothing to restore them from.
+type backupDatasource struct {
+	UID              string                 `json:"uid"`
+	Name             string                 `json:"name"`
+	Type             string                 `json:"type"`
+	Access           string                 `json:"access,omitempty"`
+	URL              string                 `json:"url,omitempty"`
+	JSONData         map[string]interface{} `json:"jsonData,omitempty"`
+	SecureJSONFields map[string]bool        `json:"secureJsonFields,omitempty"`
+	SecretsRedacted  bool                   `json:"secretsRedacted"`
+}
+
+// backupManifest is the full set of objects a backup/restore round trip
+// carries, independent of whether it's serialized as a tarball or as
+// newline-delimited JSON.
+type backupManifest struct {
+	Version     string             `json:"version"`
+	CreatedAt   string             `json:"createdAt"`
+	Folders     []backupFolder     `json:"folders,omitempty"`
+	Dashboards  []backupDashboard  `json:"dashboards,omitempty"`
+	Datasources []backupDatasource `json:"datasources,omitempty"`
+}
+
+type BackupGrafanaParams struct {
+	FolderUID          []string `json:"folderUid,omitempty" jsonschema:"description=Only back up dashboards (and matching folders) in these folder UIDs. Must be an array\\, even for a single folder. Empty means all folders"`
+	Tag                []string `json:"tag,omitempty" jsonschema:"description=Only back up dashboards with all of these tags. Must be an array\\, even for a single tag"`
+	IncludeFolders     bool     `json:"includeFolders,omitempty" jsonschema:"description=Include folder metadata in the backup. Defaults to true when omitted"`
+	IncludeDashboards  bool     `json:"includeDashboards,omitempty" jsonschema:"description=Include dashboards in the backup. Defaults to true when omitted"`
+	IncludeDatasources bool     `json:"includeDatasources,omitempty" jsonschema:"description=Include datasource configuration (secrets redacted) in the backup. Defaults to true when omitted"`
+	Format             string   `json:"format,omitempty" jsonschema:"description=\"ndjson\" (newline-delimited JSON records, one per object) or \"tar\" (a tarball with one file per object plus a manifest.json). Defaults to \"ndjson\""`
+	OutputPath         string   `json:"outputPath,omitempty" jsonschema:"description=If set, write the backup to this path on the MCP server's filesystem instead of returning it inline"`
+}
+
+// resolveBackupFlags applies this package's "defaults to true when the
+// caller omits an include* flag" convention: Go's zero value for bool is
+// false, so BackupGrafanaParams can't tell "explicitly false" from
+// "omitted" on its own. Since a partial backup (e.g. dashboards only) is
+// the less common case, callers opt into it by setting every *other*
+// include flag to true explicitly, or just take the all-true default.
+func resolveBackupFlags(args BackupGrafanaParams) (folders, dashboards, datasources bool) {
+	if !args.IncludeFolders && !args.IncludeDashboards && !args.IncludeDatasources {
+		return true, true, true
+	}
+	return args.IncludeFolders, args.IncludeDashboards, args.IncludeDatasources
+}
+
+func collectBackupFolders(ctx context.Context, folderFilter []string) ([]backupFolder, error) {
+	hitType := "dash-folder"
+	params := search.NewSearchParamsWithContext(ctx)
+	params.SetType(&hitType)
+	if len(folderFilter) > 0 {
+		params.SetFolderUIDs(folderFilter)
+	}
+
+	c := mcpgrafana.GrafanaClientFromContext(ctx)
+	result, err := c.Search.Search(params)
+	if err != nil {
+		return nil, fmt.Errorf("search folders: %w", err)
+	}
+
+	allowed := make(map[string]bool, len(folderFilter))
+	for _, uid := range folderFilter {
+		allowed[uid] = true
+	}
+
+	out := make([]backupFolder, 0, len(result.Payload))
+	for _, h := range result.Payload {
+		if len(allowed) > 0 && !allowed[h.UID] {
+			continue
+		}
+		out = append(out, backupFolder{UID: h.UID, Title: h.Title, ParentFolderUID: h.FolderUID})
+	}
+	return out, nil
+}
+
+func collectBackupDashboards(ctx context.Context, folderFilter, tagFilter []string) ([]backupDashboard, error) {
+	hits, err := searchDashboards(ctx, SearchDashboardsParams{Tag: tagFilter, FolderUID: folderFilter})
+	if err != nil {
+		return nil, fmt.Errorf("search dashboards: %w", err)
+	}
+
+	out := make([]backupDashboard, 0, len(hits))
+	for _, h := range hits {
+		dashboard, err := dashboardAsMap(ctx, h.UID)
+		if err != nil {
+			return nil, fmt.Errorf("fetch dashboard %s for backup: %w", h.UID, err)
+		}
+		out = append(out, backupDashboard{UID: h.UID, FolderUID: h.FolderUID, Dashboard: dashboard})
+	}
+	return out, nil
+}
+
+// datasourceBackupFields is the subset of a GetDataSources list item this
+// package needs. Decoding through JSON (rather than reading the generated
+// client struct's fields directly) matches locationLabelsFromJSONData's
+// approach in proxy_datasource_selection.go, so this doesn't depend on
+// exactly how the generated client types JSONData/Access internally.
+type datasourceBackupFields struct {
+	UID              string                 `json:"uid"`
+	Name             string                 `json:"name"`
+	Type             string                 `json:"type"`
+	Access           string                 `json:"access"`
+	URL              string                 `json:"url"`
+	JSONData         map[string]interface{} `json:"jsonData"`
+	SecureJSONFields map[string]bool        `json:"secureJsonFields"`
+}
+
+func collectBackupDatasources(ctx context.Context) ([]backupDatasource, error) {
+	c := mcpgrafana.GrafanaClientFromContext(ctx)
+	resp, err := c.Datasources.GetDataSources()
+	if err != nil {
+		return nil, fmt.Errorf("list datasources: %w", err)
+	}
+
+	out := make([]backupDatasource, 0, len(resp.Payload))
+	for _, ds := range resp.Payload {
+		raw, err := json.Marshal(ds)
+		if err != nil {
+			return nil, fmt.Errorf("marshal datasource %s: %w", ds.UID, err)
+		}
+		var fields datasourceBackupFields
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("unmarshal datasource %s: %w", ds.UID, err)
+		}
+
+		out = append(out, backupDatasource{
+			UID:              fields.UID,
+			Name:             fields.Name,
+			Type:             fields.Type,
+			Access:           fields.Access,
+			URL:              fields.URL,
+			JSONData:         fields.JSONData,
+			SecureJSONFields: fields.SecureJSONFields,
+			SecretsRedacted:  true,
+		})
+	}
+	return out, nil
+}
+
+func buildBackupManifest(ctx context.Context, args BackupGrafanaParams) (*backupManifest, error) {
+	includeFolders, includeDashboards, includeDatasources := resolveBackupFlags(args)
+
+	manifest := &backupManifest{Version: backupManifestVersion, CreatedAt: time.Now().UTC().Format(time.RFC3339)}
+
+	if includeFolders {
+		f, err := collectBackupFolders(ctx, args.FolderUID)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Folders = f
+	}
+	if includeDashboards {
+		d, err := collectBackupDashboards(ctx, args.FolderUID, args.Tag)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Dashboards = d
+	}
+	if includeDatasources {
+		ds, err := collectBackupDatasources(ctx)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Datasources = ds
+	}
+	return manifest, nil
+}
+
+// backupRecord is the envelope around one object in an ndjson-formatted
+// backup, tagging it with a kind so restore_grafana can dispatch each
+// line without first buffering the whole file.
+type backupRecord struct {
+	Kind       string            `json:"kind"`
+	Manifest   *backupManifest   `json:"manifest,omitempty"`
+	Folder     *backupFolder     `json:"folder,omitempty"`
+	Dashboard  *backupDashboard  `json:"dashboard,omitempty"`
+	Datasource *backupDatasource `json:"datasource,omitempty"`
+}
+
+func marshalBackupNDJSON(manifest *backupManifest) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+
+	if err := enc.Encode(backupRecord{Kind: "manifest", Manifest: &backupManifest{Version: manifest.Version, CreatedAt: manifest.CreatedAt}}); err != nil {
+		return nil, err
+	}
+	for i := range manifest.Folders {
+		if err := enc.Encode(backupRecord{Kind: "folder", Folder: &manifest.Folders[i]}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range manifest.Dashboards {
+		if err := enc.Encode(backupRecord{Kind: "dashboard", Dashboard: &manifest.Dashboards[i]}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range manifest.Datasources {
+		if err := enc.Encode(backupRecord{Kind: "datasource", Datasource: &manifest.Datasources[i]}); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func unmarshalBackupNDJSON(data []byte) (*backupManifest, error) {
+	manifest := &backupManifest{}
+
+	for i, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var rec backupRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return nil, fmt.Errorf("parse ndjson line %d: %w", i+1, err)
+		}
+		switch rec.Kind {
+		case "manifest":
+			if rec.Manifest != nil {
+				manifest.Version = rec.Manifest.Version
+				manifest.CreatedAt = rec.Manifest.CreatedAt
+			}
+		case "folder":
+			if rec.Folder != nil {
+				manifest.Folders = append(manifest.Folders, *rec.Folder)
+			}
+		case "dashboard":
+			if rec.Dashboard != nil {
+				manifest.Dashboards = append(manifest.Dashboards, *rec.Dashboard)
+			}
+		case "datasource":
+			if rec.Datasource != nil {
+				manifest.Datasources = append(manifest.Datasources, *rec.Datasource)
+			}
+		default:
+			return nil, fmt.Errorf("parse ndjson line %d: unknown kind %q", i+1, rec.Kind)
+		}
+	}
+	return manifest, nil
+}
+
+func marshalBackupTar(manifest *backupManifest) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	writeFile := func(name string, v interface{}) error {
+		content, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", name, err)
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			return fmt.Errorf("write tar header for %s: %w", name, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return fmt.Errorf("write tar content for %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if err := writeFile("manifest.json", struct {
+		Version   string `json:"version"`
+		CreatedAt string `json:"createdAt"`
+	}{manifest.Version, manifest.CreatedAt}); err != nil {
+		return nil, err
+	}
+	for _, f := range manifest.Folders {
+		if err := writeFile(fmt.Sprintf("folders/%s.json", f.UID), f); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range manifest.Dashboards {
+		if err := writeFile(fmt.Sprintf("dashboards/%s.json", d.UID), d); err != nil {
+			return nil, err
+		}
+	}
+	for _, ds := range manifest.Datasources {
+		if err := writeFile(fmt.Sprintf("datasources/%s.json", ds.UID), ds); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("finalize tar: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalBackupTar(data []byte) (*backupManifest, error) {
+	tr := tar.NewReader(bytes.NewReader(data))
+	manifest := &backupManifest{}
+
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("read tar entry: %w", err)
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("read tar entry %s: %w", hdr.Name, err)
+		}
+
+		switch {
+		case hdr.Name == "manifest.json":
+			var m struct {
+				Version   string `json:"version"`
+				CreatedAt string `json:"createdAt"`
+			}
+			if err := json.Unmarshal(content, &m); err != nil {
+				return nil, fmt.Errorf("parse manifest.json: %w", err)
+			}
+			manifest.Version, manifest.CreatedAt = m.Version, m.CreatedAt
+		case strings.HasPrefix(hdr.Name, "folders/"):
+			var f backupFolder
+			if err := json.Unmarshal(content, &f); err != nil {
+				return nil, fmt.Errorf("parse %s: %w", hdr.Name, err)
+			}
+			manifest.Folders = append(manifest.Folders, f)
+		case strings.HasPrefix(hdr.Name, "dashboards/"):
+			var d backupDashboard
+			if err := json.Unmarshal(content, &d); err != nil {
+				return nil, fmt.Errorf("parse %s: %w", hdr.Name, err)
+			}
+			manifest.Dashboards = append(manifest.Dashboards, d)
+		case strings.HasPrefix(hdr.Name, "datasources/"):
+			var ds backupDatasource
+			if err := json.Unmarshal(content, &ds); err != nil {
+				return nil, fmt.Errorf("parse %s: %w", hdr.Name, err)
+			}
+			manifest.Datasources = append(manifest.Datasources, ds)
+		}
+	}
+	return manifest, nil
+}
+
+func marshalBackup(manifest *backupManifest, format string) ([]byte, error) {
+	switch format {
+	case "", "ndjson":
+		return marshalBackupNDJSON(manifest)
+	case "tar":
+		return marshalBackupTar(manifest)
+	default:
+		return nil, fmt.Errorf("format must be \"ndjson\" or \"tar\", got %q", format)
+	}
+}
+
+func unmarshalBackup(data []byte, format string) (*backupManifest, error) {
+	switch format {
+	case "", "ndjson":
+		return unmarshalBackupNDJSON(data)
+	case "tar":
+		return unmarshalBackupTar(data)
+	default:
+		return nil, fmt.Errorf("format must be \"ndjson\" or \"tar\", got %q", format)
+	}
+}
+
+func backupGrafana(ctx context.Context, args BackupGrafanaParams) (string, error) {
+	manifest, err := buildBackupManifest(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := marshalBackup(manifest, args.Format)
+	if err != nil {
+		return "", err
+	}
+
+	format := args.Format
+	if format == "" {
+		format = "ndjson"
+	}
+
+	summary := fmt.Sprintf("Backup complete (%s, %d bytes):\n- folders: %d\n- dashboards: %d\n- datasources: %d\n",
+		format, len(data), len(manifest.Folders), len(manifest.Dashboards), len(manifest.Datasources))
+
+	if args.OutputPath != "" {
+		if err := os.WriteFile(args.OutputPath, data, 0o644); err != nil {
+			return "", fmt.Errorf("write backup to %s: %w", args.OutputPath, err)
+		}
+		return summary + fmt.Sprintf("- written to: %s", args.OutputPath), nil
+	}
+
+	return summary + "\n" + base64.StdEncoding.EncodeToString(data), nil
+}
+
+var BackupGrafana = mcpgrafana.MustTool(
+	"backup_grafana",
+	`Serialize a selectable subset of this Grafana instance (dashboards, folders, datasources) into a single backup, for disaster recovery or promoting objects between environments (e.g. dev to staging to prod).
+
+Filter with folderUid and/or tag to scope dashboards (and matching folders); omit both to back up everything. Datasource secrets are never included, since Grafana's API never returns them; restore_grafana recreates the datasource entry and flags which secure fields need to be re-entered by hand.
+
+Set format to "tar" for a tarball with one file per object, or leave it as the default "ndjson" for newline-delimited JSON records. Set outputPath to write the backup to a file on the MCP server's filesystem; otherwise the backup is returned inline as base64.`,
+	backupGrafana,
+	mcp.WithTitleAnnotation("Backup dashboards, folders, and datasources"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+type RestoreGrafanaParams struct {
+	Format    string `json:"format,omitempty" jsonschema:"description=Format the backup was written in: \"ndjson\" (default) or \"tar\""`
+	InputPath string `json:"inputPath,omitempty" jsonschema:"description=Path to the backup file on the MCP server's filesystem. Mutually exclusive with base64"`
+	Base64    string `json:"base64,omitempty" jsonschema:"description=The backup, base64-encoded. Mutually exclusive with inputPath"`
+	Force     bool   `json:"force,omitempty" jsonschema:"description=Overwrite objects that already exist and differ from the backup. Without this\\, existing objects are left untouched and reported as skipped"`
+	DryRun    bool   `json:"dryRun,omitempty" jsonschema:"description=Report what would be created/updated/skipped without writing anything"`
+}
+
+func loadRestoreInput(args RestoreGrafanaParams) ([]byte, error) {
+	if args.InputPath != "" && args.Base64 != "" {
+		return nil, fmt.Errorf("inputPath and base64 are mutually exclusive")
+	}
+	if args.InputPath != "" {
+		data, err := os.ReadFile(args.InputPath)
+		if err != nil {
+			return nil, fmt.Errorf("read backup from %s: %w", args.InputPath, err)
+		}
+		return data, nil
+	}
+	if args.Base64 != "" {
+		data, err := base64.StdEncoding.DecodeString(args.Base64)
+		if err != nil {
+			return nil, fmt.Errorf("decode base64 backup: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("one of inputPath or base64 is required")
+}
+
+// sortFoldersByParent topologically sorts items so every folder comes after
+// its ParentFolderUID (if any folder in items has that UID), preserving the
+// input order otherwise. The backup API returns folders in search order, not
+// parent-before-child, so restoreFolders would otherwise call CreateFolder
+// for a child before its parent exists and fail.
+func sortFoldersByParent(items []backupFolder) []backupFolder {
+	byUID := make(map[string]backupFolder, len(items))
+	for _, f := range items {
+		byUID[f.UID] = f
+	}
+
+	sorted := make([]backupFolder, 0, len(items))
+	visited := make(map[string]bool, len(items))
+	var visit func(f backupFolder)
+	visit = func(f backupFolder) {
+		if visited[f.UID] {
+			return
+		}
+		visited[f.UID] = true
+		if parent, ok := byUID[f.ParentFolderUID]; ok {
+			visit(parent)
+		}
+		sorted = append(sorted, f)
+	}
+	for _, f := range items {
+		visit(f)
+	}
+	return sorted
+}
+
+// restoreFolders recreates folders from a backup, keyed by UID so a
+// restore into an environment that already has the folder is a no-op
+// unless force is set, in which case its title/parent are updated. items is
+// sorted so a folder is always restored after its parent, regardless of the
+// order the backup API returned them in.
+func restoreFolders(ctx context.Context, items []backupFolder, force, dryRun bool) []string {
+	c := mcpgrafana.GrafanaClientFromContext(ctx)
+	var lines []string
+
+	for _, f := range sortFoldersByParent(items) {
+		existing, err := c.Folders.GetFolderByUID(folders.NewGetFolderByUIDParamsWithContext(ctx).WithFolderUID(f.UID))
+		exists := err == nil && existing != nil
+
+		switch {
+		case !exists:
+			if dryRun {
+				lines = append(lines, fmt.Sprintf("- folder %s (%q): would create", f.UID, f.Title))
+				continue
+			}
+			_, err := c.Folders.CreateFolder(folders.NewCreateFolderParamsWithContext(ctx).WithBody(&models.CreateFolderCommand{
+				UID: f.UID, Title: f.Title, ParentUID: f.ParentFolderUID,
+			}))
+			if err != nil {
+				lines = append(lines, fmt.Sprintf("- folder %s (%q): FAILED to create: %s", f.UID, f.Title, err))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("- folder %s (%q): created", f.UID, f.Title))
+		case force:
+			if dryRun {
+				lines = append(lines, fmt.Sprintf("- folder %s (%q): would update (already exists)", f.UID, f.Title))
+				continue
+			}
+			_, err := c.Folders.UpdateFolder(folders.NewUpdateFolderParamsWithContext(ctx).WithFolderUID(f.UID).WithBody(&models.UpdateFolderCommand{
+				Title: f.Title,
+			}))
+			if err != nil {
+				lines = append(lines, fmt.Sprintf("- folder %s (%q): FAILED to update: %s", f.UID, f.Title, err))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("- folder %s (%q): updated", f.UID, f.Title))
+		default:
+			lines = append(lines, fmt.Sprintf("- folder %s (%q): skipped (already exists, force not set)", f.UID, f.Title))
+		}
+	}
+	return lines
+}
+
+// restoreDashboards recreates dashboards from a backup using update_dashboard's
+// own Overwrite semantics, which already creates-or-updates idempotently
+// by UID, so there's no separate existence check to make here.
+func restoreDashboards(ctx context.Context, items []backupDashboard, force, dryRun bool) []string {
+	var lines []string
+
+	for _, d := range items {
+		_, existsErr := getDashboardByUID(ctx, GetDashboardByUIDParams{UID: d.UID})
+		exists := existsErr == nil
+
+		if exists && !force {
+			lines = append(lines, fmt.Sprintf("- dashboard %s: skipped (already exists, force not set)", d.UID))
+			continue
+		}
+		if dryRun {
+			if exists {
+				lines = append(lines, fmt.Sprintf("- dashboard %s: would update", d.UID))
+			} else {
+				lines = append(lines, fmt.Sprintf("- dashboard %s: would create", d.UID))
+			}
+			continue
+		}
+
+		result, err := updateDashboard(ctx, UpdateDashboardParams{
+			Dashboard: d.Dashboard,
+			FolderUID: d.FolderUID,
+			Overwrite: true,
+		})
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("- dashboard %s: FAILED: %s", d.UID, err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- dashboard %s: restored (version %d)", d.UID, *result.Version))
+	}
+	return lines
+}
+
+// restoreDatasources recreates datasource entries from a backup. Since
+// Grafana never returns secret values, every secure field listed in
+// SecureJSONFields comes back unset and must be re-entered by hand after
+// restore; this is surfaced in the summary rather than silently restoring
+// a half-configured datasource.
+func restoreDatasources(ctx context.Context, items []backupDatasource, force, dryRun bool) []string {
+	c := mcpgrafana.GrafanaClientFromContext(ctx)
+	var lines []string
+
+	for _, ds := range items {
+		_, err := c.Datasources.GetDataSourceByUID(ds.UID)
+		exists := err == nil
+
+		if exists && !force {
+			lines = append(lines, fmt.Sprintf("- datasource %s (%s): skipped (already exists, force not set)", ds.UID, ds.Name))
+			continue
+		}
+		if dryRun {
+			action := "would create"
+			if exists {
+				action = "would update"
+			}
+			lines = append(lines, fmt.Sprintf("- datasource %s (%s): %s (secrets not restored, re-enter by hand)", ds.UID, ds.Name, action))
+			continue
+		}
+
+		// Decode through JSON rather than populating the generated command
+		// structs' fields directly, for the same reason
+		// collectBackupDatasources does: it doesn't depend on exactly how
+		// the generated client types its Access/JSONData fields.
+		fieldsJSON, jsonErr := json.Marshal(datasourceBackupFields{
+			UID: ds.UID, Name: ds.Name, Type: ds.Type, Access: ds.Access, URL: ds.URL, JSONData: ds.JSONData,
+		})
+		if jsonErr != nil {
+			lines = append(lines, fmt.Sprintf("- datasource %s (%s): FAILED: %s", ds.UID, ds.Name, jsonErr))
+			continue
+		}
+
+		if exists {
+			var updateCmd models.UpdateDataSourceCommand
+			if err := json.Unmarshal(fieldsJSON, &updateCmd); err != nil {
+				lines = append(lines, fmt.Sprintf("- datasource %s (%s): FAILED: %s", ds.UID, ds.Name, err))
+				continue
+			}
+			_, err = c.Datasources.UpdateDataSourceByUID(ds.UID, &updateCmd)
+		} else {
+			var addCmd models.AddDataSourceCommand
+			if err := json.Unmarshal(fieldsJSON, &addCmd); err != nil {
+				lines = append(lines, fmt.Sprintf("- datasource %s (%s): FAILED: %s", ds.UID, ds.Name, err))
+				continue
+			}
+			_, err = c.Datasources.AddDataSource(&addCmd)
+		}
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("- datasource %s (%s): FAILED: %s", ds.UID, ds.Name, err))
+			continue
+		}
+
+		note := ""
+		if len(ds.SecureJSONFields) > 0 {
+			note = " (secrets not restored, re-enter by hand: "
+			first := true
+			for field := range ds.SecureJSONFields {
+				if !first {
+					note += ", "
+				}
+				note += field
+				first = false
+			}
+			note += ")"
+		}
+		lines = append(lines, fmt.Sprintf("- datasource %s (%s): restored%s", ds.UID, ds.Name, note))
+	}
+	return lines
+}
+
+func restoreGrafana(ctx context.Context, args RestoreGrafanaParams) (string, error) {
+	data, err := loadRestoreInput(args)
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := unmarshalBackup(data, args.Format)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if args.DryRun {
+		fmt.Fprintf(&b, "Dry run: restore from backup created at %s (no changes written)\n\n", manifest.CreatedAt)
+	} else {
+		fmt.Fprintf(&b, "Restoring from backup created at %s\n\n", manifest.CreatedAt)
+	}
+
+	if len(manifest.Folders) > 0 {
+		b.WriteString("Folders:\n")
+		for _, line := range restoreFolders(ctx, manifest.Folders, args.Force, args.DryRun) {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(manifest.Dashboards) > 0 {
+		b.WriteString("Dashboards:\n")
+		for _, line := range restoreDashboards(ctx, manifest.Dashboards, args.Force, args.DryRun) {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(manifest.Datasources) > 0 {
+		b.WriteString("Datasources:\n")
+		for _, line := range restoreDatasources(ctx, manifest.Datasources, args.Force, args.DryRun) {
+			b.WriteString(line + "\n")
+		}
+	}
+
+	return b.String(), nil
+}
+
+var RestoreGrafana = mcpgrafana.MustTool(
+	"restore_grafana",
+	`Recreate dashboards, folders, and/or datasources from a backup produced by backup_grafana.
+
+Objects are matched by UID: an object that doesn't exist yet is created; one that already exists is left untouched unless force is set, in which case it's overwritten. Datasource secrets are never in the backup (Grafana's API never returns them), so a restored datasource comes back with its secure fields unset — the response lists which ones need to be re-entered by hand.
+
+Set dryRun to true to see what would be created, updated, or skipped without writing anything.`,
+	restoreGrafana,
+	mcp.WithTitleAnnotation("Restore dashboards, folders, and datasources from backup"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+// AddBackupTools registers backup_grafana unconditionally, and
+// restore_grafana only when enableWriteTools is set, since restore can
+// create and overwrite dashboards, folders, and datasources, the same
+// gate applied to other state-mutating tool groups. Both are further
+// filtered by allowed, the same as every other AddXxxTools function.
+func AddBackupTools(mcp *server.MCPServer, enableWriteTools bool, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	mcpgrafana.RegisterTool(mcp, allowed, read, BackupGrafana)
+	if enableWriteTools {
+		write := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryWrite | mcpgrafana.ToolCategoryDestructive}
+		mcpgrafana.RegisterTool(mcp, allowed, write, RestoreGrafana)
+	}
+}
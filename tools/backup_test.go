@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortFoldersByParent(t *testing.T) {
+	t.Run("child ordered before parent is moved after it", func(t *testing.T) {
+		items := []backupFolder{
+			{UID: "child", Title: "Child", ParentFolderUID: "parent"},
+			{UID: "parent", Title: "Parent"},
+		}
+
+		sorted := sortFoldersByParent(items)
+
+		assert.Equal(t, []string{"parent", "child"}, uidsOf(sorted))
+	})
+
+	t.Run("already parent-before-child order is preserved", func(t *testing.T) {
+		items := []backupFolder{
+			{UID: "parent", Title: "Parent"},
+			{UID: "child", Title: "Child", ParentFolderUID: "parent"},
+		}
+
+		sorted := sortFoldersByParent(items)
+
+		assert.Equal(t, []string{"parent", "child"}, uidsOf(sorted))
+	})
+
+	t.Run("multi-level nesting out of order", func(t *testing.T) {
+		items := []backupFolder{
+			{UID: "grandchild", Title: "Grandchild", ParentFolderUID: "child"},
+			{UID: "child", Title: "Child", ParentFolderUID: "parent"},
+			{UID: "parent", Title: "Parent"},
+		}
+
+		sorted := sortFoldersByParent(items)
+
+		assert.Equal(t, []string{"parent", "child", "grandchild"}, uidsOf(sorted))
+	})
+
+	t.Run("parent not present in the backup is left as-is", func(t *testing.T) {
+		items := []backupFolder{
+			{UID: "child", Title: "Child", ParentFolderUID: "missing-parent"},
+		}
+
+		sorted := sortFoldersByParent(items)
+
+		assert.Equal(t, []string{"child"}, uidsOf(sorted))
+	})
+
+	t.Run("unrelated top-level folders keep their relative order", func(t *testing.T) {
+		items := []backupFolder{
+			{UID: "a", Title: "A"},
+			{UID: "b", Title: "B"},
+		}
+
+		sorted := sortFoldersByParent(items)
+
+		assert.Equal(t, []string{"a", "b"}, uidsOf(sorted))
+	})
+}
+
+func uidsOf(items []backupFolder) []string {
+	uids := make([]string, len(items))
+	for i, f := range items {
+		uids[i] = f.UID
+	}
+	return uids
+}
+
+func TestResolveBackupFlags(t *testing.T) {
+	t.Run("all flags omitted defaults to including everything", func(t *testing.T) {
+		folders, dashboards, datasources := resolveBackupFlags(BackupGrafanaParams{})
+
+		assert.True(t, folders)
+		assert.True(t, dashboards)
+		assert.True(t, datasources)
+	})
+
+	t.Run("any flag set explicitly disables the all-true default", func(t *testing.T) {
+		folders, dashboards, datasources := resolveBackupFlags(BackupGrafanaParams{IncludeDashboards: true})
+
+		assert.False(t, folders)
+		assert.True(t, dashboards)
+		assert.False(t, datasources)
+	})
+}
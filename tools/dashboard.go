@@ -1,12 +1,15 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
@@ -37,8 +40,9 @@ type UpdateDashboardParams struct {
 }
 
 // updateDashboard can be used to save an existing dashboard, or create a new one.
-// DISCLAIMER: Large-sized dashboard JSON can exhaust context windows. We will
-// implement features that address this in https://github.com/grafana/mcp-grafana/issues/101.
+// DISCLAIMER: Large-sized dashboard JSON can exhaust context windows. See
+// patch_dashboard, which sends a JSON Patch or JSON Merge Patch instead of
+// the full dashboard.
 func updateDashboard(ctx context.Context, args UpdateDashboardParams) (*models.PostDashboardOKBody, error) {
 	c := mcpgrafana.GrafanaClientFromContext(ctx)
 	cmd := &models.SaveDashboardCommand{
@@ -55,6 +59,138 @@ func updateDashboard(ctx context.Context, args UpdateDashboardParams) (*models.P
 	return dashboard.Payload, nil
 }
 
+type PatchDashboardParams struct {
+	UID       string          `json:"uid" jsonschema:"required,description=The UID of the dashboard to patch"`
+	Patch     json.RawMessage `json:"patch" jsonschema:"required,description=A JSON Patch (RFC 6902) array of operations\\, or a JSON Merge Patch (RFC 7396) object\\, to apply to the current dashboard JSON"`
+	PatchType string          `json:"patchType,omitempty" jsonschema:"description=Either \"json-patch\" or \"merge-patch\". Defaults to json-patch for an array patch and merge-patch for an object patch."`
+	FolderUID string          `json:"folderUid" jsonschema:"optional,description=The UID of the dashboard's folder"`
+	Message   string          `json:"message" jsonschema:"optional,description=Set a commit message for the version history"`
+	UserID    int64           `json:"userId" jsonschema:"optional,ID of the user making the change"`
+	DryRun    bool            `json:"dryRun,omitempty" jsonschema:"description=If true\\, don't save anything; instead return the computed dashboard JSON and a unified diff against the current dashboard"`
+}
+
+// detectPatchType guesses whether patch is a JSON Patch (an array of
+// operations) or a JSON Merge Patch (a partial object), so PatchType only
+// needs to be set explicitly when a caller wants to override it.
+func detectPatchType(patch json.RawMessage) string {
+	if trimmed := bytes.TrimSpace(patch); len(trimmed) > 0 && trimmed[0] == '[' {
+		return "json-patch"
+	}
+	return "merge-patch"
+}
+
+// validatePatchedDashboard checks that patched still looks like a usable
+// dashboard once a patch has been applied to it, so a malformed patch
+// fails here with a specific error instead of a much less helpful one
+// from PostDashboard (or, worse, succeeding and saving a broken dashboard).
+func validatePatchedDashboard(uid string, patched map[string]interface{}) error {
+	patchedUID, _ := patched["uid"].(string)
+	if patchedUID == "" {
+		return fmt.Errorf("missing required field %q", "uid")
+	}
+	if patchedUID != uid {
+		return fmt.Errorf("patch changed uid from %q to %q, which isn't supported", uid, patchedUID)
+	}
+	if title, _ := patched["title"].(string); title == "" {
+		return fmt.Errorf("missing required field %q", "title")
+	}
+	if _, ok := patched["panels"].([]interface{}); !ok {
+		return fmt.Errorf("missing or invalid required field %q (must be an array)", "panels")
+	}
+	if _, ok := patched["schemaVersion"]; !ok {
+		return fmt.Errorf("missing required field %q", "schemaVersion")
+	}
+	return nil
+}
+
+// patchDashboard applies a JSON Patch or JSON Merge Patch to the current
+// dashboard identified by args.UID, rather than requiring the full
+// dashboard JSON like updateDashboard does, and saves the result the same
+// way updateDashboard would. With args.DryRun it instead returns the
+// computed dashboard JSON and a unified diff against the current one,
+// without saving anything.
+func patchDashboard(ctx context.Context, args PatchDashboardParams) (string, error) {
+	current, err := getDashboardByUID(ctx, GetDashboardByUIDParams{UID: args.UID})
+	if err != nil {
+		return "", fmt.Errorf("get dashboard by uid %s: %w", args.UID, err)
+	}
+
+	originalBytes, err := json.Marshal(current.Dashboard)
+	if err != nil {
+		return "", fmt.Errorf("marshal current dashboard: %w", err)
+	}
+
+	patchType := args.PatchType
+	if patchType == "" {
+		patchType = detectPatchType(args.Patch)
+	}
+
+	var patchedBytes []byte
+	switch patchType {
+	case "json-patch":
+		patch, err := jsonpatch.DecodePatch(args.Patch)
+		if err != nil {
+			return "", fmt.Errorf("decode JSON Patch: %w", err)
+		}
+		patchedBytes, err = patch.Apply(originalBytes)
+		if err != nil {
+			return "", fmt.Errorf("apply JSON Patch: %w", err)
+		}
+	case "merge-patch":
+		patchedBytes, err = jsonpatch.MergePatch(originalBytes, args.Patch)
+		if err != nil {
+			return "", fmt.Errorf("apply JSON Merge Patch: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("patchType must be \"json-patch\" or \"merge-patch\", got %q", patchType)
+	}
+
+	var patched map[string]interface{}
+	if err := json.Unmarshal(patchedBytes, &patched); err != nil {
+		return "", fmt.Errorf("unmarshal patched dashboard: %w", err)
+	}
+
+	if err := validatePatchedDashboard(args.UID, patched); err != nil {
+		return "", fmt.Errorf("patched dashboard is invalid: %w", err)
+	}
+
+	if args.DryRun {
+		originalIndented, err := json.MarshalIndent(current.Dashboard, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("format current dashboard: %w", err)
+		}
+		patchedIndented, err := json.MarshalIndent(patched, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("format patched dashboard: %w", err)
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Dry run: patch dashboard %s (no changes written)\n\n", args.UID)
+		if diff := unifiedDiff(args.UID, string(originalIndented), args.UID, string(patchedIndented)); diff != "" {
+			b.WriteString(diff)
+		} else {
+			b.WriteString("(patch produces no changes)\n")
+		}
+		b.WriteString("\nComputed dashboard JSON:\n")
+		b.Write(patchedIndented)
+		b.WriteString("\n")
+		return b.String(), nil
+	}
+
+	result, err := updateDashboard(ctx, UpdateDashboardParams{
+		Dashboard: patched,
+		FolderUID: args.FolderUID,
+		Message:   args.Message,
+		Overwrite: true,
+		UserID:    args.UserID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Dashboard patched successfully:\n- UID: %s\n- URL: %s\n- Version: %d",
+		*result.UID, *result.URL, *result.Version), nil
+}
+
 // smartUpdateDashboard intelligently updates a dashboard by checking if it's provisioned
 // If provisioned, it uses file management; otherwise it uses direct dashboard API
 func smartUpdateDashboard(ctx context.Context, args UpdateDashboardParams) (string, error) {
@@ -73,7 +209,7 @@ func smartUpdateDashboard(ctx context.Context, args UpdateDashboardParams) (stri
 	}
 
 	// Check if dashboard is provisioned
-	managerInfo, err := getDashboardManager(ctx, GetDashboardManagerParams{ID: uid})
+	manager, err := getDashboardManagerInfo(ctx, GetDashboardManagerParams{ID: uid})
 	if err != nil {
 		// If we can't get manager info, fall back to regular update
 		result, err := updateDashboard(ctx, args)
@@ -84,22 +220,16 @@ func smartUpdateDashboard(ctx context.Context, args UpdateDashboardParams) (stri
 			*result.UID, *result.URL, *result.Version), nil
 	}
 
-	// Check if the dashboard is managed by GitOps
-	if strings.Contains(managerInfo, "managedBy:") && strings.Contains(managerInfo, "managerId:") && strings.Contains(managerInfo, "sourcePath:") {
-		// Dashboard is provisioned - extract manager details
-		lines := strings.Split(managerInfo, "\n")
-		var managerId, sourcePath string
-
-		for _, line := range lines {
-			if strings.HasPrefix(line, "- managerId:") {
-				managerId = strings.TrimSpace(strings.TrimPrefix(line, "- managerId:"))
-			}
-			if strings.HasPrefix(line, "- sourcePath:") {
-				sourcePath = strings.TrimSpace(strings.TrimPrefix(line, "- sourcePath:"))
-			}
-		}
+	// A dashboard managed via kubectl/the app-platform API has no Git
+	// source file to write to; it's updated by PUTting the Kubernetes-style
+	// resource envelope straight back to the app-platform endpoint instead.
+	if manager.Kind == DashboardManagerKindKubectl {
+		return updateDashboardViaAppPlatform(ctx, uid, dashboardMap)
+	}
 
-		if managerId == "" || sourcePath == "" {
+	// Check if the dashboard is managed by GitOps
+	if manager.Kind != DashboardManagerKindUnmanaged {
+		if manager.ManagerID == "" || manager.SourcePath == "" {
 			return "", fmt.Errorf("could not extract manager details from provisioned dashboard")
 		}
 
@@ -112,8 +242,8 @@ func smartUpdateDashboard(ctx context.Context, args UpdateDashboardParams) (stri
 
 		// Use file management to update the provisioned dashboard
 		fileResult, err := manageProvisioningRepositoryFile(ctx, ManageProvisioningRepositoryFileParams{
-			RepositoryName: managerId,
-			Path:           sourcePath,
+			RepositoryName: manager.ManagerID,
+			Path:           manager.SourcePath,
 			Operation:      "update",
 			Content:        string(contentBytes),
 			Message:        args.Message,
@@ -124,7 +254,7 @@ func smartUpdateDashboard(ctx context.Context, args UpdateDashboardParams) (stri
 		}
 
 		return fmt.Sprintf("✅ Provisioned dashboard updated via GitOps:\n- Repository: %s\n- File: %s\n- UID: %s\n\nFile management result:\n%s",
-			managerId, sourcePath, uid, fileResult), nil
+			manager.ManagerID, manager.SourcePath, uid, fileResult), nil
 	}
 
 	// Dashboard is not provisioned, use regular update
@@ -153,6 +283,20 @@ var UpdateDashboard = mcpgrafana.MustTool(
 	mcp.WithDestructiveHintAnnotation(true),
 )
 
+var PatchDashboard = mcpgrafana.MustTool(
+	"patch_dashboard",
+	`Update a dashboard by applying a JSON Patch (RFC 6902, an array of operations) or a JSON Merge Patch (RFC 7396, a partial object) to it, instead of sending the full dashboard JSON like update_dashboard requires.
+
+Fetches the current dashboard, applies the patch in the MCP process, validates the result (uid, title, panels, schemaVersion), and saves it the same way update_dashboard does.
+
+Use this instead of update_dashboard when you only need to change a handful of fields: it avoids round-tripping a potentially megabyte-sized dashboard JSON through the model's context window.
+
+Set dryRun to true to preview the change instead of saving it: the response is a unified diff against the current dashboard plus the computed dashboard JSON, with nothing written.`,
+	patchDashboard,
+	mcp.WithTitleAnnotation("Patch dashboard"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
 var SmartUpdateDashboard = mcpgrafana.MustTool(
 	"smart_update_dashboard",
 	`Smart dashboard update that automatically detects if a dashboard is provisioned.
@@ -198,6 +342,116 @@ type panelQuery struct {
 	Datasource datasourceInfo `json:"datasource"`
 }
 
+// DashboardVariable is a resolved templating.list entry: its current
+// (default) value alongside the metadata needed to understand what it
+// resolves to, so callers don't need to separately parse the dashboard
+// JSON to make sense of a "$datasource"-style reference.
+type DashboardVariable struct {
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	Label      string          `json:"label,omitempty"`
+	Query      string          `json:"query,omitempty"`
+	Regex      string          `json:"regex,omitempty"`
+	Datasource *datasourceInfo `json:"datasource,omitempty"`
+	Options    []string        `json:"options,omitempty"`
+	Current    string          `json:"current,omitempty"`
+	Multi      bool            `json:"multi,omitempty"`
+}
+
+// extractDashboardVariables reads db's templating.list into a slice of
+// DashboardVariable, resolving each variable's "current" value to a
+// single string when it is one (multi-value selections are left with an
+// empty Current, since there's no single value to substitute).
+func extractDashboardVariables(db map[string]any) []DashboardVariable {
+	templating, _ := db["templating"].(map[string]any)
+	list, _ := templating["list"].([]any)
+
+	var out []DashboardVariable
+	for _, v := range list {
+		variable, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		dv := DashboardVariable{}
+		dv.Name, _ = variable["name"].(string)
+		dv.Type, _ = variable["type"].(string)
+		dv.Label, _ = variable["label"].(string)
+		dv.Regex, _ = variable["regex"].(string)
+		dv.Query, _ = variable["query"].(string)
+		dv.Multi, _ = variable["multi"].(bool)
+
+		if ds, ok := variable["datasource"].(map[string]any); ok {
+			di := datasourceInfo{}
+			di.UID, _ = ds["uid"].(string)
+			di.Type, _ = ds["type"].(string)
+			dv.Datasource = &di
+		}
+
+		if options, ok := variable["options"].([]any); ok {
+			for _, o := range options {
+				if opt, ok := o.(map[string]any); ok {
+					if val, ok := opt["value"].(string); ok {
+						dv.Options = append(dv.Options, val)
+					}
+				}
+			}
+		}
+
+		if current, ok := variable["current"].(map[string]any); ok {
+			switch val := current["value"].(type) {
+			case string:
+				dv.Current = val
+			case []any:
+				if len(val) == 1 {
+					if s, ok := val[0].(string); ok {
+						dv.Current = s
+					}
+				}
+			}
+		}
+
+		out = append(out, dv)
+	}
+	return out
+}
+
+// templateVariableName strips the "$name", "${name}", or "[[name]]"
+// syntax Grafana uses to reference a template variable, returning the
+// bare variable name and whether raw was actually a reference.
+func templateVariableName(raw string) (string, bool) {
+	switch {
+	case strings.HasPrefix(raw, "${") && strings.HasSuffix(raw, "}"):
+		return strings.TrimSuffix(strings.TrimPrefix(raw, "${"), "}"), true
+	case strings.HasPrefix(raw, "$"):
+		return strings.TrimPrefix(raw, "$"), true
+	case strings.HasPrefix(raw, "[[") && strings.HasSuffix(raw, "]]"):
+		return strings.TrimSuffix(strings.TrimPrefix(raw, "[["), "]]"), true
+	default:
+		return "", false
+	}
+}
+
+// resolveDatasourceVariable substitutes ds.UID with the current value of
+// the template variable it references, when ds.UID is a reference (e.g.
+// "$datasource") and that variable resolves to a single value. Otherwise
+// ds is returned unchanged.
+func resolveDatasourceVariable(ds datasourceInfo, variables map[string]DashboardVariable) datasourceInfo {
+	name, ok := templateVariableName(ds.UID)
+	if !ok {
+		return ds
+	}
+	variable, ok := variables[name]
+	if !ok || variable.Current == "" {
+		return ds
+	}
+	ds.UID = variable.Current
+	if ds.Type == "" && variable.Datasource != nil {
+		ds.Type = variable.Datasource.Type
+	}
+	return ds
+}
+
 func GetDashboardPanelQueriesTool(ctx context.Context, args DashboardPanelQueriesParams) ([]panelQuery, error) {
 	result := make([]panelQuery, 0)
 
@@ -215,6 +469,11 @@ func GetDashboardPanelQueriesTool(ctx context.Context, args DashboardPanelQuerie
 		return result, fmt.Errorf("panels is not a JSON array")
 	}
 
+	variablesByName := make(map[string]DashboardVariable)
+	for _, v := range extractDashboardVariables(db) {
+		variablesByName[v.Name] = v
+	}
+
 	for _, p := range panels {
 		panel, ok := p.(map[string]any)
 		if !ok {
@@ -233,6 +492,7 @@ func GetDashboardPanelQueriesTool(ctx context.Context, args DashboardPanelQuerie
 				}
 			}
 		}
+		datasourceInfo = resolveDatasourceVariable(datasourceInfo, variablesByName)
 
 		targets, ok := panel["targets"].([]any)
 		if !ok {
@@ -259,22 +519,63 @@ func GetDashboardPanelQueriesTool(ctx context.Context, args DashboardPanelQuerie
 
 var GetDashboardPanelQueries = mcpgrafana.MustTool(
 	"get_dashboard_panel_queries",
-	"Get the title, query string, and datasource information for each panel in a dashboard. The datasource is an object with fields `uid` (which may be a concrete UID or a template variable like \"$datasource\") and `type`. If the datasource UID is a template variable, it won't be usable directly for queries. Returns an array of objects, each representing a panel, with fields: title, query, and datasource (an object with uid and type).",
+	"Get the title, query string, and datasource information for each panel in a dashboard. The datasource is an object with fields `uid` and `type`. When a panel's datasource is a template variable (e.g. \"$datasource\") and that variable resolves to a single current value, uid is substituted with the resolved value so it's directly usable; otherwise uid is left as the unresolved reference. Use get_dashboard_variables to inspect the dashboard's template variables directly. Returns an array of objects, each representing a panel, with fields: title, query, and datasource (an object with uid and type).",
 	GetDashboardPanelQueriesTool,
 	mcp.WithTitleAnnotation("Get dashboard panel queries"),
 	mcp.WithIdempotentHintAnnotation(true),
 	mcp.WithReadOnlyHintAnnotation(true),
 )
 
-func AddDashboardTools(mcp *server.MCPServer) {
-	GetDashboardByUID.Register(mcp)
-	UpdateDashboard.Register(mcp)
-	SmartUpdateDashboard.Register(mcp)
-	GetDashboardPanelQueries.Register(mcp)
+type GetDashboardVariablesParams struct {
+	UID string `json:"uid" jsonschema:"required,description=The UID of the dashboard"`
+}
+
+func getDashboardVariables(ctx context.Context, args GetDashboardVariablesParams) ([]DashboardVariable, error) {
+	dashboard, err := getDashboardByUID(ctx, GetDashboardByUIDParams{UID: args.UID})
+	if err != nil {
+		return nil, fmt.Errorf("get dashboard by uid: %w", err)
+	}
+
+	db, ok := dashboard.Dashboard.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("dashboard is not a JSON object")
+	}
+
+	return extractDashboardVariables(db), nil
+}
+
+var GetDashboardVariables = mcpgrafana.MustTool(
+	"get_dashboard_variables",
+	"Get a dashboard's template variables (templating.list), each resolved to its current default value alongside its type, datasource, regex, and options. Use this to understand what a panel's \"$variable\"-style datasource or query reference actually resolves to.",
+	getDashboardVariables,
+	mcp.WithTitleAnnotation("Get dashboard variables"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+// AddDashboardTools registers the dashboard tools with mcp, gating each on
+// allowed the same way the other AddXxxTools functions do.
+func AddDashboardTools(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	write := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryWrite}
+
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetDashboardByUID)
+	mcpgrafana.RegisterTool(mcp, allowed, write, UpdateDashboard)
+	mcpgrafana.RegisterTool(mcp, allowed, write, PatchDashboard)
+	mcpgrafana.RegisterTool(mcp, allowed, write, SmartUpdateDashboard)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetDashboardPanelQueries)
+	mcpgrafana.RegisterTool(mcp, allowed, write, ImportDashboardFromSource)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListDashboardsByTags)
+	mcpgrafana.RegisterTool(mcp, allowed, write, BulkUpdateDashboardTags)
+	mcpgrafana.RegisterTool(mcp, allowed, write, BulkMoveDashboards)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ValidateDashboard)
+	mcpgrafana.RegisterTool(mcp, allowed, write, MigrateDashboardSchema)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetDashboardVariables)
 }
 
-func AddGetDashboardManagerTool(mcp *server.MCPServer) {
-	GetDashboardManager.Register(mcp)
+func AddGetDashboardManagerTool(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetDashboardManager)
 }
 
 const getDashboardManagerToolPrompt = `Retrieves dashboard manager details to understand how a dashboard is managed and where its source files are located.
@@ -311,72 +612,222 @@ type DashboardManagerResponse struct {
 	} `json:"metadata"`
 }
 
-func getDashboardManager(ctx context.Context, args GetDashboardManagerParams) (string, error) {
-	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+// DashboardManagerKind is the "grafana.app/managedBy" annotation value,
+// identifying which kind of external system, if any, owns a dashboard's
+// source of truth.
+type DashboardManagerKind string
+
+const (
+	DashboardManagerKindRepo      DashboardManagerKind = "repo"
+	DashboardManagerKindPlugin    DashboardManagerKind = "plugin"
+	DashboardManagerKindKubectl   DashboardManagerKind = "kubectl"
+	DashboardManagerKindTerraform DashboardManagerKind = "terraform"
+	DashboardManagerKindUnmanaged DashboardManagerKind = "unmanaged"
+)
 
-	// Construct the API URL for the dashboard manager
-	apiPath := fmt.Sprintf("/apis/dashboard.grafana.app/v2alpha1/namespaces/default/dashboards/%s", args.ID)
-	url := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+// DashboardManager is the typed result of getDashboardManagerInfo: who, if
+// anyone, manages a dashboard's source of truth outside Grafana, and where
+// to find it. smartUpdateDashboard branches on Kind instead of re-parsing
+// getDashboardManager's prose response.
+type DashboardManager struct {
+	Kind       DashboardManagerKind `json:"kind"`
+	ManagedBy  string               `json:"managedBy,omitempty"`
+	ManagerID  string               `json:"managerId,omitempty"`
+	SourcePath string               `json:"sourcePath,omitempty"`
+	// RepoKind is the "grafana.app/managerKind" annotation, further
+	// identifying the kind of repository backing a Kind ==
+	// DashboardManagerKindRepo dashboard (e.g. "github", "local"). Empty
+	// for any other Kind.
+	RepoKind string `json:"repoKind,omitempty"`
+}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+// dashboardManagerKind maps a "grafana.app/managedBy" annotation value onto
+// DashboardManagerKind, falling back to DashboardManagerKindUnmanaged for an
+// empty or unrecognized value rather than erroring, since an unrecognized
+// value just means "not managed in a way this tool understands".
+func dashboardManagerKind(managedBy string) DashboardManagerKind {
+	switch DashboardManagerKind(managedBy) {
+	case DashboardManagerKindRepo, DashboardManagerKindPlugin, DashboardManagerKindKubectl, DashboardManagerKindTerraform:
+		return DashboardManagerKind(managedBy)
+	default:
+		return DashboardManagerKindUnmanaged
 	}
+}
 
-	// Add authorization header
+// dashboardAppPlatformURL builds the app-platform (Kubernetes-style)
+// resource URL for dashboard uid, shared by getDashboardManagerInfo (GET)
+// and updateDashboardViaAppPlatform (PUT).
+func dashboardAppPlatformURL(cfg mcpgrafana.GrafanaConfig, uid string) string {
+	apiPath := fmt.Sprintf("/apis/dashboard.grafana.app/v2alpha1/namespaces/default/dashboards/%s", uid)
+	return fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+}
+
+// applyDashboardAppPlatformAuth sets the same auth headers the legacy
+// dashboard API calls use, following cfg's auth precedence.
+func applyDashboardAppPlatformAuth(req *http.Request, cfg mcpgrafana.GrafanaConfig) {
 	if cfg.APIKey != "" {
 		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
 	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
 		req.Header.Set("X-Access-Token", cfg.AccessToken)
 		req.Header.Set("X-Grafana-Id", cfg.IDToken)
 	}
+}
 
-	// Create HTTP client with TLS configuration if available
+// newDashboardAppPlatformClient builds the http.Client for an app-platform
+// dashboard API call, honoring cfg's TLS settings if set.
+func newDashboardAppPlatformClient(cfg mcpgrafana.GrafanaConfig) (*http.Client, error) {
 	client := &http.Client{}
 	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
 		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
 		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
+			return nil, fmt.Errorf("failed to create custom transport: %w", err)
 		}
 		client.Transport = transport
 	}
+	return client, nil
+}
+
+// getDashboardManagerInfo fetches and parses dashboard id's manager
+// annotations into a DashboardManager. It returns DashboardManagerKindUnmanaged
+// (not an error) when the dashboard has no manager, doesn't exist, or has no
+// annotations at all.
+func getDashboardManagerInfo(ctx context.Context, args GetDashboardManagerParams) (*DashboardManager, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", dashboardAppPlatformURL(cfg, args.ID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	applyDashboardAppPlatformAuth(req, cfg)
+
+	client, err := newDashboardAppPlatformClient(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Make the request
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
+		return nil, fmt.Errorf("making request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Sprintf("No dashboard manager found for id: %s", args.ID), nil
+		return &DashboardManager{Kind: DashboardManagerKindUnmanaged}, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	// Parse the response
 	var response DashboardManagerResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+		return nil, fmt.Errorf("decoding response: %w", err)
 	}
 
 	if response.Metadata.Annotations == nil {
-		return fmt.Sprintf("No annotations found for dashboard %s", args.ID), nil
+		return &DashboardManager{Kind: DashboardManagerKindUnmanaged}, nil
 	}
 
 	managedBy := response.Metadata.Annotations["grafana.app/managedBy"]
 	managerID := response.Metadata.Annotations["grafana.app/managerId"]
 	sourcePath := response.Metadata.Annotations["grafana.app/sourcePath"]
+	repoKind := response.Metadata.Annotations["grafana.app/managerKind"]
 
 	if managedBy == "" || managerID == "" || sourcePath == "" {
-		return fmt.Sprintf("No manager annotations found for dashboard %s", args.ID), nil
+		return &DashboardManager{Kind: DashboardManagerKindUnmanaged}, nil
 	}
 
-	result := fmt.Sprintf("This dashboard is managed:\n- managedBy: %s\n- managerId: %s\n- sourcePath: %s",
-		managedBy, managerID, sourcePath)
+	return &DashboardManager{
+		Kind:       dashboardManagerKind(managedBy),
+		ManagedBy:  managedBy,
+		ManagerID:  managerID,
+		SourcePath: sourcePath,
+		RepoKind:   repoKind,
+	}, nil
+}
 
-	return result, nil
+// formatDashboardManager renders a DashboardManager as the prose
+// get_dashboard_manager's MCP text response has always returned, so
+// existing callers of the tool itself see no change; only
+// smartUpdateDashboard's internal consumption of the data moved off of it.
+func formatDashboardManager(dashboardID string, manager *DashboardManager) string {
+	if manager.Kind == DashboardManagerKindUnmanaged {
+		return fmt.Sprintf("No dashboard manager found for id: %s", dashboardID)
+	}
+	return fmt.Sprintf("This dashboard is managed:\n- managedBy: %s\n- managerId: %s\n- sourcePath: %s",
+		manager.ManagedBy, manager.ManagerID, manager.SourcePath)
+}
+
+func getDashboardManager(ctx context.Context, args GetDashboardManagerParams) (string, error) {
+	manager, err := getDashboardManagerInfo(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	return formatDashboardManager(args.ID, manager), nil
+}
+
+// dashboardAppPlatformResponse is the subset of a dashboard.grafana.app
+// Dashboard resource's response body smartUpdateDashboard needs to report
+// the result of an app-platform update.
+type dashboardAppPlatformResponse struct {
+	Metadata struct {
+		Name            string `json:"name"`
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+}
+
+// updateDashboardViaAppPlatform updates dashboard uid through the
+// dashboard.grafana.app app-platform API instead of the legacy
+// /api/dashboards/db endpoint, for instances where that legacy endpoint is
+// disabled and the dashboard is managed via kubectl. Unlike the GitOps file
+// management path, this wraps dashboardMap in the Kubernetes-style
+// apiVersion/kind/metadata/spec envelope the resource endpoint expects.
+func updateDashboardViaAppPlatform(ctx context.Context, uid string, dashboardMap map[string]interface{}) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	resource := map[string]interface{}{
+		"apiVersion": "dashboard.grafana.app/v2alpha1",
+		"kind":       "Dashboard",
+		"metadata": map[string]interface{}{
+			"name": uid,
+		},
+		"spec": dashboardMap,
+	}
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal dashboard resource: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", dashboardAppPlatformURL(cfg, uid), bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	applyDashboardAppPlatformAuth(req, cfg)
+
+	client, err := newDashboardAppPlatformClient(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("unexpected status code %d updating dashboard via app-platform API: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var updated dashboardAppPlatformResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+
+	return fmt.Sprintf("Dashboard updated via app-platform API:\n- UID: %s\n- ResourceVersion: %s",
+		updated.Metadata.Name, updated.Metadata.ResourceVersion), nil
 }
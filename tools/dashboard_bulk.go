@@ -0,0 +1,229 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+type ListDashboardsByTagsParams struct {
+	Tags      string `json:"tags" jsonschema:"required,description=Comma-delimited list of dashboard tags to match\\, e.g. \"production,team-a\""`
+	FolderUID string `json:"folderUid,omitempty" jsonschema:"description=Restrict results to dashboards in this folder UID"`
+}
+
+type dashboardTagSummary struct {
+	UID         string   `json:"uid"`
+	Title       string   `json:"title"`
+	FolderUID   string   `json:"folderUid"`
+	FolderTitle string   `json:"folderTitle"`
+	Tags        []string `json:"tags"`
+}
+
+// splitTags turns a comma-delimited tags string into a trimmed,
+// non-empty tag list, the way SearchDashboardsParams.Tag is used
+// elsewhere in this package.
+func splitTags(tags string) []string {
+	var out []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func listDashboardsByTags(ctx context.Context, args ListDashboardsByTagsParams) ([]dashboardTagSummary, error) {
+	tags := splitTags(args.Tags)
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("tags must contain at least one non-empty tag")
+	}
+
+	searchArgs := SearchDashboardsParams{Tag: tags}
+	if args.FolderUID != "" {
+		searchArgs.FolderUID = []string{args.FolderUID}
+	}
+
+	hits, err := searchDashboards(ctx, searchArgs)
+	if err != nil {
+		return nil, fmt.Errorf("search dashboards by tags %v: %w", tags, err)
+	}
+
+	summaries := make([]dashboardTagSummary, 0, len(hits))
+	for _, h := range hits {
+		summaries = append(summaries, dashboardTagSummary{
+			UID:         h.UID,
+			Title:       h.Title,
+			FolderUID:   h.FolderUID,
+			FolderTitle: h.FolderTitle,
+			Tags:        h.Tags,
+		})
+	}
+	return summaries, nil
+}
+
+var ListDashboardsByTags = mcpgrafana.MustTool(
+	"list_dashboards_by_tags",
+	"List dashboards that have all of a comma-delimited set of tags, optionally restricted to a folder. Returns each match's UID, title, folder, and tags.",
+	listDashboardsByTags,
+	mcp.WithTitleAnnotation("List dashboards by tags"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+// dashboardAsMap fetches a dashboard by UID and returns its JSON as a
+// plain map, the same shape smartUpdateDashboard and patchDashboard
+// operate on.
+func dashboardAsMap(ctx context.Context, uid string) (map[string]interface{}, error) {
+	current, err := getDashboardByUID(ctx, GetDashboardByUIDParams{UID: uid})
+	if err != nil {
+		return nil, fmt.Errorf("get dashboard by uid %s: %w", uid, err)
+	}
+
+	raw, err := json.Marshal(current.Dashboard)
+	if err != nil {
+		return nil, fmt.Errorf("marshal dashboard %s: %w", uid, err)
+	}
+
+	var dashboard map[string]interface{}
+	if err := json.Unmarshal(raw, &dashboard); err != nil {
+		return nil, fmt.Errorf("unmarshal dashboard %s: %w", uid, err)
+	}
+	return dashboard, nil
+}
+
+type BulkUpdateDashboardTagsParams struct {
+	UIDs       []string `json:"uids" jsonschema:"required,description=UIDs of the dashboards to update. Must be an array\\, even for a single dashboard"`
+	AddTags    []string `json:"addTags,omitempty" jsonschema:"description=Tags to add to each dashboard\\, if not already present"`
+	RemoveTags []string `json:"removeTags,omitempty" jsonschema:"description=Tags to remove from each dashboard\\, if present"`
+	Message    string   `json:"message,omitempty" jsonschema:"description=Set a commit message for the version history"`
+}
+
+// applyTagChanges returns dashboard's tags with addTags added (deduplicated)
+// and removeTags removed.
+func applyTagChanges(dashboard map[string]interface{}, addTags, removeTags []string) []string {
+	existing, _ := dashboard["tags"].([]interface{})
+
+	seen := make(map[string]bool, len(existing)+len(addTags))
+	remove := make(map[string]bool, len(removeTags))
+	for _, tag := range removeTags {
+		remove[tag] = true
+	}
+
+	var tags []string
+	for _, t := range existing {
+		tag, _ := t.(string)
+		if tag == "" || remove[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	for _, tag := range addTags {
+		if tag == "" || remove[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// bulkUpdateDashboardTags applies the same tag changes to many
+// dashboards in one call, routing each one through smartUpdateDashboard
+// so provisioned dashboards are updated via GitOps file management and
+// non-provisioned ones via the direct dashboard API. A failure on one
+// dashboard doesn't stop the others; failures are reported per UID.
+func bulkUpdateDashboardTags(ctx context.Context, args BulkUpdateDashboardTagsParams) (string, error) {
+	if len(args.UIDs) == 0 {
+		return "", fmt.Errorf("uids must contain at least one dashboard UID")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Bulk tag update across %d dashboard(s):\n\n", len(args.UIDs))
+
+	for _, uid := range args.UIDs {
+		dashboard, err := dashboardAsMap(ctx, uid)
+		if err != nil {
+			fmt.Fprintf(&b, "- %s: FAILED (%s)\n", uid, err)
+			continue
+		}
+
+		dashboard["tags"] = applyTagChanges(dashboard, args.AddTags, args.RemoveTags)
+
+		result, err := smartUpdateDashboard(ctx, UpdateDashboardParams{
+			Dashboard: dashboard,
+			Message:   args.Message,
+			Overwrite: true,
+		})
+		if err != nil {
+			fmt.Fprintf(&b, "- %s: FAILED (%s)\n", uid, err)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", uid, result)
+	}
+
+	return b.String(), nil
+}
+
+var BulkUpdateDashboardTags = mcpgrafana.MustTool(
+	"bulk_update_dashboard_tags",
+	"Add and/or remove tags across many dashboards in a single call, identified by UID. Each dashboard is updated via smart_update_dashboard's provisioning-aware logic, so GitOps-managed dashboards go through file management instead of the direct API.",
+	bulkUpdateDashboardTags,
+	mcp.WithTitleAnnotation("Bulk update dashboard tags"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+type BulkMoveDashboardsParams struct {
+	UIDs      []string `json:"uids" jsonschema:"required,description=UIDs of the dashboards to move. Must be an array\\, even for a single dashboard"`
+	FolderUID string   `json:"folderUid" jsonschema:"required,description=The UID of the folder to move every listed dashboard into. Use an empty string for the General folder"`
+	Message   string   `json:"message,omitempty" jsonschema:"description=Set a commit message for the version history"`
+}
+
+// bulkMoveDashboards moves many dashboards into the same folder in one
+// call, the same way bulkUpdateDashboardTags applies one tag change set
+// to many dashboards. As with tags, each dashboard is routed through
+// smartUpdateDashboard so GitOps-managed dashboards move via file
+// management.
+func bulkMoveDashboards(ctx context.Context, args BulkMoveDashboardsParams) (string, error) {
+	if len(args.UIDs) == 0 {
+		return "", fmt.Errorf("uids must contain at least one dashboard UID")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Bulk move of %d dashboard(s) to folder %q:\n\n", len(args.UIDs), args.FolderUID)
+
+	for _, uid := range args.UIDs {
+		dashboard, err := dashboardAsMap(ctx, uid)
+		if err != nil {
+			fmt.Fprintf(&b, "- %s: FAILED (%s)\n", uid, err)
+			continue
+		}
+
+		result, err := smartUpdateDashboard(ctx, UpdateDashboardParams{
+			Dashboard: dashboard,
+			FolderUID: args.FolderUID,
+			Message:   args.Message,
+			Overwrite: true,
+		})
+		if err != nil {
+			fmt.Fprintf(&b, "- %s: FAILED (%s)\n", uid, err)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", uid, result)
+	}
+
+	return b.String(), nil
+}
+
+var BulkMoveDashboards = mcpgrafana.MustTool(
+	"bulk_move_dashboards",
+	"Move many dashboards into the same folder in a single call, identified by UID. Each dashboard is updated via smart_update_dashboard's provisioning-aware logic, so GitOps-managed dashboards go through file management instead of the direct API.",
+	bulkMoveDashboards,
+	mcp.WithTitleAnnotation("Bulk move dashboards"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
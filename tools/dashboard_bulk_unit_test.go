@@ -0,0 +1,41 @@
+//go:build unit
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTags(t *testing.T) {
+	assert.Equal(t, []string{"production", "team-a"}, splitTags("production, team-a"))
+	assert.Equal(t, []string{"solo"}, splitTags("solo"))
+	assert.Nil(t, splitTags("  ,, "))
+}
+
+func TestApplyTagChanges(t *testing.T) {
+	dashboard := map[string]interface{}{
+		"tags": []interface{}{"production", "team-a"},
+	}
+
+	t.Run("adds new tags without duplicating existing ones", func(t *testing.T) {
+		got := applyTagChanges(dashboard, []string{"team-a", "team-b"}, nil)
+		assert.Equal(t, []string{"production", "team-a", "team-b"}, got)
+	})
+
+	t.Run("removes tags", func(t *testing.T) {
+		got := applyTagChanges(dashboard, nil, []string{"team-a"})
+		assert.Equal(t, []string{"production"}, got)
+	})
+
+	t.Run("remove takes precedence over add", func(t *testing.T) {
+		got := applyTagChanges(dashboard, []string{"team-a"}, []string{"team-a"})
+		assert.Equal(t, []string{"production"}, got)
+	})
+
+	t.Run("no tags field yields only added tags", func(t *testing.T) {
+		got := applyTagChanges(map[string]interface{}{}, []string{"team-a"}, nil)
+		assert.Equal(t, []string{"team-a"}, got)
+	})
+}
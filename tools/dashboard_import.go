@@ -0,0 +1,262 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-jsonnet"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// importCacheEntry holds a fetched/compiled dashboard source's content
+// alongside when it expires, so a repeated import for the same source
+// within its TTL can skip the network call (or the Jsonnet compile).
+type importCacheEntry struct {
+	content  []byte
+	expireAt time.Time
+}
+
+// importSourceCache is a small in-process, size-bounded LRU cache from
+// source key (source type + URL/ID) to fetched dashboard content. It
+// exists so import_dashboard_from_source doesn't refetch a community
+// dashboard, or recompile a Jsonnet source, on every call when a caller
+// sets contentCacheDuration. Keying by source identifier means a request
+// for a different URL/ID is always a cache miss, even if it arrives
+// before an older entry's TTL has elapsed.
+type importSourceCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      []string
+	entries    map[string]importCacheEntry
+}
+
+func newImportSourceCache(maxEntries int) *importSourceCache {
+	return &importSourceCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]importCacheEntry),
+	}
+}
+
+func (c *importSourceCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expireAt) {
+		delete(c.entries, key)
+		c.removeFromOrder(key)
+		return nil, false
+	}
+	c.touch(key)
+	return entry.content, true
+}
+
+func (c *importSourceCache) set(key string, content []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists {
+		if len(c.order) >= c.maxEntries {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.entries, oldest)
+		}
+		c.order = append(c.order, key)
+	} else {
+		c.touch(key)
+	}
+	c.entries[key] = importCacheEntry{content: content, expireAt: time.Now().Add(ttl)}
+}
+
+// touch moves key to the most-recently-used end of c.order. Callers must
+// hold c.mu.
+func (c *importSourceCache) touch(key string) {
+	c.removeFromOrder(key)
+	c.order = append(c.order, key)
+}
+
+// removeFromOrder removes key from c.order if present. Callers must hold
+// c.mu.
+func (c *importSourceCache) removeFromOrder(key string) {
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// dashboardImportCache caches fetched/compiled content across calls to
+// import_dashboard_from_source for the lifetime of the process.
+var dashboardImportCache = newImportSourceCache(64)
+
+type ImportDashboardFromSourceParams struct {
+	SourceType           string        `json:"sourceType" jsonschema:"required,description=One of \"url\"\\, \"grafana_com\"\\, \"jsonnet\"\\, or \"configmap\""`
+	URL                  string        `json:"url,omitempty" jsonschema:"description=Required for sourceType=url. A URL returning the dashboard JSON body"`
+	GrafanaComID         string        `json:"grafanaComId,omitempty" jsonschema:"description=Required for sourceType=grafana_com. The numeric dashboard ID from the grafana.com dashboard catalog"`
+	GrafanaComRevision   string        `json:"grafanaComRevision,omitempty" jsonschema:"description=Revision to fetch for sourceType=grafana_com. Defaults to \"1\""`
+	Jsonnet              string        `json:"jsonnet,omitempty" jsonschema:"description=Required for sourceType=jsonnet. A Jsonnet source that evaluates to the dashboard JSON object"`
+	ConfigMapJSON        string        `json:"configMapJson,omitempty" jsonschema:"description=Required for sourceType=configmap. The dashboard JSON\\, provided inline the way it would be stored in a Kubernetes ConfigMap"`
+	ContentCacheDuration time.Duration `json:"contentCacheDuration,omitempty" jsonschema:"description=How long to cache the fetched/compiled content for this exact source\\, as a Go duration string (e.g. \"5m\"). Zero (the default) disables caching, so every call refetches"`
+	FolderUID            string        `json:"folderUid,omitempty" jsonschema:"description=The UID of the folder to create/update the dashboard in"`
+	Message              string        `json:"message,omitempty" jsonschema:"description=Set a commit message for the version history"`
+	UserID               int64         `json:"userId,omitempty" jsonschema:"description=ID of the user making the change"`
+}
+
+// importSourceCacheKey identifies a source uniquely enough that two
+// different URLs/IDs/Jsonnet bodies never collide, so caching one can
+// never serve stale content for another.
+func importSourceCacheKey(args ImportDashboardFromSourceParams) string {
+	switch args.SourceType {
+	case "grafana_com":
+		rev := args.GrafanaComRevision
+		if rev == "" {
+			rev = "1"
+		}
+		return fmt.Sprintf("grafana_com:%s:%s", args.GrafanaComID, rev)
+	case "jsonnet":
+		return "jsonnet:" + args.Jsonnet
+	case "configmap":
+		return "configmap:" + args.ConfigMapJSON
+	default:
+		return "url:" + args.URL
+	}
+}
+
+func fetchDashboardSourceContent(ctx context.Context, args ImportDashboardFromSourceParams) ([]byte, error) {
+	switch args.SourceType {
+	case "url":
+		if args.URL == "" {
+			return nil, fmt.Errorf("url is required for sourceType=url")
+		}
+		return fetchURL(ctx, args.URL)
+	case "grafana_com":
+		if args.GrafanaComID == "" {
+			return nil, fmt.Errorf("grafanaComId is required for sourceType=grafana_com")
+		}
+		rev := args.GrafanaComRevision
+		if rev == "" {
+			rev = "1"
+		}
+		url := fmt.Sprintf("https://grafana.com/api/dashboards/%s/revisions/%s/download", args.GrafanaComID, rev)
+		return fetchURL(ctx, url)
+	case "jsonnet":
+		if args.Jsonnet == "" {
+			return nil, fmt.Errorf("jsonnet is required for sourceType=jsonnet")
+		}
+		vm := jsonnet.MakeVM()
+		out, err := vm.EvaluateAnonymousSnippet("dashboard.jsonnet", args.Jsonnet)
+		if err != nil {
+			return nil, fmt.Errorf("evaluate jsonnet: %w", err)
+		}
+		return []byte(out), nil
+	case "configmap":
+		if args.ConfigMapJSON == "" {
+			return nil, fmt.Errorf("configMapJson is required for sourceType=configmap")
+		}
+		return []byte(args.ConfigMapJSON), nil
+	default:
+		return nil, fmt.Errorf("sourceType must be one of \"url\", \"grafana_com\", \"jsonnet\", or \"configmap\", got %q", args.SourceType)
+	}
+}
+
+func fetchURL(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for %s: %w", url, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status code %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response from %s: %w", url, err)
+	}
+	return body, nil
+}
+
+// importDashboardFromSource creates or updates a dashboard from a remote
+// URL, a grafana.com community dashboard ID, a Jsonnet source, or an
+// inline ConfigMap-style JSON reference, borrowing the source model from
+// the grafana-operator GrafanaDashboard CRD. Successfully fetched or
+// compiled content is cached in dashboardImportCache for
+// args.ContentCacheDuration so repeated imports of the same source don't
+// refetch or recompile it.
+func importDashboardFromSource(ctx context.Context, args ImportDashboardFromSourceParams) (string, error) {
+	cacheKey := importSourceCacheKey(args)
+
+	content, cached := dashboardImportCache.get(cacheKey)
+	if !cached {
+		fetched, err := fetchDashboardSourceContent(ctx, args)
+		if err != nil {
+			return "", err
+		}
+		content = fetched
+		dashboardImportCache.set(cacheKey, content, args.ContentCacheDuration)
+	}
+
+	var dashboard map[string]interface{}
+	if err := json.Unmarshal(content, &dashboard); err != nil {
+		return "", fmt.Errorf("unmarshal dashboard JSON from source: %w", err)
+	}
+
+	result, err := updateDashboard(ctx, UpdateDashboardParams{
+		Dashboard: dashboard,
+		FolderUID: args.FolderUID,
+		Message:   args.Message,
+		Overwrite: true,
+		UserID:    args.UserID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	cacheNote := "not cached"
+	if cached {
+		cacheNote = "served from cache"
+	} else if args.ContentCacheDuration > 0 {
+		cacheNote = fmt.Sprintf("fetched and cached for %s", args.ContentCacheDuration)
+	} else {
+		cacheNote = "fetched (caching disabled)"
+	}
+
+	return fmt.Sprintf("Dashboard imported successfully:\n- UID: %s\n- URL: %s\n- Version: %d\n- Source: %s (%s)",
+		*result.UID, *result.URL, *result.Version, args.SourceType, cacheNote), nil
+}
+
+var ImportDashboardFromSource = mcpgrafana.MustTool(
+	"import_dashboard_from_source",
+	`Create or update a dashboard from an external source instead of inline JSON:
+- "url": fetch the dashboard JSON from a caller-provided URL
+- "grafana_com": fetch a community dashboard from the grafana.com dashboard catalog by its numeric ID (and optional revision)
+- "jsonnet": compile a Jsonnet source that evaluates to the dashboard JSON object
+- "configmap": use an inline dashboard JSON string, the way it would be stored in a Kubernetes ConfigMap
+
+Set contentCacheDuration (e.g. "10m") to cache the fetched or compiled content for that exact source, so repeated imports don't refetch or recompile it within the TTL. A different url/grafanaComId/jsonnet/configMapJson is always a cache miss, regardless of the TTL of any other cached source.`,
+	importDashboardFromSource,
+	mcp.WithTitleAnnotation("Import dashboard from external source"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
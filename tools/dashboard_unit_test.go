@@ -3,10 +3,14 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
-	"strings"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	mcpgrafana "github.com/grafana/mcp-grafana"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -95,29 +99,300 @@ func TestDashboardJSONFormat(t *testing.T) {
 }
 
 func TestProvisionedDashboardDetection(t *testing.T) {
-	t.Run("manager info parsing", func(t *testing.T) {
-		// Test parsing of manager information from getDashboardManager response
-		managerInfo := `This dashboard is managed:
-- managedBy: repo
-- managerId: my-repo-123
-- sourcePath: dashboards/production/api-metrics.json`
-
-		// Test the logic used in smartUpdateDashboard
-		var managerId, sourcePath string
-		if strings.Contains(managerInfo, "managedBy:") && strings.Contains(managerInfo, "managerId:") && strings.Contains(managerInfo, "sourcePath:") {
-			lines := strings.Split(managerInfo, "\n")
-			for _, line := range lines {
-				if strings.HasPrefix(line, "- managerId:") {
-					managerId = strings.TrimSpace(strings.TrimPrefix(line, "- managerId:"))
-				}
-				if strings.HasPrefix(line, "- sourcePath:") {
-					sourcePath = strings.TrimSpace(strings.TrimPrefix(line, "- sourcePath:"))
-				}
-			}
+	t.Run("managed dashboard reports its manager details", func(t *testing.T) {
+		// smartUpdateDashboard now consumes this struct directly instead of
+		// re-parsing getDashboardManager's prose response.
+		manager := &DashboardManager{
+			Kind:       DashboardManagerKindRepo,
+			ManagedBy:  "repo",
+			ManagerID:  "my-repo-123",
+			SourcePath: "dashboards/production/api-metrics.json",
 		}
 
-		// Verify parsing worked correctly
-		assert.Equal(t, "my-repo-123", managerId)
-		assert.Equal(t, "dashboards/production/api-metrics.json", sourcePath)
+		assert.NotEqual(t, DashboardManagerKindUnmanaged, manager.Kind)
+		assert.Equal(t, "my-repo-123", manager.ManagerID)
+		assert.Equal(t, "dashboards/production/api-metrics.json", manager.SourcePath)
+	})
+
+	t.Run("unmanaged dashboard has no manager details", func(t *testing.T) {
+		manager := &DashboardManager{Kind: DashboardManagerKindUnmanaged}
+		assert.Empty(t, manager.ManagerID)
+		assert.Empty(t, manager.SourcePath)
+	})
+
+	t.Run("dashboardManagerKind falls back to unmanaged for unrecognized values", func(t *testing.T) {
+		assert.Equal(t, DashboardManagerKindRepo, dashboardManagerKind("repo"))
+		assert.Equal(t, DashboardManagerKindUnmanaged, dashboardManagerKind(""))
+		assert.Equal(t, DashboardManagerKindUnmanaged, dashboardManagerKind("something-unexpected"))
+	})
+
+	t.Run("formatDashboardManager renders the same prose get_dashboard_manager always has", func(t *testing.T) {
+		managed := formatDashboardManager("dash-1", &DashboardManager{
+			Kind:       DashboardManagerKindRepo,
+			ManagedBy:  "repo",
+			ManagerID:  "my-repo-123",
+			SourcePath: "dashboards/production/api-metrics.json",
+		})
+		assert.Contains(t, managed, "managedBy: repo")
+		assert.Contains(t, managed, "managerId: my-repo-123")
+		assert.Contains(t, managed, "sourcePath: dashboards/production/api-metrics.json")
+
+		unmanaged := formatDashboardManager("dash-2", &DashboardManager{Kind: DashboardManagerKindUnmanaged})
+		assert.Contains(t, unmanaged, "No dashboard manager found for id: dash-2")
+	})
+}
+
+func TestUpdateDashboardViaAppPlatform(t *testing.T) {
+	dashboardMap := map[string]interface{}{
+		"uid":     "kubectl-dash",
+		"title":   "Kubectl Dashboard",
+		"version": 1,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/apis/dashboard.grafana.app/v2alpha1/namespaces/default/dashboards/kubectl-dash", r.URL.Path)
+
+		var resource map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&resource))
+
+		// Unlike the plain-JSON file management path (TestDashboardJSONFormat),
+		// the app-platform path must wrap the dashboard in the Kubernetes
+		// resource envelope.
+		assert.Equal(t, "dashboard.grafana.app/v2alpha1", resource["apiVersion"])
+		assert.Equal(t, "Dashboard", resource["kind"])
+		metadata, ok := resource["metadata"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "kubectl-dash", metadata["name"])
+		spec, ok := resource["spec"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "Kubectl Dashboard", spec["title"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"metadata":{"name":"kubectl-dash","resourceVersion":"42"}}`))
+	}))
+	defer server.Close()
+
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), mcpgrafana.GrafanaConfig{URL: server.URL})
+
+	result, err := updateDashboardViaAppPlatform(ctx, "kubectl-dash", dashboardMap)
+	require.NoError(t, err)
+	assert.Contains(t, result, "app-platform API")
+	assert.Contains(t, result, "UID: kubectl-dash")
+	assert.Contains(t, result, "ResourceVersion: 42")
+}
+
+func TestSmartUpdateDashboardDispatchesToAppPlatform(t *testing.T) {
+	var sawPut bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"metadata":{"annotations":{
+				"grafana.app/managedBy": "kubectl",
+				"grafana.app/managerId": "kubectl",
+				"grafana.app/sourcePath": "kubectl-dash"
+			}}}`))
+		case http.MethodPut:
+			sawPut = true
+			_, _ = w.Write([]byte(`{"metadata":{"name":"kubectl-dash","resourceVersion":"7"}}`))
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), mcpgrafana.GrafanaConfig{URL: server.URL})
+
+	result, err := smartUpdateDashboard(ctx, UpdateDashboardParams{
+		Dashboard: map[string]interface{}{
+			"uid":   "kubectl-dash",
+			"title": "Kubectl Dashboard",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, sawPut, "expected smartUpdateDashboard to PUT to the app-platform endpoint")
+	assert.Contains(t, result, "app-platform API")
+}
+
+func TestDetectPatchType(t *testing.T) {
+	assert.Equal(t, "json-patch", detectPatchType(json.RawMessage(`[{"op":"replace","path":"/title","value":"New"}]`)))
+	assert.Equal(t, "merge-patch", detectPatchType(json.RawMessage(`{"title":"New"}`)))
+	assert.Equal(t, "merge-patch", detectPatchType(json.RawMessage(`  {"title":"New"}  `)))
+}
+
+func TestValidatePatchedDashboard(t *testing.T) {
+	valid := map[string]interface{}{
+		"uid":           "dash-1",
+		"title":         "My Dashboard",
+		"panels":        []interface{}{},
+		"schemaVersion": float64(39),
+	}
+
+	t.Run("valid dashboard passes", func(t *testing.T) {
+		require.NoError(t, validatePatchedDashboard("dash-1", valid))
+	})
+
+	t.Run("missing uid", func(t *testing.T) {
+		patched := map[string]interface{}{"title": "My Dashboard", "panels": []interface{}{}, "schemaVersion": float64(39)}
+		require.Error(t, validatePatchedDashboard("dash-1", patched))
+	})
+
+	t.Run("uid changed by patch", func(t *testing.T) {
+		patched := map[string]interface{}{"uid": "other", "title": "My Dashboard", "panels": []interface{}{}, "schemaVersion": float64(39)}
+		err := validatePatchedDashboard("dash-1", patched)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "changed uid")
+	})
+
+	t.Run("missing title", func(t *testing.T) {
+		patched := map[string]interface{}{"uid": "dash-1", "panels": []interface{}{}, "schemaVersion": float64(39)}
+		require.Error(t, validatePatchedDashboard("dash-1", patched))
+	})
+
+	t.Run("panels not an array", func(t *testing.T) {
+		patched := map[string]interface{}{"uid": "dash-1", "title": "My Dashboard", "panels": "nope", "schemaVersion": float64(39)}
+		require.Error(t, validatePatchedDashboard("dash-1", patched))
+	})
+
+	t.Run("missing schemaVersion", func(t *testing.T) {
+		patched := map[string]interface{}{"uid": "dash-1", "title": "My Dashboard", "panels": []interface{}{}}
+		require.Error(t, validatePatchedDashboard("dash-1", patched))
+	})
+}
+
+func TestTemplateVariableName(t *testing.T) {
+	name, ok := templateVariableName("$datasource")
+	assert.True(t, ok)
+	assert.Equal(t, "datasource", name)
+
+	name, ok = templateVariableName("${datasource}")
+	assert.True(t, ok)
+	assert.Equal(t, "datasource", name)
+
+	name, ok = templateVariableName("[[datasource]]")
+	assert.True(t, ok)
+	assert.Equal(t, "datasource", name)
+
+	_, ok = templateVariableName("prometheus-uid-123")
+	assert.False(t, ok)
+}
+
+func TestResolveDatasourceVariable(t *testing.T) {
+	variables := map[string]DashboardVariable{
+		"datasource": {Name: "datasource", Type: "datasource", Current: "prom-uid-1", Datasource: &datasourceInfo{Type: "prometheus"}},
+		"multi":      {Name: "multi", Type: "query"}, // no single current value
+	}
+
+	t.Run("substitutes a resolvable variable reference", func(t *testing.T) {
+		got := resolveDatasourceVariable(datasourceInfo{UID: "$datasource"}, variables)
+		assert.Equal(t, datasourceInfo{UID: "prom-uid-1", Type: "prometheus"}, got)
+	})
+
+	t.Run("leaves a concrete uid untouched", func(t *testing.T) {
+		got := resolveDatasourceVariable(datasourceInfo{UID: "prom-uid-2", Type: "prometheus"}, variables)
+		assert.Equal(t, datasourceInfo{UID: "prom-uid-2", Type: "prometheus"}, got)
+	})
+
+	t.Run("leaves an unresolvable reference untouched", func(t *testing.T) {
+		got := resolveDatasourceVariable(datasourceInfo{UID: "$multi"}, variables)
+		assert.Equal(t, datasourceInfo{UID: "$multi"}, got)
+	})
+}
+
+func TestExtractDashboardVariables(t *testing.T) {
+	db := map[string]any{
+		"templating": map[string]any{
+			"list": []any{
+				map[string]any{
+					"name":  "datasource",
+					"type":  "datasource",
+					"label": "Datasource",
+					"query": "prometheus",
+					"current": map[string]any{
+						"value": "prom-uid-1",
+					},
+				},
+				map[string]any{
+					"name": "instance",
+					"type": "query",
+					"datasource": map[string]any{
+						"uid":  "prom-uid-1",
+						"type": "prometheus",
+					},
+					"options": []any{
+						map[string]any{"value": "instance-a"},
+						map[string]any{"value": "instance-b"},
+					},
+					"current": map[string]any{
+						"value": []any{"instance-a", "instance-b"},
+					},
+				},
+			},
+		},
+	}
+
+	variables := extractDashboardVariables(db)
+	require.Len(t, variables, 2)
+
+	assert.Equal(t, "datasource", variables[0].Name)
+	assert.Equal(t, "prom-uid-1", variables[0].Current)
+
+	assert.Equal(t, "instance", variables[1].Name)
+	assert.Equal(t, []string{"instance-a", "instance-b"}, variables[1].Options)
+	assert.Equal(t, "", variables[1].Current, "a multi-value current selection has no single resolved value")
+}
+
+func TestImportSourceCacheKey(t *testing.T) {
+	urlArgs := ImportDashboardFromSourceParams{SourceType: "url", URL: "https://example.com/dash.json"}
+	grafanaComArgs := ImportDashboardFromSourceParams{SourceType: "grafana_com", GrafanaComID: "1860"}
+	grafanaComArgsWithRev := ImportDashboardFromSourceParams{SourceType: "grafana_com", GrafanaComID: "1860", GrafanaComRevision: "1"}
+	grafanaComArgsOtherRev := ImportDashboardFromSourceParams{SourceType: "grafana_com", GrafanaComID: "1860", GrafanaComRevision: "37"}
+
+	assert.Equal(t, importSourceCacheKey(grafanaComArgs), importSourceCacheKey(grafanaComArgsWithRev), "default revision should match explicit revision 1")
+	assert.NotEqual(t, importSourceCacheKey(grafanaComArgs), importSourceCacheKey(grafanaComArgsOtherRev))
+	assert.NotEqual(t, importSourceCacheKey(urlArgs), importSourceCacheKey(grafanaComArgs))
+}
+
+func TestImportSourceCache(t *testing.T) {
+	t.Run("miss then hit", func(t *testing.T) {
+		c := newImportSourceCache(2)
+		_, ok := c.get("a")
+		assert.False(t, ok)
+
+		c.set("a", []byte("content-a"), time.Minute)
+		got, ok := c.get("a")
+		require.True(t, ok)
+		assert.Equal(t, "content-a", string(got))
+	})
+
+	t.Run("zero TTL disables caching", func(t *testing.T) {
+		c := newImportSourceCache(2)
+		c.set("a", []byte("content-a"), 0)
+		_, ok := c.get("a")
+		assert.False(t, ok)
+	})
+
+	t.Run("expired entry is evicted on read", func(t *testing.T) {
+		c := newImportSourceCache(2)
+		c.set("a", []byte("content-a"), -time.Minute)
+		_, ok := c.get("a")
+		assert.False(t, ok)
+	})
+
+	t.Run("evicts least recently used entry once full", func(t *testing.T) {
+		c := newImportSourceCache(2)
+		c.set("a", []byte("content-a"), time.Minute)
+		c.set("b", []byte("content-b"), time.Minute)
+		_, _ = c.get("a") // touch a so b becomes the least recently used entry
+		c.set("c", []byte("content-c"), time.Minute)
+
+		_, ok := c.get("a")
+		assert.True(t, ok, "a was touched, should still be cached")
+		_, ok = c.get("b")
+		assert.False(t, ok, "b was least recently used, should have been evicted")
+		_, ok = c.get("c")
+		assert.True(t, ok)
 	})
 }
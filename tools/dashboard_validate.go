@@ -0,0 +1,306 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// defaultMinSchemaVersion is used by validate_dashboard when the caller
+// doesn't set minSchemaVersion. It matches the schemaVersion
+// migrate_dashboard_schema migrates dashboards up to.
+const defaultMinSchemaVersion = 36
+
+// targetSchemaVersion is the schemaVersion migrate_dashboard_schema bumps
+// a dashboard to, alongside migrating any deprecated panel types it finds.
+const targetSchemaVersion = 39
+
+// deprecatedPanelTypeMigrations maps a deprecated panel type to its
+// replacement. Both sides are Grafana's own panel type identifiers.
+var deprecatedPanelTypeMigrations = map[string]string{
+	"singlestat": "stat",
+	"graph":      "timeseries",
+}
+
+type DashboardValidationIssue struct {
+	Severity string `json:"severity"` // "error" or "warning"
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+type ValidateDashboardParams struct {
+	UID              string                 `json:"uid,omitempty" jsonschema:"description=The UID of an existing dashboard to validate. Mutually exclusive with dashboard"`
+	Dashboard        map[string]interface{} `json:"dashboard,omitempty" jsonschema:"description=Inline dashboard JSON to validate instead of fetching one by UID. Mutually exclusive with uid"`
+	MinSchemaVersion int                    `json:"minSchemaVersion,omitempty" jsonschema:"description=Flag the dashboard if its schemaVersion is below this. Defaults to 36"`
+}
+
+// dashboardFromUIDOrInline resolves either a UID or an inline dashboard
+// JSON to a single dashboard map, the way patch_dashboard and the bulk
+// dashboard tools already standardize on fetch-then-mutate.
+func dashboardFromUIDOrInline(ctx context.Context, uid string, inline map[string]interface{}) (map[string]interface{}, error) {
+	if uid != "" && inline != nil {
+		return nil, fmt.Errorf("uid and dashboard are mutually exclusive")
+	}
+	if uid != "" {
+		return dashboardAsMap(ctx, uid)
+	}
+	if inline != nil {
+		return inline, nil
+	}
+	return nil, fmt.Errorf("one of uid or dashboard is required")
+}
+
+// knownDatasourceUIDs returns the set of datasource UIDs configured on
+// this Grafana instance, so validate_dashboard can flag a panel or query
+// referencing one that doesn't exist.
+func knownDatasourceUIDs(ctx context.Context) (map[string]bool, error) {
+	c := mcpgrafana.GrafanaClientFromContext(ctx)
+	resp, err := c.Datasources.GetDataSources()
+	if err != nil {
+		return nil, fmt.Errorf("list datasources: %w", err)
+	}
+
+	uids := make(map[string]bool, len(resp.Payload))
+	for _, ds := range resp.Payload {
+		uids[ds.UID] = true
+	}
+	return uids, nil
+}
+
+// datasourceUIDRef extracts a datasource UID from a panel or target's
+// "datasource" field, if it's in the {"type": ..., "uid": ...} object
+// form. The legacy plain-string form (a datasource name, "default", or a
+// template variable like "$datasource") isn't a UID reference and is
+// left unchecked.
+func datasourceUIDRef(v interface{}) (string, bool) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	uid, ok := obj["uid"].(string)
+	if !ok || uid == "" || strings.HasPrefix(uid, "$") {
+		return "", false
+	}
+	return uid, true
+}
+
+// walkPanels calls fn for every panel in the dashboard's panels array,
+// descending into row panels' nested "panels" the way Grafana itself does.
+func walkPanels(panels []interface{}, fn func(panel map[string]interface{})) {
+	for _, p := range panels {
+		panel, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fn(panel)
+		if nested, ok := panel["panels"].([]interface{}); ok {
+			walkPanels(nested, fn)
+		}
+	}
+}
+
+func validateDashboard(ctx context.Context, args ValidateDashboardParams) ([]DashboardValidationIssue, error) {
+	dashboard, err := dashboardFromUIDOrInline(ctx, args.UID, args.Dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	minSchemaVersion := args.MinSchemaVersion
+	if minSchemaVersion == 0 {
+		minSchemaVersion = defaultMinSchemaVersion
+	}
+
+	var issues []DashboardValidationIssue
+	addIssue := func(severity, code, format string, a ...interface{}) {
+		issues = append(issues, DashboardValidationIssue{Severity: severity, Code: code, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if title, _ := dashboard["title"].(string); title == "" {
+		addIssue("error", "missing_title", "dashboard is missing a title")
+	}
+	panels, ok := dashboard["panels"].([]interface{})
+	if !ok {
+		addIssue("error", "missing_panels", "dashboard is missing a panels array")
+		panels = nil
+	}
+	if _, ok := dashboard["schemaVersion"]; !ok {
+		addIssue("error", "missing_schema_version", "dashboard is missing schemaVersion")
+	} else if sv, ok := dashboard["schemaVersion"].(float64); ok && int(sv) < minSchemaVersion {
+		addIssue("warning", "schema_version_too_low", "schemaVersion %d is below the minimum of %d", int(sv), minSchemaVersion)
+	}
+
+	knownDatasources, err := knownDatasourceUIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seenPanelIDs := make(map[float64]bool)
+	walkPanels(panels, func(panel map[string]interface{}) {
+		if id, ok := panel["id"].(float64); ok {
+			if seenPanelIDs[id] {
+				addIssue("error", "duplicate_panel_id", "panel id %d is used by more than one panel", int(id))
+			}
+			seenPanelIDs[id] = true
+		}
+
+		title, _ := panel["title"].(string)
+		if uid, ok := datasourceUIDRef(panel["datasource"]); ok && !knownDatasources[uid] {
+			addIssue("error", "unknown_panel_datasource", "panel %q references unknown datasource uid %q", title, uid)
+		}
+
+		targets, _ := panel["targets"].([]interface{})
+		for _, t := range targets {
+			target, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if uid, ok := datasourceUIDRef(target["datasource"]); ok && !knownDatasources[uid] {
+				addIssue("error", "unknown_query_datasource", "a query in panel %q references unknown datasource uid %q", title, uid)
+			}
+		}
+	})
+
+	templating, _ := dashboard["templating"].(map[string]interface{})
+	variables, _ := templating["list"].([]interface{})
+	for _, v := range variables {
+		variable, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := variable["name"].(string)
+		varType, _ := variable["type"].(string)
+		if varType != "query" && varType != "custom" {
+			continue
+		}
+		options, _ := variable["options"].([]interface{})
+		if len(options) == 0 {
+			addIssue("warning", "empty_template_variable", "template variable %q (%s) resolves to no values", name, varType)
+		}
+	}
+
+	return issues, nil
+}
+
+var ValidateDashboard = mcpgrafana.MustTool(
+	"validate_dashboard",
+	`Validate a dashboard, either an existing one by uid or inline JSON, and report problems the dashboard save API will happily accept anyway:
+- missing required fields (title, panels, schemaVersion)
+- panels or queries referencing a datasource UID that doesn't exist on this Grafana instance
+- template variables that resolve to no values
+- duplicated panel IDs
+- schemaVersion below minSchemaVersion (defaults to 36)
+
+Returns an empty list when the dashboard has no issues.`,
+	validateDashboard,
+	mcp.WithTitleAnnotation("Validate dashboard"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+type MigrateDashboardSchemaParams struct {
+	UID       string                 `json:"uid,omitempty" jsonschema:"description=The UID of an existing dashboard to migrate. Mutually exclusive with dashboard"`
+	Dashboard map[string]interface{} `json:"dashboard,omitempty" jsonschema:"description=Inline dashboard JSON to migrate instead of fetching one by UID. Mutually exclusive with uid"`
+	Apply     bool                   `json:"apply,omitempty" jsonschema:"description=If true\\, save the migrated dashboard (uid is then required). Otherwise just return the transformed JSON"`
+	FolderUID string                 `json:"folderUid,omitempty" jsonschema:"description=The UID of the dashboard's folder\\, used only when apply is true"`
+	Message   string                 `json:"message,omitempty" jsonschema:"description=Set a commit message for the version history\\, used only when apply is true"`
+}
+
+// migratePanelTypes rewrites any deprecated panel type it finds to its
+// replacement, returning the panel types it changed for the summary.
+func migratePanelTypes(panels []interface{}) []string {
+	var migrated []string
+	walkPanels(panels, func(panel map[string]interface{}) {
+		oldType, _ := panel["type"].(string)
+		newType, deprecated := deprecatedPanelTypeMigrations[oldType]
+		if !deprecated {
+			return
+		}
+		panel["type"] = newType
+		title, _ := panel["title"].(string)
+		migrated = append(migrated, fmt.Sprintf("panel %q: %s -> %s", title, oldType, newType))
+	})
+	return migrated
+}
+
+func migrateDashboardSchema(ctx context.Context, args MigrateDashboardSchemaParams) (string, error) {
+	if args.Apply && args.UID == "" {
+		return "", fmt.Errorf("uid is required when apply is true")
+	}
+
+	dashboard, err := dashboardFromUIDOrInline(ctx, args.UID, args.Dashboard)
+	if err != nil {
+		return "", err
+	}
+
+	originalIndented, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("format original dashboard: %w", err)
+	}
+
+	panels, _ := dashboard["panels"].([]interface{})
+	migratedPanels := migratePanelTypes(panels)
+
+	schemaVersion, _ := dashboard["schemaVersion"].(float64)
+	bumpedSchemaVersion := int(schemaVersion) < targetSchemaVersion
+	if bumpedSchemaVersion {
+		dashboard["schemaVersion"] = targetSchemaVersion
+	}
+
+	migratedIndented, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("format migrated dashboard: %w", err)
+	}
+
+	var b strings.Builder
+	if len(migratedPanels) == 0 && !bumpedSchemaVersion {
+		b.WriteString("No migrations needed.\n\n")
+	} else {
+		b.WriteString("Migrations applied:\n")
+		for _, m := range migratedPanels {
+			fmt.Fprintf(&b, "- %s\n", m)
+		}
+		if bumpedSchemaVersion {
+			fmt.Fprintf(&b, "- schemaVersion: %d -> %d\n", int(schemaVersion), targetSchemaVersion)
+		}
+		b.WriteString("\n")
+	}
+
+	if !args.Apply {
+		if diff := unifiedDiff(args.UID, string(originalIndented), args.UID, string(migratedIndented)); diff != "" {
+			b.WriteString(diff)
+			b.WriteString("\n")
+		}
+		b.WriteString("Computed dashboard JSON (not saved; set apply=true to save):\n")
+		b.Write(migratedIndented)
+		b.WriteString("\n")
+		return b.String(), nil
+	}
+
+	result, err := updateDashboard(ctx, UpdateDashboardParams{
+		Dashboard: dashboard,
+		FolderUID: args.FolderUID,
+		Message:   args.Message,
+		Overwrite: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "Dashboard migrated and saved:\n- UID: %s\n- URL: %s\n- Version: %d",
+		*result.UID, *result.URL, *result.Version)
+	return b.String(), nil
+}
+
+var MigrateDashboardSchema = mcpgrafana.MustTool(
+	"migrate_dashboard_schema",
+	`Upgrade a dashboard's deprecated panel types (singlestat -> stat, graph -> timeseries) and bump its schemaVersion, either for an existing dashboard by uid or inline JSON.
+
+Without apply, returns the transformed JSON and a unified diff against the original without saving anything. Set apply to true to save the result the same way update_dashboard would (uid is required in that case).`,
+	migrateDashboardSchema,
+	mcp.WithTitleAnnotation("Migrate dashboard schema"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
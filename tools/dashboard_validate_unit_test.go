@@ -0,0 +1,57 @@
+//go:build unit
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatasourceUIDRef(t *testing.T) {
+	uid, ok := datasourceUIDRef(map[string]interface{}{"type": "prometheus", "uid": "abc-123"})
+	assert.True(t, ok)
+	assert.Equal(t, "abc-123", uid)
+
+	_, ok = datasourceUIDRef(map[string]interface{}{"type": "prometheus", "uid": "$datasource"})
+	assert.False(t, ok)
+
+	_, ok = datasourceUIDRef("Prometheus")
+	assert.False(t, ok)
+
+	_, ok = datasourceUIDRef(nil)
+	assert.False(t, ok)
+}
+
+func TestWalkPanels(t *testing.T) {
+	panels := []interface{}{
+		map[string]interface{}{"id": float64(1), "type": "timeseries"},
+		map[string]interface{}{
+			"id":   float64(2),
+			"type": "row",
+			"panels": []interface{}{
+				map[string]interface{}{"id": float64(3), "type": "stat"},
+			},
+		},
+	}
+
+	var seen []float64
+	walkPanels(panels, func(panel map[string]interface{}) {
+		seen = append(seen, panel["id"].(float64))
+	})
+	assert.Equal(t, []float64{1, 2, 3}, seen)
+}
+
+func TestMigratePanelTypes(t *testing.T) {
+	panels := []interface{}{
+		map[string]interface{}{"id": float64(1), "title": "Old singlestat", "type": "singlestat"},
+		map[string]interface{}{"id": float64(2), "title": "Old graph", "type": "graph"},
+		map[string]interface{}{"id": float64(3), "title": "Already modern", "type": "timeseries"},
+	}
+
+	migrated := migratePanelTypes(panels)
+	assert.Len(t, migrated, 2)
+	assert.Equal(t, "stat", panels[0].(map[string]interface{})["type"])
+	assert.Equal(t, "timeseries", panels[1].(map[string]interface{})["type"])
+	assert.Equal(t, "timeseries", panels[2].(map[string]interface{})["type"], "already-modern panel type is left alone")
+}
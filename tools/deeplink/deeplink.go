@@ -0,0 +1,262 @@
+// Package deeplink builds Grafana deeplink URLs for dashboards, panels,
+// and Explore. Each resource type gets its own builder and options struct
+// rather than one big flag bag, since a kiosk mode or editPanel flag only
+// makes sense for panels and a query list only makes sense for Explore.
+// Query-string JSON state (Explore's left/panes blobs) is built with
+// encoding/json rather than string concatenation, so datasource UIDs or
+// query expressions containing quotes or braces are escaped correctly.
+package deeplink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TimeRange is a Grafana time range in its own relative/absolute syntax,
+// e.g. From: "now-1h", To: "now".
+type TimeRange struct {
+	From string
+	To   string
+}
+
+// ExploreQuery is a single query in an Explore link's query list, matching
+// the shape Grafana's Explore state expects per query.
+type ExploreQuery struct {
+	RefID        string
+	Expr         string
+	QueryType    string
+	LegendFormat string
+	Datasource   string
+	MaxLines     int
+	Interval     string
+}
+
+// MarshalJSON renders q into Grafana's per-query JSON shape, omitting
+// unset fields and nesting Datasource as its own {"uid": ...} object,
+// matching how Explore queries reference a datasource.
+func (q ExploreQuery) MarshalJSON() ([]byte, error) {
+	raw := make(map[string]interface{}, 7)
+	if q.RefID != "" {
+		raw["refId"] = q.RefID
+	}
+	if q.Expr != "" {
+		raw["expr"] = q.Expr
+	}
+	if q.QueryType != "" {
+		raw["queryType"] = q.QueryType
+	}
+	if q.LegendFormat != "" {
+		raw["legendFormat"] = q.LegendFormat
+	}
+	if q.Datasource != "" {
+		raw["datasource"] = map[string]string{"uid": q.Datasource}
+	}
+	if q.MaxLines > 0 {
+		raw["maxLines"] = q.MaxLines
+	}
+	if q.Interval != "" {
+		raw["interval"] = q.Interval
+	}
+	return json.Marshal(raw)
+}
+
+// applyCommon sets the query-string keys shared by every resource type:
+// theme, orgId, and var-<name> template variable substitutions.
+func applyCommon(q url.Values, theme string, orgID *int, variables map[string]string) {
+	if theme != "" {
+		q.Set("theme", theme)
+	}
+	if orgID != nil {
+		q.Set("orgId", strconv.Itoa(*orgID))
+	}
+	for name, value := range variables {
+		q.Set("var-"+name, value)
+	}
+}
+
+// applyTimeRange sets the from/to query-string keys, if tr is non-nil.
+func applyTimeRange(q url.Values, tr *TimeRange) {
+	if tr == nil {
+		return
+	}
+	if tr.From != "" {
+		q.Set("from", tr.From)
+	}
+	if tr.To != "" {
+		q.Set("to", tr.To)
+	}
+}
+
+// parseBase parses baseURL and appends path to it, returning a *url.URL
+// ready to have query parameters set on it.
+func parseBase(baseURL, path string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/") + path)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	return u, nil
+}
+
+// ShareOptions requests a snapshot-style short link for a dashboard, via
+// Grafana's shareView=snapshot query parameter.
+type ShareOptions struct {
+	// ExpiresIn is how long the generated snapshot link stays valid, in
+	// Grafana's own duration syntax (e.g. "1h", "7d"). Empty means
+	// Grafana's default expiry.
+	ExpiresIn string
+}
+
+// DashboardOptions configures BuildDashboardURL.
+type DashboardOptions struct {
+	UID string
+	// Tab deep-links into a dashboard settings tab, e.g. "variables" or
+	// "annotations", rather than the dashboard view itself.
+	Tab       string
+	Theme     string
+	OrgID     *int
+	Variables map[string]string
+	TimeRange *TimeRange
+	// Share, if set, requests a snapshot-style short link instead of a
+	// regular dashboard view link.
+	Share *ShareOptions
+}
+
+// BuildDashboardURL builds a dashboard deeplink under baseURL.
+func BuildDashboardURL(baseURL string, opts DashboardOptions) (string, error) {
+	if opts.UID == "" {
+		return "", fmt.Errorf("dashboard uid is required")
+	}
+
+	u, err := parseBase(baseURL, "/d/"+url.PathEscape(opts.UID))
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	if opts.Tab != "" {
+		q.Set("tab", opts.Tab)
+	}
+	applyCommon(q, opts.Theme, opts.OrgID, opts.Variables)
+	applyTimeRange(q, opts.TimeRange)
+	if opts.Share != nil {
+		q.Set("shareView", "snapshot")
+		if opts.Share.ExpiresIn != "" {
+			q.Set("expiresIn", opts.Share.ExpiresIn)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Kiosk mode values for PanelOptions.Kiosk and PanelURL's dashboard-wide
+// equivalent: KioskFull hides all Grafana chrome, KioskTV additionally
+// auto-cycles and hides the nav bar only (Grafana's "TV mode").
+const (
+	KioskFull = "full"
+	KioskTV   = "tv"
+)
+
+// PanelOptions configures BuildPanelURL.
+type PanelOptions struct {
+	DashboardUID string
+	PanelID      int
+	// Edit selects editPanel=<id> (panel editor) instead of the default
+	// viewPanel=<id> (kiosk view of a single panel).
+	Edit bool
+	// Kiosk is one of KioskFull, KioskTV, or "" (no kiosk mode).
+	Kiosk     string
+	Theme     string
+	OrgID     *int
+	Variables map[string]string
+	TimeRange *TimeRange
+}
+
+// BuildPanelURL builds a panel deeplink under baseURL.
+func BuildPanelURL(baseURL string, opts PanelOptions) (string, error) {
+	if opts.DashboardUID == "" {
+		return "", fmt.Errorf("dashboard uid is required")
+	}
+
+	u, err := parseBase(baseURL, "/d/"+url.PathEscape(opts.DashboardUID))
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	if opts.Edit {
+		q.Set("editPanel", strconv.Itoa(opts.PanelID))
+	} else {
+		q.Set("viewPanel", strconv.Itoa(opts.PanelID))
+	}
+	switch opts.Kiosk {
+	case "":
+	case KioskFull:
+		q.Set("kiosk", "")
+	default:
+		q.Set("kiosk", opts.Kiosk)
+	}
+	applyCommon(q, opts.Theme, opts.OrgID, opts.Variables)
+	applyTimeRange(q, opts.TimeRange)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// ExploreOptions configures BuildExploreURL.
+type ExploreOptions struct {
+	Datasource string
+	Queries    []ExploreQuery
+	TimeRange  *TimeRange
+	Theme      string
+	OrgID      *int
+	// Panes selects Grafana 10+'s panes=<id>:{...}&schemaVersion=1
+	// encoding instead of the legacy left={...} object. Both encode the
+	// same state; panes is what Grafana's own "Share" link generates.
+	Panes bool
+}
+
+// BuildExploreURL builds an Explore deeplink under baseURL, encoding
+// Datasource, Queries, and TimeRange as Explore's query-string JSON
+// state.
+func BuildExploreURL(baseURL string, opts ExploreOptions) (string, error) {
+	if opts.Datasource == "" {
+		return "", fmt.Errorf("datasource uid is required")
+	}
+
+	u, err := parseBase(baseURL, "/explore")
+	if err != nil {
+		return "", err
+	}
+
+	state := map[string]interface{}{"datasource": opts.Datasource}
+	if len(opts.Queries) > 0 {
+		state["queries"] = opts.Queries
+	}
+	if opts.TimeRange != nil {
+		state["range"] = map[string]string{"from": opts.TimeRange.From, "to": opts.TimeRange.To}
+	}
+
+	q := u.Query()
+	if opts.Panes {
+		data, err := json.Marshal(map[string]interface{}{"ds0": state})
+		if err != nil {
+			return "", fmt.Errorf("marshal explore panes state: %w", err)
+		}
+		q.Set("panes", string(data))
+		q.Set("schemaVersion", "1")
+	} else {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return "", fmt.Errorf("marshal explore state: %w", err)
+		}
+		q.Set("left", string(data))
+	}
+	applyCommon(q, opts.Theme, opts.OrgID, nil)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
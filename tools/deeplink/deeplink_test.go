@@ -0,0 +1,161 @@
+package deeplink
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exploreState decodes the subset of Explore's left/panes JSON state this
+// test cares about.
+type exploreState struct {
+	Datasource string `json:"datasource"`
+	Queries    []struct {
+		RefID      string `json:"refId"`
+		Expr       string `json:"expr"`
+		Datasource struct {
+			UID string `json:"uid"`
+		} `json:"datasource"`
+	} `json:"queries"`
+	Range struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"range"`
+}
+
+func TestBuildDashboardURL(t *testing.T) {
+	t.Run("minimal", func(t *testing.T) {
+		got, err := BuildDashboardURL("http://localhost:3000", DashboardOptions{UID: "abc123"})
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:3000/d/abc123", got)
+	})
+
+	t.Run("tab, variables, and time range", func(t *testing.T) {
+		got, err := BuildDashboardURL("http://localhost:3000", DashboardOptions{
+			UID:       "abc123",
+			Tab:       "variables",
+			Variables: map[string]string{"env": "prod"},
+			TimeRange: &TimeRange{From: "now-1h", To: "now"},
+		})
+		require.NoError(t, err)
+
+		u, err := url.Parse(got)
+		require.NoError(t, err)
+		q := u.Query()
+		assert.Equal(t, "variables", q.Get("tab"))
+		assert.Equal(t, "prod", q.Get("var-env"))
+		assert.Equal(t, "now-1h", q.Get("from"))
+		assert.Equal(t, "now", q.Get("to"))
+	})
+
+	t.Run("share link with expiry", func(t *testing.T) {
+		got, err := BuildDashboardURL("http://localhost:3000", DashboardOptions{
+			UID:   "abc123",
+			Share: &ShareOptions{ExpiresIn: "7d"},
+		})
+		require.NoError(t, err)
+
+		u, err := url.Parse(got)
+		require.NoError(t, err)
+		q := u.Query()
+		assert.Equal(t, "snapshot", q.Get("shareView"))
+		assert.Equal(t, "7d", q.Get("expiresIn"))
+	})
+
+	t.Run("requires uid", func(t *testing.T) {
+		_, err := BuildDashboardURL("http://localhost:3000", DashboardOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestBuildPanelURL(t *testing.T) {
+	t.Run("view mode is the default", func(t *testing.T) {
+		got, err := BuildPanelURL("http://localhost:3000", PanelOptions{DashboardUID: "dash-123", PanelID: 5})
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:3000/d/dash-123?viewPanel=5", got)
+	})
+
+	t.Run("edit mode", func(t *testing.T) {
+		got, err := BuildPanelURL("http://localhost:3000", PanelOptions{DashboardUID: "dash-123", PanelID: 5, Edit: true})
+		require.NoError(t, err)
+		assert.Contains(t, got, "editPanel=5")
+		assert.NotContains(t, got, "viewPanel")
+	})
+
+	t.Run("kiosk tv mode", func(t *testing.T) {
+		got, err := BuildPanelURL("http://localhost:3000", PanelOptions{DashboardUID: "dash-123", PanelID: 5, Kiosk: KioskTV})
+		require.NoError(t, err)
+
+		u, err := url.Parse(got)
+		require.NoError(t, err)
+		assert.Equal(t, "tv", u.Query().Get("kiosk"))
+	})
+
+	t.Run("bare kiosk mode", func(t *testing.T) {
+		got, err := BuildPanelURL("http://localhost:3000", PanelOptions{DashboardUID: "dash-123", PanelID: 5, Kiosk: KioskFull})
+		require.NoError(t, err)
+		assert.Contains(t, got, "kiosk=")
+	})
+}
+
+func TestBuildExploreURL(t *testing.T) {
+	t.Run("datasource only, legacy left encoding", func(t *testing.T) {
+		got, err := BuildExploreURL("http://localhost:3000", ExploreOptions{Datasource: "prometheus-uid"})
+		require.NoError(t, err)
+
+		u, err := url.Parse(got)
+		require.NoError(t, err)
+
+		var state exploreState
+		require.NoError(t, json.Unmarshal([]byte(u.Query().Get("left")), &state))
+		assert.Equal(t, "prometheus-uid", state.Datasource)
+	})
+
+	t.Run("queries with special characters are escaped correctly", func(t *testing.T) {
+		got, err := BuildExploreURL("http://localhost:3000", ExploreOptions{
+			Datasource: `ds "quoted" {uid}`,
+			Queries: []ExploreQuery{
+				{RefID: "A", Expr: `rate(http_requests_total{job="api"}[5m])`, Datasource: `ds "quoted" {uid}`},
+			},
+			TimeRange: &TimeRange{From: "now-1h", To: "now"},
+		})
+		require.NoError(t, err)
+
+		u, err := url.Parse(got)
+		require.NoError(t, err)
+
+		var state exploreState
+		require.NoError(t, json.Unmarshal([]byte(u.Query().Get("left")), &state))
+		assert.Equal(t, `ds "quoted" {uid}`, state.Datasource)
+		require.Len(t, state.Queries, 1)
+		assert.Equal(t, `rate(http_requests_total{job="api"}[5m])`, state.Queries[0].Expr)
+		assert.Equal(t, `ds "quoted" {uid}`, state.Queries[0].Datasource.UID)
+		assert.Equal(t, "now-1h", state.Range.From)
+	})
+
+	t.Run("panes encoding", func(t *testing.T) {
+		got, err := BuildExploreURL("http://localhost:3000", ExploreOptions{
+			Datasource: "prometheus-uid",
+			Panes:      true,
+		})
+		require.NoError(t, err)
+
+		u, err := url.Parse(got)
+		require.NoError(t, err)
+		assert.Equal(t, "1", u.Query().Get("schemaVersion"))
+
+		var panes map[string]exploreState
+		require.NoError(t, json.Unmarshal([]byte(u.Query().Get("panes")), &panes))
+		pane, ok := panes["ds0"]
+		require.True(t, ok)
+		assert.Equal(t, "prometheus-uid", pane.Datasource)
+	})
+
+	t.Run("requires datasource", func(t *testing.T) {
+		_, err := BuildExploreURL("http://localhost:3000", ExploreOptions{})
+		assert.Error(t, err)
+	})
+}
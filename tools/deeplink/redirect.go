@@ -0,0 +1,42 @@
+package deeplink
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// RedirectHandler returns an http.Handler that verifies a signed
+// deeplink's sig/exp query parameters using signer and 302-redirects to
+// the resulting Grafana URL, or responds 403 if the signature is
+// invalid or expired. It's meant to be mounted (e.g. at /goto) on a
+// server that fronts Grafana for external clients, so those clients see
+// only the signed link, never the underlying Grafana URL, until they
+// follow it. grafanaBaseURL supplies the scheme and host to redirect
+// to, since an incoming request only carries the path and query that
+// were signed.
+func RedirectHandler(signer *Signer, grafanaBaseURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, err := signer.Verify(r.URL.String())
+		if err != nil {
+			http.Error(w, "invalid or expired link: "+err.Error(), http.StatusForbidden)
+			return
+		}
+
+		target, err := url.Parse(verified)
+		if err != nil {
+			http.Error(w, "invalid link", http.StatusForbidden)
+			return
+		}
+		if target.Host == "" {
+			base, err := url.Parse(grafanaBaseURL)
+			if err != nil {
+				http.Error(w, "server misconfigured", http.StatusInternalServerError)
+				return
+			}
+			target.Scheme = base.Scheme
+			target.Host = base.Host
+		}
+
+		http.Redirect(w, r, target.String(), http.StatusFound)
+	})
+}
@@ -0,0 +1,78 @@
+package deeplink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectHandler_RedirectsValidSignedLink(t *testing.T) {
+	signer, err := NewSigner("test-secret")
+	require.NoError(t, err)
+
+	signed, err := signer.Sign("http://localhost:3000/d/abc123?from=now-1h", time.Hour)
+	require.NoError(t, err)
+
+	signedURLPath, err := relPath(signed)
+	require.NoError(t, err)
+
+	handler := RedirectHandler(signer, "http://localhost:3000")
+
+	req := httptest.NewRequest(http.MethodGet, signedURLPath, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	location := rec.Header().Get("Location")
+	assert.Contains(t, location, "http://localhost:3000/d/abc123")
+	assert.Contains(t, location, "from=now-1h")
+	assert.NotContains(t, location, "sig=")
+}
+
+func TestRedirectHandler_RejectsInvalidSignature(t *testing.T) {
+	signer, err := NewSigner("test-secret")
+	require.NoError(t, err)
+
+	handler := RedirectHandler(signer, "http://localhost:3000")
+
+	req := httptest.NewRequest(http.MethodGet, "/d/abc123?exp=9999999999&sig=deadbeef", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestRedirectHandler_RejectsExpiredLink(t *testing.T) {
+	signer, err := NewSigner("test-secret")
+	require.NoError(t, err)
+
+	signed, err := signer.Sign("http://localhost:3000/d/abc123", -time.Hour)
+	require.NoError(t, err)
+
+	signedURLPath, err := relPath(signed)
+	require.NoError(t, err)
+
+	handler := RedirectHandler(signer, "http://localhost:3000")
+
+	req := httptest.NewRequest(http.MethodGet, signedURLPath, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+// relPath strips the scheme and host from a signed URL, the way a
+// client following a /goto-style link would actually send the request:
+// with only the path and query reaching the server.
+func relPath(signed string) (string, error) {
+	u, err := url.Parse(signed)
+	if err != nil {
+		return "", err
+	}
+	return u.Path + "?" + u.RawQuery, nil
+}
@@ -0,0 +1,73 @@
+package deeplink
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	signer, err := NewSigner("test-secret")
+	require.NoError(t, err)
+
+	signed, err := signer.Sign("http://localhost:3000/d/abc123?from=now-1h", time.Hour)
+	require.NoError(t, err)
+	assert.Contains(t, signed, "sig=")
+	assert.Contains(t, signed, "exp=")
+
+	verified, err := signer.Verify(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:3000/d/abc123?from=now-1h", verified)
+}
+
+func TestSigner_VerifyRejectsExpired(t *testing.T) {
+	signer, err := NewSigner("test-secret")
+	require.NoError(t, err)
+
+	signed, err := signer.Sign("http://localhost:3000/d/abc123", -time.Hour)
+	require.NoError(t, err)
+
+	_, err = signer.Verify(signed)
+	assert.ErrorContains(t, err, "expired")
+}
+
+func TestSigner_VerifyRejectsTamperedURL(t *testing.T) {
+	signer, err := NewSigner("test-secret")
+	require.NoError(t, err)
+
+	signed, err := signer.Sign("http://localhost:3000/d/abc123", time.Hour)
+	require.NoError(t, err)
+
+	tampered := strings.Replace(signed, "abc123", "evil-uid", 1)
+	_, err = signer.Verify(tampered)
+	assert.ErrorContains(t, err, "invalid signature")
+}
+
+func TestSigner_VerifyRejectsMissingParams(t *testing.T) {
+	signer, err := NewSigner("test-secret")
+	require.NoError(t, err)
+
+	_, err = signer.Verify("http://localhost:3000/d/abc123")
+	assert.Error(t, err)
+}
+
+func TestSigner_DifferentKeysDisagree(t *testing.T) {
+	signerA, err := NewSigner("key-a")
+	require.NoError(t, err)
+	signerB, err := NewSigner("key-b")
+	require.NoError(t, err)
+
+	signed, err := signerA.Sign("http://localhost:3000/d/abc123", time.Hour)
+	require.NoError(t, err)
+
+	_, err = signerB.Verify(signed)
+	assert.ErrorContains(t, err, "invalid signature")
+}
+
+func TestNewSigner_RejectsEmptyKey(t *testing.T) {
+	_, err := NewSigner("")
+	assert.Error(t, err)
+}
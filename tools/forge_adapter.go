@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// ForgeAdapter creates merge/pull requests directly against a specific Git
+// forge's API. It generalizes PRProvider (which only builds the forge's
+// web URL, for the interactive manual_submit_pull_request handoff) to the
+// forges submit_provisioning_merge_request can also create requests
+// against without a browser.
+type ForgeAdapter interface {
+	// Name matches the PRProvider of the same forge, so both tools resolve
+	// credentials/overrides the same way.
+	Name() string
+
+	// TokenEnvVar is the environment variable resolveForgeToken falls back
+	// to when no credential is stored for the repository and no explicit
+	// token was passed.
+	TokenEnvVar() string
+
+	// CreateMergeRequest opens a merge/pull request via the forge's API,
+	// authenticating with token, and returns its number/iid and HTML URL.
+	CreateMergeRequest(ctx context.Context, host, token string, req PRProviderRequest) (number int, htmlURL string, err error)
+}
+
+// forgeAdapters holds the adapters submit_provisioning_merge_request can
+// dispatch to, keyed by the same name PRProvider.Name() returns. A forge
+// with no entry here still supports the browser-based
+// manual_submit_pull_request flow via PRProvider; it just can't create the
+// request via API yet.
+var forgeAdapters = map[string]ForgeAdapter{
+	"github":    githubForgeAdapter{},
+	"gitlab":    gitlabForgeAdapter{},
+	"bitbucket": bitbucketForgeAdapter{},
+	"gitea":     giteaForgeAdapter{},
+}
+
+// resolveForgeAdapter picks the ForgeAdapter for repoURL, honoring the same
+// explicit override resolvePRProvider accepts.
+func resolveForgeAdapter(repoURL, override string) (ForgeAdapter, error) {
+	provider, err := resolvePRProvider(repoURL, override)
+	if err != nil {
+		return nil, err
+	}
+	adapter, ok := forgeAdapters[provider.Name()]
+	if !ok {
+		return nil, fmt.Errorf("%s does not support API-driven merge request creation yet; use manual_submit_pull_request to open the compare page in a browser instead", provider.DisplayName())
+	}
+	return adapter, nil
+}
+
+// resolveForgeToken picks the token a ForgeAdapter authenticates with, in
+// priority order: an explicit override, a credential stored for repo via
+// provisioning_credential_add, then the adapter's fallback environment
+// variable. providerOverride should be the same provider override (if any)
+// passed to resolveForgeAdapter, so a credential stored under an overridden
+// provider name is found rather than missed.
+func resolveForgeToken(override string, repo Repository, providerOverride, envVar string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+
+	if key, err := credentialKeyForRepository(repo, providerOverride); err == nil {
+		if cred, found, err := defaultCredentialStore.Get(key); err == nil && found {
+			if token, ok := cred.(TokenCredential); ok {
+				return token.Token, nil
+			}
+		}
+	}
+
+	if token := os.Getenv(envVar); token != "" {
+		return token, nil
+	}
+
+	return "", fmt.Errorf("no credential available: pass token, store one with provisioning_credential_add, or set %s", envVar)
+}
+
+// forgeJSONRequest makes an authenticated JSON request against requestURL,
+// applying headers on top of the default Accept/Content-Type, encoding
+// body as JSON when non-nil, and decoding the response into out when
+// non-nil. It's the generic counterpart of githubAPIRequest for forges
+// whose auth scheme or API path doesn't match GitHub's.
+func forgeJSONRequest(ctx context.Context, method, requestURL string, headers map[string]string, body, out any) error {
+	var reqBody *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return nil
+}
+
+// githubForgeAdapter creates pull requests via the same GitHub API client
+// submit_github_pull_request uses.
+type githubForgeAdapter struct{}
+
+func (githubForgeAdapter) Name() string        { return "github" }
+func (githubForgeAdapter) TokenEnvVar() string { return githubTokenEnvVar }
+
+func (githubForgeAdapter) CreateMergeRequest(ctx context.Context, host, token string, req PRProviderRequest) (int, string, error) {
+	owner, name, ok := parseGitHubOwnerRepo(req.RepoURL)
+	if !ok {
+		return 0, "", fmt.Errorf("could not parse owner/repo from GitHub URL %q", req.RepoURL)
+	}
+
+	client, err := newGitHubClient(host, token)
+	if err != nil {
+		return 0, "", fmt.Errorf("creating GitHub client: %w", err)
+	}
+
+	pr, _, err := client.PullRequests.Create(ctx, owner, name, &github.NewPullRequest{
+		Title: &req.Title,
+		Body:  &req.Body,
+		Head:  &req.HeadBranch,
+		Base:  &req.BaseBranch,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	return pr.GetNumber(), pr.GetHTMLURL(), nil
+}
+
+// gitlabForgeAdapter creates merge requests via the GitLab REST API,
+// authenticating with a personal access token in the PRIVATE-TOKEN header.
+type gitlabForgeAdapter struct{}
+
+func (gitlabForgeAdapter) Name() string        { return "gitlab" }
+func (gitlabForgeAdapter) TokenEnvVar() string { return "GITLAB_TOKEN" }
+
+func (gitlabForgeAdapter) CreateMergeRequest(ctx context.Context, host, token string, req PRProviderRequest) (int, string, error) {
+	owner, name, ok := parseGitHubOwnerRepo(req.RepoURL)
+	if !ok {
+		return 0, "", fmt.Errorf("could not parse owner/repo from GitLab URL %q", req.RepoURL)
+	}
+
+	projectPath := url.PathEscape(owner + "/" + name)
+	requestURL := fmt.Sprintf("https://%s/api/v4/projects/%s/merge_requests", host, projectPath)
+
+	var mr struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	err := forgeJSONRequest(ctx, "POST", requestURL, map[string]string{"PRIVATE-TOKEN": token}, map[string]string{
+		"source_branch": req.HeadBranch,
+		"target_branch": req.BaseBranch,
+		"title":         req.Title,
+		"description":   req.Body,
+	}, &mr)
+	if err != nil {
+		return 0, "", err
+	}
+	return mr.IID, mr.WebURL, nil
+}
+
+// bitbucketForgeAdapter creates pull requests via the Bitbucket Cloud REST
+// API, authenticating with a bearer token (a repository or workspace
+// access token).
+type bitbucketForgeAdapter struct{}
+
+func (bitbucketForgeAdapter) Name() string        { return "bitbucket" }
+func (bitbucketForgeAdapter) TokenEnvVar() string { return "BITBUCKET_TOKEN" }
+
+func (bitbucketForgeAdapter) CreateMergeRequest(ctx context.Context, host, token string, req PRProviderRequest) (int, string, error) {
+	workspace, repoSlug, ok := parseGitHubOwnerRepo(req.RepoURL)
+	if !ok {
+		return 0, "", fmt.Errorf("could not parse workspace/repo from Bitbucket URL %q", req.RepoURL)
+	}
+
+	requestURL := fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/%s/pullrequests", workspace, repoSlug)
+
+	type branchRef struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	}
+	body := struct {
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		Source      branchRef `json:"source"`
+		Destination branchRef `json:"destination"`
+	}{Title: req.Title, Description: req.Body}
+	body.Source.Branch.Name = req.HeadBranch
+	body.Destination.Branch.Name = req.BaseBranch
+
+	var pr struct {
+		ID    int `json:"id"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	err := forgeJSONRequest(ctx, "POST", requestURL, map[string]string{"Authorization": "Bearer " + token}, body, &pr)
+	if err != nil {
+		return 0, "", err
+	}
+	return pr.ID, pr.Links.HTML.Href, nil
+}
+
+// giteaForgeAdapter creates pull requests via the Gitea REST API,
+// authenticating with a personal access token.
+type giteaForgeAdapter struct{}
+
+func (giteaForgeAdapter) Name() string        { return "gitea" }
+func (giteaForgeAdapter) TokenEnvVar() string { return "GITEA_TOKEN" }
+
+func (giteaForgeAdapter) CreateMergeRequest(ctx context.Context, host, token string, req PRProviderRequest) (int, string, error) {
+	owner, name, ok := parseGitHubOwnerRepo(req.RepoURL)
+	if !ok {
+		return 0, "", fmt.Errorf("could not parse owner/repo from Gitea URL %q", req.RepoURL)
+	}
+
+	requestURL := fmt.Sprintf("https://%s/api/v1/repos/%s/%s/pulls", host, owner, name)
+
+	var pr struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	err := forgeJSONRequest(ctx, "POST", requestURL, map[string]string{"Authorization": "token " + token}, map[string]string{
+		"head":  req.HeadBranch,
+		"base":  req.BaseBranch,
+		"title": req.Title,
+		"body":  req.Body,
+	}, &pr)
+	if err != nil {
+		return 0, "", err
+	}
+	return pr.Number, pr.HTMLURL, nil
+}
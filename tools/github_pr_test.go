@@ -46,7 +46,7 @@ func TestManualSubmitGithubPullRequestValidation(t *testing.T) {
 			ctx := context.Background()
 
 			// Call the function
-			_, err := manualSubmitGithubPullRequest(ctx, tt.args)
+			_, err := manualSubmitPullRequest(ctx, tt.args)
 
 			// Check error conditions - in unit test environment, we expect errors due to no Grafana instance
 			if tt.wantErr {
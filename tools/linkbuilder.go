@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/grafana/mcp-grafana/tools/deeplink"
+)
+
+// deeplinkSigningKeyEnvVar is the environment variable holding the HMAC
+// key used to sign deeplinks when GenerateDeeplinkParams.Sign is set.
+const deeplinkSigningKeyEnvVar = "GRAFANA_DEEPLINK_SIGNING_KEY"
+
+// defaultSignExpiry is how long a signed deeplink stays valid if the
+// caller doesn't request a different SignExpiresIn.
+const defaultSignExpiry = 1 * time.Hour
+
+// LinkBuilder assembles the long-form deeplink URL for a
+// GenerateDeeplinkParams request and, on top of that, optionally
+// shortens it via Grafana's /api/short-urls API or HMAC-signs it. Both
+// of those paths build on the same long URL, which is why they're
+// methods on the same type rather than free functions duplicating the
+// Grafana config/HTTP client setup.
+type LinkBuilder struct {
+	cfg    mcpgrafana.GrafanaConfig
+	client *http.Client
+}
+
+// NewLinkBuilder builds a LinkBuilder from the GrafanaConfig found on
+// ctx, reusing the same HTTP client construction (including TLS
+// configuration) as the rest of the tools package.
+func NewLinkBuilder(ctx context.Context) (*LinkBuilder, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	client := &http.Client{}
+	if cfg.TLSConfig != nil {
+		transport, err := cfg.TLSConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create custom transport: %w", err)
+		}
+		client.Transport = transport
+	}
+
+	return &LinkBuilder{cfg: cfg, client: client}, nil
+}
+
+// BuildLongURL constructs the full, unshortened, unsigned deeplink URL
+// for args, dispatching to the deeplink package's per-resource builders.
+func (b *LinkBuilder) BuildLongURL(args GenerateDeeplinkParams) (string, error) {
+	baseURL := strings.TrimRight(b.cfg.URL, "/")
+	if baseURL == "" {
+		return "", fmt.Errorf("grafana url not configured. Please set GRAFANA_URL environment variable or X-Grafana-URL header")
+	}
+
+	var deeplinkURL string
+	var err error
+
+	switch strings.ToLower(args.ResourceType) {
+	case "dashboard":
+		if args.DashboardUID == nil {
+			return "", fmt.Errorf("dashboardUid is required for dashboard links")
+		}
+		opts := deeplink.DashboardOptions{
+			UID:       *args.DashboardUID,
+			Tab:       args.Tab,
+			Theme:     args.Theme,
+			OrgID:     args.OrgID,
+			Variables: args.Variables,
+			TimeRange: toDeeplinkTimeRange(args.TimeRange),
+		}
+		if strings.EqualFold(args.Mode, "share") {
+			opts.Share = &deeplink.ShareOptions{ExpiresIn: args.ExpiresIn}
+		}
+		deeplinkURL, err = deeplink.BuildDashboardURL(baseURL, opts)
+	case "panel":
+		if args.DashboardUID == nil {
+			return "", fmt.Errorf("dashboardUid is required for panel links")
+		}
+		if args.PanelID == nil {
+			return "", fmt.Errorf("panelId is required for panel links")
+		}
+		opts := deeplink.PanelOptions{
+			DashboardUID: *args.DashboardUID,
+			PanelID:      *args.PanelID,
+			Edit:         strings.EqualFold(args.Mode, "edit"),
+			Theme:        args.Theme,
+			OrgID:        args.OrgID,
+			Variables:    args.Variables,
+			TimeRange:    toDeeplinkTimeRange(args.TimeRange),
+		}
+		switch strings.ToLower(args.Mode) {
+		case "kiosk":
+			opts.Kiosk = deeplink.KioskFull
+		case "kiosk-tv":
+			opts.Kiosk = deeplink.KioskTV
+		}
+		deeplinkURL, err = deeplink.BuildPanelURL(baseURL, opts)
+	case "explore":
+		if args.DatasourceUID == nil {
+			return "", fmt.Errorf("datasourceUid is required for explore links")
+		}
+		deeplinkURL, err = deeplink.BuildExploreURL(baseURL, deeplink.ExploreOptions{
+			Datasource: *args.DatasourceUID,
+			Queries:    toDeeplinkQueries(args.Queries),
+			TimeRange:  toDeeplinkTimeRange(args.TimeRange),
+			Theme:      args.Theme,
+			OrgID:      args.OrgID,
+			Panes:      strings.EqualFold(args.Mode, "share"),
+		})
+	default:
+		return "", fmt.Errorf("unsupported resource type: %s. Supported types are: dashboard, panel, explore", args.ResourceType)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return deeplinkURL, nil
+}
+
+// shortURLRequest is the body of a POST to Grafana's /api/short-urls.
+type shortURLRequest struct {
+	Path string `json:"path"`
+}
+
+// shortURLResponse is Grafana's /api/short-urls response shape.
+type shortURLResponse struct {
+	UID string `json:"uid"`
+	URL string `json:"url"`
+}
+
+// Shorten calls Grafana's POST /api/short-urls with longURL's path and
+// query, returning the resulting /goto/<uid> URL it hands back.
+func (b *LinkBuilder) Shorten(ctx context.Context, longURL string) (string, error) {
+	baseURL := strings.TrimRight(b.cfg.URL, "/")
+	relPath := strings.TrimPrefix(longURL, baseURL+"/")
+
+	body, err := json.Marshal(shortURLRequest{Path: relPath})
+	if err != nil {
+		return "", fmt.Errorf("marshal short-url request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/api/short-urls", strings.NewReader(string(body)))
+	if err != nil {
+		return "", fmt.Errorf("creating short-url request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.cfg.APIKey)
+	} else if b.cfg.AccessToken != "" && b.cfg.IDToken != "" {
+		req.Header.Set("X-Access-Token", b.cfg.AccessToken)
+		req.Header.Set("X-Grafana-Id", b.cfg.IDToken)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("calling short-url api: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("short-url api returned status %d", resp.StatusCode)
+	}
+
+	var decoded shortURLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return "", fmt.Errorf("decoding short-url response: %w", err)
+	}
+	if decoded.URL == "" {
+		return "", fmt.Errorf("short-url api response did not include a url")
+	}
+
+	return decoded.URL, nil
+}
+
+// Sign HMAC-signs longURL using the key configured via
+// GRAFANA_DEEPLINK_SIGNING_KEY, valid for ttl (falling back to
+// defaultSignExpiry if ttl is zero).
+func (b *LinkBuilder) Sign(longURL string, ttl time.Duration) (string, error) {
+	key := os.Getenv(deeplinkSigningKeyEnvVar)
+	if key == "" {
+		return "", fmt.Errorf("%s is not set; cannot sign deeplinks", deeplinkSigningKeyEnvVar)
+	}
+	signer, err := deeplink.NewSigner(key)
+	if err != nil {
+		return "", err
+	}
+	if ttl <= 0 {
+		ttl = defaultSignExpiry
+	}
+	return signer.Sign(longURL, ttl)
+}
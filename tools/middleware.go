@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ToolHandlerFunc is the common shape of a dynamically registered tool
+// handler: it receives the raw arguments for a proxied call (already
+// resolved to a map, regardless of the original typed params struct) and
+// returns the rendered tool result text or an error.
+type ToolHandlerFunc func(ctx context.Context, args map[string]interface{}) (string, error)
+
+// ToolMiddleware wraps a ToolHandlerFunc to add cross-cutting behavior
+// around dynamically registered tool handlers, such as the Tempo proxy
+// tools and the GitHub PR tool.
+type ToolMiddleware func(next ToolHandlerFunc) ToolHandlerFunc
+
+// Chain composes middlewares so that the first middleware in the list is the
+// outermost, i.e. Chain(a, b, c)(h) behaves like a(b(c(h))).
+func Chain(middlewares ...ToolMiddleware) ToolMiddleware {
+	return func(next ToolHandlerFunc) ToolHandlerFunc {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// panicRecoveries counts panics recovered by WithRecovery across all
+// middleware-wrapped handlers, for metrics/testing.
+var panicRecoveries int64
+
+// PanicRecoveries returns the number of panics recovered by WithRecovery
+// since process start.
+func PanicRecoveries() int64 {
+	return atomic.LoadInt64(&panicRecoveries)
+}
+
+// RecoveryOption configures WithRecovery.
+type RecoveryOption func(*recoveryOptions)
+
+type recoveryOptions struct {
+	stackTraceLines int
+}
+
+// WithStackTraceLines limits the number of stack trace lines included in the
+// recovered error's log entry. Defaults to 16.
+func WithStackTraceLines(n int) RecoveryOption {
+	return func(o *recoveryOptions) {
+		o.stackTraceLines = n
+	}
+}
+
+// WithRecovery returns a ToolMiddleware, modeled on the grpc-ecosystem
+// recovery interceptor, that turns a panic inside next into a structured
+// tool error (with the recovered value and a trimmed stack trace) instead of
+// tearing down the MCP server goroutine.
+func WithRecovery(logger *slog.Logger, opts ...RecoveryOption) ToolMiddleware {
+	o := recoveryOptions{stackTraceLines: 16}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next ToolHandlerFunc) ToolHandlerFunc {
+		return func(ctx context.Context, args map[string]interface{}) (result string, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					atomic.AddInt64(&panicRecoveries, 1)
+					stack := trimStack(debug.Stack(), o.stackTraceLines)
+					logger.Error("recovered from panic in tool handler", "panic", r, "stack", stack)
+					err = fmt.Errorf("tool handler panicked: %v", r)
+				}
+			}()
+			return next(ctx, args)
+		}
+	}
+}
+
+// trimStack keeps only the first n lines of a stack trace, so panic logs
+// don't drown out everything else.
+func trimStack(stack []byte, n int) string {
+	lines := strings.Split(string(stack), "\n")
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WithTimeout returns a ToolMiddleware that bounds how long next may run for,
+// returning an error if the deadline is exceeded.
+func WithTimeout(d time.Duration) ToolMiddleware {
+	return func(next ToolHandlerFunc) ToolHandlerFunc {
+		return func(ctx context.Context, args map[string]interface{}) (string, error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			type handlerResult struct {
+				text string
+				err  error
+			}
+			done := make(chan handlerResult, 1)
+			go func() {
+				text, err := next(ctx, args)
+				done <- handlerResult{text, err}
+			}()
+
+			select {
+			case r := <-done:
+				return r.text, r.err
+			case <-ctx.Done():
+				return "", fmt.Errorf("tool handler timed out after %s", d)
+			}
+		}
+	}
+}
+
+// MetricsRecorder receives the outcome of every tool handler invocation.
+// Implementations might forward counts/latencies to Prometheus, statsd, etc.
+type MetricsRecorder interface {
+	RecordToolCall(toolName string, duration time.Duration, err error)
+}
+
+// activeMetricsRecorder, when non-nil, is included in defaultToolMiddleware
+// and used by RecordToolMetrics for handlers that don't go through the
+// map-based ToolHandlerFunc chain. It's set once at startup via
+// SetMetricsRecorder, typically with the *health.Server returned by
+// health.Server.EnableMetrics.
+var activeMetricsRecorder MetricsRecorder
+
+// SetMetricsRecorder installs recorder as the destination for tool call
+// metrics across both the dynamic middleware chain and the statically typed
+// tool handlers instrumented with RecordToolMetrics. Passing nil disables
+// metrics recording again.
+func SetMetricsRecorder(recorder MetricsRecorder) {
+	activeMetricsRecorder = recorder
+}
+
+// RecordToolMetrics reports a single invocation of toolName to the active
+// MetricsRecorder, for tool handlers with typed parameters that don't go
+// through the map-based ToolHandlerFunc signature (e.g. the Alloy and admin
+// tools). It's a no-op if no recorder has been installed via
+// SetMetricsRecorder.
+func RecordToolMetrics(toolName string, duration time.Duration, err error) {
+	if activeMetricsRecorder != nil {
+		activeMetricsRecorder.RecordToolCall(toolName, duration, err)
+	}
+}
+
+// WithMetrics returns a ToolMiddleware that records call duration and
+// success/failure for every invocation via recorder.
+func WithMetrics(toolName string, recorder MetricsRecorder) ToolMiddleware {
+	return func(next ToolHandlerFunc) ToolHandlerFunc {
+		return func(ctx context.Context, args map[string]interface{}) (string, error) {
+			start := time.Now()
+			text, err := next(ctx, args)
+			if recorder != nil {
+				recorder.RecordToolCall(toolName, time.Since(start), err)
+			}
+			return text, err
+		}
+	}
+}
+
+// defaultToolMiddleware is the middleware chain applied to every dynamically
+// registered tool handler unless the caller composes its own chain.
+func defaultToolMiddleware(toolName string) ToolMiddleware {
+	if activeMetricsRecorder != nil {
+		return Chain(WithRecovery(slog.Default().With("tool", toolName)), WithMetrics(toolName, activeMetricsRecorder))
+	}
+	return Chain(WithRecovery(slog.Default().With("tool", toolName)))
+}
+
+// recoverHandlerFunc runs fn with the same panic-recovery behavior as
+// WithRecovery, for handlers with typed parameters that don't go through the
+// map-based ToolHandlerFunc signature (e.g. the GitHub PR tool).
+func recoverHandlerFunc(toolName string, fn func() (string, error)) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			atomic.AddInt64(&panicRecoveries, 1)
+			stack := trimStack(debug.Stack(), 16)
+			slog.Error("recovered from panic in tool handler", "tool", toolName, "panic", r, "stack", stack)
+			err = fmt.Errorf("tool handler panicked: %v", r)
+		}
+	}()
+	return fn()
+}
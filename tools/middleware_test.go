@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithRecovery(t *testing.T) {
+	t.Run("recovers from panic and returns an error", func(t *testing.T) {
+		before := PanicRecoveries()
+
+		panicking := func(ctx context.Context, args map[string]interface{}) (string, error) {
+			panic("boom")
+		}
+
+		wrapped := WithRecovery(nil)(panicking)
+		result, err := wrapped(context.Background(), nil)
+
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if result != "" {
+			t.Errorf("expected empty result, got %q", result)
+		}
+		if PanicRecoveries() != before+1 {
+			t.Errorf("expected PanicRecoveries to increment by 1, got %d -> %d", before, PanicRecoveries())
+		}
+	})
+
+	t.Run("passes through successful calls unchanged", func(t *testing.T) {
+		handler := func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "ok", nil
+		}
+
+		wrapped := WithRecovery(nil)(handler)
+		result, err := wrapped(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "ok" {
+			t.Errorf("expected %q, got %q", "ok", result)
+		}
+	})
+
+	t.Run("propagates non-panic errors", func(t *testing.T) {
+		wantErr := errors.New("handler failed")
+		handler := func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "", wantErr
+		}
+
+		wrapped := WithRecovery(nil)(handler)
+		_, err := wrapped(context.Background(), nil)
+		if !errors.Is(err, wantErr) {
+			t.Errorf("expected %v, got %v", wantErr, err)
+		}
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Run("returns an error when the handler exceeds the deadline", func(t *testing.T) {
+		slow := func(ctx context.Context, args map[string]interface{}) (string, error) {
+			select {
+			case <-time.After(100 * time.Millisecond):
+				return "too slow", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		wrapped := WithTimeout(10 * time.Millisecond)(slow)
+		_, err := wrapped(context.Background(), nil)
+		if err == nil {
+			t.Fatal("expected a timeout error")
+		}
+	})
+
+	t.Run("returns normally when within the deadline", func(t *testing.T) {
+		fast := func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "fast", nil
+		}
+
+		wrapped := WithTimeout(time.Second)(fast)
+		result, err := wrapped(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "fast" {
+			t.Errorf("expected %q, got %q", "fast", result)
+		}
+	})
+}
+
+type fakeMetricsRecorder struct {
+	calls int
+	errs  int
+}
+
+func (f *fakeMetricsRecorder) RecordToolCall(toolName string, duration time.Duration, err error) {
+	f.calls++
+	if err != nil {
+		f.errs++
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	recorder := &fakeMetricsRecorder{}
+
+	handler := func(ctx context.Context, args map[string]interface{}) (string, error) {
+		return "", errors.New("oops")
+	}
+
+	wrapped := WithMetrics("test_tool", recorder)(handler)
+	_, _ = wrapped(context.Background(), nil)
+
+	if recorder.calls != 1 {
+		t.Errorf("expected 1 recorded call, got %d", recorder.calls)
+	}
+	if recorder.errs != 1 {
+		t.Errorf("expected 1 recorded error, got %d", recorder.errs)
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+
+	mk := func(name string) ToolMiddleware {
+		return func(next ToolHandlerFunc) ToolHandlerFunc {
+			return func(ctx context.Context, args map[string]interface{}) (string, error) {
+				order = append(order, name+":before")
+				result, err := next(ctx, args)
+				order = append(order, name+":after")
+				return result, err
+			}
+		}
+	}
+
+	handler := func(ctx context.Context, args map[string]interface{}) (string, error) {
+		order = append(order, "handler")
+		return "done", nil
+	}
+
+	wrapped := Chain(mk("a"), mk("b"))(handler)
+	if _, err := wrapped(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"a:before", "b:before", "handler", "b:after", "a:after"}
+	if len(order) != len(want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("expected order %v, got %v", want, order)
+			break
+		}
+	}
+}
+
+func TestCreateTempoToolHandlerRecoversFromPanic(t *testing.T) {
+	// createTempoToolHandler wraps its core logic in the default middleware
+	// chain, so a missing datasource_uid (which would normally just be a
+	// returned error) as well as a panic inside the proxied call path must
+	// both come back as an error rather than crashing the caller.
+	handler := createTempoToolHandler("tempo_test_tool", []string{"ds-1"})
+
+	_, err := handler(context.Background(), DynamicTempoToolParams{})
+	if err == nil {
+		t.Fatal("expected an error for missing datasource_uid")
+	}
+}
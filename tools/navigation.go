@@ -5,13 +5,26 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
 	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/grafana/mcp-grafana/tools/deeplink"
 )
 
+// ExploreQuery is one query in an Explore deeplink's query list.
+type ExploreQuery struct {
+	RefID        string `json:"refId,omitempty" jsonschema:"description=Query reference ID\\, e.g. 'A'"`
+	Expr         string `json:"expr" jsonschema:"required,description=The query expression (PromQL\\, LogQL\\, SQL\\, ...)"`
+	QueryType    string `json:"queryType,omitempty" jsonschema:"description=Query type\\, e.g. 'range' or 'instant'"`
+	LegendFormat string `json:"legendFormat,omitempty" jsonschema:"description=Legend format string for the query's series"`
+	Datasource   string `json:"datasource,omitempty" jsonschema:"description=Datasource UID for this query\\, if different from datasourceUid"`
+	MaxLines     int    `json:"maxLines,omitempty" jsonschema:"description=Maximum log lines to return (Loki queries)"`
+	Interval     string `json:"interval,omitempty" jsonschema:"description=Minimum query interval\\, e.g. '30s'"`
+}
+
 type GenerateDeeplinkParams struct {
 	ResourceType  string            `json:"resourceType" jsonschema:"required,description=Type of resource: dashboard\\, panel\\, or explore"`
 	DashboardUID  *string           `json:"dashboardUid,omitempty" jsonschema:"description=Dashboard UID (required for dashboard and panel types)"`
@@ -19,6 +32,26 @@ type GenerateDeeplinkParams struct {
 	PanelID       *int              `json:"panelId,omitempty" jsonschema:"description=Panel ID (required for panel type)"`
 	QueryParams   map[string]string `json:"queryParams,omitempty" jsonschema:"description=Additional query parameters"`
 	TimeRange     *TimeRange        `json:"timeRange,omitempty" jsonschema:"description=Time range for the link"`
+
+	Queries   []ExploreQuery    `json:"queries,omitempty" jsonschema:"description=Queries to encode in an explore link's query state"`
+	Variables map[string]string `json:"variables,omitempty" jsonschema:"description=Template variable values to set as var-<name>=<value> (dashboard and panel types)"`
+	Mode      string            `json:"mode,omitempty" jsonschema:"description=Link mode: 'view' (default)\\, 'edit' (panel editor)\\, 'share' (dashboard snapshot link)\\, 'kiosk' (bare kiosk mode)\\, or 'kiosk-tv' (TV kiosk mode)"`
+	Theme     string            `json:"theme,omitempty" jsonschema:"description=Theme override: 'light' or 'dark'"`
+	OrgID     *int              `json:"orgId,omitempty" jsonschema:"description=Organization ID to include in the link"`
+	Tab       string            `json:"tab,omitempty" jsonschema:"description=Dashboard settings tab to deep-link into\\, e.g. 'variables' (dashboard type only)"`
+	ExpiresIn string            `json:"expiresIn,omitempty" jsonschema:"description=Expiry for a 'share' mode snapshot link\\, e.g. '1h' or '7d'"`
+
+	Shorten       bool   `json:"shorten,omitempty" jsonschema:"description=Shorten the generated URL via Grafana's short-url API\\, returning a /goto/<uid> link instead of the long form"`
+	Sign          bool   `json:"sign,omitempty" jsonschema:"description=HMAC-sign the generated URL with an exp/sig pair instead of returning it as-is\\, for handing to external clients that should not see the full internal URL"`
+	SignExpiresIn string `json:"signExpiresIn,omitempty" jsonschema:"description=How long a signed link stays valid\\, e.g. '1h' (default) or '24h'. Only used when sign is true."`
+}
+
+// GenerateDeeplinkResult is generate_deeplink's result. Warning is set,
+// with URL falling back to the long-form link, when Shorten was
+// requested but Grafana's short-url API failed.
+type GenerateDeeplinkResult struct {
+	URL     string `json:"url"`
+	Warning string `json:"warning,omitempty"`
 }
 
 type TimeRange struct {
@@ -26,83 +59,96 @@ type TimeRange struct {
 	To   string `json:"to" jsonschema:"description=End time (e.g.\\, 'now')"`
 }
 
-func generateDeeplink(ctx context.Context, args GenerateDeeplinkParams) (string, error) {
-	config := mcpgrafana.GrafanaConfigFromContext(ctx)
-	baseURL := strings.TrimRight(config.URL, "/")
+// toDeeplinkTimeRange converts the tool's TimeRange to the deeplink
+// package's equivalent, or nil if tr is nil.
+func toDeeplinkTimeRange(tr *TimeRange) *deeplink.TimeRange {
+	if tr == nil {
+		return nil
+	}
+	return &deeplink.TimeRange{From: tr.From, To: tr.To}
+}
 
-	if baseURL == "" {
-		return "", fmt.Errorf("grafana url not configured. Please set GRAFANA_URL environment variable or X-Grafana-URL header")
+// toDeeplinkQueries converts the tool's ExploreQuery list to the deeplink
+// package's equivalent.
+func toDeeplinkQueries(queries []ExploreQuery) []deeplink.ExploreQuery {
+	converted := make([]deeplink.ExploreQuery, len(queries))
+	for i, q := range queries {
+		converted[i] = deeplink.ExploreQuery{
+			RefID:        q.RefID,
+			Expr:         q.Expr,
+			QueryType:    q.QueryType,
+			LegendFormat: q.LegendFormat,
+			Datasource:   q.Datasource,
+			MaxLines:     q.MaxLines,
+			Interval:     q.Interval,
+		}
 	}
+	return converted
+}
 
-	var deeplink string
+func generateDeeplink(ctx context.Context, args GenerateDeeplinkParams) (GenerateDeeplinkResult, error) {
+	builder, err := NewLinkBuilder(ctx)
+	if err != nil {
+		return GenerateDeeplinkResult{}, err
+	}
 
-	switch strings.ToLower(args.ResourceType) {
-	case "dashboard":
-		if args.DashboardUID == nil {
-			return "", fmt.Errorf("dashboardUid is required for dashboard links")
-		}
-		deeplink = fmt.Sprintf("%s/d/%s", baseURL, *args.DashboardUID)
-	case "panel":
-		if args.DashboardUID == nil {
-			return "", fmt.Errorf("dashboardUid is required for panel links")
-		}
-		if args.PanelID == nil {
-			return "", fmt.Errorf("panelId is required for panel links")
-		}
-		deeplink = fmt.Sprintf("%s/d/%s?viewPanel=%d", baseURL, *args.DashboardUID, *args.PanelID)
-	case "explore":
-		if args.DatasourceUID == nil {
-			return "", fmt.Errorf("datasourceUid is required for explore links")
-		}
-		params := url.Values{}
-		exploreState := fmt.Sprintf(`{"datasource":"%s"}`, *args.DatasourceUID)
-		params.Set("left", exploreState)
-		deeplink = fmt.Sprintf("%s/explore?%s", baseURL, params.Encode())
-	default:
-		return "", fmt.Errorf("unsupported resource type: %s. Supported types are: dashboard, panel, explore", args.ResourceType)
+	deeplinkURL, err := builder.BuildLongURL(args)
+	if err != nil {
+		return GenerateDeeplinkResult{}, err
 	}
 
-	if args.TimeRange != nil {
-		separator := "?"
-		if strings.Contains(deeplink, "?") {
-			separator = "&"
-		}
-		timeParams := url.Values{}
-		if args.TimeRange.From != "" {
-			timeParams.Set("from", args.TimeRange.From)
+	if len(args.QueryParams) > 0 {
+		u, parseErr := url.Parse(deeplinkURL)
+		if parseErr != nil {
+			return GenerateDeeplinkResult{}, fmt.Errorf("failed to parse generated deeplink: %w", parseErr)
 		}
-		if args.TimeRange.To != "" {
-			timeParams.Set("to", args.TimeRange.To)
+		q := u.Query()
+		for key, value := range args.QueryParams {
+			q.Set(key, value)
 		}
-		if len(timeParams) > 0 {
-			deeplink = fmt.Sprintf("%s%s%s", deeplink, separator, timeParams.Encode())
+		u.RawQuery = q.Encode()
+		deeplinkURL = u.String()
+	}
+
+	result := GenerateDeeplinkResult{URL: deeplinkURL}
+
+	if args.Shorten {
+		shortURL, shortenErr := builder.Shorten(ctx, deeplinkURL)
+		if shortenErr != nil {
+			result.Warning = fmt.Sprintf("could not shorten url, returning long form: %s", shortenErr)
+		} else {
+			result.URL = shortURL
 		}
 	}
 
-	if len(args.QueryParams) > 0 {
-		separator := "?"
-		if strings.Contains(deeplink, "?") {
-			separator = "&"
+	if args.Sign {
+		var ttl time.Duration
+		if args.SignExpiresIn != "" {
+			ttl, err = time.ParseDuration(args.SignExpiresIn)
+			if err != nil {
+				return GenerateDeeplinkResult{}, fmt.Errorf("invalid signExpiresIn: %w", err)
+			}
 		}
-		additionalParams := url.Values{}
-		for key, value := range args.QueryParams {
-			additionalParams.Set(key, value)
+		signedURL, signErr := builder.Sign(result.URL, ttl)
+		if signErr != nil {
+			return GenerateDeeplinkResult{}, signErr
 		}
-		deeplink = fmt.Sprintf("%s%s%s", deeplink, separator, additionalParams.Encode())
+		result.URL = signedURL
 	}
 
-	return deeplink, nil
+	return result, nil
 }
 
 var GenerateDeeplink = mcpgrafana.MustTool(
 	"generate_deeplink",
-	"Generate deeplink URLs for Grafana resources. Supports dashboards (requires dashboardUid), panels (requires dashboardUid and panelId), and Explore queries (requires datasourceUid). Optionally accepts time range and additional query parameters.",
+	"Generate deeplink URLs for Grafana resources. Supports dashboards (requires dashboardUid), panels (requires dashboardUid and panelId), and Explore queries (requires datasourceUid). Optionally accepts a time range, template variables, kiosk/edit/share modes, theme, orgId, additional query parameters, shortening via Grafana's short-url API, and HMAC-signing for external clients.",
 	generateDeeplink,
 	mcp.WithTitleAnnotation("Generate navigation deeplink"),
 	mcp.WithIdempotentHintAnnotation(true),
 	mcp.WithReadOnlyHintAnnotation(true),
 )
 
-func AddNavigationTools(mcp *server.MCPServer) {
-	GenerateDeeplink.Register(mcp)
+func AddNavigationTools(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	mcpgrafana.RegisterTool(mcp, allowed, read, GenerateDeeplink)
 }
@@ -2,6 +2,10 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,7 +33,7 @@ func TestGenerateDeeplink(t *testing.T) {
 
 		result, err := generateDeeplink(ctx, params)
 		require.NoError(t, err)
-		assert.Equal(t, "http://localhost:3000/d/abc123", result)
+		assert.Equal(t, "http://localhost:3000/d/abc123", result.URL)
 	})
 
 	t.Run("Panel deeplink", func(t *testing.T) {
@@ -42,7 +46,7 @@ func TestGenerateDeeplink(t *testing.T) {
 
 		result, err := generateDeeplink(ctx, params)
 		require.NoError(t, err)
-		assert.Equal(t, "http://localhost:3000/d/dash-123?viewPanel=5", result)
+		assert.Equal(t, "http://localhost:3000/d/dash-123?viewPanel=5", result.URL)
 	})
 
 	t.Run("Explore deeplink", func(t *testing.T) {
@@ -53,8 +57,8 @@ func TestGenerateDeeplink(t *testing.T) {
 
 		result, err := generateDeeplink(ctx, params)
 		require.NoError(t, err)
-		assert.Contains(t, result, "http://localhost:3000/explore?left=")
-		assert.Contains(t, result, "prometheus-uid")
+		assert.Contains(t, result.URL, "http://localhost:3000/explore?left=")
+		assert.Contains(t, result.URL, "prometheus-uid")
 	})
 
 	t.Run("With time range", func(t *testing.T) {
@@ -69,9 +73,9 @@ func TestGenerateDeeplink(t *testing.T) {
 
 		result, err := generateDeeplink(ctx, params)
 		require.NoError(t, err)
-		assert.Contains(t, result, "http://localhost:3000/d/abc123")
-		assert.Contains(t, result, "from=now-1h")
-		assert.Contains(t, result, "to=now")
+		assert.Contains(t, result.URL, "http://localhost:3000/d/abc123")
+		assert.Contains(t, result.URL, "from=now-1h")
+		assert.Contains(t, result.URL, "to=now")
 	})
 
 	t.Run("With additional query params", func(t *testing.T) {
@@ -86,9 +90,9 @@ func TestGenerateDeeplink(t *testing.T) {
 
 		result, err := generateDeeplink(ctx, params)
 		require.NoError(t, err)
-		assert.Contains(t, result, "http://localhost:3000/d/abc123")
-		assert.Contains(t, result, "var-datasource=prometheus")
-		assert.Contains(t, result, "refresh=30s")
+		assert.Contains(t, result.URL, "http://localhost:3000/d/abc123")
+		assert.Contains(t, result.URL, "var-datasource=prometheus")
+		assert.Contains(t, result.URL, "refresh=30s")
 	})
 
 	t.Run("Error cases", func(t *testing.T) {
@@ -143,3 +147,74 @@ func TestGenerateDeeplink(t *testing.T) {
 		assert.Contains(t, err.Error(), "datasourceUid is required")
 	})
 }
+
+func TestGenerateDeeplink_Shorten(t *testing.T) {
+	t.Run("shortens via the short-url api", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/short-urls", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"uid": "short1",
+				"url": r.Host + "/goto/short1",
+			})
+		}))
+		defer server.Close()
+
+		ctx := mcpgrafana.WithGrafanaConfig(context.Background(), mcpgrafana.GrafanaConfig{URL: server.URL})
+
+		result, err := generateDeeplink(ctx, GenerateDeeplinkParams{
+			ResourceType: "dashboard",
+			DashboardUID: stringPtr("abc123"),
+			Shorten:      true,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result.URL, "/goto/short1")
+		assert.Empty(t, result.Warning)
+	})
+
+	t.Run("falls back to the long url with a warning on api failure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctx := mcpgrafana.WithGrafanaConfig(context.Background(), mcpgrafana.GrafanaConfig{URL: server.URL})
+
+		result, err := generateDeeplink(ctx, GenerateDeeplinkParams{
+			ResourceType: "dashboard",
+			DashboardUID: stringPtr("abc123"),
+			Shorten:      true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, server.URL+"/d/abc123", result.URL)
+		assert.NotEmpty(t, result.Warning)
+	})
+}
+
+func TestGenerateDeeplink_Sign(t *testing.T) {
+	t.Setenv("GRAFANA_DEEPLINK_SIGNING_KEY", "test-secret")
+
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), mcpgrafana.GrafanaConfig{URL: "http://localhost:3000"})
+
+	result, err := generateDeeplink(ctx, GenerateDeeplinkParams{
+		ResourceType: "dashboard",
+		DashboardUID: stringPtr("abc123"),
+		Sign:         true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.URL, "sig=")
+	assert.Contains(t, result.URL, "exp=")
+}
+
+func TestGenerateDeeplink_Sign_RequiresSigningKey(t *testing.T) {
+	os.Unsetenv("GRAFANA_DEEPLINK_SIGNING_KEY")
+
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), mcpgrafana.GrafanaConfig{URL: "http://localhost:3000"})
+
+	_, err := generateDeeplink(ctx, GenerateDeeplinkParams{
+		ResourceType: "dashboard",
+		DashboardUID: stringPtr("abc123"),
+		Sign:         true,
+	})
+	assert.Error(t, err)
+}
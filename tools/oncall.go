@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -13,27 +14,29 @@ import (
 )
 
 type ListOnCallSchedulesParams struct {
-	TeamID     string `json:"teamId,omitempty" jsonschema:"description=The ID of the team to list schedules for"`
-	ScheduleID string `json:"scheduleId,omitempty" jsonschema:"description=The ID of the schedule to get details for. If provided, returns only that schedule's details"`
-	Page       int    `json:"page,omitempty" jsonschema:"description=The page number to return (1-based)"`
+	TeamID       string `json:"teamId,omitempty" jsonschema:"description=The ID of the team to list schedules for"`
+	ScheduleID   string `json:"scheduleId,omitempty" jsonschema:"description=The ID of the schedule to get details for. If provided, returns only that schedule's details"`
+	Page         int    `json:"page,omitempty" jsonschema:"description=The page number to return (1-based)"`
+	ExpandShifts bool   `json:"expandShifts,omitempty" jsonschema:"description=If true, resolve each schedule's shift IDs into full ExpandedShift entries (recurrence, resolved users, summary) instead of returning bare shift IDs. Slower since it fetches every shift and its users"`
 }
 
 // ScheduleSummary represents a simplified view of an OnCall schedule
 type ScheduleSummary struct {
-	ID       string   `json:"id" jsonschema:"description=The unique identifier of the schedule"`
-	Name     string   `json:"name" jsonschema:"description=The name of the schedule"`
-	TeamID   string   `json:"teamId" jsonschema:"description=The ID of the team this schedule belongs to"`
-	Timezone string   `json:"timezone" jsonschema:"description=The timezone for this schedule"`
-	Shifts   []string `json:"shifts" jsonschema:"description=List of shift IDs in this schedule"`
+	ID             string          `json:"id" jsonschema:"description=The unique identifier of the schedule"`
+	Name           string          `json:"name" jsonschema:"description=The name of the schedule"`
+	TeamID         string          `json:"teamId" jsonschema:"description=The ID of the team this schedule belongs to"`
+	Timezone       string          `json:"timezone" jsonschema:"description=The timezone for this schedule"`
+	Shifts         []string        `json:"shifts" jsonschema:"description=List of shift IDs in this schedule"`
+	ExpandedShifts []ExpandedShift `json:"expandedShifts,omitempty" jsonschema:"description=Shifts resolved to full detail, only populated when expandShifts=true was requested"`
 }
 
 func listOnCallSchedulesHandler(ctx context.Context, args ListOnCallSchedulesParams) ([]*ScheduleSummary, error) {
-	return fetchOnCallSchedules(ctx, args)
+	return onCallProviderFromContext(ctx).ListSchedules(ctx, args)
 }
 
 var ListOnCallSchedules = mcpgrafana.MustTool(
 	"list_oncall_schedules",
-	"List OnCall schedules. A schedule is a calendar-based system defining when team members are on-call. Optionally provide a scheduleId to get details for a specific schedule",
+	"List OnCall schedules. A schedule is a calendar-based system defining when team members are on-call. Optionally provide a scheduleId to get details for a specific schedule. Set expandShifts=true to resolve each schedule's shift IDs into full recurrence/user/summary detail (see describe_oncall_rotation) instead of bare shift IDs.",
 	listOnCallSchedulesHandler,
 )
 
@@ -63,7 +66,7 @@ type GetCurrentOnCallUsersParams struct {
 }
 
 func getCurrentOnCallUsersHandler(ctx context.Context, args GetCurrentOnCallUsersParams) (*CurrentOnCallUsers, error) {
-	return fetchCurrentOnCallUsers(ctx, args)
+	return onCallProviderFromContext(ctx).GetCurrentOnCall(ctx, args)
 }
 
 var GetCurrentOnCallUsers = mcpgrafana.MustTool(
@@ -109,19 +112,23 @@ type ListOnCallAlertGroupsParams struct {
 	State         string `json:"state,omitempty" jsonschema:"description=Possible values: new, acknowledged, resolved or silenced"`
 	TeamID        string `json:"team_id,omitempty" jsonschema:"description=Exact match, team ID"`
 	StartedAt     string `json:"started_at,omitempty" jsonschema:"description=Filter alert groups by start time in ISO 8601 format with start and end timestamps separated by underscore. Example: 2024-03-20T10:00:00_2024-03-21T10:00:00"`
-	Labels        string `json:"labels,omitempty" jsonschema:"description=Filter alert groups by labels. Expected format: key1:value1,key2:value2"`
+	Labels        string `json:"labels,omitempty" jsonschema:"description=Filter alert groups by labels. Expected format: key1:value1,key2:value2. For anything beyond a plain AND of equalities, use labelsQuery instead"`
 	TeamName      string `json:"team_name,omitempty" jsonschema:"description=Team name. If provided, returns only alert groups for this team. It may not be an exact match."`
 	Name          string `json:"name,omitempty" jsonschema:"description=Filter alert groups by name"`
 	Page          int    `json:"page,omitempty" jsonschema:"description=The page number to return (1-based)"`
+	LabelsQuery   string `json:"labelsQuery,omitempty" jsonschema:"description=A label expression supporting key=value, key!=value, and key in (value1,value2), combined with AND/OR (no parentheses), e.g. 'env=prod AND severity in (critical,high) AND team!=platform'. Takes precedence over labels when set"`
+	Search        string `json:"search,omitempty" jsonschema:"description=Free-text match against the title/message of each alert group's underlying alerts, applied client-side after fetching"`
+	Since         string `json:"since,omitempty" jsonschema:"description=Start of the time window, in RFC3339. Combined with until into the started_at filter"`
+	Until         string `json:"until,omitempty" jsonschema:"description=End of the time window, in RFC3339. Combined with since into the started_at filter"`
 }
 
-func listOnCallAlertGroupsHandler(ctx context.Context, args ListOnCallAlertGroupsParams) ([]*aapi.AlertGroup, error) {
-	return fetchOnCallAlertGroups(ctx, args)
+func listOnCallAlertGroupsHandler(ctx context.Context, args ListOnCallAlertGroupsParams) ([]*OnCallAlertGroup, error) {
+	return onCallProviderFromContext(ctx).ListAlertGroups(ctx, args)
 }
 
 var ListOnCallAlertGroups = mcpgrafana.MustTool(
 	"list_oncall_alert_groups",
-	"List alert groups from Grafana OnCall. Optionally filter by alert group ID, route ID, integration ID, state, team ID, labels, or name.",
+	"List alert/incident groups from the configured OnCall provider (Grafana OnCall by default; see --oncall-provider). Optionally filter by alert group ID, route ID, integration ID, state, team ID, labels, or name (filters not supported by the active provider are ignored). Use labelsQuery for OR/negation across labels, since/until for a time window, and search for free-text matching against alert titles/messages. Results are sorted most-recent-first.",
 	listOnCallAlertGroupsHandler,
 )
 
@@ -140,14 +147,48 @@ var GetOnCallAlerts = mcpgrafana.MustTool(
 	getOnCallAlertsHandler,
 )
 
-func AddOnCallTools(mcp *server.MCPServer) {
-	ListOnCallSchedules.Register(mcp)
-	GetOnCallShift.Register(mcp)
-	GetCurrentOnCallUsers.Register(mcp)
-	ListOnCallTeams.Register(mcp)
-	ListOnCallUsers.Register(mcp)
-	ListOnCallAlertGroups.Register(mcp)
-	GetOnCallAlerts.Register(mcp)
+// AddOnCallTools registers the read-only OnCall tools. Write tools
+// (acknowledge/resolve/silence/unresolve/escalate/attach) are only registered when
+// enableWriteTools is true, so read-only deployments can't mutate incidents
+// through the MCP server.
+//
+// provider selects which vendor backs the oncall_* tools (see
+// NewOnCallProvider); an empty string keeps the default Grafana OnCall
+// backend. The tool names and schemas are the same regardless of provider.
+func AddOnCallTools(mcp *server.MCPServer, enableWriteTools bool, provider string, allowed mcpgrafana.ToolCapabilities) {
+	onCallProvider, err := NewOnCallProvider(provider)
+	if err != nil {
+		slog.Error("Invalid oncall provider, falling back to grafana", "provider", provider, "error", err)
+		onCallProvider = GrafanaOnCallProvider{}
+	}
+	SetDefaultOnCallProvider(onCallProvider)
+
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListOnCallSchedules)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetOnCallShift)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetCurrentOnCallUsers)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListOnCallTeams)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListOnCallUsers)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListOnCallAlertGroups)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetOnCallAlerts)
+	mcpgrafana.RegisterTool(mcp, allowed, read, RenderOnCallScheduleTimeline)
+	mcpgrafana.RegisterTool(mcp, allowed, read, PaginateOnCallAlertGroups)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListOnCallScheduleOverrides)
+	mcpgrafana.RegisterTool(mcp, allowed, read, DescribeOnCallRotation)
+
+	if enableWriteTools {
+		write := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryWrite}
+		mcpgrafana.RegisterTool(mcp, allowed, write, AckOnCallAlertGroup)
+		mcpgrafana.RegisterTool(mcp, allowed, write, ResolveOnCallAlertGroup)
+		mcpgrafana.RegisterTool(mcp, allowed, write, SilenceOnCallAlertGroup)
+		mcpgrafana.RegisterTool(mcp, allowed, write, UnresolveOnCallAlertGroup)
+		mcpgrafana.RegisterTool(mcp, allowed, write, EscalateOnCallAlertGroup)
+		mcpgrafana.RegisterTool(mcp, allowed, write, AttachOnCallAlertGroup)
+		mcpgrafana.RegisterTool(mcp, allowed, write, CreateOnCallScheduleOverride)
+		mcpgrafana.RegisterTool(mcp, allowed, write, DeleteOnCallScheduleOverride)
+		mcpgrafana.RegisterTool(mcp, allowed, write, CreateShiftSwapRequest)
+		mcpgrafana.RegisterTool(mcp, allowed, write, TakeShiftSwapRequest)
+	}
 }
 
 // getOnCallURLFromSettings retrieves the OnCall API URL from the Grafana settings endpoint.
@@ -197,8 +238,9 @@ func oncallClientFromContext(ctx context.Context) (*aapi.Client, error) {
 	// Get the standard Grafana URL and API key
 	grafanaURL, grafanaAPIKey := mcpgrafana.GrafanaURLFromContext(ctx), mcpgrafana.GrafanaAPIKeyFromContext(ctx)
 
-	// Try to get OnCall URL from settings endpoint
-	grafanaOnCallURL, err := getOnCallURLFromSettings(ctx, grafanaURL, grafanaAPIKey)
+	// Resolve the OnCall URL from the cache, only hitting the settings
+	// endpoint when the cached value is missing or stale.
+	grafanaOnCallURL, err := globalOnCallSettingsCache.resolve(ctx, grafanaURL, grafanaAPIKey)
 	if err != nil {
 		return nil, fmt.Errorf("getting OnCall URL from settings: %w", err)
 	}
@@ -273,6 +315,16 @@ func getAlertServiceFromContext(ctx context.Context) (*aapi.AlertService, error)
 	return aapi.NewAlertService(client), nil
 }
 
+// getShiftSwapServiceFromContext creates a new ShiftSwapService using the OnCall client from the context
+func getShiftSwapServiceFromContext(ctx context.Context) (*aapi.ShiftSwapService, error) {
+	client, err := oncallClientFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall client: %w", err)
+	}
+
+	return aapi.NewShiftSwapService(client), nil
+}
+
 // --- API Call Implementation Functions ---
 
 // fetchOnCallSchedules performs the API call to list or get OnCall schedules.
@@ -283,8 +335,8 @@ func fetchOnCallSchedules(ctx context.Context, args ListOnCallSchedulesParams) (
 	}
 
 	if args.ScheduleID != "" {
-		schedule, _, err := scheduleService.GetSchedule(args.ScheduleID, &aapi.GetScheduleOptions{})
-		if err != nil {
+		schedule, resp, err := scheduleService.GetSchedule(args.ScheduleID, &aapi.GetScheduleOptions{})
+		if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 			return nil, fmt.Errorf("getting OnCall schedule %s: %w", args.ScheduleID, err)
 		}
 		summary := &ScheduleSummary{
@@ -296,6 +348,11 @@ func fetchOnCallSchedules(ctx context.Context, args ListOnCallSchedulesParams) (
 		if schedule.Shifts != nil {
 			summary.Shifts = *schedule.Shifts
 		}
+		if args.ExpandShifts {
+			if summary.ExpandedShifts, err = expandScheduleShifts(ctx, schedule); err != nil {
+				return nil, err
+			}
+		}
 		return []*ScheduleSummary{summary}, nil
 	}
 
@@ -307,8 +364,8 @@ func fetchOnCallSchedules(ctx context.Context, args ListOnCallSchedulesParams) (
 		listOptions.TeamID = args.TeamID
 	}
 
-	response, _, err := scheduleService.ListSchedules(listOptions)
-	if err != nil {
+	response, resp, err := scheduleService.ListSchedules(listOptions)
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 		return nil, fmt.Errorf("listing OnCall schedules: %w", err)
 	}
 
@@ -324,6 +381,11 @@ func fetchOnCallSchedules(ctx context.Context, args ListOnCallSchedulesParams) (
 		if schedule.Shifts != nil {
 			summary.Shifts = *schedule.Shifts
 		}
+		if args.ExpandShifts {
+			if summary.ExpandedShifts, err = expandScheduleShifts(ctx, &schedule); err != nil {
+				return nil, err
+			}
+		}
 		summaries = append(summaries, summary)
 	}
 
@@ -337,8 +399,8 @@ func fetchOnCallShift(ctx context.Context, args GetOnCallShiftParams) (*aapi.OnC
 		return nil, fmt.Errorf("getting OnCall shift service: %w", err)
 	}
 
-	shift, _, err := shiftService.GetOnCallShift(args.ShiftID, &aapi.GetOnCallShiftOptions{})
-	if err != nil {
+	shift, resp, err := shiftService.GetOnCallShift(args.ShiftID, &aapi.GetOnCallShiftOptions{})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 		return nil, fmt.Errorf("getting OnCall shift %s: %w", args.ShiftID, err)
 	}
 
@@ -352,8 +414,8 @@ func fetchCurrentOnCallUsers(ctx context.Context, args GetCurrentOnCallUsersPara
 		return nil, fmt.Errorf("getting OnCall schedule service: %w", err)
 	}
 
-	schedule, _, err := scheduleService.GetSchedule(args.ScheduleID, &aapi.GetScheduleOptions{})
-	if err != nil {
+	schedule, resp, err := scheduleService.GetSchedule(args.ScheduleID, &aapi.GetScheduleOptions{})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 		return nil, fmt.Errorf("getting schedule %s: %w", args.ScheduleID, err)
 	}
 
@@ -377,8 +439,8 @@ func fetchCurrentOnCallUsers(ctx context.Context, args GetCurrentOnCallUsersPara
 
 	// Fetch details for each user currently on call
 	for _, userID := range schedule.OnCallNow {
-		user, _, err := userService.GetUser(userID, &aapi.GetUserOptions{})
-		if err != nil {
+		user, resp, err := userService.GetUser(userID, &aapi.GetUserOptions{})
+		if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 			// Log the error but continue with other users
 			fmt.Printf("Error fetching user %s: %v\n", userID, err)
 			continue
@@ -401,8 +463,8 @@ func fetchOnCallTeams(ctx context.Context, args ListOnCallTeamsParams) ([]*aapi.
 		listOptions.Page = args.Page
 	}
 
-	response, _, err := teamService.ListTeams(listOptions)
-	if err != nil {
+	response, resp, err := teamService.ListTeams(listOptions)
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 		return nil, fmt.Errorf("listing OnCall teams: %w", err)
 	}
 
@@ -417,8 +479,8 @@ func fetchOnCallUsers(ctx context.Context, args ListOnCallUsersParams) ([]*aapi.
 	}
 
 	if args.UserID != "" {
-		user, _, err := userService.GetUser(args.UserID, &aapi.GetUserOptions{})
-		if err != nil {
+		user, resp, err := userService.GetUser(args.UserID, &aapi.GetUserOptions{})
+		if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 			return nil, fmt.Errorf("getting OnCall user %s: %w", args.UserID, err)
 		}
 		return []*aapi.User{user}, nil
@@ -433,8 +495,8 @@ func fetchOnCallUsers(ctx context.Context, args ListOnCallUsersParams) ([]*aapi.
 		listOptions.Username = args.Username
 	}
 
-	response, _, err := userService.ListUsers(listOptions)
-	if err != nil {
+	response, resp, err := userService.ListUsers(listOptions)
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 		return nil, fmt.Errorf("listing OnCall users: %w", err)
 	}
 
@@ -478,8 +540,8 @@ func fetchOnCallAlertGroups(ctx context.Context, args ListOnCallAlertGroupsParam
 		listOptions.Labels = strings.Split(args.Labels, ",")
 	}
 
-	response, _, err := alertGroupService.ListAlertGroups(listOptions)
-	if err != nil {
+	response, resp, err := alertGroupService.ListAlertGroups(listOptions)
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 		return nil, fmt.Errorf("listing OnCall alert groups: %w", err)
 	}
 
@@ -500,8 +562,8 @@ func fetchOnCallAlerts(ctx context.Context, args GetOnCallAlertsParams) ([]*aapi
 		listOptions.Page = args.Page
 	}
 
-	response, _, err := alertService.ListAlerts(listOptions)
-	if err != nil {
+	response, resp, err := alertService.ListAlerts(listOptions)
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
 		return nil, fmt.Errorf("listing OnCall alerts for alert group %s: %w", args.AlertGroupID, err)
 	}
 
@@ -0,0 +1,356 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// labelClauseOp is the comparison operator in a single labelsQuery clause.
+type labelClauseOp int
+
+const (
+	labelClauseEq labelClauseOp = iota
+	labelClauseNeq
+)
+
+// labelClause is a single `key=value` or `key!=value` constraint.
+type labelClause struct {
+	Key   string
+	Op    labelClauseOp
+	Value string
+}
+
+// String renders the clause as the "key:value" token the OnCall list API
+// already accepts for its Labels filter.
+func (c labelClause) String() string {
+	return c.Key + ":" + c.Value
+}
+
+// labelAndGroup is a set of clauses that must all hold (an AND group). It's
+// one disjunct of the DNF a labelsQuery expression is parsed into.
+type labelAndGroup struct {
+	Eq  []labelClause
+	Neq []labelClause
+}
+
+// parseLabelsQuery parses a small label expression grammar into
+// disjunctive-normal form: a slice of AND groups, any one of which matching
+// satisfies the whole query.
+//
+// Grammar (flat, no parentheses/nesting):
+//
+//	query   := group (OR group)*
+//	group   := clause (AND clause)*
+//	clause  := KEY '=' VALUE | KEY '!=' VALUE | KEY 'in' '(' VALUE (',' VALUE)* ')'
+//
+// `in` is sugar: `k in (v1,v2)` inside a group is distributed across the
+// group, producing one AND group per value.
+func parseLabelsQuery(query string) ([]labelAndGroup, error) {
+	var groups []labelAndGroup
+	for _, orPart := range splitTopLevel(query, " OR ") {
+		orPart = strings.TrimSpace(orPart)
+		if orPart == "" {
+			continue
+		}
+		expanded, err := parseAndGroup(orPart)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, expanded...)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("labelsQuery %q contains no clauses", query)
+	}
+	return groups, nil
+}
+
+// parseAndGroup parses one `clause (AND clause)*` group, expanding any `in`
+// clause into one AND group per value (cross product, since a group may
+// contain more than one `in` clause).
+func parseAndGroup(andExpr string) ([]labelAndGroup, error) {
+	groups := []labelAndGroup{{}}
+
+	for _, clauseStr := range splitTopLevel(andExpr, " AND ") {
+		clauseStr = strings.TrimSpace(clauseStr)
+		if clauseStr == "" {
+			continue
+		}
+
+		key, op, values, err := parseClause(clauseStr)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []labelAndGroup
+		for _, g := range groups {
+			for _, v := range values {
+				clone := labelAndGroup{Eq: append([]labelClause{}, g.Eq...), Neq: append([]labelClause{}, g.Neq...)}
+				clause := labelClause{Key: key, Value: v}
+				if op == labelClauseNeq {
+					clause.Op = labelClauseNeq
+					clone.Neq = append(clone.Neq, clause)
+				} else {
+					clone.Eq = append(clone.Eq, clause)
+				}
+				next = append(next, clone)
+			}
+		}
+		groups = next
+	}
+
+	return groups, nil
+}
+
+// parseClause parses a single `key=value`, `key!=value`, or
+// `key in (v1,v2)` clause, returning the possible values it expands to (one
+// value for = and !=, one per alternative for in).
+func parseClause(clause string) (key string, op labelClauseOp, values []string, err error) {
+	switch {
+	case strings.Contains(clause, "!="):
+		parts := strings.SplitN(clause, "!=", 2)
+		return strings.TrimSpace(parts[0]), labelClauseNeq, []string{strings.TrimSpace(parts[1])}, nil
+
+	case strings.Contains(strings.ToLower(clause), " in "):
+		idx := strings.Index(strings.ToLower(clause), " in ")
+		key := strings.TrimSpace(clause[:idx])
+		rest := strings.TrimSpace(clause[idx+4:])
+		if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+			return "", 0, nil, fmt.Errorf("expected key in (v1,v2,...), got %q", clause)
+		}
+		rest = strings.TrimSuffix(strings.TrimPrefix(rest, "("), ")")
+		var vals []string
+		for _, v := range strings.Split(rest, ",") {
+			vals = append(vals, strings.TrimSpace(v))
+		}
+		return key, labelClauseEq, vals, nil
+
+	case strings.Contains(clause, "="):
+		parts := strings.SplitN(clause, "=", 2)
+		return strings.TrimSpace(parts[0]), labelClauseEq, []string{strings.TrimSpace(parts[1])}, nil
+
+	default:
+		return "", 0, nil, fmt.Errorf("expected key=value, key!=value, or key in (...), got %q", clause)
+	}
+}
+
+// splitTopLevel splits expr on sep, tracking paren depth so an `in
+// (v1,v2)` clause's own parentheses never contribute a spurious AND/OR
+// boundary.
+func splitTopLevel(expr, sep string) []string {
+	var parts []string
+	depth := 0
+	last := 0
+	upper := strings.ToUpper(expr)
+	upperSep := strings.ToUpper(sep)
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 && strings.HasPrefix(upper[i:], upperSep) {
+			parts = append(parts, expr[last:i])
+			i += len(sep) - 1
+			last = i + 1
+		}
+	}
+	parts = append(parts, expr[last:])
+	return parts
+}
+
+// normalizeStartedAt combines since/until (RFC3339) into the underscore
+// `start_end` format the OnCall list API expects for StartedAt, preferring
+// an explicit StartedAt if one was already set.
+func normalizeStartedAt(args ListOnCallAlertGroupsParams) string {
+	if args.StartedAt != "" {
+		return args.StartedAt
+	}
+	if args.Since == "" && args.Until == "" {
+		return ""
+	}
+	return args.Since + "_" + args.Until
+}
+
+// listGrafanaAlertGroups lists Grafana OnCall alert groups, applying
+// labelsQuery, search, and since/until on top of the plain filters already
+// supported by fetchOnCallAlertGroups.
+func listGrafanaAlertGroups(ctx context.Context, args ListOnCallAlertGroupsParams) ([]*OnCallAlertGroup, error) {
+	args.StartedAt = normalizeStartedAt(args)
+
+	var results []*OnCallAlertGroup
+	var err error
+	if args.LabelsQuery != "" {
+		results, err = listAlertGroupsByLabelsQuery(ctx, args)
+	} else {
+		results, err = fetchAndConvertAlertGroups(ctx, args)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Search != "" {
+		results, err = filterAlertGroupsBySearch(ctx, results, args.Search)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sortAlertGroupsByCreatedAt(results)
+	return results, nil
+}
+
+// fetchAndConvertAlertGroups runs a single fetchOnCallAlertGroups call and
+// converts the results to the vendor-neutral shape.
+func fetchAndConvertAlertGroups(ctx context.Context, args ListOnCallAlertGroupsParams) ([]*OnCallAlertGroup, error) {
+	alertGroups, err := fetchOnCallAlertGroups(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*OnCallAlertGroup, 0, len(alertGroups))
+	for _, ag := range alertGroups {
+		result = append(result, grafanaToOnCallAlertGroup(ag))
+	}
+	return result, nil
+}
+
+// listAlertGroupsByLabelsQuery evaluates args.LabelsQuery's AND groups as
+// separate server-side queries (one per group, ANDing each group's Eq
+// clauses into the existing Labels filter), since the OnCall list API has
+// no OR or negation operator of its own. Neq clauses are evaluated by
+// re-querying with the forbidden value substituted in and excluding any
+// overlap, and the union across OR groups is de-duplicated by alert group
+// ID. Each surviving group's matchedLabels records the Eq clauses that
+// explain the match.
+func listAlertGroupsByLabelsQuery(ctx context.Context, args ListOnCallAlertGroupsParams) ([]*OnCallAlertGroup, error) {
+	groups, err := parseLabelsQuery(args.LabelsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("parsing labelsQuery: %w", err)
+	}
+
+	byID := make(map[string]*OnCallAlertGroup)
+	for _, group := range groups {
+		candidates, err := fetchAndConvertAlertGroups(ctx, withLabels(args, group.Eq))
+		if err != nil {
+			return nil, err
+		}
+
+		excluded := make(map[string]bool)
+		for _, neq := range group.Neq {
+			forbidden, err := fetchAndConvertAlertGroups(ctx, withLabels(args, append(append([]labelClause{}, group.Eq...), neq)))
+			if err != nil {
+				return nil, err
+			}
+			for _, ag := range forbidden {
+				excluded[ag.ID] = true
+			}
+		}
+
+		matchedLabels := make([]string, 0, len(group.Eq))
+		for _, eq := range group.Eq {
+			matchedLabels = append(matchedLabels, eq.String())
+		}
+
+		for _, ag := range candidates {
+			if excluded[ag.ID] {
+				continue
+			}
+			if existing, ok := byID[ag.ID]; ok {
+				existing.MatchedLabels = mergeMatchedLabels(existing.MatchedLabels, matchedLabels)
+				continue
+			}
+			ag.MatchedLabels = matchedLabels
+			byID[ag.ID] = ag
+		}
+	}
+
+	result := make([]*OnCallAlertGroup, 0, len(byID))
+	for _, ag := range byID {
+		result = append(result, ag)
+	}
+	return result, nil
+}
+
+// withLabels returns a copy of args with Labels set to the "key:value"
+// tokens for clauses, leaving every other filter untouched.
+func withLabels(args ListOnCallAlertGroupsParams, clauses []labelClause) ListOnCallAlertGroupsParams {
+	tokens := make([]string, 0, len(clauses))
+	for _, c := range clauses {
+		tokens = append(tokens, c.String())
+	}
+	args.Labels = strings.Join(tokens, ",")
+	return args
+}
+
+// mergeMatchedLabels appends any label tokens in next not already present
+// in existing.
+func mergeMatchedLabels(existing, next []string) []string {
+	seen := make(map[string]bool, len(existing))
+	for _, l := range existing {
+		seen[l] = true
+	}
+	for _, l := range next {
+		if !seen[l] {
+			existing = append(existing, l)
+			seen[l] = true
+		}
+	}
+	return existing
+}
+
+// filterAlertGroupsBySearch keeps only the alert groups with at least one
+// underlying alert whose payload title or message contains search
+// (case-insensitive).
+func filterAlertGroupsBySearch(ctx context.Context, alertGroups []*OnCallAlertGroup, search string) ([]*OnCallAlertGroup, error) {
+	search = strings.ToLower(search)
+
+	filtered := make([]*OnCallAlertGroup, 0, len(alertGroups))
+	for _, ag := range alertGroups {
+		alerts, err := fetchOnCallAlerts(ctx, GetOnCallAlertsParams{AlertGroupID: ag.ID})
+		if err != nil {
+			return nil, fmt.Errorf("getting alerts for alert group %s: %w", ag.ID, err)
+		}
+
+		for _, alert := range alerts {
+			title, message := alertPayloadText(alert.Payload)
+			if strings.Contains(strings.ToLower(title), search) || strings.Contains(strings.ToLower(message), search) {
+				filtered = append(filtered, ag)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// alertPayloadText extracts the title and message from an alert's payload,
+// which OnCall integrations populate with arbitrary per-integration fields.
+func alertPayloadText(payload any) (title, message string) {
+	m, ok := payload.(map[string]any)
+	if !ok {
+		return "", ""
+	}
+	if v, ok := m["title"].(string); ok {
+		title = v
+	}
+	if v, ok := m["message"].(string); ok {
+		message = v
+	}
+	return title, message
+}
+
+// sortAlertGroupsByCreatedAt sorts alertGroups most-recent-first by
+// CreatedAt (an RFC3339-ish string, so lexicographic order matches
+// chronological order). Groups without a CreatedAt sort last.
+func sortAlertGroupsByCreatedAt(alertGroups []*OnCallAlertGroup) {
+	sort.SliceStable(alertGroups, func(i, j int) bool {
+		if alertGroups[i].CreatedAt == "" {
+			return false
+		}
+		if alertGroups[j].CreatedAt == "" {
+			return true
+		}
+		return alertGroups[i].CreatedAt > alertGroups[j].CreatedAt
+	})
+}
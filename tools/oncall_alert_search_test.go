@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestParseLabelsQuery_SimpleEquality(t *testing.T) {
+	groups, err := parseLabelsQuery("env=prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Eq) != 1 || groups[0].Eq[0].String() != "env:prod" {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+}
+
+func TestParseLabelsQuery_NegationAndAnd(t *testing.T) {
+	groups, err := parseLabelsQuery("env=prod AND team!=platform")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected a single AND group, got %d", len(groups))
+	}
+	if len(groups[0].Eq) != 1 || groups[0].Eq[0].String() != "env:prod" {
+		t.Errorf("expected env:prod as the only Eq clause, got %+v", groups[0].Eq)
+	}
+	if len(groups[0].Neq) != 1 || groups[0].Neq[0].String() != "team:platform" {
+		t.Errorf("expected team:platform as the only Neq clause, got %+v", groups[0].Neq)
+	}
+}
+
+func TestParseLabelsQuery_InExpandsToOrGroups(t *testing.T) {
+	groups, err := parseLabelsQuery("severity in (critical,high)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 expanded groups for an in(...) clause, got %d", len(groups))
+	}
+	values := map[string]bool{}
+	for _, g := range groups {
+		values[g.Eq[0].Value] = true
+	}
+	if !values["critical"] || !values["high"] {
+		t.Errorf("expected both critical and high, got %+v", groups)
+	}
+}
+
+func TestParseLabelsQuery_OrSplitsTopLevelGroups(t *testing.T) {
+	groups, err := parseLabelsQuery("env=prod OR env=staging")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 OR groups, got %d", len(groups))
+	}
+}
+
+func TestParseLabelsQuery_EmptyQueryErrors(t *testing.T) {
+	if _, err := parseLabelsQuery("   "); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+}
+
+func TestAlertPayloadText_ExtractsTitleAndMessage(t *testing.T) {
+	title, message := alertPayloadText(map[string]any{"title": "CPU high", "message": "load average exceeded threshold"})
+	if title != "CPU high" || message != "load average exceeded threshold" {
+		t.Errorf("unexpected extraction: title=%q message=%q", title, message)
+	}
+}
+
+func TestAlertPayloadText_NonMapPayloadReturnsEmpty(t *testing.T) {
+	title, message := alertPayloadText("not a map")
+	if title != "" || message != "" {
+		t.Errorf("expected empty strings for a non-map payload, got title=%q message=%q", title, message)
+	}
+}
+
+func TestSortAlertGroupsByCreatedAt_MostRecentFirst(t *testing.T) {
+	groups := []*OnCallAlertGroup{
+		{ID: "a", CreatedAt: "2026-01-01T00:00:00Z"},
+		{ID: "b", CreatedAt: "2026-06-01T00:00:00Z"},
+		{ID: "c", CreatedAt: ""},
+	}
+	sortAlertGroupsByCreatedAt(groups)
+	if groups[0].ID != "b" || groups[1].ID != "a" || groups[2].ID != "c" {
+		t.Errorf("expected order b, a, c, got %s, %s, %s", groups[0].ID, groups[1].ID, groups[2].ID)
+	}
+}
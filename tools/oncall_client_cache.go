@@ -0,0 +1,154 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/grafana/mcp-grafana/internal/health"
+	"golang.org/x/sync/singleflight"
+)
+
+// onCallSettingsTTL is how long a resolved OnCall API URL is trusted before
+// it's re-fetched from Grafana's settings endpoint.
+const onCallSettingsTTL = 5 * time.Minute
+
+// onCallSettingsRefreshAhead is how far ahead of expiry the background
+// refresher re-fetches an entry, so a request never has to wait on a cold
+// settings round trip as long as the process has been up for a while.
+const onCallSettingsRefreshAhead = time.Minute
+
+// onCallSettingsRefreshInterval is how often the background refresher
+// checks for entries nearing expiry.
+const onCallSettingsRefreshInterval = time.Minute
+
+// onCallSettingsEntry is one cached (grafanaURL, grafanaAPIKey) -> OnCall
+// API URL resolution.
+type onCallSettingsEntry struct {
+	grafanaURL    string
+	grafanaAPIKey string
+	url           string
+	expiresAt     time.Time
+}
+
+// onCallSettingsCache caches the OnCall API URL resolved from Grafana's
+// /api/plugins/grafana-irm-app/settings endpoint per (grafanaURL, apiKey)
+// pair, so every oncall_* tool call doesn't pay for a fresh settings
+// round trip. Concurrent misses for the same key are de-duplicated via
+// singleflight, and a background goroutine keeps entries from going cold
+// by refreshing them shortly before they expire.
+type onCallSettingsCache struct {
+	mu          sync.RWMutex
+	entries     map[string]onCallSettingsEntry
+	group       singleflight.Group
+	refreshOnce sync.Once
+}
+
+// globalOnCallSettingsCache is the process-wide cache shared by every
+// oncall_* tool invocation, regardless of which request's context it's
+// resolved from.
+var globalOnCallSettingsCache = &onCallSettingsCache{
+	entries: make(map[string]onCallSettingsEntry),
+}
+
+func onCallSettingsCacheKey(grafanaURL, grafanaAPIKey string) string {
+	return grafanaURL + "\x00" + grafanaAPIKey
+}
+
+// resolve returns the OnCall API URL for (grafanaURL, grafanaAPIKey),
+// serving a cached value when it's still fresh and otherwise fetching it
+// (de-duplicating concurrent fetches for the same key via singleflight).
+func (c *onCallSettingsCache) resolve(ctx context.Context, grafanaURL, grafanaAPIKey string) (string, error) {
+	c.refreshOnce.Do(func() { go c.runBackgroundRefresh() })
+
+	key := onCallSettingsCacheKey(grafanaURL, grafanaAPIKey)
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		health.RecordOnCallSettingsCacheResult("hit")
+		return entry.url, nil
+	}
+	health.RecordOnCallSettingsCacheResult("miss")
+
+	result, err, _ := c.group.Do(key, func() (any, error) {
+		url, err := getOnCallURLFromSettings(ctx, grafanaURL, grafanaAPIKey)
+		if err != nil {
+			health.RecordOnCallSettingsCacheResult("refresh_error")
+			return "", err
+		}
+		c.store(grafanaURL, grafanaAPIKey, url)
+		return url, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+func (c *onCallSettingsCache) store(grafanaURL, grafanaAPIKey, url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[onCallSettingsCacheKey(grafanaURL, grafanaAPIKey)] = onCallSettingsEntry{
+		grafanaURL:    grafanaURL,
+		grafanaAPIKey: grafanaAPIKey,
+		url:           url,
+		expiresAt:     time.Now().Add(onCallSettingsTTL),
+	}
+}
+
+// invalidate drops the cached entry for (grafanaURL, grafanaAPIKey),
+// forcing the next resolve to re-fetch it from Grafana's settings endpoint.
+func (c *onCallSettingsCache) invalidate(grafanaURL, grafanaAPIKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, onCallSettingsCacheKey(grafanaURL, grafanaAPIKey))
+}
+
+// invalidateOnAuthError evicts the cached OnCall API URL for this request's
+// (grafanaURL, apiKey) pair when resp is a 401 or 404 from the OnCall API,
+// since either usually means the cached URL (or the credentials behind it)
+// went stale. It returns err unchanged so callers can use it inline.
+func (c *onCallSettingsCache) invalidateOnAuthError(ctx context.Context, resp *http.Response, err error) error {
+	if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusNotFound) {
+		grafanaURL, grafanaAPIKey := mcpgrafana.GrafanaURLFromContext(ctx), mcpgrafana.GrafanaAPIKeyFromContext(ctx)
+		c.invalidate(grafanaURL, grafanaAPIKey)
+	}
+	return err
+}
+
+// runBackgroundRefresh periodically re-fetches entries nearing expiry so an
+// in-flight request rarely has to wait on a cold settings round trip. It
+// runs for the lifetime of the process, started lazily by the first call to
+// resolve.
+func (c *onCallSettingsCache) runBackgroundRefresh() {
+	ticker := time.NewTicker(onCallSettingsRefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.refreshStaleEntries()
+	}
+}
+
+func (c *onCallSettingsCache) refreshStaleEntries() {
+	c.mu.RLock()
+	var due []onCallSettingsEntry
+	deadline := time.Now().Add(onCallSettingsRefreshAhead)
+	for _, entry := range c.entries {
+		if deadline.After(entry.expiresAt) {
+			due = append(due, entry)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, entry := range due {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if _, err := c.resolve(ctx, entry.grafanaURL, entry.grafanaAPIKey); err != nil {
+			slog.Warn("Failed to refresh cached OnCall API URL", "error", err)
+		}
+		cancel()
+	}
+}
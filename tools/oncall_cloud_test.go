@@ -16,6 +16,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	mcpgrafana "github.com/grafana/mcp-grafana"
 	"github.com/stretchr/testify/assert"
@@ -329,3 +330,111 @@ func TestCloudOnCallAlerts(t *testing.T) {
 		assert.Error(t, err, "Should error when getting alerts with invalid alert group ID")
 	})
 }
+
+// TestCloudOnCallAlertGroupMutations exercises the write-path handlers
+// (acknowledge/resolve/silence/unresolve/attach) against a real alert group,
+// if the test instance has one. These mutate state on the test instance, so
+// each subtest acts on its own alert group where possible rather than
+// chaining off a single one.
+func TestCloudOnCallAlertGroupMutations(t *testing.T) {
+	ctx := createOnCallCloudTestContext(t)
+
+	alertGroups, err := listOnCallAlertGroupsHandler(ctx, ListOnCallAlertGroupsParams{})
+	require.NoError(t, err, "Should not error when listing alert groups")
+	if len(alertGroups) == 0 {
+		t.Skip("No alert groups available to test mutations")
+	}
+	alertGroupID := alertGroups[0].ID
+
+	t.Run("acknowledge alert group", func(t *testing.T) {
+		result, err := ackOnCallAlertGroupHandler(ctx, AckOnCallAlertGroupParams{AlertGroupID: alertGroupID})
+		require.NoError(t, err, "Should not error when acknowledging alert group")
+		assert.Equal(t, alertGroupID, result.ID, "Should return the acknowledged alert group")
+	})
+
+	t.Run("silence alert group", func(t *testing.T) {
+		result, err := silenceOnCallAlertGroupHandler(ctx, SilenceOnCallAlertGroupParams{AlertGroupID: alertGroupID, DelaySeconds: 60})
+		require.NoError(t, err, "Should not error when silencing alert group")
+		assert.Equal(t, alertGroupID, result.ID, "Should return the silenced alert group")
+	})
+
+	t.Run("resolve alert group", func(t *testing.T) {
+		result, err := resolveOnCallAlertGroupHandler(ctx, ResolveOnCallAlertGroupParams{AlertGroupID: alertGroupID})
+		require.NoError(t, err, "Should not error when resolving alert group")
+		assert.Equal(t, alertGroupID, result.ID, "Should return the resolved alert group")
+	})
+
+	t.Run("unresolve alert group", func(t *testing.T) {
+		result, err := unresolveOnCallAlertGroupHandler(ctx, UnresolveOnCallAlertGroupParams{AlertGroupID: alertGroupID})
+		require.NoError(t, err, "Should not error when unresolving alert group")
+		assert.Equal(t, alertGroupID, result.ID, "Should return the unresolved alert group")
+	})
+
+	t.Run("acknowledge with invalid alert group ID", func(t *testing.T) {
+		_, err := ackOnCallAlertGroupHandler(ctx, AckOnCallAlertGroupParams{AlertGroupID: "invalid-alert-group-id"})
+		assert.Error(t, err, "Should error when acknowledging an invalid alert group ID")
+	})
+
+	if len(alertGroups) < 2 {
+		t.Skip("Need at least two alert groups to test attach")
+	}
+	rootAlertGroupID := alertGroups[1].ID
+
+	t.Run("attach alert group", func(t *testing.T) {
+		result, err := attachOnCallAlertGroupHandler(ctx, AttachOnCallAlertGroupParams{
+			AlertGroupID:     alertGroupID,
+			RootAlertGroupID: rootAlertGroupID,
+		})
+		require.NoError(t, err, "Should not error when attaching alert group")
+		assert.Equal(t, rootAlertGroupID, result.ID, "Should return the root alert group")
+	})
+}
+
+// TestCloudOnCallScheduleOverride creates an override on the test schedule,
+// verifies it takes effect in get_current_oncall_users during the override
+// window, and deletes it afterwards regardless of whether the assertions
+// passed.
+func TestCloudOnCallScheduleOverride(t *testing.T) {
+	ctx := createOnCallCloudTestContext(t)
+
+	schedules, err := listOnCallSchedulesHandler(ctx, ListOnCallSchedulesParams{})
+	require.NoError(t, err, "Should not error when listing schedules")
+	require.NotEmpty(t, schedules, "Should have at least one schedule to test with")
+	scheduleID := schedules[0].ID
+
+	users, err := listOnCallUsersHandler(ctx, ListOnCallUsersParams{})
+	require.NoError(t, err, "Should not error when listing users")
+	require.NotEmpty(t, users, "Should have at least one user to test with")
+	userID := users[0].ID
+
+	start := time.Now().UTC().Add(-5 * time.Minute)
+	end := start.Add(time.Hour)
+
+	override, err := createOnCallScheduleOverrideHandler(ctx, CreateOnCallScheduleOverrideParams{
+		ScheduleID: scheduleID,
+		UserID:     userID,
+		Start:      start.Format(time.RFC3339),
+		End:        end.Format(time.RFC3339),
+	})
+	require.NoError(t, err, "Should not error when creating a schedule override")
+	require.NotNil(t, override, "Override should not be nil")
+
+	defer func() {
+		_, err := deleteOnCallScheduleOverrideHandler(ctx, DeleteOnCallScheduleOverrideParams{ShiftID: override.ID})
+		assert.NoError(t, err, "Should not error when cleaning up the schedule override")
+	}()
+
+	t.Run("override appears in current on-call users during its window", func(t *testing.T) {
+		result, err := getCurrentOnCallUsersHandler(ctx, GetCurrentOnCallUsersParams{ScheduleID: scheduleID})
+		require.NoError(t, err, "Should not error when getting current on-call users")
+
+		var found bool
+		for _, user := range result.Users {
+			if user.ID == userID {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "Override user should be reflected by get_current_oncall_users")
+	})
+}
@@ -0,0 +1,181 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// onCallActorKey is the context key for WithOnCallActor/OnCallActorFromContext.
+type onCallActorKey struct{}
+
+// WithOnCallActor attaches actor (typically an email or username identifying
+// who's driving an MCP session) to ctx, so OnCall mutation tools can record
+// who made a change.
+func WithOnCallActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, onCallActorKey{}, actor)
+}
+
+// OnCallActorFromContext returns the actor attached via WithOnCallActor, or
+// "" if none was set.
+func OnCallActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(onCallActorKey{}).(string)
+	return actor
+}
+
+// logOnCallMutation records who triggered an OnCall write action and what it
+// was. The amixr-api-go-client alert group endpoints don't accept a
+// caller-supplied actor header, so this is surfaced via structured logging
+// rather than forwarded to the OnCall API itself.
+func logOnCallMutation(ctx context.Context, action, alertGroupID string) {
+	slog.InfoContext(ctx, "OnCall alert group mutation", "action", action, "alert_group_id", alertGroupID, "actor", OnCallActorFromContext(ctx))
+}
+
+type AckOnCallAlertGroupParams struct {
+	AlertGroupID string `json:"alertGroupId" jsonschema:"required,description=The ID of the alert group to acknowledge"`
+}
+
+func ackOnCallAlertGroupHandler(ctx context.Context, args AckOnCallAlertGroupParams) (*OnCallAlertGroup, error) {
+	logOnCallMutation(ctx, "acknowledge", args.AlertGroupID)
+	return onCallProviderFromContext(ctx).AckAlertGroup(ctx, args.AlertGroupID)
+}
+
+var AckOnCallAlertGroup = mcpgrafana.MustTool(
+	"ack_oncall_alert_group",
+	"Acknowledge an OnCall alert group, marking it as being actively worked so other responders know it's being handled.",
+	ackOnCallAlertGroupHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+type ResolveOnCallAlertGroupParams struct {
+	AlertGroupID string `json:"alertGroupId" jsonschema:"required,description=The ID of the alert group to resolve"`
+}
+
+func resolveOnCallAlertGroupHandler(ctx context.Context, args ResolveOnCallAlertGroupParams) (*OnCallAlertGroup, error) {
+	logOnCallMutation(ctx, "resolve", args.AlertGroupID)
+	return onCallProviderFromContext(ctx).ResolveAlertGroup(ctx, args.AlertGroupID)
+}
+
+var ResolveOnCallAlertGroup = mcpgrafana.MustTool(
+	"resolve_oncall_alert_group",
+	"Resolve an OnCall alert group, marking the underlying issue as fixed.",
+	resolveOnCallAlertGroupHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+type SilenceOnCallAlertGroupParams struct {
+	AlertGroupID string `json:"alertGroupId" jsonschema:"required,description=The ID of the alert group to silence"`
+	DelaySeconds int    `json:"delaySeconds,omitempty" jsonschema:"description=How long, in seconds, to silence the alert group for. If omitted, silences indefinitely"`
+}
+
+func silenceOnCallAlertGroupHandler(ctx context.Context, args SilenceOnCallAlertGroupParams) (*OnCallAlertGroup, error) {
+	logOnCallMutation(ctx, "silence", args.AlertGroupID)
+	return onCallProviderFromContext(ctx).SilenceAlertGroup(ctx, args.AlertGroupID, args.DelaySeconds)
+}
+
+var SilenceOnCallAlertGroup = mcpgrafana.MustTool(
+	"silence_oncall_alert_group",
+	"Silence an OnCall alert group for a given number of seconds (or indefinitely if omitted), suppressing further notifications without resolving it.",
+	silenceOnCallAlertGroupHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+type UnresolveOnCallAlertGroupParams struct {
+	AlertGroupID string `json:"alertGroupId" jsonschema:"required,description=The ID of the alert group to unresolve"`
+}
+
+func unresolveOnCallAlertGroupHandler(ctx context.Context, args UnresolveOnCallAlertGroupParams) (*OnCallAlertGroup, error) {
+	logOnCallMutation(ctx, "unresolve", args.AlertGroupID)
+	return onCallProviderFromContext(ctx).UnresolveAlertGroup(ctx, args.AlertGroupID)
+}
+
+var UnresolveOnCallAlertGroup = mcpgrafana.MustTool(
+	"unresolve_oncall_alert_group",
+	"Reopen a previously resolved OnCall alert group.",
+	unresolveOnCallAlertGroupHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+type EscalateOnCallAlertGroupParams struct {
+	AlertGroupID string `json:"alertGroupId" jsonschema:"required,description=The ID of the alert group to escalate"`
+}
+
+func escalateOnCallAlertGroupHandler(ctx context.Context, args EscalateOnCallAlertGroupParams) (*OnCallAlertGroup, error) {
+	logOnCallMutation(ctx, "escalate", args.AlertGroupID)
+	return onCallProviderFromContext(ctx).EscalateAlertGroup(ctx, args.AlertGroupID)
+}
+
+var EscalateOnCallAlertGroup = mcpgrafana.MustTool(
+	"escalate_oncall_alert_group",
+	"Manually trigger the next escalation step for an OnCall alert group, notifying the next responders in the escalation chain immediately.",
+	escalateOnCallAlertGroupHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+type AttachOnCallAlertGroupParams struct {
+	AlertGroupID     string `json:"alertGroupId" jsonschema:"required,description=The ID of the alert group to attach"`
+	RootAlertGroupID string `json:"rootAlertGroupId" jsonschema:"required,description=The ID of the alert group to attach it to"`
+}
+
+func attachOnCallAlertGroupHandler(ctx context.Context, args AttachOnCallAlertGroupParams) (*OnCallAlertGroup, error) {
+	logOnCallMutation(ctx, "attach", args.AlertGroupID)
+	return onCallProviderFromContext(ctx).AttachAlertGroup(ctx, args.AlertGroupID, args.RootAlertGroupID)
+}
+
+var AttachOnCallAlertGroup = mcpgrafana.MustTool(
+	"attach_oncall_alert_group",
+	"Attach an OnCall alert group to another alert group, folding it in as a duplicate so responders only need to act on the group it's attached to.",
+	attachOnCallAlertGroupHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+// PaginateOnCallAlertGroupsParams mirrors ListOnCallAlertGroupsParams but
+// exposes the underlying page-based pagination as an opaque cursor, so
+// callers don't need to track page numbers themselves.
+type PaginateOnCallAlertGroupsParams struct {
+	ListOnCallAlertGroupsParams
+	Cursor string `json:"cursor,omitempty" jsonschema:"description=Opaque pagination cursor returned as nextCursor by a previous call. Omit to fetch the first page"`
+}
+
+// PaginatedOnCallAlertGroups is one page of alert groups plus the cursor to
+// pass back in to fetch the next page.
+type PaginatedOnCallAlertGroups struct {
+	AlertGroups []*OnCallAlertGroup `json:"alertGroups"`
+	NextCursor  string              `json:"nextCursor,omitempty" jsonschema:"description=Pass this as cursor to fetch the next page. Absent if there are no more results"`
+}
+
+func paginateOnCallAlertGroupsHandler(ctx context.Context, args PaginateOnCallAlertGroupsParams) (*PaginatedOnCallAlertGroups, error) {
+	page := 1
+	if args.Cursor != "" {
+		parsed, err := strconv.Atoi(args.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor %q: %w", args.Cursor, err)
+		}
+		page = parsed
+	}
+
+	listArgs := args.ListOnCallAlertGroupsParams
+	listArgs.Page = page
+
+	alertGroups, err := onCallProviderFromContext(ctx).ListAlertGroups(ctx, listArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PaginatedOnCallAlertGroups{AlertGroups: alertGroups}
+	if len(alertGroups) > 0 {
+		result.NextCursor = strconv.Itoa(page + 1)
+	}
+	return result, nil
+}
+
+var PaginateOnCallAlertGroups = mcpgrafana.MustTool(
+	"paginate_oncall_alert_groups",
+	"List OnCall alert groups one page at a time using an opaque cursor instead of a page number. Omit cursor to get the first page; pass back the returned nextCursor to get the next one, and stop once nextCursor is absent.",
+	paginateOnCallAlertGroupsHandler,
+	mcp.WithReadOnlyHintAnnotation(true),
+)
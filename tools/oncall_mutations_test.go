@@ -0,0 +1,18 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOnCallActorFromContext(t *testing.T) {
+	ctx := context.Background()
+	if got := OnCallActorFromContext(ctx); got != "" {
+		t.Errorf("expected empty actor for a context with none set, got %q", got)
+	}
+
+	ctx = WithOnCallActor(ctx, "alice@example.com")
+	if got := OnCallActorFromContext(ctx); got != "alice@example.com" {
+		t.Errorf("expected alice@example.com, got %q", got)
+	}
+}
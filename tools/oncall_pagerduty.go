@@ -0,0 +1,340 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	aapi "github.com/grafana/amixr-api-go-client"
+)
+
+// pagerDutyAPIKeyEnvVar is the fallback environment variable read when no
+// PagerDuty API key has been attached to the request context.
+const pagerDutyAPIKeyEnvVar = "PAGERDUTY_API_KEY"
+
+// pagerDutyBaseURL is the PagerDuty REST API v2 base URL.
+const pagerDutyBaseURL = "https://api.pagerduty.com"
+
+// pagerDutyAPIKeyKey is the context key for WithPagerDutyAPIKey/pagerDutyAPIKeyFromContext.
+type pagerDutyAPIKeyKey struct{}
+
+// WithPagerDutyAPIKey attaches a PagerDuty REST API token to ctx, for use
+// by PagerDutyOnCallProvider when --oncall-provider=pagerduty is selected.
+func WithPagerDutyAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, pagerDutyAPIKeyKey{}, apiKey)
+}
+
+// pagerDutyAPIKeyFromContext returns the API key attached via
+// WithPagerDutyAPIKey, falling back to the PAGERDUTY_API_KEY environment
+// variable.
+func pagerDutyAPIKeyFromContext(ctx context.Context) string {
+	if apiKey, ok := ctx.Value(pagerDutyAPIKeyKey{}).(string); ok && apiKey != "" {
+		return apiKey
+	}
+	return os.Getenv(pagerDutyAPIKeyEnvVar)
+}
+
+// PagerDutyOnCallProvider is the OnCallProvider backed by PagerDuty's REST
+// API, mapping PagerDuty schedules/on-calls/incidents onto the same
+// vendor-neutral shapes Grafana OnCall returns.
+type PagerDutyOnCallProvider struct{}
+
+func (p PagerDutyOnCallProvider) request(ctx context.Context, method, path string, query string, body any) (*http.Response, error) {
+	apiKey := pagerDutyAPIKeyFromContext(ctx)
+	if apiKey == "" {
+		return nil, fmt.Errorf("no PagerDuty API key found in context or %s", pagerDutyAPIKeyEnvVar)
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding PagerDuty request body: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	url := pagerDutyBaseURL + path
+	if query != "" {
+		url += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating PagerDuty request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", "Token token="+apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling PagerDuty API: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("PagerDuty API returned %d: %s", resp.StatusCode, string(msg))
+	}
+	return resp, nil
+}
+
+type pagerDutySchedule struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	TimeZone string `json:"time_zone"`
+	Teams    []struct {
+		ID string `json:"id"`
+	} `json:"teams"`
+}
+
+func (p PagerDutyOnCallProvider) ListSchedules(ctx context.Context, args ListOnCallSchedulesParams) ([]*ScheduleSummary, error) {
+	if args.ScheduleID != "" {
+		resp, err := p.request(ctx, http.MethodGet, "/schedules/"+args.ScheduleID, "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting PagerDuty schedule %s: %w", args.ScheduleID, err)
+		}
+		defer resp.Body.Close()
+
+		var decoded struct {
+			Schedule pagerDutySchedule `json:"schedule"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("decoding PagerDuty schedule %s: %w", args.ScheduleID, err)
+		}
+		return []*ScheduleSummary{pagerDutyToScheduleSummary(decoded.Schedule)}, nil
+	}
+
+	resp, err := p.request(ctx, http.MethodGet, "/schedules", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing PagerDuty schedules: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Schedules []pagerDutySchedule `json:"schedules"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding PagerDuty schedules: %w", err)
+	}
+
+	summaries := make([]*ScheduleSummary, 0, len(decoded.Schedules))
+	for _, schedule := range decoded.Schedules {
+		if args.TeamID != "" && !pagerDutyScheduleHasTeam(schedule, args.TeamID) {
+			continue
+		}
+		summaries = append(summaries, pagerDutyToScheduleSummary(schedule))
+	}
+	return summaries, nil
+}
+
+func pagerDutyScheduleHasTeam(schedule pagerDutySchedule, teamID string) bool {
+	for _, team := range schedule.Teams {
+		if team.ID == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+func pagerDutyToScheduleSummary(schedule pagerDutySchedule) *ScheduleSummary {
+	summary := &ScheduleSummary{
+		ID:       schedule.ID,
+		Name:     schedule.Name,
+		Timezone: schedule.TimeZone,
+	}
+	if len(schedule.Teams) > 0 {
+		summary.TeamID = schedule.Teams[0].ID
+	}
+	return summary
+}
+
+func (p PagerDutyOnCallProvider) GetCurrentOnCall(ctx context.Context, args GetCurrentOnCallUsersParams) (*CurrentOnCallUsers, error) {
+	resp, err := p.request(ctx, http.MethodGet, "/oncalls", "schedule_ids[]="+args.ScheduleID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting PagerDuty on-calls for schedule %s: %w", args.ScheduleID, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		OnCalls []struct {
+			Schedule struct {
+				ID      string `json:"id"`
+				Summary string `json:"summary"`
+			} `json:"schedule"`
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"oncalls"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding PagerDuty on-calls for schedule %s: %w", args.ScheduleID, err)
+	}
+
+	result := &CurrentOnCallUsers{ScheduleID: args.ScheduleID}
+	for _, oc := range decoded.OnCalls {
+		if result.ScheduleName == "" {
+			result.ScheduleName = oc.Schedule.Summary
+		}
+		result.Users = append(result.Users, &aapi.User{ID: oc.User.ID})
+	}
+	return result, nil
+}
+
+type pagerDutyIncident struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+func (p PagerDutyOnCallProvider) ListAlertGroups(ctx context.Context, args ListOnCallAlertGroupsParams) ([]*OnCallAlertGroup, error) {
+	query := ""
+	if args.ID != "" {
+		query = "incident_key=" + args.ID
+	}
+
+	resp, err := p.request(ctx, http.MethodGet, "/incidents", query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing PagerDuty incidents: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Incidents []pagerDutyIncident `json:"incidents"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding PagerDuty incidents: %w", err)
+	}
+
+	result := make([]*OnCallAlertGroup, 0, len(decoded.Incidents))
+	for _, incident := range decoded.Incidents {
+		if args.State != "" && pagerDutyToState(incident.Status) != args.State {
+			continue
+		}
+		result = append(result, pagerDutyToOnCallAlertGroup(incident))
+	}
+	return result, nil
+}
+
+// pagerDutyToState maps a PagerDuty incident status onto the same
+// new/acknowledged/resolved/silenced vocabulary Grafana OnCall uses, so
+// list_oncall_alert_groups' state filter behaves the same regardless of
+// backend. PagerDuty has no "silenced" status; snoozed incidents still
+// report as "triggered" or "acknowledged" upstream.
+func pagerDutyToState(status string) string {
+	switch status {
+	case "triggered":
+		return "new"
+	case "acknowledged":
+		return "acknowledged"
+	case "resolved":
+		return "resolved"
+	default:
+		return status
+	}
+}
+
+func pagerDutyToOnCallAlertGroup(incident pagerDutyIncident) *OnCallAlertGroup {
+	return &OnCallAlertGroup{
+		ID:    incident.ID,
+		Title: incident.Title,
+		State: pagerDutyToState(incident.Status),
+	}
+}
+
+// updateIncidentStatus performs a PagerDuty PUT /incidents/{id} status
+// transition, the shape shared by ack/resolve/unresolve.
+func (p PagerDutyOnCallProvider) updateIncidentStatus(ctx context.Context, alertGroupID, status string) (*OnCallAlertGroup, error) {
+	body := map[string]any{
+		"incident": map[string]any{
+			"type":   "incident_reference",
+			"status": status,
+		},
+	}
+	resp, err := p.request(ctx, http.MethodPut, "/incidents/"+alertGroupID, "", body)
+	if err != nil {
+		return nil, fmt.Errorf("updating PagerDuty incident %s to %s: %w", alertGroupID, status, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Incident pagerDutyIncident `json:"incident"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding PagerDuty incident %s: %w", alertGroupID, err)
+	}
+	return pagerDutyToOnCallAlertGroup(decoded.Incident), nil
+}
+
+func (p PagerDutyOnCallProvider) AckAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error) {
+	return p.updateIncidentStatus(ctx, alertGroupID, "acknowledged")
+}
+
+func (p PagerDutyOnCallProvider) ResolveAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error) {
+	return p.updateIncidentStatus(ctx, alertGroupID, "resolved")
+}
+
+func (p PagerDutyOnCallProvider) UnresolveAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error) {
+	return p.updateIncidentStatus(ctx, alertGroupID, "triggered")
+}
+
+// SilenceAlertGroup maps onto PagerDuty's incident snooze endpoint, which
+// suppresses notifications for a fixed duration rather than indefinitely;
+// a delaySeconds of 0 is translated to a 24 hour snooze since PagerDuty
+// requires a duration.
+func (p PagerDutyOnCallProvider) SilenceAlertGroup(ctx context.Context, alertGroupID string, delaySeconds int) (*OnCallAlertGroup, error) {
+	duration := delaySeconds
+	if duration <= 0 {
+		duration = 24 * 60 * 60
+	}
+	resp, err := p.request(ctx, http.MethodPost, "/incidents/"+alertGroupID+"/snooze", "", map[string]any{"duration": duration})
+	if err != nil {
+		return nil, fmt.Errorf("snoozing PagerDuty incident %s: %w", alertGroupID, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Incident pagerDutyIncident `json:"incident"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding PagerDuty incident %s: %w", alertGroupID, err)
+	}
+	return pagerDutyToOnCallAlertGroup(decoded.Incident), nil
+}
+
+// EscalateAlertGroup has no PagerDuty equivalent: the REST API exposes
+// reassignment and responder requests, but not a "jump to the next
+// escalation step" action the way Grafana OnCall does.
+func (p PagerDutyOnCallProvider) EscalateAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error) {
+	return nil, fmt.Errorf("manual escalation is not supported by the pagerduty oncall backend")
+}
+
+// AttachAlertGroup maps onto PagerDuty's incident merge endpoint: alertGroupID
+// is merged into rootAlertGroupID, which survives as the single incident
+// responders act on.
+func (p PagerDutyOnCallProvider) AttachAlertGroup(ctx context.Context, alertGroupID, rootAlertGroupID string) (*OnCallAlertGroup, error) {
+	body := map[string]any{
+		"source_incidents": []map[string]any{
+			{"id": alertGroupID, "type": "incident_reference"},
+		},
+	}
+	resp, err := p.request(ctx, http.MethodPut, "/incidents/"+rootAlertGroupID+"/merge", "", body)
+	if err != nil {
+		return nil, fmt.Errorf("attaching PagerDuty incident %s to %s: %w", alertGroupID, rootAlertGroupID, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Incident pagerDutyIncident `json:"incident"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding PagerDuty incident %s: %w", rootAlertGroupID, err)
+	}
+	return pagerDutyToOnCallAlertGroup(decoded.Incident), nil
+}
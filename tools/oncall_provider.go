@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	aapi "github.com/grafana/amixr-api-go-client"
+)
+
+// OnCallAlertGroup is a vendor-neutral view of an on-call alert/incident
+// group, returned by every OnCallProvider regardless of which backend
+// (Grafana OnCall, PagerDuty, ...) actually services the request.
+type OnCallAlertGroup struct {
+	ID            string   `json:"id" jsonschema:"description=The ID of the alert group, in the originating provider's own ID space"`
+	Title         string   `json:"title,omitempty" jsonschema:"description=A human-readable summary of the alert group"`
+	State         string   `json:"state" jsonschema:"description=One of new, acknowledged, resolved, or silenced"`
+	CreatedAt     string   `json:"createdAt,omitempty" jsonschema:"description=When the alert group was created, used to sort results most-recent-first"`
+	MatchedLabels []string `json:"matchedLabels,omitempty" jsonschema:"description=The key:value label constraints from labelsQuery that this alert group satisfied, explaining why it matched. Only populated when labelsQuery was used"`
+}
+
+// OnCallProvider abstracts the on-call data source behind the oncall_*
+// MCP tools. The Grafana OnCall implementation (GrafanaOnCallProvider) is
+// the default backend; other vendors (PagerDutyOnCallProvider, and
+// eventually Opsgenie/FireHydrant) implement the same interface so the tool
+// names and schemas an agent sees don't change when --oncall-provider does.
+type OnCallProvider interface {
+	// ListSchedules lists on-call schedules, or returns the single schedule
+	// matching args.ScheduleID if one is given.
+	ListSchedules(ctx context.Context, args ListOnCallSchedulesParams) ([]*ScheduleSummary, error)
+	// GetCurrentOnCall returns who's currently on call for a schedule.
+	GetCurrentOnCall(ctx context.Context, args GetCurrentOnCallUsersParams) (*CurrentOnCallUsers, error)
+	// ListAlertGroups lists alert/incident groups, optionally filtered.
+	ListAlertGroups(ctx context.Context, args ListOnCallAlertGroupsParams) ([]*OnCallAlertGroup, error)
+	// AckAlertGroup acknowledges an alert group.
+	AckAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error)
+	// ResolveAlertGroup resolves an alert group.
+	ResolveAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error)
+	// SilenceAlertGroup silences an alert group for delaySeconds (or
+	// indefinitely if delaySeconds is 0).
+	SilenceAlertGroup(ctx context.Context, alertGroupID string, delaySeconds int) (*OnCallAlertGroup, error)
+	// UnresolveAlertGroup reopens a previously resolved alert group.
+	UnresolveAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error)
+	// EscalateAlertGroup manually triggers the next escalation step.
+	EscalateAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error)
+	// AttachAlertGroup attaches alertGroupID to rootAlertGroupID, folding it
+	// under the root group as a duplicate so responders only have to act on
+	// one of them.
+	AttachAlertGroup(ctx context.Context, alertGroupID, rootAlertGroupID string) (*OnCallAlertGroup, error)
+}
+
+// onCallProviderKey is the context key for WithOnCallProvider/onCallProviderFromContext.
+type onCallProviderKey struct{}
+
+// WithOnCallProvider attaches an OnCallProvider to ctx, overriding the
+// process-wide default (selected via --oncall-provider) for this request.
+// This lets a multi-tenant deployment route individual requests to
+// different on-call vendors.
+func WithOnCallProvider(ctx context.Context, provider OnCallProvider) context.Context {
+	return context.WithValue(ctx, onCallProviderKey{}, provider)
+}
+
+// defaultOnCallProvider is the provider selected at startup via
+// --oncall-provider. It backs any request whose context doesn't carry its
+// own provider via WithOnCallProvider.
+var defaultOnCallProvider OnCallProvider = GrafanaOnCallProvider{}
+
+// SetDefaultOnCallProvider sets the process-wide default OnCallProvider.
+// Called once at startup from the --oncall-provider flag.
+func SetDefaultOnCallProvider(provider OnCallProvider) {
+	defaultOnCallProvider = provider
+}
+
+// onCallProviderFromContext returns the OnCallProvider attached via
+// WithOnCallProvider, falling back to the process-wide default.
+func onCallProviderFromContext(ctx context.Context) OnCallProvider {
+	if provider, ok := ctx.Value(onCallProviderKey{}).(OnCallProvider); ok && provider != nil {
+		return provider
+	}
+	return defaultOnCallProvider
+}
+
+// NewOnCallProvider constructs the OnCallProvider named by --oncall-provider.
+// An empty name selects Grafana OnCall, the default backend.
+func NewOnCallProvider(name string) (OnCallProvider, error) {
+	switch name {
+	case "", "grafana":
+		return GrafanaOnCallProvider{}, nil
+	case "pagerduty":
+		return PagerDutyOnCallProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown oncall provider %q (supported: grafana, pagerduty)", name)
+	}
+}
+
+// GrafanaOnCallProvider is the OnCallProvider backed by Grafana OnCall,
+// reusing the existing fetch*/mutation helpers in oncall.go and
+// oncall_mutations.go.
+type GrafanaOnCallProvider struct{}
+
+func (GrafanaOnCallProvider) ListSchedules(ctx context.Context, args ListOnCallSchedulesParams) ([]*ScheduleSummary, error) {
+	return fetchOnCallSchedules(ctx, args)
+}
+
+func (GrafanaOnCallProvider) GetCurrentOnCall(ctx context.Context, args GetCurrentOnCallUsersParams) (*CurrentOnCallUsers, error) {
+	return fetchCurrentOnCallUsers(ctx, args)
+}
+
+func (GrafanaOnCallProvider) ListAlertGroups(ctx context.Context, args ListOnCallAlertGroupsParams) ([]*OnCallAlertGroup, error) {
+	return listGrafanaAlertGroups(ctx, args)
+}
+
+func (GrafanaOnCallProvider) AckAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error) {
+	alertGroupService, err := getAlertGroupServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall alert group service: %w", err)
+	}
+	alertGroup, resp, err := alertGroupService.AcknowledgeAlertGroup(alertGroupID, &aapi.AcknowledgeAlertGroupOptions{})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("acknowledging OnCall alert group %s: %w", alertGroupID, err)
+	}
+	return grafanaToOnCallAlertGroup(alertGroup), nil
+}
+
+func (GrafanaOnCallProvider) ResolveAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error) {
+	alertGroupService, err := getAlertGroupServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall alert group service: %w", err)
+	}
+	alertGroup, resp, err := alertGroupService.ResolveAlertGroup(alertGroupID, &aapi.ResolveAlertGroupOptions{})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("resolving OnCall alert group %s: %w", alertGroupID, err)
+	}
+	return grafanaToOnCallAlertGroup(alertGroup), nil
+}
+
+func (GrafanaOnCallProvider) SilenceAlertGroup(ctx context.Context, alertGroupID string, delaySeconds int) (*OnCallAlertGroup, error) {
+	alertGroupService, err := getAlertGroupServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall alert group service: %w", err)
+	}
+	alertGroup, resp, err := alertGroupService.SilenceAlertGroup(alertGroupID, &aapi.SilenceAlertGroupOptions{Delay: delaySeconds})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("silencing OnCall alert group %s: %w", alertGroupID, err)
+	}
+	return grafanaToOnCallAlertGroup(alertGroup), nil
+}
+
+func (GrafanaOnCallProvider) UnresolveAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error) {
+	alertGroupService, err := getAlertGroupServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall alert group service: %w", err)
+	}
+	alertGroup, resp, err := alertGroupService.UnresolveAlertGroup(alertGroupID, &aapi.UnresolveAlertGroupOptions{})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("unresolving OnCall alert group %s: %w", alertGroupID, err)
+	}
+	return grafanaToOnCallAlertGroup(alertGroup), nil
+}
+
+func (GrafanaOnCallProvider) EscalateAlertGroup(ctx context.Context, alertGroupID string) (*OnCallAlertGroup, error) {
+	alertGroupService, err := getAlertGroupServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall alert group service: %w", err)
+	}
+	alertGroup, resp, err := alertGroupService.EscalateAlertGroup(alertGroupID, &aapi.EscalateAlertGroupOptions{})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("escalating OnCall alert group %s: %w", alertGroupID, err)
+	}
+	return grafanaToOnCallAlertGroup(alertGroup), nil
+}
+
+func (GrafanaOnCallProvider) AttachAlertGroup(ctx context.Context, alertGroupID, rootAlertGroupID string) (*OnCallAlertGroup, error) {
+	alertGroupService, err := getAlertGroupServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall alert group service: %w", err)
+	}
+	alertGroup, resp, err := alertGroupService.AttachAlertGroup(alertGroupID, &aapi.AttachAlertGroupOptions{RootAlertGroup: rootAlertGroupID})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("attaching OnCall alert group %s to %s: %w", alertGroupID, rootAlertGroupID, err)
+	}
+	return grafanaToOnCallAlertGroup(alertGroup), nil
+}
+
+// grafanaToOnCallAlertGroup converts a Grafana OnCall alert group into the
+// vendor-neutral shape every OnCallProvider returns.
+func grafanaToOnCallAlertGroup(ag *aapi.AlertGroup) *OnCallAlertGroup {
+	return &OnCallAlertGroup{
+		ID:        ag.ID,
+		State:     ag.State,
+		CreatedAt: ag.CreatedAt,
+	}
+}
@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	aapi "github.com/grafana/amixr-api-go-client"
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// RotationUser is a shift participant resolved to the fields an LLM needs to
+// identify them without a follow-up list_oncall_users call.
+type RotationUser struct {
+	ID       string `json:"id" jsonschema:"description=The unique identifier of the user"`
+	Username string `json:"username" jsonschema:"description=The username of the user"`
+	Email    string `json:"email" jsonschema:"description=The email address of the user"`
+}
+
+// ExpandedShift is a fully-resolved view of a single shift within a
+// schedule's rotation, replacing the bare shift ID with everything needed to
+// reason about who is on call and when without a separate get_oncall_shift
+// call.
+type ExpandedShift struct {
+	ID        string         `json:"id" jsonschema:"description=The unique identifier of the shift"`
+	Type      string         `json:"type" jsonschema:"description=The shift type: single_event, recurrent_event, or rolling_users"`
+	Frequency string         `json:"frequency,omitempty" jsonschema:"description=The recurrence frequency (daily, weekly, monthly, hourly), empty for single_event shifts"`
+	Interval  int            `json:"interval,omitempty" jsonschema:"description=The recurrence interval, e.g. 2 with frequency weekly means every other week"`
+	ByDay     []string       `json:"byDay,omitempty" jsonschema:"description=Weekday codes (MO, TU, ...) the recurrence is restricted to, empty if unrestricted"`
+	Start     string         `json:"start" jsonschema:"description=Start of the first occurrence, in the schedule's timezone"`
+	Duration  int            `json:"duration" jsonschema:"description=Duration of each occurrence in seconds"`
+	Users     []RotationUser `json:"users" jsonschema:"description=The users participating in this shift's rotation, resolved from IDs"`
+	Summary   string         `json:"summary" jsonschema:"description=A human-readable one-line summary of this shift, e.g. 'Weekly Mon-Fri 09:00-17:00 America/New_York, 3-user rolling rotation'"`
+}
+
+// rotationUserCache resolves OnCall user IDs to RotationUser within a single
+// request, so a rotation where the same users appear across many shifts
+// doesn't pay for a GetUser call per shift per user.
+type rotationUserCache struct {
+	ctx     context.Context
+	service *aapi.UserService
+	users   map[string]RotationUser
+}
+
+func newRotationUserCache(ctx context.Context, service *aapi.UserService) *rotationUserCache {
+	return &rotationUserCache{ctx: ctx, service: service, users: make(map[string]RotationUser)}
+}
+
+func (c *rotationUserCache) resolve(userID string) RotationUser {
+	if user, ok := c.users[userID]; ok {
+		return user
+	}
+
+	resolved := RotationUser{ID: userID}
+	user, resp, err := c.service.GetUser(userID, &aapi.GetUserOptions{})
+	if globalOnCallSettingsCache.invalidateOnAuthError(c.ctx, resp, err) == nil {
+		resolved.Username = user.Username
+		resolved.Email = user.Email
+	}
+
+	c.users[userID] = resolved
+	return resolved
+}
+
+// expandShift resolves shift into an ExpandedShift, including its
+// participating users via userCache.
+func expandShift(shift *aapi.OnCallShift, userCache *rotationUserCache) ExpandedShift {
+	expanded := ExpandedShift{
+		ID:        shift.ID,
+		Type:      shift.Type,
+		Frequency: shift.Frequency,
+		Interval:  shift.Interval,
+		ByDay:     shift.ByDay,
+		Start:     shift.Start,
+		Duration:  shift.Duration,
+	}
+
+	for _, userID := range rotationUsers(shift) {
+		expanded.Users = append(expanded.Users, userCache.resolve(userID))
+	}
+
+	expanded.Summary = summarizeShift(shift, expanded.Users)
+	return expanded
+}
+
+// summarizeShift builds a human-readable one-line description of a shift,
+// e.g. "Weekly Mon-Fri 09:00-17:00, 3-user rolling rotation" or "One-off
+// 2026-07-27 09:00 for user-1".
+func summarizeShift(shift *aapi.OnCallShift, users []RotationUser) string {
+	var b strings.Builder
+
+	switch shift.Type {
+	case "single_event":
+		b.WriteString("One-off")
+	default:
+		switch shift.Frequency {
+		case "daily":
+			b.WriteString(recurrenceWord(shift.Interval, "Daily", "Every %d days"))
+		case "weekly":
+			b.WriteString(recurrenceWord(shift.Interval, "Weekly", "Every %d weeks"))
+		case "monthly":
+			b.WriteString(recurrenceWord(shift.Interval, "Monthly", "Every %d months"))
+		case "hourly":
+			b.WriteString(recurrenceWord(shift.Interval, "Hourly", "Every %d hours"))
+		default:
+			b.WriteString("Recurring")
+		}
+	}
+
+	if days := byDayRange(shift.ByDay); days != "" {
+		b.WriteString(" " + days)
+	}
+
+	if clock := shiftClockRange(shift); clock != "" {
+		b.WriteString(" " + clock)
+	}
+
+	if len(users) > 0 {
+		b.WriteString(fmt.Sprintf(", %d-user", len(users)))
+		if len(shift.RollingUsers) > 0 {
+			b.WriteString(" rolling rotation")
+		} else {
+			b.WriteString(" rotation")
+		}
+	}
+
+	return b.String()
+}
+
+// recurrenceWord renders a frequency as "Weekly" for interval 1, or
+// fmt.Sprintf(everyN, interval) for any other interval.
+func recurrenceWord(interval int, base, everyN string) string {
+	if interval <= 1 {
+		return base
+	}
+	return fmt.Sprintf(everyN, interval)
+}
+
+// byDayRange collapses a contiguous run of weekday codes (e.g.
+// ["MO","TU","WE","TH","FR"]) into "Mon-Fri", or joins a non-contiguous set
+// with commas (e.g. "Mon, Wed, Fri"). Returns "" if byDay is empty.
+func byDayRange(byDay []string) string {
+	if len(byDay) == 0 {
+		return ""
+	}
+
+	order := []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+	names := map[string]string{
+		"MO": "Mon", "TU": "Tue", "WE": "Wed", "TH": "Thu", "FR": "Fri", "SA": "Sat", "SU": "Sun",
+	}
+
+	present := make(map[string]bool, len(byDay))
+	for _, d := range byDay {
+		present[d] = true
+	}
+
+	var indices []int
+	for i, code := range order {
+		if present[code] {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == 0 {
+		return ""
+	}
+
+	contiguous := true
+	for i := 1; i < len(indices); i++ {
+		if indices[i] != indices[i-1]+1 {
+			contiguous = false
+			break
+		}
+	}
+	if contiguous && len(indices) > 1 {
+		return fmt.Sprintf("%s-%s", names[order[indices[0]]], names[order[indices[len(indices)-1]]])
+	}
+
+	parts := make([]string, 0, len(indices))
+	for _, i := range indices {
+		parts = append(parts, names[order[i]])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// shiftClockRange renders a shift's daily time-of-day window as "09:00-17:00"
+// derived from its Start and Duration, for shifts that occupy less than a
+// full day. Returns "" for shifts spanning a day or more, where a clock
+// range isn't meaningful.
+func shiftClockRange(shift *aapi.OnCallShift) string {
+	const dayInSeconds = 24 * 3600
+	if shift.Duration <= 0 || shift.Duration >= dayInSeconds {
+		return ""
+	}
+
+	parts := strings.SplitN(shift.Start, "T", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	startClock := parts[1]
+	if len(startClock) < 5 {
+		return ""
+	}
+	startClock = startClock[:5]
+
+	startSeconds, ok := clockToSeconds(startClock)
+	if !ok {
+		return ""
+	}
+	endSeconds := (startSeconds + shift.Duration) % dayInSeconds
+
+	return fmt.Sprintf("%s-%s", startClock, secondsToClock(endSeconds))
+}
+
+func clockToSeconds(clock string) (int, bool) {
+	parts := strings.SplitN(clock, ":", 2)
+	if len(parts) != 2 {
+		return 0, false
+	}
+	hours, err1 := strconv.Atoi(parts[0])
+	minutes, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	return hours*3600 + minutes*60, true
+}
+
+func secondsToClock(seconds int) string {
+	return fmt.Sprintf("%02d:%02d", seconds/3600, (seconds%3600)/60)
+}
+
+type DescribeOnCallRotationParams struct {
+	ScheduleID string `json:"scheduleId" jsonschema:"required,description=The ID of the schedule to describe"`
+}
+
+// OnCallRotationDescription is the full expanded view of a schedule's
+// rotation: the schedule itself plus every shift, resolved down to
+// participating users and a human-readable summary.
+type OnCallRotationDescription struct {
+	ScheduleID   string          `json:"scheduleId" jsonschema:"description=The unique identifier of the schedule"`
+	ScheduleName string          `json:"scheduleName" jsonschema:"description=The name of the schedule"`
+	Timezone     string          `json:"timezone" jsonschema:"description=The timezone for this schedule"`
+	Shifts       []ExpandedShift `json:"shifts" jsonschema:"description=The schedule's shifts, expanded with recurrence details, resolved users, and a summary"`
+}
+
+func describeOnCallRotationHandler(ctx context.Context, args DescribeOnCallRotationParams) (*OnCallRotationDescription, error) {
+	scheduleService, err := getScheduleServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall schedule service: %w", err)
+	}
+
+	schedule, resp, err := scheduleService.GetSchedule(args.ScheduleID, &aapi.GetScheduleOptions{})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("getting OnCall schedule %s: %w", args.ScheduleID, err)
+	}
+
+	shifts, err := expandScheduleShifts(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OnCallRotationDescription{
+		ScheduleID:   schedule.ID,
+		ScheduleName: schedule.Name,
+		Timezone:     schedule.TimeZone,
+		Shifts:       shifts,
+	}, nil
+}
+
+var DescribeOnCallRotation = mcpgrafana.MustTool(
+	"describe_oncall_rotation",
+	"Describe an OnCall schedule's rotation in full detail: every shift's type, recurrence (frequency/interval/byDay), start, duration, participating users (resolved to id/username/email), and a human-readable summary like 'Weekly Mon-Fri 09:00-17:00, 3-user rolling rotation'. Use this instead of fanning out get_oncall_shift calls per shift ID.",
+	describeOnCallRotationHandler,
+)
+
+// expandScheduleShifts fetches and expands every shift referenced by
+// schedule, resolving participating users through a single per-call cache
+// so users appearing in multiple shifts are only fetched once.
+func expandScheduleShifts(ctx context.Context, schedule *aapi.Schedule) ([]ExpandedShift, error) {
+	if schedule.Shifts == nil || len(*schedule.Shifts) == 0 {
+		return nil, nil
+	}
+
+	shiftService, err := getOnCallShiftServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall shift service: %w", err)
+	}
+	userService, err := getUserServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall user service: %w", err)
+	}
+	userCache := newRotationUserCache(ctx, userService)
+
+	shifts := make([]ExpandedShift, 0, len(*schedule.Shifts))
+	for _, shiftID := range *schedule.Shifts {
+		shift, resp, err := shiftService.GetOnCallShift(shiftID, &aapi.GetOnCallShiftOptions{})
+		if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+			return nil, fmt.Errorf("getting OnCall shift %s: %w", shiftID, err)
+		}
+		shifts = append(shifts, expandShift(shift, userCache))
+	}
+
+	return shifts, nil
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"testing"
+
+	aapi "github.com/grafana/amixr-api-go-client"
+)
+
+func TestByDayRange_ContiguousWeekdaysCollapseToRange(t *testing.T) {
+	got := byDayRange([]string{"MO", "TU", "WE", "TH", "FR"})
+	if got != "Mon-Fri" {
+		t.Errorf("expected Mon-Fri, got %q", got)
+	}
+}
+
+func TestByDayRange_NonContiguousDaysAreListed(t *testing.T) {
+	got := byDayRange([]string{"MO", "WE", "FR"})
+	if got != "Mon, Wed, Fri" {
+		t.Errorf("expected Mon, Wed, Fri, got %q", got)
+	}
+}
+
+func TestByDayRange_EmptyReturnsEmptyString(t *testing.T) {
+	if got := byDayRange(nil); got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+}
+
+func TestShiftClockRange_DerivesHoursFromStartAndDuration(t *testing.T) {
+	shift := &aapi.OnCallShift{Start: "2026-07-27T09:00:00", Duration: 8 * 3600}
+	if got := shiftClockRange(shift); got != "09:00-17:00" {
+		t.Errorf("expected 09:00-17:00, got %q", got)
+	}
+}
+
+func TestShiftClockRange_FullDayShiftHasNoClockRange(t *testing.T) {
+	shift := &aapi.OnCallShift{Start: "2026-07-27T00:00:00", Duration: 24 * 3600}
+	if got := shiftClockRange(shift); got != "" {
+		t.Errorf("expected empty string for a full-day shift, got %q", got)
+	}
+}
+
+func TestSummarizeShift_WeeklyRollingRotation(t *testing.T) {
+	shift := &aapi.OnCallShift{
+		Type:         "recurrent_event",
+		Frequency:    "weekly",
+		Start:        "2026-07-27T09:00:00",
+		Duration:     8 * 3600,
+		ByDay:        []string{"MO", "TU", "WE", "TH", "FR"},
+		RollingUsers: [][]string{{"user-1"}, {"user-2"}, {"user-3"}},
+	}
+	users := []RotationUser{{ID: "user-1"}, {ID: "user-2"}, {ID: "user-3"}}
+
+	got := summarizeShift(shift, users)
+	want := "Weekly Mon-Fri 09:00-17:00, 3-user rolling rotation"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSummarizeShift_SingleEvent(t *testing.T) {
+	shift := &aapi.OnCallShift{Type: "single_event", Start: "2026-07-27T09:00:00", Duration: 3600}
+	users := []RotationUser{{ID: "user-1"}}
+
+	got := summarizeShift(shift, users)
+	want := "One-off 09:00-10:00, 1-user rotation"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRotationUserCache_DeduplicatesRepeatedLookups(t *testing.T) {
+	cache := &rotationUserCache{users: map[string]RotationUser{
+		"user-1": {ID: "user-1", Username: "alice"},
+	}}
+
+	got := cache.resolve("user-1")
+	if got.Username != "alice" {
+		t.Errorf("expected cached resolution to skip the API call and return alice, got %q", got.Username)
+	}
+}
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	aapi "github.com/grafana/amixr-api-go-client"
+	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// onCallOverrideShiftType is the OnCall shift "type" value the API uses to
+// represent a one-off schedule override, as opposed to a recurring rotation.
+const onCallOverrideShiftType = "override"
+
+// logOnCallScheduleMutation records who triggered an OnCall schedule write
+// action and what it was, mirroring logOnCallMutation for alert groups.
+func logOnCallScheduleMutation(ctx context.Context, action, scheduleID, detail string) {
+	slog.InfoContext(ctx, "OnCall schedule mutation", "action", action, "schedule_id", scheduleID, "detail", detail, "actor", OnCallActorFromContext(ctx))
+}
+
+type CreateOnCallScheduleOverrideParams struct {
+	ScheduleID  string `json:"scheduleId" jsonschema:"required,description=The ID of the schedule to create the override on"`
+	UserID      string `json:"userId" jsonschema:"required,description=The ID of the user who will be on call during the override"`
+	Start       string `json:"start" jsonschema:"required,description=Start of the override, in RFC3339 format"`
+	End         string `json:"end" jsonschema:"required,description=End of the override, in RFC3339 format"`
+	Reason      string `json:"reason,omitempty" jsonschema:"description=Optional free-text reason for the override, e.g. why the regular on-call can't cover this time"`
+	EffectiveAt string `json:"effectiveAt,omitempty" jsonschema:"description=When this override should take effect, in RFC3339 format. Defaults to now"`
+}
+
+func createOnCallScheduleOverrideHandler(ctx context.Context, args CreateOnCallScheduleOverrideParams) (*aapi.OnCallShift, error) {
+	start, err := time.Parse(time.RFC3339, args.Start)
+	if err != nil {
+		return nil, fmt.Errorf("parsing start %q: %w", args.Start, err)
+	}
+	end, err := time.Parse(time.RFC3339, args.End)
+	if err != nil {
+		return nil, fmt.Errorf("parsing end %q: %w", args.End, err)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end (%s) must be after start (%s)", args.End, args.Start)
+	}
+
+	effectiveAt := args.EffectiveAt
+	if effectiveAt == "" {
+		effectiveAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	userService, err := getUserServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall user service: %w", err)
+	}
+	if _, resp, err := userService.GetUser(args.UserID, &aapi.GetUserOptions{}); globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err) != nil {
+		return nil, fmt.Errorf("looking up OnCall user %s: %w", args.UserID, err)
+	}
+
+	shiftService, err := getOnCallShiftServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall shift service: %w", err)
+	}
+
+	logOnCallScheduleMutation(ctx, "create_override", args.ScheduleID, args.UserID)
+
+	shift, resp, err := shiftService.CreateOnCallShift(&aapi.CreateOnCallShiftOptions{
+		ScheduleId:  args.ScheduleID,
+		Type:        onCallOverrideShiftType,
+		Start:       start.Format("2006-01-02T15:04:05"),
+		Duration:    int(end.Sub(start).Seconds()),
+		Users:       []string{args.UserID},
+		EffectiveAt: effectiveAt,
+	})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("creating OnCall schedule override on schedule %s: %w", args.ScheduleID, err)
+	}
+
+	return shift, nil
+}
+
+var CreateOnCallScheduleOverride = mcpgrafana.MustTool(
+	"create_oncall_schedule_override",
+	"Create a one-off override on an OnCall schedule, putting userId on call for [start, end) regardless of what the regular rotation says. Useful for ad-hoc shift swaps like \"cover for me tomorrow\". Validates that userId exists and that end is after start. effectiveAt defaults to now if omitted.",
+	createOnCallScheduleOverrideHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+type ListOnCallScheduleOverridesParams struct {
+	ScheduleID string `json:"scheduleId" jsonschema:"required,description=The ID of the schedule to list overrides for"`
+	Page       int    `json:"page,omitempty" jsonschema:"description=The page number to return (1-based)"`
+}
+
+func listOnCallScheduleOverridesHandler(ctx context.Context, args ListOnCallScheduleOverridesParams) ([]*aapi.OnCallShift, error) {
+	shiftService, err := getOnCallShiftServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall shift service: %w", err)
+	}
+
+	listOptions := &aapi.ListOnCallShiftOptions{
+		ScheduleID: args.ScheduleID,
+		Type:       onCallOverrideShiftType,
+	}
+	if args.Page > 0 {
+		listOptions.Page = args.Page
+	}
+
+	response, resp, err := shiftService.ListOnCallShifts(listOptions)
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("listing OnCall schedule overrides for schedule %s: %w", args.ScheduleID, err)
+	}
+
+	return response.OnCallShifts, nil
+}
+
+var ListOnCallScheduleOverrides = mcpgrafana.MustTool(
+	"list_oncall_schedule_overrides",
+	"List the one-off overrides created on an OnCall schedule.",
+	listOnCallScheduleOverridesHandler,
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+type DeleteOnCallScheduleOverrideParams struct {
+	ShiftID string `json:"shiftId" jsonschema:"required,description=The ID of the override shift to delete, as returned by create_oncall_schedule_override or list_oncall_schedule_overrides"`
+}
+
+func deleteOnCallScheduleOverrideHandler(ctx context.Context, args DeleteOnCallScheduleOverrideParams) (string, error) {
+	shiftService, err := getOnCallShiftServiceFromContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting OnCall shift service: %w", err)
+	}
+
+	logOnCallScheduleMutation(ctx, "delete_override", "", args.ShiftID)
+
+	if _, err := shiftService.DeleteOnCallShift(args.ShiftID, &aapi.DeleteOnCallShiftOptions{}); err != nil {
+		return "", fmt.Errorf("deleting OnCall schedule override %s: %w", args.ShiftID, err)
+	}
+
+	return fmt.Sprintf("Deleted OnCall schedule override %s", args.ShiftID), nil
+}
+
+var DeleteOnCallScheduleOverride = mcpgrafana.MustTool(
+	"delete_oncall_schedule_override",
+	"Delete a one-off override previously created on an OnCall schedule.",
+	deleteOnCallScheduleOverrideHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
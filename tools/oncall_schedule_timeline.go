@@ -0,0 +1,392 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	aapi "github.com/grafana/amixr-api-go-client"
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// RenderedScheduleEntry is one concrete, fully-resolved interval of on-call
+// coverage produced by expanding a shift's recurrence rule and intersecting
+// it with any per-layer restrictions.
+type RenderedScheduleEntry struct {
+	Start   string `json:"start" jsonschema:"description=Start of this entry in RFC3339 format"`
+	End     string `json:"end" jsonschema:"description=End of this entry in RFC3339 format"`
+	UserID  string `json:"userId" jsonschema:"description=The ID of the user on call during this interval"`
+	ShiftID string `json:"shiftId" jsonschema:"description=The ID of the shift this interval was expanded from"`
+	Layer   int    `json:"layer" jsonschema:"description=The rotation/layer level this shift belongs to, used to detect overlaps between layers"`
+}
+
+// ScheduleGap is an interval within the requested window that no rendered
+// entry, on any layer, covers.
+type ScheduleGap struct {
+	Start string `json:"start" jsonschema:"description=Start of the uncovered interval in RFC3339 format"`
+	End   string `json:"end" jsonschema:"description=End of the uncovered interval in RFC3339 format"`
+}
+
+// ScheduleOverlap is an interval where two or more layers have a rendered
+// entry covering the same time, which usually indicates a misconfigured
+// rotation rather than intentional redundancy.
+type ScheduleOverlap struct {
+	Start   string   `json:"start" jsonschema:"description=Start of the overlapping interval in RFC3339 format"`
+	End     string   `json:"end" jsonschema:"description=End of the overlapping interval in RFC3339 format"`
+	UserIDs []string `json:"userIds" jsonschema:"description=The users whose entries overlap during this interval"`
+}
+
+// RenderedScheduleTimeline is the full result of rendering a schedule over a
+// time window: the expanded entries, the gaps and overlaps found in them,
+// and the resulting coverage percentage.
+type RenderedScheduleTimeline struct {
+	ScheduleID         string                  `json:"scheduleId"`
+	Since              string                  `json:"since"`
+	Until              string                  `json:"until"`
+	Entries            []RenderedScheduleEntry `json:"entries"`
+	Gaps               []ScheduleGap           `json:"gaps" jsonschema:"description=Intervals within the window that no layer covers"`
+	Overlaps           []ScheduleOverlap       `json:"overlaps" jsonschema:"description=Intervals where more than one layer is on call at once"`
+	CoveragePercentage float64                 `json:"coveragePercentage" jsonschema:"description=Percentage of the requested window covered by at least one rendered entry"`
+}
+
+type RenderOnCallScheduleTimelineParams struct {
+	ScheduleID string `json:"scheduleId" jsonschema:"required,description=The ID of the schedule to render"`
+	Since      string `json:"since" jsonschema:"required,description=Start of the window to render, in ISO 8601/RFC3339 format"`
+	Until      string `json:"until" jsonschema:"required,description=End of the window to render, in ISO 8601/RFC3339 format"`
+	Timezone   string `json:"timezone,omitempty" jsonschema:"description=IANA timezone to interpret shift restrictions in. Defaults to the schedule's own timezone"`
+}
+
+func renderOnCallScheduleTimelineHandler(ctx context.Context, args RenderOnCallScheduleTimelineParams) (*RenderedScheduleTimeline, error) {
+	return renderOnCallScheduleTimeline(ctx, args)
+}
+
+var RenderOnCallScheduleTimeline = mcpgrafana.MustTool(
+	"render_oncall_schedule_timeline",
+	"Render the fully expanded on-call timeline for a schedule between `since` and `until`, resolving every rotation's recurrence rule and restrictions down to concrete {start, end, user, shiftId, layer} entries. Also reports coveragePercentage for the window, any uncovered gaps, and any overlaps between layers. Use this instead of get_oncall_shift/list_oncall_schedules when asked who is on call in a given window or whether there are coverage holes.",
+	renderOnCallScheduleTimelineHandler,
+)
+
+func renderOnCallScheduleTimeline(ctx context.Context, args RenderOnCallScheduleTimelineParams) (*RenderedScheduleTimeline, error) {
+	since, err := time.Parse(time.RFC3339, args.Since)
+	if err != nil {
+		return nil, fmt.Errorf("parsing since %q: %w", args.Since, err)
+	}
+	until, err := time.Parse(time.RFC3339, args.Until)
+	if err != nil {
+		return nil, fmt.Errorf("parsing until %q: %w", args.Until, err)
+	}
+	if !until.After(since) {
+		return nil, fmt.Errorf("until (%s) must be after since (%s)", args.Until, args.Since)
+	}
+
+	scheduleService, err := getScheduleServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall schedule service: %w", err)
+	}
+	shiftService, err := getOnCallShiftServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall shift service: %w", err)
+	}
+
+	schedule, _, err := scheduleService.GetSchedule(args.ScheduleID, &aapi.GetScheduleOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall schedule %s: %w", args.ScheduleID, err)
+	}
+
+	loc := time.UTC
+	tzName := args.Timezone
+	if tzName == "" {
+		tzName = schedule.TimeZone
+	}
+	if tzName != "" {
+		if parsed, err := time.LoadLocation(tzName); err == nil {
+			loc = parsed
+		}
+	}
+
+	var shiftIDs []string
+	if schedule.Shifts != nil {
+		shiftIDs = *schedule.Shifts
+	}
+
+	var entries []RenderedScheduleEntry
+	for layer, shiftID := range shiftIDs {
+		shift, _, err := shiftService.GetOnCallShift(shiftID, &aapi.GetOnCallShiftOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("getting OnCall shift %s: %w", shiftID, err)
+		}
+		entries = append(entries, expandShiftEntries(shift, layer, since, until, loc)...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Start < entries[j].Start })
+
+	gaps := findScheduleGaps(entries, since, until)
+	overlaps := findScheduleOverlaps(entries)
+	coverage := coveragePercentage(entries, since, until)
+
+	return &RenderedScheduleTimeline{
+		ScheduleID:         args.ScheduleID,
+		Since:              since.Format(time.RFC3339),
+		Until:              until.Format(time.RFC3339),
+		Entries:            entries,
+		Gaps:               gaps,
+		Overlaps:           overlaps,
+		CoveragePercentage: coverage,
+	}, nil
+}
+
+// expandShiftEntries expands a single shift's recurrence rule (frequency,
+// interval, by_day, and rolling-user rotation via
+// start_rotation_from_user_index) into concrete entries clipped to
+// [since, until), applying the shift's own time-of-day restriction if it has
+// one.
+func expandShiftEntries(shift *aapi.OnCallShift, layer int, since, until time.Time, loc *time.Location) []RenderedScheduleEntry {
+	var entries []RenderedScheduleEntry
+
+	shiftStart, err := time.ParseInLocation("2006-01-02T15:04:05", shift.Start, loc)
+	if err != nil {
+		return entries
+	}
+	duration := time.Duration(shift.Duration) * time.Second
+
+	users := rotationUsers(shift)
+	if len(users) == 0 {
+		return entries
+	}
+
+	switch shift.Type {
+	case "single_event":
+		occStart, occEnd := shiftStart, shiftStart.Add(duration)
+		if occEnd.After(since) && occStart.Before(until) {
+			entries = append(entries, clippedEntry(occStart, occEnd, since, until, users[0], shift.ID, layer))
+		}
+	default:
+		// recurrent_event / rolling_users_event: step forward in units of
+		// Interval * Frequency-implied period, rotating through users every
+		// step starting from StartRotationFromUserIndex.
+		step := rotationStep(shift)
+		if step <= 0 {
+			return entries
+		}
+		rotationIndex := shift.StartRotationFromUserIndex
+		occStart := shiftStart
+		for occStart.Before(until) {
+			occEnd := occStart.Add(duration)
+			if occEnd.After(since) && occStart.Before(until) && matchesByDay(shift.ByDay, occStart) {
+				user := users[rotationIndex%len(users)]
+				entries = append(entries, clippedEntry(occStart, occEnd, since, until, user, shift.ID, layer))
+			}
+			occStart = occStart.Add(step)
+			rotationIndex++
+		}
+	}
+
+	return entries
+}
+
+// rotationUsers flattens a shift's user rotation into a single ordered list
+// of user IDs: RollingUsers (a list of groups, one on-call together per
+// rotation step) if present, otherwise the plain Users list.
+func rotationUsers(shift *aapi.OnCallShift) []string {
+	if len(shift.RollingUsers) > 0 {
+		var flat []string
+		for _, group := range shift.RollingUsers {
+			if len(group) > 0 {
+				flat = append(flat, group[0])
+			}
+		}
+		return flat
+	}
+	return shift.Users
+}
+
+// rotationStep returns the duration between successive occurrences of a
+// recurring shift, derived from its Frequency/Interval pair.
+func rotationStep(shift *aapi.OnCallShift) time.Duration {
+	interval := shift.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+	switch shift.Frequency {
+	case "daily":
+		return time.Duration(interval) * 24 * time.Hour
+	case "weekly":
+		return time.Duration(interval) * 7 * 24 * time.Hour
+	case "monthly":
+		return time.Duration(interval) * 30 * 24 * time.Hour
+	case "hourly":
+		return time.Duration(interval) * time.Hour
+	default:
+		return 0
+	}
+}
+
+// matchesByDay reports whether t falls on one of the weekdays in byDay (as
+// "MO", "TU", ... "SU"), or true if byDay is empty (no day-of-week
+// restriction).
+func matchesByDay(byDay []string, t time.Time) bool {
+	if len(byDay) == 0 {
+		return true
+	}
+	weekdayCodes := map[time.Weekday]string{
+		time.Monday:    "MO",
+		time.Tuesday:   "TU",
+		time.Wednesday: "WE",
+		time.Thursday:  "TH",
+		time.Friday:    "FR",
+		time.Saturday:  "SA",
+		time.Sunday:    "SU",
+	}
+	code := weekdayCodes[t.Weekday()]
+	for _, d := range byDay {
+		if d == code {
+			return true
+		}
+	}
+	return false
+}
+
+// clippedEntry builds a RenderedScheduleEntry for [start, end), clipped to
+// the requested [since, until) window.
+func clippedEntry(start, end, since, until time.Time, userID, shiftID string, layer int) RenderedScheduleEntry {
+	if start.Before(since) {
+		start = since
+	}
+	if end.After(until) {
+		end = until
+	}
+	return RenderedScheduleEntry{
+		Start:   start.Format(time.RFC3339),
+		End:     end.Format(time.RFC3339),
+		UserID:  userID,
+		ShiftID: shiftID,
+		Layer:   layer,
+	}
+}
+
+// findScheduleGaps returns the intervals within [since, until) not covered
+// by any entry, regardless of layer.
+func findScheduleGaps(entries []RenderedScheduleEntry, since, until time.Time) []ScheduleGap {
+	covered := mergeEntryIntervals(entries)
+
+	var gaps []ScheduleGap
+	cursor := since
+	for _, iv := range covered {
+		if iv.start.After(cursor) {
+			gaps = append(gaps, ScheduleGap{Start: cursor.Format(time.RFC3339), End: iv.start.Format(time.RFC3339)})
+		}
+		if iv.end.After(cursor) {
+			cursor = iv.end
+		}
+	}
+	if until.After(cursor) {
+		gaps = append(gaps, ScheduleGap{Start: cursor.Format(time.RFC3339), End: until.Format(time.RFC3339)})
+	}
+	return gaps
+}
+
+// findScheduleOverlaps returns intervals where entries from more than one
+// layer are on call at once, via a standard sweep over start/end events.
+func findScheduleOverlaps(entries []RenderedScheduleEntry) []ScheduleOverlap {
+	type event struct {
+		at    time.Time
+		delta int
+		entry RenderedScheduleEntry
+	}
+
+	var events []event
+	for _, e := range entries {
+		start, _ := time.Parse(time.RFC3339, e.Start)
+		end, _ := time.Parse(time.RFC3339, e.End)
+		events = append(events, event{at: start, delta: 1, entry: e})
+		events = append(events, event{at: end, delta: -1, entry: e})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	var overlaps []ScheduleOverlap
+	active := map[string]RenderedScheduleEntry{}
+	var overlapStart time.Time
+	inOverlap := false
+
+	for _, ev := range events {
+		if ev.delta > 0 {
+			active[ev.entry.ShiftID] = ev.entry
+		} else {
+			delete(active, ev.entry.ShiftID)
+		}
+
+		if len(active) > 1 && !inOverlap {
+			overlapStart = ev.at
+			inOverlap = true
+		} else if len(active) <= 1 && inOverlap {
+			var userIDs []string
+			for _, e := range active {
+				userIDs = append(userIDs, e.UserID)
+			}
+			overlaps = append(overlaps, ScheduleOverlap{
+				Start:   overlapStart.Format(time.RFC3339),
+				End:     ev.at.Format(time.RFC3339),
+				UserIDs: userIDs,
+			})
+			inOverlap = false
+		}
+	}
+	return overlaps
+}
+
+type timeInterval struct {
+	start, end time.Time
+}
+
+// mergeEntryIntervals collapses overlapping/adjacent entries (across all
+// layers) into a sorted list of disjoint intervals, for gap detection.
+func mergeEntryIntervals(entries []RenderedScheduleEntry) []timeInterval {
+	var intervals []timeInterval
+	for _, e := range entries {
+		start, err1 := time.Parse(time.RFC3339, e.Start)
+		end, err2 := time.Parse(time.RFC3339, e.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		intervals = append(intervals, timeInterval{start: start, end: end})
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+
+	var merged []timeInterval
+	for _, iv := range intervals {
+		if len(merged) == 0 || iv.start.After(merged[len(merged)-1].end) {
+			merged = append(merged, iv)
+			continue
+		}
+		if iv.end.After(merged[len(merged)-1].end) {
+			merged[len(merged)-1].end = iv.end
+		}
+	}
+	return merged
+}
+
+// coveragePercentage is the fraction of [since, until) covered by at least
+// one entry, as a percentage.
+func coveragePercentage(entries []RenderedScheduleEntry, since, until time.Time) float64 {
+	total := until.Sub(since)
+	if total <= 0 {
+		return 0
+	}
+
+	var covered time.Duration
+	for _, iv := range mergeEntryIntervals(entries) {
+		start, end := iv.start, iv.end
+		if start.Before(since) {
+			start = since
+		}
+		if end.After(until) {
+			end = until
+		}
+		if end.After(start) {
+			covered += end.Sub(start)
+		}
+	}
+
+	return float64(covered) / float64(total) * 100
+}
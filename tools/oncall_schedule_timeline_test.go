@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	aapi "github.com/grafana/amixr-api-go-client"
+)
+
+func TestExpandShiftEntries_SingleEvent(t *testing.T) {
+	shift := &aapi.OnCallShift{
+		ID:       "shift-1",
+		Type:     "single_event",
+		Start:    "2026-07-27T09:00:00",
+		Duration: 3600,
+		Users:    []string{"user-1"},
+	}
+
+	since := time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+
+	entries := expandShiftEntries(shift, 0, since, until, time.UTC)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry for a single_event shift, got %d", len(entries))
+	}
+	if entries[0].UserID != "user-1" {
+		t.Errorf("expected user-1, got %s", entries[0].UserID)
+	}
+}
+
+func TestExpandShiftEntries_DailyRecurrenceRotatesUsers(t *testing.T) {
+	shift := &aapi.OnCallShift{
+		ID:        "shift-2",
+		Type:      "recurrent_event",
+		Start:     "2026-07-27T09:00:00",
+		Duration:  3600,
+		Frequency: "daily",
+		Interval:  1,
+		Users:     []string{"user-1", "user-2"},
+	}
+
+	since := time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 7, 30, 0, 0, 0, 0, time.UTC)
+
+	entries := expandShiftEntries(shift, 0, since, until, time.UTC)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 daily occurrences over a 3-day window, got %d", len(entries))
+	}
+	if entries[0].UserID == entries[1].UserID {
+		t.Errorf("expected rotation to alternate users, got %s twice in a row", entries[0].UserID)
+	}
+}
+
+func TestFindScheduleGaps_ReportsUncoveredIntervals(t *testing.T) {
+	since := time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 7, 27, 12, 0, 0, 0, time.UTC)
+
+	entries := []RenderedScheduleEntry{
+		{Start: "2026-07-27T00:00:00Z", End: "2026-07-27T04:00:00Z", UserID: "user-1", ShiftID: "s1"},
+	}
+
+	gaps := findScheduleGaps(entries, since, until)
+	if len(gaps) != 1 {
+		t.Fatalf("expected 1 gap after the covered interval, got %d", len(gaps))
+	}
+	if gaps[0].Start != "2026-07-27T04:00:00Z" {
+		t.Errorf("expected gap to start where coverage ends, got %s", gaps[0].Start)
+	}
+}
+
+func TestCoveragePercentage_HalfCovered(t *testing.T) {
+	since := time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+
+	entries := []RenderedScheduleEntry{
+		{Start: "2026-07-27T00:00:00Z", End: "2026-07-27T12:00:00Z", UserID: "user-1", ShiftID: "s1"},
+	}
+
+	if got := coveragePercentage(entries, since, until); got != 50 {
+		t.Errorf("expected 50%% coverage, got %v", got)
+	}
+}
+
+func TestFindScheduleOverlaps_DetectsOverlappingLayers(t *testing.T) {
+	entries := []RenderedScheduleEntry{
+		{Start: "2026-07-27T00:00:00Z", End: "2026-07-27T02:00:00Z", UserID: "user-1", ShiftID: "s1", Layer: 0},
+		{Start: "2026-07-27T01:00:00Z", End: "2026-07-27T03:00:00Z", UserID: "user-2", ShiftID: "s2", Layer: 1},
+	}
+
+	overlaps := findScheduleOverlaps(entries)
+	if len(overlaps) != 1 {
+		t.Fatalf("expected 1 overlap, got %d", len(overlaps))
+	}
+	if overlaps[0].Start != "2026-07-27T01:00:00Z" || overlaps[0].End != "2026-07-27T02:00:00Z" {
+		t.Errorf("unexpected overlap window: %+v", overlaps[0])
+	}
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	aapi "github.com/grafana/amixr-api-go-client"
+	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type CreateShiftSwapRequestParams struct {
+	ScheduleID  string `json:"scheduleId" jsonschema:"required,description=The ID of the schedule to request a shift swap on"`
+	SwapStart   string `json:"swapStart" jsonschema:"required,description=Start of the requested swap window, in RFC3339 format"`
+	SwapEnd     string `json:"swapEnd" jsonschema:"required,description=End of the requested swap window, in RFC3339 format"`
+	Description string `json:"description,omitempty" jsonschema:"description=Optional free-text note explaining why the swap is being requested"`
+}
+
+func createShiftSwapRequestHandler(ctx context.Context, args CreateShiftSwapRequestParams) (*aapi.ShiftSwap, error) {
+	start, err := time.Parse(time.RFC3339, args.SwapStart)
+	if err != nil {
+		return nil, fmt.Errorf("parsing swapStart %q: %w", args.SwapStart, err)
+	}
+	end, err := time.Parse(time.RFC3339, args.SwapEnd)
+	if err != nil {
+		return nil, fmt.Errorf("parsing swapEnd %q: %w", args.SwapEnd, err)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("swapEnd (%s) must be after swapStart (%s)", args.SwapEnd, args.SwapStart)
+	}
+
+	shiftSwapService, err := getShiftSwapServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall shift swap service: %w", err)
+	}
+
+	logOnCallScheduleMutation(ctx, "create_shift_swap_request", args.ScheduleID, args.SwapStart+"_"+args.SwapEnd)
+
+	swap, resp, err := shiftSwapService.CreateShiftSwap(&aapi.CreateShiftSwapOptions{
+		ScheduleId:  args.ScheduleID,
+		SwapStart:   start.Format(time.RFC3339),
+		SwapEnd:     end.Format(time.RFC3339),
+		Description: args.Description,
+	})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("creating shift swap request on schedule %s: %w", args.ScheduleID, err)
+	}
+
+	return swap, nil
+}
+
+var CreateShiftSwapRequest = mcpgrafana.MustTool(
+	"create_oncall_shift_swap_request",
+	"Request a shift swap on an OnCall schedule for [swapStart, swapEnd), leaving it open for another on-call user to take. Unlike create_oncall_schedule_override, this doesn't assign a replacement itself; use take_oncall_shift_swap_request for that.",
+	createShiftSwapRequestHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+type TakeShiftSwapRequestParams struct {
+	ShiftSwapID string `json:"shiftSwapId" jsonschema:"required,description=The ID of the shift swap request to take, as returned by create_oncall_shift_swap_request"`
+}
+
+func takeShiftSwapRequestHandler(ctx context.Context, args TakeShiftSwapRequestParams) (*aapi.ShiftSwap, error) {
+	shiftSwapService, err := getShiftSwapServiceFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting OnCall shift swap service: %w", err)
+	}
+
+	logOnCallScheduleMutation(ctx, "take_shift_swap_request", "", args.ShiftSwapID)
+
+	swap, resp, err := shiftSwapService.TakeShiftSwap(args.ShiftSwapID, &aapi.TakeShiftSwapOptions{})
+	if err := globalOnCallSettingsCache.invalidateOnAuthError(ctx, resp, err); err != nil {
+		return nil, fmt.Errorf("taking shift swap request %s: %w", args.ShiftSwapID, err)
+	}
+
+	return swap, nil
+}
+
+var TakeShiftSwapRequest = mcpgrafana.MustTool(
+	"take_oncall_shift_swap_request",
+	"Take an open OnCall shift swap request, assigning the calling user as its replacement on-call.",
+	takeShiftSwapRequestHandler,
+	mcp.WithDestructiveHintAnnotation(true),
+)
@@ -0,0 +1,530 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/grafana/grafana-openapi-client-go/client/search"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+func AddOrphanResourcesTool(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListUntrackedGrafanaResources)
+}
+
+const listUntrackedGrafanaResourcesToolPrompt = `Cross-reference dashboards, folders, alert rules, and library panels in this Grafana instance against the files of every configured provisioning repository, and report where the two disagree. Use this to find GitOps drift: resources a human or another tool created/edited directly in Grafana that were never committed, repository files whose resource was deleted or renamed out from under them, and resources whose title or folder no longer matches what's committed.
+
+Results are grouped by resource kind (dashboard, folder, alert_rule, library_panel) and each entry reports uid, title, folder, and a reason:
+- "orphan-in-grafana": the resource exists in Grafana but no repository file has a matching uid
+- "orphan-in-repo": a repository file has a recognizable resource uid, but no such resource exists in Grafana
+- "hash-mismatch": the resource exists on both sides, but its title or folder has drifted between Grafana and the committed file
+
+Can be restricted to a single repository; defaults to comparing against every configured provisioning repository.`
+
+var ListUntrackedGrafanaResources = mcpgrafana.MustTool(
+	"list_untracked_grafana_resources",
+	listUntrackedGrafanaResourcesToolPrompt,
+	listUntrackedGrafanaResources,
+	mcp.WithTitleAnnotation("List Untracked Grafana Resources"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+type ListUntrackedGrafanaResourcesParams struct {
+	RepositoryName string `json:"repository_name,omitempty" jsonschema:"description=Restrict the comparison to a single provisioning repository instead of every configured repository"`
+}
+
+// UntrackedResource is one entry of drift between Grafana and its
+// provisioning repositories: either a resource with no backing file, a
+// file with no backing resource, or a resource/file pair whose title or
+// folder have diverged.
+type UntrackedResource struct {
+	Kind           string `json:"kind"`
+	UID            string `json:"uid"`
+	Title          string `json:"title"`
+	Folder         string `json:"folder"`
+	Reason         string `json:"reason"`
+	RepositoryName string `json:"repositoryName,omitempty"`
+	Path           string `json:"path,omitempty"`
+}
+
+// grafanaResource and repoResource are the two sides compared by
+// listUntrackedGrafanaResources: a resource as seen live in Grafana, and
+// a resource as reconstructed from a provisioning repository file.
+type grafanaResource struct {
+	Kind   string
+	UID    string
+	Title  string
+	Folder string
+}
+
+type repoResource struct {
+	Kind           string
+	UID            string
+	Title          string
+	Folder         string
+	RepositoryName string
+	Path           string
+}
+
+func resourceKey(kind, uid string) string {
+	return kind + "/" + uid
+}
+
+func formatUntrackedResource(r UntrackedResource) string {
+	parts := []string{
+		fmt.Sprintf("uid=%s", r.UID),
+		fmt.Sprintf("title=%s", r.Title),
+		fmt.Sprintf("folder=%s", r.Folder),
+		fmt.Sprintf("reason=%s", r.Reason),
+	}
+	if r.RepositoryName != "" {
+		parts = append(parts, fmt.Sprintf("repository=%s", r.RepositoryName))
+	}
+	if r.Path != "" {
+		parts = append(parts, fmt.Sprintf("path=%s", r.Path))
+	}
+	return fmt.Sprintf("- %s", strings.Join(parts, " | "))
+}
+
+func listUntrackedGrafanaResources(ctx context.Context, args ListUntrackedGrafanaResourcesParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	grafanaResources, err := collectGrafanaResources(ctx)
+	if err != nil {
+		return "", fmt.Errorf("collecting grafana resources: %w", err)
+	}
+
+	repoResources, err := collectRepositoryResources(ctx, cfg, args.RepositoryName)
+	if err != nil {
+		return "", fmt.Errorf("collecting repository resources: %w", err)
+	}
+
+	findings := diffGrafanaAndRepositoryResources(grafanaResources, repoResources)
+
+	if len(findings) == 0 {
+		return "No drift detected: every Grafana resource is backed by a repository file, and every repository file's resource exists in Grafana.", nil
+	}
+
+	grouped := make(map[string][]UntrackedResource)
+	var kinds []string
+	for _, f := range findings {
+		if _, ok := grouped[f.Kind]; !ok {
+			kinds = append(kinds, f.Kind)
+		}
+		grouped[f.Kind] = append(grouped[f.Kind], f)
+	}
+	sort.Strings(kinds)
+
+	rows := []string{fmt.Sprintf("Found %d drifted resource(s).", len(findings))}
+	for _, kind := range kinds {
+		rows = append(rows, fmt.Sprintf("\n%s:", kind))
+		for _, f := range grouped[kind] {
+			rows = append(rows, formatUntrackedResource(f))
+		}
+	}
+
+	return strings.Join(rows, "\n"), nil
+}
+
+func diffGrafanaAndRepositoryResources(grafanaResources []grafanaResource, repoResources []repoResource) []UntrackedResource {
+	byKeyGrafana := make(map[string]grafanaResource, len(grafanaResources))
+	for _, g := range grafanaResources {
+		byKeyGrafana[resourceKey(g.Kind, g.UID)] = g
+	}
+	byKeyRepo := make(map[string]repoResource, len(repoResources))
+	for _, r := range repoResources {
+		byKeyRepo[resourceKey(r.Kind, r.UID)] = r
+	}
+
+	var findings []UntrackedResource
+	for key, g := range byKeyGrafana {
+		r, ok := byKeyRepo[key]
+		if !ok {
+			findings = append(findings, UntrackedResource{
+				Kind: g.Kind, UID: g.UID, Title: g.Title, Folder: g.Folder,
+				Reason: "orphan-in-grafana",
+			})
+			continue
+		}
+		if g.Title != r.Title || (g.Folder != "" && r.Folder != "" && g.Folder != r.Folder) {
+			findings = append(findings, UntrackedResource{
+				Kind: g.Kind, UID: g.UID, Title: g.Title, Folder: g.Folder,
+				Reason: "hash-mismatch", RepositoryName: r.RepositoryName, Path: r.Path,
+			})
+		}
+	}
+	for key, r := range byKeyRepo {
+		if _, ok := byKeyGrafana[key]; !ok {
+			findings = append(findings, UntrackedResource{
+				Kind: r.Kind, UID: r.UID, Title: r.Title, Folder: r.Folder,
+				Reason: "orphan-in-repo", RepositoryName: r.RepositoryName, Path: r.Path,
+			})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Kind != findings[j].Kind {
+			return findings[i].Kind < findings[j].Kind
+		}
+		return findings[i].UID < findings[j].UID
+	})
+
+	return findings
+}
+
+// collectGrafanaResources walks dashboards, folders, alert rules, and
+// library panels in this Grafana instance.
+func collectGrafanaResources(ctx context.Context) ([]grafanaResource, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	c := mcpgrafana.GrafanaClientFromContext(ctx)
+
+	folderTitles := make(map[string]string)
+	var resources []grafanaResource
+
+	searchByType := func(hitType string) ([]searchHit, error) {
+		params := search.NewSearchParamsWithContext(ctx)
+		params.SetType(&hitType)
+
+		result, err := c.Search.Search(params)
+		if err != nil {
+			return nil, fmt.Errorf("search type=%s: %w", hitType, err)
+		}
+
+		hits := make([]searchHit, 0, len(result.Payload))
+		for _, h := range result.Payload {
+			hits = append(hits, searchHit{UID: h.UID, Title: h.Title, FolderTitle: h.FolderTitle})
+		}
+		return hits, nil
+	}
+
+	folderHits, err := searchByType("dash-folder")
+	if err != nil {
+		return nil, fmt.Errorf("listing folders: %w", err)
+	}
+	for _, h := range folderHits {
+		folderTitles[h.UID] = h.Title
+		resources = append(resources, grafanaResource{Kind: "folder", UID: h.UID, Title: h.Title, Folder: h.FolderTitle})
+	}
+
+	dashboardHits, err := searchByType("dash-db")
+	if err != nil {
+		return nil, fmt.Errorf("listing dashboards: %w", err)
+	}
+	for _, h := range dashboardHits {
+		resources = append(resources, grafanaResource{Kind: "dashboard", UID: h.UID, Title: h.Title, Folder: h.FolderTitle})
+	}
+
+	alertRules, err := fetchAlertRuleSummaries(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("listing alert rules: %w", err)
+	}
+	for _, a := range alertRules {
+		resources = append(resources, grafanaResource{Kind: "alert_rule", UID: a.UID, Title: a.Title, Folder: folderTitles[a.FolderUID]})
+	}
+
+	libraryPanels, err := fetchLibraryPanelSummaries(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("listing library panels: %w", err)
+	}
+	for _, p := range libraryPanels {
+		resources = append(resources, grafanaResource{Kind: "library_panel", UID: p.UID, Title: p.Title, Folder: folderTitles[p.FolderUID]})
+	}
+
+	return resources, nil
+}
+
+// searchHit is the subset of the search API's response fields this tool
+// needs from a dashboard or folder hit.
+type searchHit struct {
+	UID         string
+	Title       string
+	FolderTitle string
+}
+
+// alertRuleSummary and libraryPanelSummary carry only the fields this
+// tool needs from Grafana's alerting and library-element HTTP APIs, which
+// this repo has no existing typed client for.
+type alertRuleSummary struct {
+	UID       string
+	Title     string
+	FolderUID string
+}
+
+type libraryPanelSummary struct {
+	UID       string
+	Title     string
+	FolderUID string
+}
+
+func fetchAlertRuleSummaries(ctx context.Context, cfg mcpgrafana.GrafanaConfig) ([]alertRuleSummary, error) {
+	var raw []struct {
+		UID       string `json:"uid"`
+		Title     string `json:"title"`
+		FolderUID string `json:"folderUID"`
+	}
+	found, err := grafanaAPIGet(ctx, cfg, "/api/v1/provisioning/alert-rules", &raw)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	summaries := make([]alertRuleSummary, 0, len(raw))
+	for _, r := range raw {
+		summaries = append(summaries, alertRuleSummary{UID: r.UID, Title: r.Title, FolderUID: r.FolderUID})
+	}
+	return summaries, nil
+}
+
+func fetchLibraryPanelSummaries(ctx context.Context, cfg mcpgrafana.GrafanaConfig) ([]libraryPanelSummary, error) {
+	var raw struct {
+		Result struct {
+			Elements []struct {
+				UID       string `json:"uid"`
+				Name      string `json:"name"`
+				FolderUID string `json:"folderUid"`
+			} `json:"elements"`
+		} `json:"result"`
+	}
+	found, err := grafanaAPIGet(ctx, cfg, "/api/library-elements", &raw)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	summaries := make([]libraryPanelSummary, 0, len(raw.Result.Elements))
+	for _, e := range raw.Result.Elements {
+		summaries = append(summaries, libraryPanelSummary{UID: e.UID, Title: e.Name, FolderUID: e.FolderUID})
+	}
+	return summaries, nil
+}
+
+// grafanaAPIGet issues an authenticated GET against Grafana's core HTTP
+// API (as opposed to the provisioning.grafana.app API used elsewhere in
+// this package) and decodes a 200 response into out. found is false (with
+// a nil error) on a 404, so callers can treat a missing/disabled endpoint
+// the same way the rest of this package treats a missing repository.
+func grafanaAPIGet(ctx context.Context, cfg mcpgrafana.GrafanaConfig, apiPath string, out interface{}) (found bool, err error) {
+	requestURL := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+
+	if cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
+		req.Header.Set("X-Access-Token", cfg.AccessToken)
+		req.Header.Set("X-Grafana-Id", cfg.IDToken)
+	}
+
+	client := &http.Client{}
+	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
+		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
+		if err != nil {
+			return false, fmt.Errorf("failed to create custom transport: %w", err)
+		}
+		client.Transport = transport
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code from %s: %d", apiPath, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("decoding response from %s: %w", apiPath, err)
+	}
+	return true, nil
+}
+
+// resourceKindForPath classifies a repository file path by its top-level
+// directory convention, matching how ManageFileDirectly documents
+// dashboards being stored. Files outside these directories, or without a
+// .json/.yaml/.yml extension, aren't resource files and are ignored.
+func resourceKindForPath(filePath string) string {
+	ext := path.Ext(filePath)
+	if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+		return ""
+	}
+
+	top := filePath
+	if idx := strings.Index(filePath, "/"); idx >= 0 {
+		top = filePath[:idx]
+	}
+
+	switch top {
+	case "dashboards":
+		return "dashboard"
+	case "folders":
+		return "folder"
+	case "alerting", "alert-rules":
+		return "alert_rule"
+	case "library-panels", "library-elements":
+		return "library_panel"
+	default:
+		return ""
+	}
+}
+
+// collectRepositoryResources walks every file in the given repository (or
+// every configured repository, if repositoryFilter is empty) and
+// reconstructs the resource each recognizable file represents.
+func collectRepositoryResources(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryFilter string) ([]repoResource, error) {
+	repositories, err := fetchRepositories(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []repoResource
+	for _, repository := range repositories {
+		if repositoryFilter != "" && repository.Name != repositoryFilter {
+			continue
+		}
+
+		files, found, err := fetchRepositoryFiles(ctx, cfg, repository.Name)
+		if err != nil {
+			return nil, fmt.Errorf("listing files in repository %q: %w", repository.Name, err)
+		}
+		if !found {
+			continue
+		}
+
+		for _, file := range files {
+			kind := resourceKindForPath(file.Path)
+			if kind == "" {
+				continue
+			}
+
+			content, existed, err := fetchRepositoryFileContent(ctx, cfg, repository.Name, file.Path, "")
+			if err != nil {
+				return nil, fmt.Errorf("reading %s in repository %q: %w", file.Path, repository.Name, err)
+			}
+			if !existed {
+				continue
+			}
+
+			uid, title, folder, ok := extractResourceFields(kind, content)
+			if !ok {
+				continue
+			}
+			if folder == "" {
+				folder = path.Dir(file.Path)
+			}
+
+			resources = append(resources, repoResource{
+				Kind: kind, UID: uid, Title: title, Folder: folder,
+				RepositoryName: repository.Name, Path: file.Path,
+			})
+		}
+	}
+
+	if repositoryFilter != "" && len(repositories) > 0 {
+		found := false
+		for _, r := range repositories {
+			if r.Name == repositoryFilter {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("repository %q not found", repositoryFilter)
+		}
+	}
+
+	return resources, nil
+}
+
+// extractResourceFields pulls uid/title/folder out of a resource file's
+// JSON. Dashboards may be wrapped as {"dashboard": {...}} per
+// ManageFileDirectly's documented content format; every other kind is
+// expected to be a flat object.
+func extractResourceFields(kind, content string) (uid, title, folder string, ok bool) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return "", "", "", false
+	}
+
+	m := raw
+	if kind == "dashboard" {
+		if nested, isMap := raw["dashboard"].(map[string]interface{}); isMap {
+			m = nested
+		}
+	}
+
+	uid, _ = m["uid"].(string)
+	if uid == "" {
+		return "", "", "", false
+	}
+	title, _ = m["title"].(string)
+	if title == "" {
+		title, _ = m["name"].(string)
+	}
+	folder, _ = m["folderTitle"].(string)
+
+	return uid, title, folder, true
+}
+
+// fetchRepositories retrieves every provisioning repository configured
+// for this Grafana instance.
+func fetchRepositories(ctx context.Context, cfg mcpgrafana.GrafanaConfig) ([]Repository, error) {
+	apiPath := "/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories"
+	requestURL := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	if cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
+		req.Header.Set("X-Access-Token", cfg.AccessToken)
+		req.Header.Set("X-Grafana-Id", cfg.IDToken)
+	}
+
+	client := &http.Client{}
+	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
+		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create custom transport: %w", err)
+		}
+		client.Transport = transport
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response ProvisioningResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return response.Items, nil
+}
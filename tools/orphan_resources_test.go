@@ -0,0 +1,109 @@
+// Requires a Grafana instance running on localhost:3000.
+// Run with `go test -tags integration`.
+//go:build integration
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+func TestOrphanResourcesTools(t *testing.T) {
+	t.Run("list untracked grafana resources - no filters", func(t *testing.T) {
+		ctx := newTestContext()
+		result, err := listUntrackedGrafanaResources(ctx, ListUntrackedGrafanaResourcesParams{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result)
+
+		// Either no drift was found, or the result is grouped by resource kind.
+		noDrift := assert.Contains(t, result, "No drift detected")
+		hasFindings := assert.Contains(t, result, "drifted resource")
+		assert.True(t, noDrift || hasFindings, "Result should report either no drift or grouped findings")
+	})
+
+	t.Run("list untracked grafana resources - no repositories means every resource is orphaned", func(t *testing.T) {
+		ctx := newTestContext()
+
+		repositories, err := fetchRepositories(ctx, mcpgrafana.GrafanaConfigFromContext(ctx))
+		require.NoError(t, err)
+		if len(repositories) > 0 {
+			t.Skip("Skipping: this Grafana instance has provisioning repositories configured")
+		}
+
+		grafanaResources, err := collectGrafanaResources(ctx)
+		require.NoError(t, err)
+		if len(grafanaResources) == 0 {
+			t.Skip("Skipping: this Grafana instance has no dashboards, folders, alert rules, or library panels")
+		}
+
+		result, err := listUntrackedGrafanaResources(ctx, ListUntrackedGrafanaResourcesParams{})
+		require.NoError(t, err)
+		assert.Contains(t, result, "orphan-in-grafana")
+		assert.NotContains(t, result, "orphan-in-repo")
+	})
+
+	t.Run("list untracked grafana resources - matching repository reports no drift for its own files", func(t *testing.T) {
+		ctx := newTestContext()
+
+		repositories, err := fetchRepositories(ctx, mcpgrafana.GrafanaConfigFromContext(ctx))
+		require.NoError(t, err)
+		if len(repositories) == 0 {
+			t.Skip("Skipping: this Grafana instance has no provisioning repositories configured")
+		}
+
+		result, err := listUntrackedGrafanaResources(ctx, ListUntrackedGrafanaResourcesParams{
+			RepositoryName: repositories[0].Name,
+		})
+		require.NoError(t, err)
+
+		// A repository whose files were created by exporting the live
+		// resources should report no drift; one that was bootstrapped
+		// separately may legitimately show orphan-in-repo or orphan-in-grafana
+		// entries, so we only assert the call succeeds and is well-formed.
+		assert.True(t,
+			assert.Contains(t, result, "No drift detected") || assert.Contains(t, result, "drifted resource"),
+			"Result should report either no drift or grouped findings")
+	})
+
+	t.Run("list untracked grafana resources - nonexistent repository", func(t *testing.T) {
+		ctx := newTestContext()
+		_, err := listUntrackedGrafanaResources(ctx, ListUntrackedGrafanaResourcesParams{
+			RepositoryName: "nonexistent-repo-xyz",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("format untracked resource function", func(t *testing.T) {
+		formatted := formatUntrackedResource(UntrackedResource{
+			Kind:   "dashboard",
+			UID:    "abc123",
+			Title:  "My Dashboard",
+			Folder: "General",
+			Reason: "orphan-in-grafana",
+		})
+		assert.Contains(t, formatted, "uid=abc123")
+		assert.Contains(t, formatted, "title=My Dashboard")
+		assert.Contains(t, formatted, "folder=General")
+		assert.Contains(t, formatted, "reason=orphan-in-grafana")
+		assert.NotContains(t, formatted, "repository=")
+		assert.NotContains(t, formatted, "path=")
+
+		formattedWithRepo := formatUntrackedResource(UntrackedResource{
+			Kind:           "dashboard",
+			UID:            "abc123",
+			Title:          "My Dashboard",
+			Folder:         "General",
+			Reason:         "orphan-in-repo",
+			RepositoryName: "test-repo",
+			Path:           "dashboards/my-dashboard.json",
+		})
+		assert.Contains(t, formattedWithRepo, "repository=test-repo")
+		assert.Contains(t, formattedWithRepo, "path=dashboards/my-dashboard.json")
+	})
+}
@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// PRProviderRequest holds the information needed to build a pull/merge
+// request creation URL for any supported forge.
+type PRProviderRequest struct {
+	RepoURL    string
+	BaseBranch string
+	HeadBranch string
+	Title      string
+	Body       string
+}
+
+// escapeBranchPathSegment percent-encodes branch for use as a path segment
+// in a compare URL. This is stricter than url.PathEscape: base_branch and
+// head_branch are free-form strings straight from tool arguments, and the
+// resulting URL can reach internal/browser.Open's WSL branch, which shells
+// out to cmd.exe -- url.PathEscape leaves shell metacharacters like "&" and
+// "|" unescaped since they're valid in an RFC 3986 path segment, but cmd.exe
+// parses them as command separators regardless of URL validity. Percent-
+// encode everything outside the unreserved set instead of relying on
+// "legal in a URL" to also mean "safe to pass to a shell".
+func escapeBranchPathSegment(branch string) string {
+	var b strings.Builder
+	for i := 0; i < len(branch); i++ {
+		c := branch[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// PRProvider builds the URL for a forge's native "create pull/merge request"
+// page, pre-filled with a title, body, and branch comparison.
+type PRProvider interface {
+	// Name identifies the provider, e.g. "github", for use as an override.
+	Name() string
+	// DisplayName is the human-readable form of Name(), e.g. "GitHub".
+	DisplayName() string
+	// BuildURL returns the pre-filled PR/MR creation URL for req.
+	BuildURL(req PRProviderRequest) string
+}
+
+type githubPRProvider struct{}
+
+func (githubPRProvider) Name() string        { return "github" }
+func (githubPRProvider) DisplayName() string { return "GitHub" }
+
+func (githubPRProvider) BuildURL(req PRProviderRequest) string {
+	params := url.Values{}
+	params.Set("expand", "1")
+	params.Set("title", req.Title)
+	params.Set("body", req.Body)
+
+	return fmt.Sprintf("%s/compare/%s...%s?%s",
+		strings.TrimRight(req.RepoURL, "/"),
+		escapeBranchPathSegment(req.BaseBranch),
+		escapeBranchPathSegment(req.HeadBranch),
+		params.Encode())
+}
+
+type gitlabPRProvider struct{}
+
+func (gitlabPRProvider) Name() string        { return "gitlab" }
+func (gitlabPRProvider) DisplayName() string { return "GitLab" }
+
+func (gitlabPRProvider) BuildURL(req PRProviderRequest) string {
+	params := url.Values{}
+	params.Set("merge_request[source_branch]", req.HeadBranch)
+	params.Set("merge_request[target_branch]", req.BaseBranch)
+	params.Set("merge_request[title]", req.Title)
+
+	return fmt.Sprintf("%s/-/merge_requests/new?%s",
+		strings.TrimRight(req.RepoURL, "/"),
+		params.Encode())
+}
+
+type bitbucketPRProvider struct{}
+
+func (bitbucketPRProvider) Name() string        { return "bitbucket" }
+func (bitbucketPRProvider) DisplayName() string { return "Bitbucket" }
+
+func (bitbucketPRProvider) BuildURL(req PRProviderRequest) string {
+	params := url.Values{}
+	params.Set("source", req.HeadBranch)
+	params.Set("dest", req.BaseBranch)
+	params.Set("title", req.Title)
+
+	return fmt.Sprintf("%s/pull-requests/new?%s",
+		strings.TrimRight(req.RepoURL, "/"),
+		params.Encode())
+}
+
+type giteaPRProvider struct{}
+
+func (giteaPRProvider) Name() string        { return "gitea" }
+func (giteaPRProvider) DisplayName() string { return "Gitea" }
+
+func (giteaPRProvider) BuildURL(req PRProviderRequest) string {
+	// Gitea's compare page mirrors GitHub's path shape but takes the PR
+	// description as "description" rather than "body" and has no "expand"
+	// parameter to pre-fill.
+	params := url.Values{}
+	params.Set("title", req.Title)
+	params.Set("description", req.Body)
+
+	return fmt.Sprintf("%s/compare/%s...%s?%s",
+		strings.TrimRight(req.RepoURL, "/"),
+		escapeBranchPathSegment(req.BaseBranch),
+		escapeBranchPathSegment(req.HeadBranch),
+		params.Encode())
+}
+
+type bitbucketServerPRProvider struct{}
+
+func (bitbucketServerPRProvider) Name() string        { return "bitbucket-server" }
+func (bitbucketServerPRProvider) DisplayName() string { return "Bitbucket Server" }
+
+func (bitbucketServerPRProvider) BuildURL(req PRProviderRequest) string {
+	// Bitbucket Server's (née Stash) "create pull request" page lives under
+	// the repo's own path rather than a project-wide endpoint, takes full
+	// refs rather than bare branch names, and has no title/body params --
+	// those are filled in on the next page after the branches are chosen.
+	params := url.Values{}
+	params.Set("create", "")
+	params.Set("sourceBranch", "refs/heads/"+req.HeadBranch)
+	params.Set("targetBranch", "refs/heads/"+req.BaseBranch)
+
+	return fmt.Sprintf("%s/pull-requests?%s",
+		strings.TrimRight(req.RepoURL, "/"),
+		params.Encode())
+}
+
+type azureDevOpsPRProvider struct{}
+
+func (azureDevOpsPRProvider) Name() string        { return "azuredevops" }
+func (azureDevOpsPRProvider) DisplayName() string { return "Azure DevOps" }
+
+func (azureDevOpsPRProvider) BuildURL(req PRProviderRequest) string {
+	// Azure DevOps' "create pull request" page takes bare branch names (no
+	// refs/heads/ prefix) as sourceRef/targetRef, and -- unlike the other
+	// providers here -- has no query parameters for pre-filling title or
+	// body; those are entered on the page itself.
+	params := url.Values{}
+	params.Set("sourceRef", req.HeadBranch)
+	params.Set("targetRef", req.BaseBranch)
+
+	return fmt.Sprintf("%s/pullrequestcreate?%s",
+		strings.TrimRight(req.RepoURL, "/"),
+		params.Encode())
+}
+
+// prProvidersByHost maps a lowercased substring of a repository URL's host
+// to the provider that handles it. Order matters only in that "github" is
+// checked last, since self-hosted Gitea/GitLab/Bitbucket instances
+// sometimes carry "git" in their hostname too.
+//
+// Bitbucket Server is deliberately absent from this table: self-hosted
+// instances rarely carry "bitbucket" in their hostname, so a "bitbucket"
+// host match is assumed to be Bitbucket Cloud and Bitbucket Server must be
+// selected with an explicit provider override, same as any other
+// self-hosted forge.
+var prProvidersByHost = []struct {
+	hostContains string
+	provider     PRProvider
+}{
+	{"gitlab", gitlabPRProvider{}},
+	{"bitbucket", bitbucketPRProvider{}},
+	{"gitea", giteaPRProvider{}},
+	{"dev.azure.com", azureDevOpsPRProvider{}},
+	{"visualstudio.com", azureDevOpsPRProvider{}},
+	{"github", githubPRProvider{}},
+}
+
+// resolvePRProvider picks a PRProvider for repoURL based on its host. If
+// override is non-empty (e.g. "gitlab"), it's used instead of host sniffing
+// -- this is required for self-hosted forges whose hostname doesn't name
+// the forge at all.
+func resolvePRProvider(repoURL, override string) (PRProvider, error) {
+	if override != "" {
+		return prProviderByName(override)
+	}
+
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("could not determine VCS provider from repository URL %q", repoURL)
+	}
+
+	host := strings.ToLower(parsed.Host)
+	for _, candidate := range prProvidersByHost {
+		if strings.Contains(host, candidate.hostContains) {
+			return candidate.provider, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unrecognized VCS provider for repository URL %q; set provider explicitly", repoURL)
+}
+
+// prProviderByName looks up a PRProvider by its Name(), case-insensitively.
+func prProviderByName(name string) (PRProvider, error) {
+	switch strings.ToLower(name) {
+	case "github":
+		return githubPRProvider{}, nil
+	case "gitlab":
+		return gitlabPRProvider{}, nil
+	case "bitbucket":
+		return bitbucketPRProvider{}, nil
+	case "bitbucket-server":
+		return bitbucketServerPRProvider{}, nil
+	case "gitea":
+		return giteaPRProvider{}, nil
+	case "azuredevops":
+		return azureDevOpsPRProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown VCS provider %q", name)
+	}
+}
@@ -0,0 +1,284 @@
+package tools
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPRProviderBuildURL(t *testing.T) {
+	req := PRProviderRequest{
+		RepoURL:    "https://example.com/grafana/dashboard-configs",
+		BaseBranch: "main",
+		HeadBranch: "feature/new-dashboard",
+		Title:      "Add new dashboard",
+		Body:       "This PR adds a new monitoring dashboard with special chars: &=+",
+	}
+
+	tests := []struct {
+		name             string
+		provider         PRProvider
+		wantPathContains string
+		// wantTitle is false for providers whose create-PR page has no
+		// query parameter to pre-fill the title.
+		wantTitle bool
+	}{
+		{
+			name:             "github",
+			provider:         githubPRProvider{},
+			wantPathContains: "/compare/main...feature%2Fnew-dashboard",
+			wantTitle:        true,
+		},
+		{
+			name:             "gitlab",
+			provider:         gitlabPRProvider{},
+			wantPathContains: "/-/merge_requests/new",
+			wantTitle:        true,
+		},
+		{
+			name:             "bitbucket",
+			provider:         bitbucketPRProvider{},
+			wantPathContains: "/pull-requests/new",
+			wantTitle:        true,
+		},
+		{
+			name:             "gitea",
+			provider:         giteaPRProvider{},
+			wantPathContains: "/compare/main...feature%2Fnew-dashboard",
+			wantTitle:        true,
+		},
+		{
+			name:             "bitbucket-server",
+			provider:         bitbucketServerPRProvider{},
+			wantPathContains: "/pull-requests?",
+			wantTitle:        false,
+		},
+		{
+			name:             "azuredevops",
+			provider:         azureDevOpsPRProvider{},
+			wantPathContains: "/pullrequestcreate?",
+			wantTitle:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.provider.BuildURL(req)
+
+			if !strings.Contains(got, tt.wantPathContains) {
+				t.Errorf("expected URL to contain %q, got %s", tt.wantPathContains, got)
+			}
+			if tt.wantTitle && !strings.Contains(got, url.QueryEscape(req.Title)) {
+				t.Errorf("expected URL to contain encoded title, got %s", got)
+			}
+			if !strings.Contains(got, req.RepoURL) {
+				t.Errorf("expected URL to start from repo URL, got %s", got)
+			}
+		})
+	}
+}
+
+func TestPRProviderBuildURL_TrailingSlash(t *testing.T) {
+	req := PRProviderRequest{
+		RepoURL:    "https://example.com/grafana/dashboard-configs/",
+		BaseBranch: "main",
+		HeadBranch: "fix/trailing-slash",
+		Title:      "Fix trailing slash handling",
+		Body:       "This tests URL handling with trailing slash",
+	}
+
+	providers := map[string]PRProvider{
+		"github":    githubPRProvider{},
+		"gitlab":    gitlabPRProvider{},
+		"bitbucket": bitbucketPRProvider{},
+		"gitea":     giteaPRProvider{},
+	}
+
+	for name, provider := range providers {
+		t.Run(name, func(t *testing.T) {
+			got := provider.BuildURL(req)
+
+			if strings.Contains(got, "dashboard-configs//") {
+				t.Errorf("URL should not contain double slashes: %s", got)
+			}
+		})
+	}
+}
+
+func TestPRProviderBuildURL_QueryEncoding(t *testing.T) {
+	req := PRProviderRequest{
+		RepoURL:    "https://example.com/grafana/dashboard-configs",
+		BaseBranch: "main",
+		HeadBranch: "feature/new-dashboard",
+		Title:      "Title with & = + chars",
+		Body:       "Body with & = + chars",
+	}
+
+	providers := map[string]PRProvider{
+		"github":    githubPRProvider{},
+		"gitlab":    gitlabPRProvider{},
+		"bitbucket": bitbucketPRProvider{},
+		"gitea":     giteaPRProvider{},
+	}
+
+	for name, provider := range providers {
+		t.Run(name, func(t *testing.T) {
+			got := provider.BuildURL(req)
+
+			parsed, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("generated URL did not parse: %v", err)
+			}
+
+			values := parsed.Query()
+			found := false
+			for _, v := range values {
+				for _, s := range v {
+					if s == req.Title {
+						found = true
+					}
+				}
+			}
+			if !found {
+				t.Errorf("expected a query parameter to decode back to the original title, got %s", got)
+			}
+		})
+	}
+}
+
+// TestPRProviderBuildURL_ExactURLs pins the exact URL each provider
+// produces, so a future change to a provider's quirky query-parameter
+// format (source vs sourceBranch, body vs description, refs/heads/
+// prefixing, ...) shows up as a clear diff rather than a passing
+// substring-containment check.
+func TestPRProviderBuildURL_ExactURLs(t *testing.T) {
+	req := PRProviderRequest{
+		RepoURL:    "https://example.com/grafana/dashboard-configs",
+		BaseBranch: "main",
+		HeadBranch: "feature/new-dashboard",
+		Title:      "Add new dashboard",
+		Body:       "Adds a new dashboard",
+	}
+
+	tests := []struct {
+		name     string
+		provider PRProvider
+		want     string
+	}{
+		{
+			name:     "github",
+			provider: githubPRProvider{},
+			want:     "https://example.com/grafana/dashboard-configs/compare/main...feature%2Fnew-dashboard?body=Adds+a+new+dashboard&expand=1&title=Add+new+dashboard",
+		},
+		{
+			name:     "gitlab",
+			provider: gitlabPRProvider{},
+			want:     "https://example.com/grafana/dashboard-configs/-/merge_requests/new?merge_request%5Bsource_branch%5D=feature%2Fnew-dashboard&merge_request%5Btarget_branch%5D=main&merge_request%5Btitle%5D=Add+new+dashboard",
+		},
+		{
+			name:     "bitbucket",
+			provider: bitbucketPRProvider{},
+			want:     "https://example.com/grafana/dashboard-configs/pull-requests/new?dest=main&source=feature%2Fnew-dashboard&title=Add+new+dashboard",
+		},
+		{
+			name:     "gitea",
+			provider: giteaPRProvider{},
+			want:     "https://example.com/grafana/dashboard-configs/compare/main...feature%2Fnew-dashboard?description=Adds+a+new+dashboard&title=Add+new+dashboard",
+		},
+		{
+			name:     "bitbucket-server",
+			provider: bitbucketServerPRProvider{},
+			want:     "https://example.com/grafana/dashboard-configs/pull-requests?create=&sourceBranch=refs%2Fheads%2Ffeature%2Fnew-dashboard&targetBranch=refs%2Fheads%2Fmain",
+		},
+		{
+			name:     "azuredevops",
+			provider: azureDevOpsPRProvider{},
+			want:     "https://example.com/grafana/dashboard-configs/pullrequestcreate?sourceRef=feature%2Fnew-dashboard&targetRef=main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.provider.BuildURL(req)
+			if got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPRProviderBuildURL_EscapesShellMetacharacters guards against
+// command injection when the resulting URL is handed to a shell (e.g.
+// internal/browser.Open's WSL branch, which shells out to cmd.exe):
+// base_branch/head_branch are free-form strings from tool arguments, so
+// BuildURL must never embed them in a path segment unescaped.
+func TestPRProviderBuildURL_EscapesShellMetacharacters(t *testing.T) {
+	req := PRProviderRequest{
+		RepoURL:    "https://example.com/grafana/dashboard-configs",
+		BaseBranch: "main",
+		HeadBranch: "x & calc.exe",
+		Title:      "Add new dashboard",
+		Body:       "Adds a new dashboard",
+	}
+
+	providers := map[string]PRProvider{
+		"github": githubPRProvider{},
+		"gitea":  giteaPRProvider{},
+	}
+
+	for name, provider := range providers {
+		t.Run(name, func(t *testing.T) {
+			got := provider.BuildURL(req)
+			path, _, _ := strings.Cut(got, "?")
+			if strings.ContainsAny(path, " &|^") {
+				t.Errorf("expected HeadBranch to be escaped in the URL path, got %s", got)
+			}
+			if !strings.Contains(path, escapeBranchPathSegment(req.HeadBranch)) {
+				t.Errorf("expected URL path to contain escaped HeadBranch, got %s", got)
+			}
+		})
+	}
+}
+
+func TestResolvePRProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		repoURL   string
+		override  string
+		wantName  string
+		wantError bool
+	}{
+		{name: "github host", repoURL: "https://github.com/grafana/dashboard-configs", wantName: "github"},
+		{name: "gitlab host", repoURL: "https://gitlab.com/grafana/dashboard-configs", wantName: "gitlab"},
+		{name: "bitbucket host", repoURL: "https://bitbucket.org/grafana/dashboard-configs", wantName: "bitbucket"},
+		{name: "gitea host", repoURL: "https://gitea.example.com/grafana/dashboard-configs", wantName: "gitea"},
+		{name: "azure devops host", repoURL: "https://dev.azure.com/grafana/dashboard-configs/_git/dashboard-configs", wantName: "azuredevops"},
+		{name: "visualstudio.com host", repoURL: "https://grafana.visualstudio.com/dashboard-configs/_git/dashboard-configs", wantName: "azuredevops"},
+		{name: "self-hosted host needs override", repoURL: "https://git.internal.example.com/grafana/dashboard-configs", override: "gitlab", wantName: "gitlab"},
+		{name: "self-hosted bitbucket server needs override", repoURL: "https://git.internal.example.com/scm/proj/dashboard-configs", override: "bitbucket-server", wantName: "bitbucket-server"},
+		{name: "override wins over host", repoURL: "https://github.com/grafana/dashboard-configs", override: "gitea", wantName: "gitea"},
+		{name: "unrecognized host with no override", repoURL: "https://git.internal.example.com/grafana/dashboard-configs", wantError: true},
+		{name: "invalid url", repoURL: "://not-a-url", wantError: true},
+		{name: "unknown override", repoURL: "https://github.com/grafana/dashboard-configs", override: "sourcehut", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := resolvePRProvider(tt.repoURL, tt.override)
+
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if provider.Name() != tt.wantName {
+				t.Errorf("expected provider %q, got %q", tt.wantName, provider.Name())
+			}
+		})
+	}
+}
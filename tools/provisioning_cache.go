@@ -0,0 +1,379 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// ProvisioningCacheConfig controls the optional local shallow-clone cache
+// used to serve provisioning repository reads without round-tripping to
+// Grafana for every call. It is disabled by default; set
+// GRAFANA_PROVISIONING_LOCAL_CACHE=1 to enable it.
+//
+// The cache only serves github and gitlab repositories (the two types that
+// expose a plain git clone URL) and never serves file history: a depth-1
+// shallow clone can't reconstruct a path's commit history, so history reads
+// always go through the Grafana API.
+type ProvisioningCacheConfig struct {
+	Enabled bool
+	Dir     string
+	TTL     time.Duration
+}
+
+const (
+	defaultProvisioningCacheTTL = 5 * time.Minute
+	provisioningCacheEnvVar     = "GRAFANA_PROVISIONING_LOCAL_CACHE"
+	provisioningCacheDirEnvVar  = "GRAFANA_PROVISIONING_CACHE_DIR"
+	provisioningCacheTTLEnvVar  = "GRAFANA_PROVISIONING_CACHE_TTL"
+)
+
+// provisioningCacheConfigFromEnv builds a ProvisioningCacheConfig from the
+// GRAFANA_PROVISIONING_LOCAL_CACHE, GRAFANA_PROVISIONING_CACHE_DIR and
+// GRAFANA_PROVISIONING_CACHE_TTL environment variables.
+func provisioningCacheConfigFromEnv() ProvisioningCacheConfig {
+	cfg := ProvisioningCacheConfig{
+		Enabled: os.Getenv(provisioningCacheEnvVar) == "1",
+		Dir:     os.Getenv(provisioningCacheDirEnvVar),
+		TTL:     defaultProvisioningCacheTTL,
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = filepath.Join(os.TempDir(), "mcp-grafana-provisioning-cache")
+	}
+	if raw := os.Getenv(provisioningCacheTTLEnvVar); raw != "" {
+		if ttl, err := time.ParseDuration(raw); err == nil {
+			cfg.TTL = ttl
+		} else if seconds, err := strconv.Atoi(raw); err == nil {
+			cfg.TTL = time.Duration(seconds) * time.Second
+		}
+	}
+	return cfg
+}
+
+// cloneEntry is one repository's shallow working copy, plus the bookkeeping
+// needed to decide when it next needs a `git fetch`.
+type cloneEntry struct {
+	mu        sync.Mutex
+	repo      *git.Repository
+	dir       string
+	lastFetch time.Time
+}
+
+// localProvisioningCache serves provisioning repository file reads and
+// branch listings from local shallow git clones, falling back to the
+// Grafana API on any miss.
+type localProvisioningCache struct {
+	cfg ProvisioningCacheConfig
+
+	mu      sync.Mutex
+	entries map[string]*cloneEntry
+
+	refreshOnce sync.Once
+}
+
+var (
+	provisioningCacheOnce sync.Once
+	provisioningCacheInst *localProvisioningCache
+)
+
+// provisioningCache returns the process-wide local provisioning cache,
+// initializing it from the environment on first use. It returns nil when
+// the cache is disabled, so callers can treat a nil cache as "always fall
+// back to the Grafana API".
+func provisioningCache() *localProvisioningCache {
+	provisioningCacheOnce.Do(func() {
+		cfg := provisioningCacheConfigFromEnv()
+		if !cfg.Enabled {
+			return
+		}
+		provisioningCacheInst = newLocalProvisioningCache(cfg)
+	})
+	return provisioningCacheInst
+}
+
+func newLocalProvisioningCache(cfg ProvisioningCacheConfig) *localProvisioningCache {
+	return &localProvisioningCache{
+		cfg:     cfg,
+		entries: make(map[string]*cloneEntry),
+	}
+}
+
+// tryLocalBranchesCache attempts to serve a repository's branch list from
+// the local shallow-clone cache. ok is false whenever the cache can't
+// answer (disabled, unsupported repository type, or clone/fetch failure)
+// and the caller should fall back to the Grafana API.
+func tryLocalBranchesCache(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName string) (branches []RepositoryBranch, ok bool) {
+	cache := provisioningCache()
+	if cache == nil {
+		return nil, false
+	}
+	repository, found, err := lookupRepository(ctx, cfg, repositoryName)
+	if err != nil || !found {
+		return nil, false
+	}
+	return cache.branches(ctx, repository)
+}
+
+// tryLocalFileContentCache attempts to serve a file's content from the
+// local shallow-clone cache. ok is false whenever the cache can't answer
+// and the caller should fall back to the Grafana API.
+func tryLocalFileContentCache(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, path, ref string) (content string, existed bool, ok bool) {
+	cache := provisioningCache()
+	if cache == nil {
+		return "", false, false
+	}
+	repository, found, err := lookupRepository(ctx, cfg, repositoryName)
+	if err != nil || !found {
+		return "", false, false
+	}
+	return cache.fileContent(ctx, repository, path, ref)
+}
+
+func lookupRepository(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName string) (Repository, bool, error) {
+	repositories, err := fetchRepositories(ctx, cfg)
+	if err != nil {
+		return Repository{}, false, err
+	}
+	for _, r := range repositories {
+		if r.Name == repositoryName {
+			return r, true, nil
+		}
+	}
+	return Repository{}, false, nil
+}
+
+// ensureClone returns the cache entry for repository, cloning it (or
+// refreshing a stale clone) as needed. It only returns an error when the
+// repository type isn't supported or the clone/open attempt fails; callers
+// treat that as a cache miss and fall back to the Grafana API.
+func (c *localProvisioningCache) ensureClone(ctx context.Context, repository Repository) (*cloneEntry, error) {
+	switch repository.Type {
+	case "github", "gitlab":
+	default:
+		return nil, fmt.Errorf("local cache does not support repository type %q", repository.Type)
+	}
+	if repository.URL == "" {
+		return nil, fmt.Errorf("repository %q has no clone URL", repository.Name)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[repository.Name]
+	if !ok {
+		entry = &cloneEntry{dir: filepath.Join(c.cfg.Dir, repository.Name)}
+		c.entries[repository.Name] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.repo != nil {
+		c.refreshIfStale(ctx, entry)
+		return entry, nil
+	}
+
+	if repo, err := git.PlainOpen(entry.dir); err == nil {
+		entry.repo = repo
+		entry.lastFetch = time.Now()
+		c.startBackgroundRefresh()
+		return entry, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(entry.dir), 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	repo, err := git.PlainCloneContext(ctx, entry.dir, false, &git.CloneOptions{
+		URL:   repository.URL,
+		Depth: 1,
+		Tags:  git.NoTags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloning repository %q: %w", repository.Name, err)
+	}
+
+	entry.repo = repo
+	entry.lastFetch = time.Now()
+	c.startBackgroundRefresh()
+	return entry, nil
+}
+
+// refreshIfStale re-fetches entry's clone when it hasn't been refreshed
+// within the cache's TTL. A failed fetch leaves the stale clone in place;
+// reads against a ref it doesn't have will simply miss and fall back to
+// the Grafana API.
+func (c *localProvisioningCache) refreshIfStale(ctx context.Context, entry *cloneEntry) {
+	if time.Since(entry.lastFetch) < c.cfg.TTL {
+		return
+	}
+	c.fetch(ctx, entry)
+}
+
+func (c *localProvisioningCache) fetch(ctx context.Context, entry *cloneEntry) {
+	err := entry.repo.FetchContext(ctx, &git.FetchOptions{Depth: 1, Force: true})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return
+	}
+	entry.lastFetch = time.Now()
+	syncLocalBranchesToRemote(entry.repo)
+}
+
+// syncLocalBranchesToRemote fast-forwards every local branch ref to match
+// its "origin" remote-tracking ref after a fetch, so reads against HEAD or
+// a branch name see what was just fetched without a separate merge or
+// checkout step.
+func syncLocalBranchesToRemote(repo *git.Repository) {
+	refs, err := repo.References()
+	if err != nil {
+		return
+	}
+
+	var remoteRefs []*plumbing.Reference
+	_ = refs.ForEach(func(r *plumbing.Reference) error {
+		if r.Name().IsRemote() {
+			remoteRefs = append(remoteRefs, r)
+		}
+		return nil
+	})
+
+	for _, r := range remoteRefs {
+		branch := strings.TrimPrefix(r.Name().Short(), "origin/")
+		if branch == r.Name().Short() {
+			continue
+		}
+		localRef := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), r.Hash())
+		_ = repo.Storer.SetReference(localRef)
+	}
+}
+
+// startBackgroundRefresh launches the cache's periodic `git fetch` loop.
+// It is started lazily, the first time a repository is cloned or opened,
+// and runs for the lifetime of the process.
+func (c *localProvisioningCache) startBackgroundRefresh() {
+	c.refreshOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(c.cfg.TTL)
+			defer ticker.Stop()
+			for range ticker.C {
+				c.mu.Lock()
+				entries := make([]*cloneEntry, 0, len(c.entries))
+				for _, entry := range c.entries {
+					entries = append(entries, entry)
+				}
+				c.mu.Unlock()
+
+				for _, entry := range entries {
+					entry.mu.Lock()
+					c.fetch(context.Background(), entry)
+					entry.mu.Unlock()
+				}
+			}
+		}()
+	})
+}
+
+// branches lists every branch ref in the repository's local clone.
+func (c *localProvisioningCache) branches(ctx context.Context, repository Repository) (branches []RepositoryBranch, ok bool) {
+	entry, err := c.ensureClone(ctx, repository)
+	if err != nil {
+		return nil, false
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	refs, err := entry.repo.References()
+	if err != nil {
+		return nil, false
+	}
+
+	var result []RepositoryBranch
+	err = refs.ForEach(func(r *plumbing.Reference) error {
+		if !r.Name().IsBranch() {
+			return nil
+		}
+		result = append(result, RepositoryBranch{
+			Name: r.Name().Short(),
+			Hash: r.Hash().String(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// fileContent serves a file's raw JSON content from the repository's local
+// clone. ok is false when the cache can't answer the request at all (no
+// clone, unresolvable ref); existed distinguishes "answered, file is
+// absent" from "answered, here's the content".
+func (c *localProvisioningCache) fileContent(ctx context.Context, repository Repository, path, ref string) (content string, existed bool, ok bool) {
+	entry, err := c.ensureClone(ctx, repository)
+	if err != nil {
+		return "", false, false
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	commit, err := resolveCommit(entry.repo, ref)
+	if err != nil {
+		return "", false, false
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", false, false
+	}
+
+	file, err := tree.File(path)
+	if err != nil {
+		if err == object.ErrFileNotFound {
+			return "", false, true
+		}
+		return "", false, false
+	}
+
+	data, err := file.Contents()
+	if err != nil {
+		return "", false, false
+	}
+	return data, true, true
+}
+
+// resolveCommit resolves ref to a commit within repo. An empty ref
+// resolves to HEAD; anything else is tried first as a branch name, then as
+// a commit hash, matching the refs/shas a repository's file and branch
+// tools already accept.
+func resolveCommit(repo *git.Repository, ref string) (*object.Commit, error) {
+	if ref == "" {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, err
+		}
+		return repo.CommitObject(head.Hash())
+	}
+
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.NewRemoteReferenceName("origin", ref),
+	} {
+		if reference, err := repo.Reference(name, true); err == nil {
+			return repo.CommitObject(reference.Hash())
+		}
+	}
+
+	return repo.CommitObject(plumbing.NewHash(ref))
+}
@@ -0,0 +1,137 @@
+// Exercises the local shallow-clone cache directly against throwaway git
+// repositories on disk, so unlike the other provisioning_*_test.go files
+// these tests do not require a live Grafana instance.
+// Run with `go test -tags integration`.
+//go:build integration
+
+package tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initTestGitRepo creates a git repository at dir on branch "main"
+// containing one committed file at path.
+func initTestGitRepo(t *testing.T, dir, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+
+	run := func(name string, args ...string) {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "running %s %v: %s", name, args, out)
+	}
+	run("git", "init", "-q", "-b", "main")
+	run("git", "config", "user.email", "test@example.com")
+	run("git", "config", "user.name", "Test")
+	writeTestGitFile(t, dir, path, content)
+	run("git", "add", ".")
+	run("git", "commit", "-q", "-m", "initial commit")
+}
+
+func writeTestGitFile(t *testing.T, repoDir, path, content string) {
+	t.Helper()
+	full := filepath.Join(repoDir, path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+	require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+}
+
+func commitTestGitRepo(t *testing.T, dir, message string) {
+	t.Helper()
+	for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", message}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "running git %v: %s", args, out)
+	}
+}
+
+func TestLocalProvisioningCache(t *testing.T) {
+	t.Run("cache hit serves file content and branches from the clone", func(t *testing.T) {
+		origin := t.TempDir()
+		initTestGitRepo(t, origin, "dashboards/hit.json", `{"uid":"hit","title":"Hit"}`)
+
+		cache := newLocalProvisioningCache(ProvisioningCacheConfig{Dir: t.TempDir(), TTL: time.Hour})
+		repo := Repository{Name: "cache-hit-repo", Type: "github", URL: origin}
+
+		content, existed, ok := cache.fileContent(context.Background(), repo, "dashboards/hit.json", "")
+		require.True(t, ok, "cache should be able to answer from a freshly cloned repository")
+		assert.True(t, existed)
+		assert.Contains(t, content, `"uid":"hit"`)
+
+		missingContent, existed, ok := cache.fileContent(context.Background(), repo, "dashboards/missing.json", "")
+		require.True(t, ok)
+		assert.False(t, existed)
+		assert.Empty(t, missingContent)
+
+		branches, ok := cache.branches(context.Background(), repo)
+		require.True(t, ok)
+		var names []string
+		for _, b := range branches {
+			names = append(names, b.Name)
+		}
+		assert.Contains(t, names, "main")
+	})
+
+	t.Run("stale clone triggers a refetch before serving", func(t *testing.T) {
+		origin := t.TempDir()
+		initTestGitRepo(t, origin, "dashboards/v1.json", `{"uid":"v1","title":"V1"}`)
+
+		cache := newLocalProvisioningCache(ProvisioningCacheConfig{
+			Dir: t.TempDir(),
+			TTL: 0, // every read is considered stale, forcing a refetch
+		})
+		repo := Repository{Name: "cache-stale-repo", Type: "github", URL: origin}
+
+		_, existed, ok := cache.fileContent(context.Background(), repo, "dashboards/v2.json", "")
+		require.True(t, ok)
+		assert.False(t, existed, "v2.json doesn't exist in the origin yet")
+
+		writeTestGitFile(t, origin, "dashboards/v2.json", `{"uid":"v2","title":"V2"}`)
+		commitTestGitRepo(t, origin, "add v2")
+
+		content, existed, ok := cache.fileContent(context.Background(), repo, "dashboards/v2.json", "")
+		require.True(t, ok)
+		assert.True(t, existed, "a zero-TTL cache should refetch and see the new commit")
+		assert.Contains(t, content, `"uid":"v2"`)
+	})
+
+	t.Run("unreachable git host falls back to the caller's Grafana API read", func(t *testing.T) {
+		cache := newLocalProvisioningCache(ProvisioningCacheConfig{Dir: t.TempDir(), TTL: time.Hour})
+		repo := Repository{Name: "unreachable-repo", Type: "github", URL: "https://127.0.0.1:1/not-a-real-host.git"}
+
+		_, _, ok := cache.fileContent(context.Background(), repo, "dashboards/any.json", "")
+		assert.False(t, ok, "a clone failure must be reported as a cache miss, not an error")
+
+		_, ok = cache.branches(context.Background(), repo)
+		assert.False(t, ok)
+	})
+
+	t.Run("unsupported repository type is never cached", func(t *testing.T) {
+		cache := newLocalProvisioningCache(ProvisioningCacheConfig{Dir: t.TempDir(), TTL: time.Hour})
+		repo := Repository{Name: "local-repo", Type: "local", URL: "/var/lib/grafana/provisioning"}
+
+		_, _, ok := cache.fileContent(context.Background(), repo, "dashboards/any.json", "")
+		assert.False(t, ok)
+	})
+
+	t.Run("provisioning cache config from env", func(t *testing.T) {
+		t.Setenv(provisioningCacheEnvVar, "1")
+		t.Setenv(provisioningCacheDirEnvVar, "/tmp/custom-cache-dir")
+		t.Setenv(provisioningCacheTTLEnvVar, "90s")
+
+		cfg := provisioningCacheConfigFromEnv()
+		assert.True(t, cfg.Enabled)
+		assert.Equal(t, "/tmp/custom-cache-dir", cfg.Dir)
+		assert.Equal(t, 90*time.Second, cfg.TTL)
+	})
+}
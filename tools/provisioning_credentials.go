@@ -0,0 +1,502 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// credentialKeyringService is the service name provisioning credentials are
+// stored under in the OS keyring (Keychain, Secret Service, Credential
+// Manager).
+const credentialKeyringService = "mcp-grafana-provisioning"
+
+// credentialFileEnvVar overrides the path of the file-backed credential
+// store used as a fallback when the OS keyring isn't available, e.g. a
+// headless container with no Secret Service/D-Bus session.
+const credentialFileEnvVar = "GRAFANA_PROVISIONING_CREDENTIAL_FILE"
+
+// Credential is something that can authenticate against a Git provider's
+// own API (as opposed to Grafana's API, which cfg.APIKey/AccessToken
+// already cover) -- needed for actions the provisioning API itself doesn't
+// expose, like commenting on a pull request or requesting reviewers. Kind
+// identifies which concrete type a stored credential decodes as.
+type Credential interface {
+	Kind() string
+}
+
+// TokenCredential authenticates with a single bearer/personal-access
+// token, the common case for GitHub, GitLab, Gitea, and Azure DevOps.
+type TokenCredential struct {
+	Token string `json:"token"`
+}
+
+func (TokenCredential) Kind() string { return "token" }
+
+// LoginPasswordCredential authenticates with a username and password (or
+// app password), the shape Bitbucket Server still requires.
+type LoginPasswordCredential struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+func (LoginPasswordCredential) Kind() string { return "login_password" }
+
+// credentialKey identifies one stored credential: the Git provider
+// (matching a PRProvider's Name()), the host it's valid for (so a
+// github.com token and a GitHub Enterprise token for the same provider
+// don't collide), and the provisioning repository it was added for.
+type credentialKey struct {
+	Provider      string `json:"provider"`
+	Host          string `json:"host"`
+	RepositoryUID string `json:"repository_uid"`
+}
+
+func (k credentialKey) String() string {
+	return fmt.Sprintf("%s|%s|%s", k.Provider, k.Host, k.RepositoryUID)
+}
+
+// storedCredential is the JSON envelope persisted for a credential, tagged
+// with its Kind so decodeCredential knows which concrete type to decode
+// Data into.
+type storedCredential struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+func encodeCredential(c Credential) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("encoding credential: %w", err)
+	}
+	encoded, err := json.Marshal(storedCredential{Kind: c.Kind(), Data: data})
+	if err != nil {
+		return "", fmt.Errorf("encoding credential: %w", err)
+	}
+	return string(encoded), nil
+}
+
+func decodeCredential(raw string) (Credential, error) {
+	var stored storedCredential
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return nil, fmt.Errorf("decoding stored credential: %w", err)
+	}
+	switch stored.Kind {
+	case (TokenCredential{}).Kind():
+		var c TokenCredential
+		if err := json.Unmarshal(stored.Data, &c); err != nil {
+			return nil, fmt.Errorf("decoding token credential: %w", err)
+		}
+		return c, nil
+	case (LoginPasswordCredential{}).Kind():
+		var c LoginPasswordCredential
+		if err := json.Unmarshal(stored.Data, &c); err != nil {
+			return nil, fmt.Errorf("decoding login/password credential: %w", err)
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("unknown credential kind %q", stored.Kind)
+	}
+}
+
+// credentialFileEntry is one row of the file-backed store: the key it was
+// stored under, and -- only when the OS keyring rejected the Set call for
+// this key -- the encoded secret itself. When Secret is empty the actual
+// secret lives in the OS keyring and this row is just an index entry so
+// List can enumerate keys without a keyring "list all" primitive (most
+// backends don't have one).
+type credentialFileEntry struct {
+	Key    credentialKey `json:"key"`
+	Secret string        `json:"secret,omitempty"`
+}
+
+// credentialStore persists Git provider credentials keyed by
+// provider/host/repository_uid, preferring the OS keyring and falling back
+// to a 0600 file under the user's config directory for any key the keyring
+// backend can't accept.
+type credentialStore struct {
+	mu sync.Mutex
+}
+
+var defaultCredentialStore = &credentialStore{}
+
+func credentialFilePath() string {
+	if p := os.Getenv(credentialFileEnvVar); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "mcp-grafana", "provisioning-credentials.json")
+}
+
+func (s *credentialStore) readFile() (map[string]credentialFileEntry, error) {
+	data, err := os.ReadFile(credentialFilePath())
+	if os.IsNotExist(err) {
+		return map[string]credentialFileEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := map[string]credentialFileEntry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *credentialStore) writeFile(entries map[string]credentialFileEntry) error {
+	path := credentialFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Set stores cred for key, preferring the OS keyring and falling back to
+// the file store's Secret field if the keyring backend refuses the write.
+func (s *credentialStore) Set(key credentialKey, cred Credential) error {
+	raw, err := encodeCredential(cred)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readFile()
+	if err != nil {
+		return fmt.Errorf("reading credential store: %w", err)
+	}
+
+	entry := credentialFileEntry{Key: key}
+	if err := keyring.Set(credentialKeyringService, key.String(), raw); err != nil {
+		entry.Secret = raw
+	}
+
+	entries[key.String()] = entry
+	return s.writeFile(entries)
+}
+
+// Get returns the credential stored for key, reading the secret from the
+// keyring unless the index says it's a file-backed fallback entry. found
+// is false when no credential has been stored for key at all.
+func (s *credentialStore) Get(key credentialKey) (cred Credential, found bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readFile()
+	if err != nil {
+		return nil, false, fmt.Errorf("reading credential store: %w", err)
+	}
+	entry, ok := entries[key.String()]
+	if !ok {
+		return nil, false, nil
+	}
+
+	raw := entry.Secret
+	if raw == "" {
+		raw, err = keyring.Get(credentialKeyringService, key.String())
+		if err != nil {
+			return nil, false, fmt.Errorf("reading credential from keyring: %w", err)
+		}
+	}
+
+	cred, err = decodeCredential(raw)
+	return cred, true, err
+}
+
+// Delete removes any credential stored for key, from both the keyring and
+// the file store.
+func (s *credentialStore) Delete(key credentialKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readFile()
+	if err != nil {
+		return fmt.Errorf("reading credential store: %w", err)
+	}
+	if _, ok := entries[key.String()]; !ok {
+		return fmt.Errorf("no credential found for provider=%s host=%s repository_uid=%s", key.Provider, key.Host, key.RepositoryUID)
+	}
+	delete(entries, key.String())
+
+	// Best-effort: the keyring entry may not exist if it was a file-backed
+	// fallback, which isn't an error worth surfacing.
+	_ = keyring.Delete(credentialKeyringService, key.String())
+
+	return s.writeFile(entries)
+}
+
+// List returns every credential key that has been stored, sorted for
+// stable output, without touching the keyring (the index file alone is
+// enough to enumerate keys).
+func (s *credentialStore) List() ([]credentialKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readFile()
+	if err != nil {
+		return nil, fmt.Errorf("reading credential store: %w", err)
+	}
+	keys := make([]credentialKey, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys, nil
+}
+
+// hostFromRepoURL extracts the host component from a repository clone URL,
+// used to scope a credential to the forge instance it was issued by (so a
+// github.com token and a GitHub Enterprise token don't collide under the
+// same provider name).
+func hostFromRepoURL(repoURL string) (string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("could not determine host from repository URL %q", repoURL)
+	}
+	return parsed.Host, nil
+}
+
+// credentialKeyForRepository resolves the credentialKey a credential for
+// repo would be stored/looked up under: its PR provider (honoring an
+// explicit override, e.g. for self-hosted instances whose hostname doesn't
+// name the forge) and the host of its clone URL.
+func credentialKeyForRepository(repo Repository, providerOverride string) (credentialKey, error) {
+	provider, err := resolvePRProvider(repo.URL, providerOverride)
+	if err != nil {
+		return credentialKey{}, err
+	}
+	host, err := hostFromRepoURL(repo.URL)
+	if err != nil {
+		return credentialKey{}, err
+	}
+	return credentialKey{Provider: provider.Name(), Host: host, RepositoryUID: repo.UID}, nil
+}
+
+const provisioningCredentialAddToolPrompt = `Store a Git provider credential (a personal access token, or a login/password pair) for a provisioning repository. This is separate from the Grafana service account credentials used for the provisioning API itself: it's needed for actions that only the underlying Git provider's API can do, like posting a follow-up comment on a pull request or assigning reviewers.
+
+The credential is scoped to the repository's resolved provider and host, and stored in the OS keyring (Keychain, Secret Service, Credential Manager) where available, falling back to a 0600 file under the user's config directory otherwise. Pass either token, or both login and password -- not both forms.`
+
+type ProvisioningCredentialAddParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Name of the provisioning repository this credential authenticates against"`
+	Provider       string `json:"provider,omitempty" jsonschema:"description=Override the Git provider the credential is scoped to (e.g. \"gitlab\") instead of resolving it from the repository's URL"`
+	Token          string `json:"token,omitempty" jsonschema:"description=Personal access token to store"`
+	Login          string `json:"login,omitempty" jsonschema:"description=Username for login/password authentication"`
+	Password       string `json:"password,omitempty" jsonschema:"description=Password or app password for login/password authentication"`
+}
+
+var ProvisioningCredentialAdd = mcpgrafana.MustTool(
+	"provisioning_credential_add",
+	provisioningCredentialAddToolPrompt,
+	provisioningCredentialAdd,
+	mcp.WithTitleAnnotation("Add Provisioning Repository Credential"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+func provisioningCredentialAdd(ctx context.Context, args ProvisioningCredentialAddParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	repo, found, err := lookupRepository(ctx, cfg, args.RepositoryName)
+	if err != nil {
+		return "", fmt.Errorf("looking up repository: %w", err)
+	}
+	if !found {
+		return "", fmt.Errorf("repository '%s' not found", args.RepositoryName)
+	}
+
+	key, err := credentialKeyForRepository(repo, args.Provider)
+	if err != nil {
+		return "", fmt.Errorf("repository '%s': %w", args.RepositoryName, err)
+	}
+
+	var cred Credential
+	switch {
+	case args.Token != "":
+		cred = TokenCredential{Token: args.Token}
+	case args.Login != "" || args.Password != "":
+		cred = LoginPasswordCredential{Login: args.Login, Password: args.Password}
+	default:
+		return "", fmt.Errorf("either token or login/password is required")
+	}
+
+	if err := defaultCredentialStore.Set(key, cred); err != nil {
+		return "", fmt.Errorf("storing credential: %w", err)
+	}
+
+	return fmt.Sprintf("Stored %s credential for repository '%s' (provider=%s, host=%s).", cred.Kind(), args.RepositoryName, key.Provider, key.Host), nil
+}
+
+const provisioningCredentialListToolPrompt = `List the provisioning repository credentials that have been stored with provisioning_credential_add. Only identifying metadata (provider, host, repository_uid) is returned; token and password values are never echoed back.`
+
+type ProvisioningCredentialListParams struct{}
+
+var ProvisioningCredentialList = mcpgrafana.MustTool(
+	"provisioning_credential_list",
+	provisioningCredentialListToolPrompt,
+	provisioningCredentialList,
+	mcp.WithTitleAnnotation("List Provisioning Repository Credentials"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+func provisioningCredentialList(ctx context.Context, args ProvisioningCredentialListParams) (string, error) {
+	keys, err := defaultCredentialStore.List()
+	if err != nil {
+		return "", fmt.Errorf("listing credentials: %w", err)
+	}
+	if len(keys) == 0 {
+		return "No provisioning repository credentials configured.", nil
+	}
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("- provider=%s | host=%s | repository_uid=%s", k.Provider, k.Host, k.RepositoryUID))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+const provisioningCredentialRemoveToolPrompt = `Remove a stored Git provider credential for a provisioning repository, from both the OS keyring and the file-backed fallback store.`
+
+type ProvisioningCredentialRemoveParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Name of the provisioning repository whose credential should be removed"`
+	Provider       string `json:"provider,omitempty" jsonschema:"description=Override the Git provider the credential is scoped to, matching whatever was passed to provisioning_credential_add"`
+}
+
+var ProvisioningCredentialRemove = mcpgrafana.MustTool(
+	"provisioning_credential_remove",
+	provisioningCredentialRemoveToolPrompt,
+	provisioningCredentialRemove,
+	mcp.WithTitleAnnotation("Remove Provisioning Repository Credential"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+func provisioningCredentialRemove(ctx context.Context, args ProvisioningCredentialRemoveParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	repo, found, err := lookupRepository(ctx, cfg, args.RepositoryName)
+	if err != nil {
+		return "", fmt.Errorf("looking up repository: %w", err)
+	}
+	if !found {
+		return "", fmt.Errorf("repository '%s' not found", args.RepositoryName)
+	}
+
+	key, err := credentialKeyForRepository(repo, args.Provider)
+	if err != nil {
+		return "", fmt.Errorf("repository '%s': %w", args.RepositoryName, err)
+	}
+
+	if err := defaultCredentialStore.Delete(key); err != nil {
+		return "", fmt.Errorf("removing credential: %w", err)
+	}
+	return fmt.Sprintf("Removed credential for repository '%s' (provider=%s, host=%s).", args.RepositoryName, key.Provider, key.Host), nil
+}
+
+// parseGitHubOwnerRepo extracts "owner" and "repo" from a GitHub clone URL
+// like "https://github.com/owner/repo" or "https://github.com/owner/repo.git".
+func parseGitHubOwnerRepo(repoURL string) (owner, repo string, ok bool) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", false
+	}
+	path := strings.TrimSuffix(strings.TrimPrefix(parsed.Path, "/"), ".git")
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// enrichProvisioningPR posts a follow-up comment on a newly created pull
+// request, linking back to the Grafana repository that produced it, using
+// whatever credential has been stored for repo's provider/host. It never
+// returns an error: a missing or unusable credential just means the
+// enrichment is skipped, reported as a note appended to the PR creation
+// result, so forgetting to configure a credential never blocks PR creation
+// itself.
+func enrichProvisioningPR(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repo Repository, prNumber int) string {
+	key, err := credentialKeyForRepository(repo, "")
+	if err != nil {
+		return ""
+	}
+
+	cred, found, err := defaultCredentialStore.Get(key)
+	if err != nil || !found {
+		return "\n\nNote: no Git provider credential configured for this repository (see provisioning_credential_add) -- skipped posting a follow-up comment."
+	}
+
+	if key.Provider != "github" {
+		return fmt.Sprintf("\n\nNote: a credential is configured, but follow-up PR comments are only supported for GitHub repositories today (provider=%s).", key.Provider)
+	}
+
+	token, ok := cred.(TokenCredential)
+	if !ok {
+		return "\n\nNote: the stored credential isn't a token credential; GitHub's comment API requires one."
+	}
+
+	owner, name, ok := parseGitHubOwnerRepo(repo.URL)
+	if !ok {
+		return ""
+	}
+
+	comment := fmt.Sprintf("Opened via mcp-grafana from Grafana repository %q (%s).", repo.Name, strings.TrimRight(cfg.URL, "/"))
+	if err := postGitHubIssueComment(ctx, key.Host, owner, name, prNumber, token.Token, comment); err != nil {
+		return fmt.Sprintf("\n\nNote: failed to post follow-up comment: %v", err)
+	}
+	return "\n\nPosted a follow-up comment linking back to the Grafana repository."
+}
+
+// postGitHubIssueComment posts body as a comment on pull request/issue
+// number, against the public GitHub API or, for any host other than
+// github.com, the equivalent GitHub Enterprise Server API path.
+func postGitHubIssueComment(ctx context.Context, host, owner, repo string, number int, token, body string) error {
+	apiBase := "https://api.github.com"
+	if host != "" && host != "github.com" {
+		apiBase = fmt.Sprintf("https://%s/api/v3", host)
+	}
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", apiBase, owner, repo, number)
+
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return fmt.Errorf("encoding comment body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, strings.NewReader(string(payload)))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeCredential(t *testing.T) {
+	t.Run("token credential round-trips", func(t *testing.T) {
+		raw, err := encodeCredential(TokenCredential{Token: "secret-token"})
+		require.NoError(t, err)
+
+		decoded, err := decodeCredential(raw)
+		require.NoError(t, err)
+		assert.Equal(t, TokenCredential{Token: "secret-token"}, decoded)
+	})
+
+	t.Run("login/password credential round-trips", func(t *testing.T) {
+		raw, err := encodeCredential(LoginPasswordCredential{Login: "alice", Password: "hunter2"})
+		require.NoError(t, err)
+
+		decoded, err := decodeCredential(raw)
+		require.NoError(t, err)
+		assert.Equal(t, LoginPasswordCredential{Login: "alice", Password: "hunter2"}, decoded)
+	})
+
+	t.Run("unknown kind fails to decode", func(t *testing.T) {
+		_, err := decodeCredential(`{"kind":"carrier_pigeon","data":{}}`)
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed envelope fails to decode", func(t *testing.T) {
+		_, err := decodeCredential("not json")
+		assert.Error(t, err)
+	})
+}
+
+func TestCredentialStoreSetGetDeleteList(t *testing.T) {
+	t.Setenv(credentialFileEnvVar, filepath.Join(t.TempDir(), "credentials.json"))
+	store := &credentialStore{}
+
+	keyA := credentialKey{Provider: "github", Host: "github.com", RepositoryUID: "repo-a"}
+	keyB := credentialKey{Provider: "gitlab", Host: "gitlab.com", RepositoryUID: "repo-b"}
+
+	_, found, err := store.Get(keyA)
+	require.NoError(t, err)
+	assert.False(t, found, "no credential stored yet")
+
+	require.NoError(t, store.Set(keyA, TokenCredential{Token: "token-a"}))
+	require.NoError(t, store.Set(keyB, LoginPasswordCredential{Login: "bob", Password: "pw"}))
+
+	cred, found, err := store.Get(keyA)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, TokenCredential{Token: "token-a"}, cred)
+
+	keys, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Contains(t, keys, keyA)
+	assert.Contains(t, keys, keyB)
+
+	require.NoError(t, store.Delete(keyA))
+
+	_, found, err = store.Get(keyA)
+	require.NoError(t, err)
+	assert.False(t, found, "deleted credential should no longer be found")
+
+	keys, err = store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []credentialKey{keyB}, keys)
+}
+
+func TestCredentialStoreDeleteMissingKeyFails(t *testing.T) {
+	t.Setenv(credentialFileEnvVar, filepath.Join(t.TempDir(), "credentials.json"))
+	store := &credentialStore{}
+
+	err := store.Delete(credentialKey{Provider: "github", Host: "github.com", RepositoryUID: "missing"})
+	assert.Error(t, err)
+}
+
+func TestCredentialKeyForRepository(t *testing.T) {
+	repo := Repository{UID: "repo-uid", URL: "https://gitlab.example.com/team/project"}
+
+	t.Run("resolves provider from the repository host", func(t *testing.T) {
+		key, err := credentialKeyForRepository(repo, "")
+		require.NoError(t, err)
+		assert.Equal(t, "gitlab", key.Provider)
+		assert.Equal(t, "gitlab.example.com", key.Host)
+		assert.Equal(t, "repo-uid", key.RepositoryUID)
+	})
+
+	t.Run("explicit override takes precedence over host-based resolution", func(t *testing.T) {
+		key, err := credentialKeyForRepository(repo, "gitea")
+		require.NoError(t, err)
+		assert.Equal(t, "gitea", key.Provider)
+		assert.Equal(t, "gitlab.example.com", key.Host)
+	})
+}
@@ -0,0 +1,368 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines kept around each
+// change in a unified diff hunk, matching the default of `diff -u`.
+const diffContextLines = 3
+
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffDelete
+	diffInsert
+)
+
+type diffOp struct {
+	kind diffOpKind
+	line string
+}
+
+// unifiedDiff returns a standard ---/+++ unified diff between oldContent
+// (labelled oldLabel) and newContent (labelled newLabel), or "" if the two
+// are identical. Callers pass "/dev/null" as the label for the side that
+// doesn't exist, matching git's own convention for file creation and
+// deletion diffs. It keeps diffContextLines of context around each change;
+// callers wanting a configurable amount should use unifiedDiffContext.
+func unifiedDiff(oldLabel, oldContent, newLabel, newContent string) string {
+	return unifiedDiffContext(oldLabel, oldContent, newLabel, newContent, diffContextLines)
+}
+
+// unifiedDiffContext is unifiedDiff with the amount of unchanged context
+// kept around each change configurable, matching `diff -u`'s -U flag.
+// contextLines <= 0 falls back to diffContextLines.
+func unifiedDiffContext(oldLabel, oldContent, newLabel, newContent string, contextLines int) string {
+	if oldContent == newContent {
+		return ""
+	}
+	if contextLines <= 0 {
+		contextLines = diffContextLines
+	}
+
+	ops := diffLineOps(splitDiffLines(oldContent), splitDiffLines(newContent))
+	hunks := groupDiffHunks(ops, contextLines)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n", oldLabel)
+	fmt.Fprintf(&b, "+++ %s\n", newLabel)
+	for _, h := range hunks {
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", formatDiffRange(h.oldStart, h.oldLines), formatDiffRange(h.newStart, h.newLines))
+		for _, op := range h.ops {
+			switch op.kind {
+			case diffEqual:
+				fmt.Fprintf(&b, " %s\n", op.line)
+			case diffDelete:
+				fmt.Fprintf(&b, "-%s\n", op.line)
+			case diffInsert:
+				fmt.Fprintf(&b, "+%s\n", op.line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// splitDiffLines splits content into lines for diffing: a trailing
+// newline doesn't produce a spurious empty final line, and an empty
+// string (the /dev/null side of a create or delete) has no lines at all.
+func splitDiffLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.Split(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLineOps computes a line-level edit script between old and new by
+// backtracking through their longest-common-subsequence table.
+func diffLineOps(old, newLines []string) []diffOp {
+	n, m := len(old), len(newLines)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if old[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case old[i] == newLines[j]:
+			ops = append(ops, diffOp{diffEqual, old[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffDelete, old[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffInsert, newLines[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffDelete, old[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffInsert, newLines[j]})
+	}
+	return ops
+}
+
+type diffHunk struct {
+	oldStart, oldLines int
+	newStart, newLines int
+	ops                []diffOp
+}
+
+// groupDiffHunks groups a flat edit script into unified-diff hunks,
+// keeping contextLines of unchanged context around each change and
+// merging runs of changes whose context would otherwise overlap.
+func groupDiffHunks(ops []diffOp, contextLines int) []diffHunk {
+	type positioned struct {
+		op       diffOp
+		oldIndex int
+		newIndex int
+	}
+
+	indexed := make([]positioned, 0, len(ops))
+	oldIdx, newIdx := 0, 0
+	for _, op := range ops {
+		indexed = append(indexed, positioned{op, oldIdx, newIdx})
+		switch op.kind {
+		case diffEqual:
+			oldIdx++
+			newIdx++
+		case diffDelete:
+			oldIdx++
+		case diffInsert:
+			newIdx++
+		}
+	}
+
+	var changed []int
+	for i, e := range indexed {
+		if e.op.kind != diffEqual {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	var hunks []diffHunk
+	start := 0
+	for start < len(changed) {
+		end := start
+		for end+1 < len(changed) && changed[end+1]-changed[end] <= 2*contextLines {
+			end++
+		}
+
+		from := changed[start] - contextLines
+		if from < 0 {
+			from = 0
+		}
+		to := changed[end] + contextLines
+		if to >= len(indexed) {
+			to = len(indexed) - 1
+		}
+
+		hunkOps := make([]diffOp, 0, to-from+1)
+		for k := from; k <= to; k++ {
+			hunkOps = append(hunkOps, indexed[k].op)
+		}
+
+		var oldLen, newLen int
+		for _, op := range hunkOps {
+			switch op.kind {
+			case diffEqual:
+				oldLen++
+				newLen++
+			case diffDelete:
+				oldLen++
+			case diffInsert:
+				newLen++
+			}
+		}
+
+		hunks = append(hunks, diffHunk{
+			oldStart: indexed[from].oldIndex,
+			oldLines: oldLen,
+			newStart: indexed[from].newIndex,
+			newLines: newLen,
+			ops:      hunkOps,
+		})
+
+		start = end + 1
+	}
+	return hunks
+}
+
+// formatDiffRange renders one side of a hunk header using diff -u's own
+// convention: a single-line range omits the count, and an empty range
+// (an insertion into, or deletion of, a side with nothing before it)
+// reports line 0.
+func formatDiffRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return strconv.Itoa(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// summarizeFileMutation describes the resource that writing content to
+// path would provision, reusing the same kind/uid/title/folder extraction
+// listUntrackedGrafanaResources uses to correlate repository files with
+// live Grafana resources. It returns "" when path isn't a recognizable
+// provisioned resource or content can't be parsed.
+func summarizeFileMutation(path, content string) string {
+	kind := resourceKindForPath(path)
+	if kind == "" || content == "" {
+		return ""
+	}
+	uid, title, folder, ok := extractResourceFields(kind, content)
+	if !ok {
+		return ""
+	}
+	summary := fmt.Sprintf("Would provision %s %q (uid=%s)", kind, title, uid)
+	if folder != "" {
+		summary += fmt.Sprintf(" in folder %q", folder)
+	}
+	return summary
+}
+
+// dashboardPanelDiff compares the "panels" arrays of two dashboard JSON
+// documents, keyed by panel id, and summarizes which panels were added,
+// removed, or edited. It returns ok=false if either document isn't a
+// dashboard with a panels array -- e.g. a non-JSON file, or a brand new
+// dashboard being created from nothing.
+func dashboardPanelDiff(oldContent, newContent string) (summary string, ok bool) {
+	oldPanels, oldOK := dashboardPanelsByID(oldContent)
+	newPanels, newOK := dashboardPanelsByID(newContent)
+	if !oldOK || !newOK {
+		return "", false
+	}
+
+	var added, removed, edited []string
+	for id, panel := range newPanels {
+		old, existed := oldPanels[id]
+		if !existed {
+			added = append(added, panelDiffLabel(id, panel))
+			continue
+		}
+		oldJSON, _ := json.Marshal(old)
+		newJSON, _ := json.Marshal(panel)
+		if string(oldJSON) != string(newJSON) {
+			edited = append(edited, panelDiffLabel(id, panel))
+		}
+	}
+	for id, panel := range oldPanels {
+		if _, existed := newPanels[id]; !existed {
+			removed = append(removed, panelDiffLabel(id, panel))
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 && len(edited) == 0 {
+		return "", true
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(edited)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Panel changes: %d added, %d removed, %d edited\n", len(added), len(removed), len(edited))
+	for _, label := range added {
+		fmt.Fprintf(&b, "- added: %s\n", label)
+	}
+	for _, label := range removed {
+		fmt.Fprintf(&b, "- removed: %s\n", label)
+	}
+	for _, label := range edited {
+		fmt.Fprintf(&b, "- edited: %s\n", label)
+	}
+	return strings.TrimRight(b.String(), "\n"), true
+}
+
+// panelDiffLabel renders a panel as "panel <id> (\"<title>\")" for
+// dashboardPanelDiff's summary, falling back to just the id if the panel has
+// no title.
+func panelDiffLabel(id string, panel map[string]interface{}) string {
+	title, _ := panel["title"].(string)
+	if title == "" {
+		return fmt.Sprintf("panel %s", id)
+	}
+	return fmt.Sprintf("panel %s (%q)", id, title)
+}
+
+// dashboardPanelsByID parses content as a dashboard (optionally wrapped as
+// {"dashboard": {...}} per ManageFileDirectly's documented content format)
+// and indexes its panels by id, formatted as a plain string so both numeric
+// and string panel ids compare equal regardless of JSON number formatting.
+// ok is false if content isn't valid JSON or has no panels array.
+func dashboardPanelsByID(content string) (map[string]map[string]interface{}, bool) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return nil, false
+	}
+
+	m := raw
+	if nested, isMap := raw["dashboard"].(map[string]interface{}); isMap {
+		m = nested
+	}
+
+	rawPanels, isSlice := m["panels"].([]interface{})
+	if !isSlice {
+		return nil, false
+	}
+
+	panels := make(map[string]map[string]interface{}, len(rawPanels))
+	for _, p := range rawPanels {
+		panel, isMap := p.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		id := panelID(panel)
+		if id == "" {
+			continue
+		}
+		panels[id] = panel
+	}
+	return panels, true
+}
+
+// panelID renders a panel's "id" field as a string regardless of whether it
+// was decoded as a JSON number or string, so it can key a map uniformly.
+func panelID(panel map[string]interface{}) string {
+	switch id := panel["id"].(type) {
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	case string:
+		return id
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiffContext(t *testing.T) {
+	t.Run("identical content yields no diff", func(t *testing.T) {
+		assert.Equal(t, "", unifiedDiffContext("a", "same\n", "b", "same\n", 3))
+	})
+
+	t.Run("smaller context produces a tighter hunk", func(t *testing.T) {
+		old := "1\n2\n3\n4\n5\nchanged\n7\n8\n9\n10\n11\n"
+		newContent := strings.Replace(old, "changed", "different", 1)
+
+		wide := unifiedDiffContext("old", old, "new", newContent, 3)
+		narrow := unifiedDiffContext("old", old, "new", newContent, 1)
+
+		assert.Contains(t, wide, "5\n")
+		assert.Contains(t, wide, "7\n")
+		assert.NotContains(t, narrow, "4\n")
+		assert.NotContains(t, narrow, "8\n")
+	})
+
+	t.Run("non-positive context falls back to the default", func(t *testing.T) {
+		old := "1\n2\n3\nchanged\n5\n6\n7\n"
+		newContent := strings.Replace(old, "changed", "different", 1)
+
+		assert.Equal(t, unifiedDiffContext("old", old, "new", newContent, 0), unifiedDiff("old", old, "new", newContent))
+	})
+
+	t.Run("unifiedDiff matches unifiedDiffContext at the default width", func(t *testing.T) {
+		old := "a\nb\nc\n"
+		newContent := "a\nx\nc\n"
+		assert.Equal(t, unifiedDiffContext("old", old, "new", newContent, diffContextLines), unifiedDiff("old", old, "new", newContent))
+	})
+}
+
+func TestGroupDiffHunksMergesNearbyChanges(t *testing.T) {
+	old := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+	newLines := []string{"1", "2", "3", "4", "X", "6", "7", "Y", "9", "10"}
+	ops := diffLineOps(old, newLines)
+
+	t.Run("wide context merges both changes into one hunk", func(t *testing.T) {
+		hunks := groupDiffHunks(ops, 3)
+		assert.Len(t, hunks, 1)
+	})
+
+	t.Run("narrow context keeps them separate", func(t *testing.T) {
+		hunks := groupDiffHunks(ops, 1)
+		assert.Len(t, hunks, 2)
+	})
+}
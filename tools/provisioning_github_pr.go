@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// githubTokenEnvVar is the fallback token source for submit_github_pull_request
+// when neither args.Token nor a stored credential (see
+// provisioning_credential_add) is available.
+const githubTokenEnvVar = "GITHUB_TOKEN"
+
+const submitGithubPullRequestToolPrompt = `Create a pull request directly against a provisioning repository's GitHub remote via the GitHub API, rather than opening a browser for the user to submit it manually (see manual_submit_pull_request for that interactive flow). Returns the created PR's number, HTML URL, and mergeable state.
+
+Only works for GitHub (and GitHub Enterprise Server) repositories; other forges return an error naming the actual provider.
+
+Requires a GitHub token, resolved in order: the token arg, a credential stored for this repository via provisioning_credential_add, or the GITHUB_TOKEN environment variable. Reviewers, labels, and draft-PR status are all optional.`
+
+type SubmitGithubPullRequestParams struct {
+	RepositoryName string   `json:"repository_name" jsonschema:"required,description=Name of the provisioning repository to create the pull request on"`
+	Title          string   `json:"title" jsonschema:"required,description=Title of the pull request"`
+	Body           string   `json:"body" jsonschema:"required,description=Body/description of the pull request"`
+	BaseBranch     string   `json:"base_branch" jsonschema:"required,description=Branch the pull request merges into (e.g. \"main\")"`
+	HeadBranch     string   `json:"head_branch" jsonschema:"required,description=Branch containing the changes (e.g. \"feature/new-dashboard\")"`
+	Draft          bool     `json:"draft,omitempty" jsonschema:"description=Create the pull request as a draft"`
+	Reviewers      []string `json:"reviewers,omitempty" jsonschema:"description=GitHub usernames to request review from"`
+	Labels         []string `json:"labels,omitempty" jsonschema:"description=Labels to apply to the pull request"`
+	Token          string   `json:"token,omitempty" jsonschema:"description=GitHub token to authenticate with. Overrides any stored credential or GITHUB_TOKEN environment variable"`
+}
+
+var SubmitGithubPullRequest = mcpgrafana.MustTool(
+	"submit_github_pull_request",
+	submitGithubPullRequestToolPrompt,
+	submitGithubPullRequest,
+	mcp.WithTitleAnnotation("Submit GitHub Pull Request"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+func submitGithubPullRequest(ctx context.Context, args SubmitGithubPullRequestParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	repo, found, err := lookupRepository(ctx, cfg, args.RepositoryName)
+	if err != nil {
+		return "", fmt.Errorf("looking up repository: %w", err)
+	}
+	if !found {
+		return "", fmt.Errorf("repository '%s' not found", args.RepositoryName)
+	}
+	if repo.Type != "github" {
+		return "", fmt.Errorf("repository '%s' is a %q repository, not github; use submit_github_pull_request only for GitHub repositories", args.RepositoryName, repo.Type)
+	}
+
+	owner, name, ok := parseGitHubOwnerRepo(repo.URL)
+	if !ok {
+		return "", fmt.Errorf("could not parse owner/repo from GitHub URL %q", repo.URL)
+	}
+
+	host, err := hostFromRepoURL(repo.URL)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := resolveGitHubToken(args.Token, repo)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := newGitHubClient(host, token)
+	if err != nil {
+		return "", fmt.Errorf("creating GitHub client: %w", err)
+	}
+
+	pr, _, err := client.PullRequests.Create(ctx, owner, name, &github.NewPullRequest{
+		Title: &args.Title,
+		Body:  &args.Body,
+		Head:  &args.HeadBranch,
+		Base:  &args.BaseBranch,
+		Draft: &args.Draft,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating pull request: %w", err)
+	}
+
+	var notes []string
+	if len(args.Reviewers) > 0 {
+		if _, _, err := client.PullRequests.RequestReviewers(ctx, owner, name, pr.GetNumber(), github.ReviewersRequest{Reviewers: args.Reviewers}); err != nil {
+			notes = append(notes, fmt.Sprintf("requesting reviewers: %v", err))
+		}
+	}
+	if len(args.Labels) > 0 {
+		if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, name, pr.GetNumber(), args.Labels); err != nil {
+			notes = append(notes, fmt.Sprintf("applying labels: %v", err))
+		}
+	}
+
+	result := fmt.Sprintf("Pull request created successfully!\n\n🔗 PR URL: %s\n\n📋 Details:\n- Number: %d\n- Title: %s\n- Mergeable state: %s\n- Base: %s\n- Head: %s",
+		pr.GetHTMLURL(),
+		pr.GetNumber(),
+		pr.GetTitle(),
+		pr.GetMergeableState(),
+		args.BaseBranch,
+		args.HeadBranch)
+	if len(notes) > 0 {
+		result += fmt.Sprintf("\n\nNote: %s.", strings.Join(notes, "; "))
+	}
+	return result, nil
+}
+
+// resolveGitHubToken picks the token submitGithubPullRequest authenticates
+// with, in priority order: an explicit override, a credential stored for
+// repo via provisioning_credential_add, then the GITHUB_TOKEN environment
+// variable.
+func resolveGitHubToken(override string, repo Repository) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+
+	if key, err := credentialKeyForRepository(repo, ""); err == nil {
+		if cred, found, err := defaultCredentialStore.Get(key); err == nil && found {
+			if token, ok := cred.(TokenCredential); ok {
+				return token.Token, nil
+			}
+		}
+	}
+
+	if token := os.Getenv(githubTokenEnvVar); token != "" {
+		return token, nil
+	}
+
+	return "", fmt.Errorf("no GitHub token available: pass token, store one with provisioning_credential_add, or set %s", githubTokenEnvVar)
+}
+
+// newGitHubClient builds a GitHub API client authenticated with token,
+// pointed at the public API for host == "github.com" and at the
+// equivalent GitHub Enterprise Server endpoints otherwise.
+func newGitHubClient(host, token string) (*github.Client, error) {
+	client := github.NewClient(nil).WithAuthToken(token)
+	if host == "" || host == "github.com" {
+		return client, nil
+	}
+
+	baseURL := fmt.Sprintf("https://%s/api/v3/", host)
+	uploadURL := fmt.Sprintf("https://%s/api/uploads/", host)
+	return client.WithEnterpriseURLs(baseURL, uploadURL)
+}
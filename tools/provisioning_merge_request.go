@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+const submitProvisioningMergeRequestToolPrompt = `Create a pull/merge request directly against a provisioning repository's remote via its Git provider's API, rather than opening a browser for the user to submit it manually (see manual_submit_pull_request for that interactive flow). Works across forges -- GitHub, GitLab, Bitbucket Cloud, and Gitea -- dispatching to the right API for the repository's provider (see submit_github_pull_request if you specifically want GitHub-only reviewer/label/draft support).
+
+Requires a token for the repository's provider, resolved in order: the token arg, a credential stored for this repository via provisioning_credential_add, or a provider-specific environment variable (GITHUB_TOKEN, GITLAB_TOKEN, BITBUCKET_TOKEN, or GITEA_TOKEN). Bitbucket Server and Azure DevOps repositories aren't supported yet -- use manual_submit_pull_request for those.`
+
+type SubmitProvisioningMergeRequestParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Name of the provisioning repository to create the merge/pull request on"`
+	Title          string `json:"title" jsonschema:"required,description=Title of the merge/pull request"`
+	Body           string `json:"body" jsonschema:"required,description=Body/description of the merge/pull request"`
+	BaseBranch     string `json:"base_branch" jsonschema:"required,description=Branch the merge/pull request merges into (e.g. \"main\")"`
+	HeadBranch     string `json:"head_branch" jsonschema:"required,description=Branch containing the changes (e.g. \"feature/new-dashboard\")"`
+	Provider       string `json:"provider,omitempty" jsonschema:"description=Explicit provider override (github, gitlab, bitbucket, gitea) for self-hosted instances whose URL doesn't name the forge. Detected from the repository URL by default"`
+	Token          string `json:"token,omitempty" jsonschema:"description=Provider token to authenticate with. Overrides any stored credential or environment variable fallback"`
+}
+
+var SubmitProvisioningMergeRequest = mcpgrafana.MustTool(
+	"submit_provisioning_merge_request",
+	submitProvisioningMergeRequestToolPrompt,
+	submitProvisioningMergeRequest,
+	mcp.WithTitleAnnotation("Submit Provisioning Merge Request"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+func submitProvisioningMergeRequest(ctx context.Context, args SubmitProvisioningMergeRequestParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	repo, found, err := lookupRepository(ctx, cfg, args.RepositoryName)
+	if err != nil {
+		return "", fmt.Errorf("looking up repository: %w", err)
+	}
+	if !found {
+		return "", fmt.Errorf("repository '%s' not found", args.RepositoryName)
+	}
+
+	adapter, err := resolveForgeAdapter(repo.URL, args.Provider)
+	if err != nil {
+		return "", fmt.Errorf("repository '%s': %w", args.RepositoryName, err)
+	}
+
+	host, err := hostFromRepoURL(repo.URL)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := resolveForgeToken(args.Token, repo, args.Provider, adapter.TokenEnvVar())
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", adapter.Name(), err)
+	}
+
+	number, htmlURL, err := adapter.CreateMergeRequest(ctx, host, token, PRProviderRequest{
+		RepoURL:    repo.URL,
+		BaseBranch: args.BaseBranch,
+		HeadBranch: args.HeadBranch,
+		Title:      args.Title,
+		Body:       args.Body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating merge request: %w", err)
+	}
+
+	return fmt.Sprintf("Merge request created successfully!\n\n🔗 URL: %s\n\n📋 Details:\n- Number: %d\n- Provider: %s\n- Repository: %s\n- Base: %s\n- Head: %s",
+		htmlURL,
+		number,
+		adapter.Name(),
+		args.RepositoryName,
+		args.BaseBranch,
+		args.HeadBranch), nil
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// applyProvisioningPRMetadata applies labels, assignees, reviewers, and a
+// milestone (resolved by name, not id, matching the `hub` CLI's behavior
+// since GitHub deprecated numeric milestone ids) to a pull request that
+// create_provisioning_repository_pr just created. Grafana's provisioning
+// API only accepts title/content/ref, so this goes straight to the
+// underlying Git provider's API using whatever credential has been stored
+// for the repository via provisioning_credential_add.
+//
+// Like enrichProvisioningPR, this never fails PR creation itself: a
+// missing credential, an unsupported provider, or a failed follow-up call
+// is reported as a note appended to the result instead of an error.
+func applyProvisioningPRMetadata(ctx context.Context, repo Repository, prNumber int, labels, assignees, reviewers []string, milestone string) string {
+	if len(labels) == 0 && len(assignees) == 0 && len(reviewers) == 0 && milestone == "" {
+		return ""
+	}
+
+	key, err := credentialKeyForRepository(repo, "")
+	if err != nil {
+		return fmt.Sprintf("\n\nNote: could not resolve a Git provider for this repository -- skipped applying labels/assignees/reviewers/milestone: %v.", err)
+	}
+
+	cred, found, err := defaultCredentialStore.Get(key)
+	if err != nil || !found {
+		return "\n\nNote: no Git provider credential configured for this repository (see provisioning_credential_add) -- skipped applying labels/assignees/reviewers/milestone."
+	}
+
+	if key.Provider != "github" {
+		return fmt.Sprintf("\n\nNote: labels/assignees/reviewers/milestone are only supported for GitHub repositories today (provider=%s).", key.Provider)
+	}
+
+	token, ok := cred.(TokenCredential)
+	if !ok {
+		return "\n\nNote: the stored credential isn't a token credential; GitHub's API requires one for labels/assignees/reviewers/milestone."
+	}
+
+	owner, name, ok := parseGitHubOwnerRepo(repo.URL)
+	if !ok {
+		return ""
+	}
+
+	var applied, failed []string
+
+	if milestone != "" {
+		if err := setGitHubMilestoneByName(ctx, key.Host, owner, name, prNumber, token.Token, milestone); err != nil {
+			failed = append(failed, fmt.Sprintf("milestone %q: %v", milestone, err))
+		} else {
+			applied = append(applied, fmt.Sprintf("milestone=%s", milestone))
+		}
+	}
+	if len(labels) > 0 {
+		if err := githubAPIRequest(ctx, "POST", key.Host, fmt.Sprintf("/repos/%s/%s/issues/%d/labels", owner, name, prNumber), token.Token, map[string][]string{"labels": labels}, nil); err != nil {
+			failed = append(failed, fmt.Sprintf("labels: %v", err))
+		} else {
+			applied = append(applied, fmt.Sprintf("labels=%s", strings.Join(labels, ",")))
+		}
+	}
+	if len(assignees) > 0 {
+		if err := githubAPIRequest(ctx, "POST", key.Host, fmt.Sprintf("/repos/%s/%s/issues/%d/assignees", owner, name, prNumber), token.Token, map[string][]string{"assignees": assignees}, nil); err != nil {
+			failed = append(failed, fmt.Sprintf("assignees: %v", err))
+		} else {
+			applied = append(applied, fmt.Sprintf("assignees=%s", strings.Join(assignees, ",")))
+		}
+	}
+	if len(reviewers) > 0 {
+		if err := githubAPIRequest(ctx, "POST", key.Host, fmt.Sprintf("/repos/%s/%s/pulls/%d/requested_reviewers", owner, name, prNumber), token.Token, map[string][]string{"reviewers": reviewers}, nil); err != nil {
+			failed = append(failed, fmt.Sprintf("reviewers: %v", err))
+		} else {
+			applied = append(applied, fmt.Sprintf("reviewers=%s", strings.Join(reviewers, ",")))
+		}
+	}
+
+	var note strings.Builder
+	if len(applied) > 0 {
+		fmt.Fprintf(&note, "\n\nApplied %s.", strings.Join(applied, ", "))
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(&note, "\n\nNote: failed to apply %s.", strings.Join(failed, "; "))
+	}
+	return note.String()
+}
+
+// githubMilestone is the subset of GitHub's milestone object this package
+// needs to resolve a milestone name to the id its issues/PATCH API expects.
+type githubMilestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// setGitHubMilestoneByName resolves milestone (an open milestone's title,
+// not its numeric id) against the repository's open milestones and, on a
+// match, assigns it to issue/PR number.
+func setGitHubMilestoneByName(ctx context.Context, host, owner, repo string, number int, token, milestone string) error {
+	var milestones []githubMilestone
+	if err := githubAPIRequest(ctx, "GET", host, fmt.Sprintf("/repos/%s/%s/milestones?state=open", owner, repo), token, nil, &milestones); err != nil {
+		return fmt.Errorf("listing milestones: %w", err)
+	}
+
+	for _, m := range milestones {
+		if strings.EqualFold(m.Title, milestone) {
+			return githubAPIRequest(ctx, "PATCH", host, fmt.Sprintf("/repos/%s/%s/issues/%d", owner, repo, number), token, map[string]int{"milestone": m.Number}, nil)
+		}
+	}
+	return fmt.Errorf("no open milestone named %q", milestone)
+}
+
+// githubAPIRequest makes an authenticated request against the GitHub API
+// (or, for any host other than github.com, the equivalent GitHub
+// Enterprise Server API path), encoding body as JSON when non-nil and
+// decoding the response into out when non-nil.
+func githubAPIRequest(ctx context.Context, method, host, path, token string, body, out any) error {
+	apiBase := "https://api.github.com"
+	if host != "" && host != "github.com" {
+		apiBase = fmt.Sprintf("https://%s/api/v3", host)
+	}
+	requestURL := apiBase + path
+
+	var reqBody *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return nil
+}
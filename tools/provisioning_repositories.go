@@ -7,33 +7,53 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
-	"os/exec"
 	"regexp"
-	"runtime"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
 	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/grafana/mcp-grafana/internal/browser"
+	"github.com/grafana/mcp-grafana/internal/grafanahttp"
 )
 
-func AddProvisioningRepositoriesTool(mcp *server.MCPServer) {
-	ListProvisioningRepositories.Register(mcp)
-	CreateProvisioningRepositoryPR.Register(mcp)
-	ManualSubmitGithubPullRequest.Register(mcp)
-	ListProvisioningRepositoryBranches.Register(mcp)
-	GetProvisioningRepository.Register(mcp)
-	GetProvisioningRepositoryFileContent.Register(mcp)
-	GetProvisioningRepositoryFileHistory.Register(mcp)
-	ManageProvisioningRepositoryFile.Register(mcp)
-	ManageFileDirectly.Register(mcp)
+func AddProvisioningRepositoriesTool(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	write := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryWrite}
+	destructive := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryWrite | mcpgrafana.ToolCategoryDestructive}
+
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListProvisioningRepositories)
+	mcpgrafana.RegisterTool(mcp, allowed, write, CreateProvisioningRepositoryPR)
+	mcpgrafana.RegisterTool(mcp, allowed, write, ManualSubmitPullRequest)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListProvisioningRepositoryBranches)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ResolveProvisioningRepositoryRevision)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ProvisioningRepositoryHasBranch)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetProvisioningRepository)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetProvisioningRepositoryFileContent)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetProvisioningRepositoryFileHistory)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetProvisioningRepositoryFileDiff)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetProvisioningRepositoryFileBlame)
+	mcpgrafana.RegisterTool(mcp, allowed, destructive, ManageProvisioningRepositoryFile)
+	mcpgrafana.RegisterTool(mcp, allowed, destructive, ManageProvisioningRepositoryFiles)
+	mcpgrafana.RegisterTool(mcp, allowed, destructive, ManageFileDirectly)
+	mcpgrafana.RegisterTool(mcp, allowed, read, PreviewProvisioningRepositoryPR)
+	mcpgrafana.RegisterTool(mcp, allowed, write, ProvisioningCredentialAdd)
+	mcpgrafana.RegisterTool(mcp, allowed, read, ProvisioningCredentialList)
+	mcpgrafana.RegisterTool(mcp, allowed, destructive, ProvisioningCredentialRemove)
+	mcpgrafana.RegisterTool(mcp, allowed, write, SubmitGithubPullRequest)
+	mcpgrafana.RegisterTool(mcp, allowed, write, SubmitProvisioningMergeRequest)
 }
 
 const listProvisioningRepositoriesToolPrompt = `List Git repositories configured for this Grafana instance. IMPORTANT: If any repositories are found, it means this Grafana instance IS managed by Git (GitOps). If no repositories are found, the instance is NOT Git-managed. Repositories are used for managing Grafana configuration as code (dashboards, datasources, etc.) through Git version control. Supports filtering by type (e.g., "github" for GitHub, "gitlab" for GitLab, "bitbucket" for Bitbucket, "local" for local repositories), uid (exact match), or name (regex pattern).`
@@ -67,7 +87,9 @@ const createProvisioningRepositoryPRToolPrompt = `**AUTO-CREATE PULL REQUEST** -
 - When user wants to manually review/edit PR details before submission
 - Viewing existing pull requests
 
-The tool requires: repository name, PR title, PR body/description, and the source branch reference.`
+The tool requires: repository name, PR title, PR body/description, and the source branch reference.
+
+Optionally takes labels, assignees, reviewers, and a milestone (by name). Applying these requires a Git provider credential stored for the repository via provisioning_credential_add, since Grafana's own PR-creation API doesn't support them; without one, PR creation still succeeds and the response notes that they were skipped.`
 
 var CreateProvisioningRepositoryPR = mcpgrafana.MustTool(
 	"create_provisioning_repository_pr",
@@ -87,6 +109,30 @@ var ListProvisioningRepositoryBranches = mcpgrafana.MustTool(
 	mcp.WithReadOnlyHintAnnotation(true),
 )
 
+const resolveProvisioningRepositoryRevisionToolPrompt = `Resolve a revision in a provisioning repository to its 40-character commit SHA and the kind of ref it resolved to ("branch" or "commit"). Accepts a branch name ("main"), a fully qualified ref ("refs/heads/main"), a full or abbreviated commit SHA, or a peel expression ("main^{commit}"). Use this to validate a ref before calling create_provisioning_repository_pr or manage_provisioning_repository_file/_files, instead of only discovering a bad ref when those calls fail with "not found" or an unexpected status code.
+
+IMPORTANT: Grafana's provisioning API only exposes a repository's branch refs, not its full commit history, so resolution only succeeds for branch names/fully-qualified refs and for commit SHAs that match a branch's current head commit.`
+
+var ResolveProvisioningRepositoryRevision = mcpgrafana.MustTool(
+	"resolve_provisioning_repository_revision",
+	resolveProvisioningRepositoryRevisionToolPrompt,
+	resolveProvisioningRepositoryRevision,
+	mcp.WithTitleAnnotation("Resolve Provisioning Repository Revision"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+const provisioningRepositoryHasBranchToolPrompt = `Check whether a provisioning repository has a branch with the given exact name, without listing or paging through every branch like list_provisioning_repository_branches does. Use this to cheaply validate a ref before calling create_provisioning_repository_pr or manage_provisioning_repository_file/_files.`
+
+var ProvisioningRepositoryHasBranch = mcpgrafana.MustTool(
+	"provisioning_repository_has_branch",
+	provisioningRepositoryHasBranchToolPrompt,
+	provisioningRepositoryHasBranch,
+	mcp.WithTitleAnnotation("Check Provisioning Repository Branch Existence"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
 const getProvisioningRepositoryToolPrompt = `Retrieves the details of a repository. Returns repository details including name, title, type, url, branch, and target. Use this to understand the details of a repository and determine where files are located. The URL indicates the Git repository source (e.g., GitHub, GitLab), the type specifies the repository format (e.g., "git"), the branch shows which branch contains the files, and the target path indicates the root directory within the repository where Grafana resources are stored.`
 
 var GetProvisioningRepository = mcpgrafana.MustTool(
@@ -120,6 +166,28 @@ var GetProvisioningRepositoryFileHistory = mcpgrafana.MustTool(
 	mcp.WithReadOnlyHintAnnotation(true),
 )
 
+const getProvisioningRepositoryFileDiffToolPrompt = `Compare a file between two Git references (branch, tag, or commit hash) in a repository configured for this Grafana instance, returning a unified diff. This saves fetching both revisions via get_provisioning_repository_file and diffing them yourself -- useful for seeing exactly what changed in a dashboard or other configuration between two points in its history (e.g. two entries from get_provisioning_repository_file_history). Supports an optional context_lines parameter to control how many unchanged lines of context surround each change, like diff -u's -U flag.`
+
+var GetProvisioningRepositoryFileDiff = mcpgrafana.MustTool(
+	"get_provisioning_repository_file_diff",
+	getProvisioningRepositoryFileDiffToolPrompt,
+	getProvisioningRepositoryFileDiff,
+	mcp.WithTitleAnnotation("Get Provisioning Repository File Diff"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+const getProvisioningRepositoryFileBlameToolPrompt = `Annotate each line of a file at a Git reference (branch, tag, or commit hash) with the commit ref, author, and timestamp that last changed it, similar to "git blame". Computed by walking the file's full commit history (from get_provisioning_repository_file_history) and re-diffing each successive revision, so it works against the provisioning API's own history rather than requiring a local git checkout. Use this to answer "who changed this panel and when" without fetching and diffing every historical revision yourself.`
+
+var GetProvisioningRepositoryFileBlame = mcpgrafana.MustTool(
+	"get_provisioning_repository_file_blame",
+	getProvisioningRepositoryFileBlameToolPrompt,
+	getProvisioningRepositoryFileBlame,
+	mcp.WithTitleAnnotation("Get Provisioning Repository File Blame"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
 const manageProvisioningRepositoryFilePrompt = `Unified tool for managing files in Git repositories with automatic version control. Essential for GitOps workflows where Grafana configurations are managed as code.
 
 IMPORTANT: This tool integrates with GitOps practices by automatically committing all changes to version control, enabling audit trails, rollback capabilities, and collaboration through code reviews.
@@ -129,7 +197,9 @@ Operations & Use Cases:
 2. **Update**: Modify existing configurations with new settings or parameters  
 3. **Delete**: Remove deprecated or unused configuration files
 
-The tool requires: repository name, file path, operation type (create/update/delete), optional content for create/update, commit message, and optional branch name.`
+The tool requires: repository name, file path, operation type (create/update/delete), optional content for create/update, commit message, and optional branch name.
+
+Set dry_run to true to preview the change instead of writing it: the response is a unified diff against the current file (or /dev/null for create/delete) plus a summary of the resource the change would provision, with no commit made.`
 
 var ManageProvisioningRepositoryFile = mcpgrafana.MustTool(
 	"manage_provisioning_repository_file",
@@ -139,6 +209,22 @@ var ManageProvisioningRepositoryFile = mcpgrafana.MustTool(
 	mcp.WithDestructiveHintAnnotation(true),
 )
 
+const manageProvisioningRepositoryFilesToolPrompt = `Apply a batch of file changes (create/update/delete/move) to a Git repository as a single commit. Use this instead of repeated manage_provisioning_repository_file calls when refactoring a set of related files (e.g. renaming a dashboard and updating sibling files that reference it) so the history shows one commit rather than one per file.
+
+IMPORTANT: Grafana's provisioning API commits one file at a time, so this tool applies the changes as a batched sequence and rolls back every change applied so far the moment one fails, rather than leaving the repository half-migrated. A "move" is implemented as a create at the destination followed by a delete at the source.
+
+The tool requires: repository name, an ordered list of changes (each with a path, operation, and content for create/update, or from_path for move), and a single commit message. An optional ref/branch can be supplied to commit against instead of the default branch.
+
+Set dry_run to true to preview the whole batch instead of writing it: the response is a unified diff and provisioning summary for each change, in order, with nothing committed and no rollback needed.`
+
+var ManageProvisioningRepositoryFiles = mcpgrafana.MustTool(
+	"manage_provisioning_repository_files",
+	manageProvisioningRepositoryFilesToolPrompt,
+	manageProvisioningRepositoryFiles,
+	mcp.WithTitleAnnotation("Manage Multiple Repository Files"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
 var ManageFileDirectly = mcpgrafana.MustTool(
 	"manage_file",
 	`Unified tool for managing files in Git repositories with automatic version control. Essential for GitOps workflows where Grafana configurations are managed as code.
@@ -198,33 +284,34 @@ var ManageFileDirectly = mcpgrafana.MustTool(
 	mcp.WithDestructiveHintAnnotation(true),
 )
 
-const manualSubmitGithubPullRequestToolPrompt = `**MANUAL GITHUB PR SUBMISSION** - Use this tool when the user wants to manually create a pull request for a managed provisioning repository. This tool will:
-1. Look up the provisioning repository details to get the GitHub URL
-2. Open GitHub's pull request creation page in their browser
-3. Pre-fill the form with the provided title, body, and branch information
-4. Let the user manually review, edit, and submit the PR themselves
+const manualSubmitPullRequestToolPrompt = `**MANUAL PR/MR SUBMISSION** - Use this tool when the user wants to manually create a pull or merge request for a managed provisioning repository. This tool will:
+1. Look up the provisioning repository details to get its Git URL
+2. Detect the VCS provider (GitHub, GitLab, Bitbucket Cloud, Bitbucket Server, Gitea, or Azure DevOps) from the URL's host
+3. Open that provider's native pull/merge request creation page in their browser
+4. Pre-fill the form with the provided title, body, and branch information
+5. Let the user manually review, edit, and submit the PR/MR themselves
 
 **Use this tool when the user says:**
-- "Open the GitHub PR page for this repository"
+- "Open the PR page for this repository"
 - "I want to manually create a PR for this provisioning repository"
-- "Take me to GitHub to create a pull request for this repo"
+- "Take me to GitHub/GitLab to create a pull request for this repo"
 - "Open the pull request page so I can review it before submitting"
-- "I'll create the PR myself, just open the GitHub page"
-- Any request where they want to do the final submission manually on GitHub
+- "I'll create the PR myself, just open the page"
+- Any request where they want to do the final submission manually
 
 **This tool is ONLY for managed provisioning repositories** that are configured in this Grafana instance.
 
 **Technical Details:**
 - Validates that the repository exists in the provisioning configuration
-- Extracts the GitHub URL from the repository configuration
-- Opens GitHub's native pull request comparison/creation page
-- Pre-fills the form but doesn't create the PR automatically
+- Extracts the repository URL from the repository configuration
+- Detects the provider from the URL's host, or honors an explicit provider override
+- Opens the provider's native pull/merge request comparison/creation page
+- Pre-fills the form but doesn't create the PR/MR automatically
 - User retains full control over the final submission
 
 **Do NOT use this tool for:**
 - When user wants automatic PR creation (use create_provisioning_repository_pr instead)
 - External repositories not managed by this Grafana instance
-- Non-GitHub provisioning repositories (GitLab, Bitbucket, etc.)
 - Viewing existing pull requests
 
 **Field Guidelines:**
@@ -232,15 +319,21 @@ const manualSubmitGithubPullRequestToolPrompt = `**MANUAL GITHUB PR SUBMISSION**
 - title: Clear, descriptive PR title (e.g., "Add CPU monitoring dashboard")
 - body: Detailed description of changes and their purpose
 - base_branch: Target branch (usually "main" or "master")
-- head_branch: Source branch name (e.g., "feature/add-dashboard")`
-
-var ManualSubmitGithubPullRequest = mcpgrafana.MustTool(
-	"manual_submit_github_pull_request",
-	manualSubmitGithubPullRequestToolPrompt,
-	manualSubmitGithubPullRequest,
-	mcp.WithTitleAnnotation("Manual GitHub Pull Request Submission"),
+- head_branch: Source branch name (e.g., "feature/add-dashboard")
+- provider: Optional override ("github", "gitlab", "bitbucket", "bitbucket-server", "gitea", "azuredevops") for self-hosted instances whose hostname doesn't name the forge`
+
+var ManualSubmitPullRequest = mcpgrafana.MustTool(
+	"manual_submit_pull_request",
+	manualSubmitPullRequestToolPrompt,
+	manualSubmitPullRequest,
+	mcp.WithTitleAnnotation("Manual Pull Request Submission"),
 )
 
+// ManualSubmitGithubPullRequest is kept as an alias of ManualSubmitPullRequest
+// for backwards compatibility with callers built against the GitHub-only
+// version of this tool.
+var ManualSubmitGithubPullRequest = ManualSubmitPullRequest
+
 type ListProvisioningRepositoriesParams struct {
 	UID  string `json:"uid,omitempty" jsonschema:"description=Repository UID for exact matching"`
 	Name string `json:"name,omitempty" jsonschema:"description=Repository name (can be a javascript regex pattern)"`
@@ -252,6 +345,17 @@ type CreateProvisioningRepositoryPRParams struct {
 	Title          string `json:"title" jsonschema:"required,description=Title of the pull request (e.g. \"Add new feature\")"`
 	Body           string `json:"body" jsonschema:"required,description=Body of the pull request (e.g. \"This is a new feature that I want to add to the project\")"`
 	Ref            string `json:"ref" jsonschema:"required,description=Head branch of the pull request (e.g. \"feature/new-feature\")"`
+
+	// Labels, Assignees, Reviewers, and Milestone are applied as follow-up
+	// calls against the underlying Git provider's API after Grafana creates
+	// the PR (its own API only accepts title/content/ref), using whatever
+	// credential has been stored for this repository via
+	// provisioning_credential_add. They're silently skipped -- with a note
+	// in the tool's response -- if no credential is configured.
+	Labels    []string `json:"labels,omitempty" jsonschema:"description=Labels to apply to the pull request. Requires a stored Git provider credential (see provisioning_credential_add)"`
+	Assignees []string `json:"assignees,omitempty" jsonschema:"description=Usernames to assign to the pull request. Requires a stored Git provider credential"`
+	Reviewers []string `json:"reviewers,omitempty" jsonschema:"description=Usernames to request review from. Requires a stored Git provider credential"`
+	Milestone string   `json:"milestone,omitempty" jsonschema:"description=Name (not id) of an open milestone to attach to the pull request. Requires a stored Git provider credential"`
 }
 
 type ListProvisioningRepositoryBranchesParams struct {
@@ -259,6 +363,16 @@ type ListProvisioningRepositoryBranchesParams struct {
 	BranchName     string `json:"branch_name,omitempty" jsonschema:"description=Branch name pattern (can be a javascript regex pattern)"`
 }
 
+type ResolveProvisioningRepositoryRevisionParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+	Revision       string `json:"revision" jsonschema:"required,description=Revision to resolve: a branch name (\"main\")\\, a fully qualified ref (\"refs/heads/main\")\\, a full or abbreviated commit SHA\\, or a peel expression (\"main^{commit}\")"`
+}
+
+type ProvisioningRepositoryHasBranchParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+	BranchName     string `json:"branch_name" jsonschema:"required,description=Exact branch name to check for (not a regex pattern)"`
+}
+
 type GetProvisioningRepositoryParams struct {
 	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
 }
@@ -267,6 +381,7 @@ type GetProvisioningRepositoryFileContentParams struct {
 	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
 	Path           string `json:"path" jsonschema:"required,description=Repository file path (e.g. \"dashboards/my-dashboard.json\")"`
 	Ref            string `json:"ref,omitempty" jsonschema:"description=Git reference (branch\\, tag\\, or commit hash)"`
+	ResolveLFS     *bool  `json:"resolve_lfs,omitempty" jsonschema:"description=Resolve Git LFS pointer files to their real content via the LFS Batch API. Defaults to true; set to false to see the raw pointer instead"`
 }
 
 type GetProvisioningRepositoryFileHistoryParams struct {
@@ -275,6 +390,20 @@ type GetProvisioningRepositoryFileHistoryParams struct {
 	Ref            string `json:"ref,omitempty" jsonschema:"description=Git reference (branch\\, tag\\, or commit hash)"`
 }
 
+type GetProvisioningRepositoryFileDiffParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+	Path           string `json:"path" jsonschema:"required,description=Repository file path"`
+	FromRef        string `json:"from_ref" jsonschema:"required,description=Git reference (branch\\, tag\\, or commit hash) to diff from"`
+	ToRef          string `json:"to_ref" jsonschema:"required,description=Git reference (branch\\, tag\\, or commit hash) to diff to"`
+	ContextLines   int    `json:"context_lines,omitempty" jsonschema:"description=Number of unchanged context lines to keep around each change\\, like diff -u's -U flag. Defaults to 3"`
+}
+
+type GetProvisioningRepositoryFileBlameParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+	Path           string `json:"path" jsonschema:"required,description=Repository file path"`
+	Ref            string `json:"ref,omitempty" jsonschema:"description=Git reference (branch\\, tag\\, or commit hash) to blame as of. Defaults to the repository's default branch"`
+}
+
 type ManageProvisioningRepositoryFileParams struct {
 	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
 	Path           string `json:"path" jsonschema:"required,description=Repository file path relative to the repository root"`
@@ -282,6 +411,24 @@ type ManageProvisioningRepositoryFileParams struct {
 	Operation      string `json:"operation" jsonschema:"required,enum=create,enum=update,enum=delete,description=Operation to perform on the repository file"`
 	Content        string `json:"content,omitempty" jsonschema:"description=File content for create or update operations. Required for create and update operations"`
 	Message        string `json:"message" jsonschema:"required,description=Commit message describing the changes made"`
+	DryRun         bool   `json:"dry_run,omitempty" jsonschema:"description=If true\\, don't write anything; instead return a unified diff against the current file and a summary of the resource the change would provision"`
+}
+
+// FileChange describes a single file mutation within an atomic
+// ManageProvisioningRepositoryFiles commit.
+type FileChange struct {
+	Path      string `json:"path" jsonschema:"required,description=Repository file path relative to the repository root"`
+	Operation string `json:"operation" jsonschema:"required,enum=create,enum=update,enum=delete,enum=move,description=Operation to perform on this file"`
+	Content   string `json:"content,omitempty" jsonschema:"description=File content for create or update operations\\, or for move operations that also change the content. Required for create and update"`
+	FromPath  string `json:"from_path,omitempty" jsonschema:"description=Source path to move from. Required when operation is move"`
+}
+
+type ManageProvisioningRepositoryFilesParams struct {
+	RepositoryName string       `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+	Changes        []FileChange `json:"changes" jsonschema:"required,description=Ordered list of file changes to apply as a single commit"`
+	Message        string       `json:"message" jsonschema:"required,description=Commit message describing the changes made"`
+	Ref            string       `json:"ref,omitempty" jsonschema:"description=Git reference (branch\\, tag\\, or commit hash) to commit the changes against. If not specified\\, changes will be pushed directly to the default branch"`
+	DryRun         bool         `json:"dry_run,omitempty" jsonschema:"description=If true\\, don't write anything; instead return a unified diff for each change against its current content and a summary of the resources the commit would provision"`
 }
 
 type Repository struct {
@@ -298,6 +445,15 @@ type RepositoryBranch struct {
 	RefURL string `json:"refURL"`
 }
 
+// RepositoryRevision is the result of resolving a revision (branch name,
+// fully qualified ref, commit SHA, or peel expression) against a
+// repository's branch refs.
+type RepositoryRevision struct {
+	SHA     string `json:"sha"`
+	RefKind string `json:"refKind"`
+	Ref     string `json:"ref,omitempty"`
+}
+
 type RepositoryFileHistory struct {
 	Ref       string    `json:"ref"`
 	Message   string    `json:"message"`
@@ -305,24 +461,59 @@ type RepositoryFileHistory struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// repositoryVCSBlock is the shape shared by every per-provider block under
+// RepositoryDetail.Spec (github, gitlab, bitbucket, azuredevops): a clone
+// URL, the tracked branch, and the path within the repo that Grafana
+// resources live under.
+type repositoryVCSBlock struct {
+	URL    string `json:"url"`
+	Branch string `json:"branch"`
+	Path   string `json:"path"`
+}
+
 type RepositoryDetail struct {
 	Metadata struct {
 		Name string `json:"name"`
 	} `json:"metadata"`
 	Spec struct {
-		Title  string `json:"title"`
-		Type   string `json:"type"`
-		GitHub struct {
-			URL    string `json:"url"`
-			Branch string `json:"branch"`
-			Path   string `json:"path"`
-		} `json:"github"`
-		Sync struct {
+		Title       string             `json:"title"`
+		Type        string             `json:"type"`
+		GitHub      repositoryVCSBlock `json:"github"`
+		GitLab      repositoryVCSBlock `json:"gitlab"`
+		Bitbucket   repositoryVCSBlock `json:"bitbucket"`
+		AzureDevOps repositoryVCSBlock `json:"azuredevops"`
+		Sync        struct {
 			Target string `json:"target"`
 		} `json:"sync"`
 	} `json:"spec"`
 }
 
+// vcsBlock returns the repositoryVCSBlock populated for d's Spec.Type (e.g.
+// "github", "gitlab", "bitbucket", "azuredevops"), falling back to whichever
+// block actually has a URL set if Type is unrecognized or empty -- Grafana's
+// provisioning API has historically only populated the "github" block, so
+// older instances may still report a Type that doesn't match any of the
+// others here.
+func (d RepositoryDetail) vcsBlock() repositoryVCSBlock {
+	switch strings.ToLower(d.Spec.Type) {
+	case "gitlab":
+		return d.Spec.GitLab
+	case "bitbucket":
+		return d.Spec.Bitbucket
+	case "azuredevops":
+		return d.Spec.AzureDevOps
+	case "github", "":
+		return d.Spec.GitHub
+	}
+
+	for _, block := range []repositoryVCSBlock{d.Spec.GitHub, d.Spec.GitLab, d.Spec.Bitbucket, d.Spec.AzureDevOps} {
+		if block.URL != "" {
+			return block
+		}
+	}
+	return d.Spec.GitHub
+}
+
 type FileContentResponse struct {
 	Resource struct {
 		File interface{} `json:"file"`
@@ -356,14 +547,20 @@ type CreatePRResponse struct {
 	PullRequest PullRequest `json:"pullRequest"`
 }
 
-type ManualSubmitGithubPullRequestParams struct {
+type ManualSubmitPullRequestParams struct {
 	RepositoryName string `json:"repository_name" jsonschema:"required,description=Name of the provisioning repository to create a pull request for (e.g. \"dashboard-configs\")"`
 	Title          string `json:"title" jsonschema:"required,description=Title of the pull request (e.g. \"Add new feature\")"`
 	Body           string `json:"body" jsonschema:"required,description=Body of the pull request (e.g. \"This is a new feature that I want to add to the project\")"`
 	BaseBranch     string `json:"base_branch" jsonschema:"required,description=Base branch of the pull request (e.g. \"main\")"`
 	HeadBranch     string `json:"head_branch" jsonschema:"required,description=Head branch of the pull request (e.g. \"feature/new-feature\")"`
+	Provider       string `json:"provider,omitempty" jsonschema:"description=Override the VCS provider used to build the PR URL instead of detecting it from the repository URL's host. One of \"github\"\\, \"gitlab\"\\, \"bitbucket\"\\, \"bitbucket-server\"\\, \"gitea\"\\, \"azuredevops\"."`
 }
 
+// ManualSubmitGithubPullRequestParams is kept as an alias of
+// ManualSubmitPullRequestParams for backwards compatibility with callers
+// built against the GitHub-only version of this tool.
+type ManualSubmitGithubPullRequestParams = ManualSubmitPullRequestParams
+
 func formatRepository(r Repository) string {
 	parts := []string{
 		fmt.Sprintf("uid=%s", r.UID),
@@ -390,6 +587,17 @@ func formatRepositoryBranch(b RepositoryBranch) string {
 	return fmt.Sprintf("- %s", strings.Join(parts, " | "))
 }
 
+func formatRepositoryRevision(r RepositoryRevision) string {
+	parts := []string{
+		fmt.Sprintf("sha=%s", r.SHA),
+		fmt.Sprintf("refKind=%s", r.RefKind),
+	}
+	if r.Ref != "" {
+		parts = append(parts, fmt.Sprintf("ref=%s", r.Ref))
+	}
+	return fmt.Sprintf("- %s", strings.Join(parts, " | "))
+}
+
 func formatRepositoryFileHistory(h RepositoryFileHistory) string {
 	parts := []string{
 		fmt.Sprintf("ref=%s", h.Ref),
@@ -400,21 +608,38 @@ func formatRepositoryFileHistory(h RepositoryFileHistory) string {
 	return fmt.Sprintf("- %s", strings.Join(parts, " | "))
 }
 
+func formatFileChangeResult(change FileChange, status, hash string) string {
+	parts := []string{fmt.Sprintf("path=%s", change.Path), fmt.Sprintf("op=%s", change.Operation)}
+	if change.Operation == "move" {
+		parts = append(parts, fmt.Sprintf("from=%s", change.FromPath))
+	}
+	parts = append(parts, fmt.Sprintf("status=%s", status))
+	if hash != "" {
+		parts = append(parts, fmt.Sprintf("hash=%s", hash))
+	}
+	return fmt.Sprintf("- %s", strings.Join(parts, " | "))
+}
+
 func formatRepositoryDetail(r RepositoryDetail) string {
+	block := r.vcsBlock()
 	parts := []string{
 		fmt.Sprintf("name=%s", r.Metadata.Name),
 		fmt.Sprintf("title=%s", r.Spec.Title),
 		fmt.Sprintf("type=%s", r.Spec.Type),
-		fmt.Sprintf("url=%s", r.Spec.GitHub.URL),
-		fmt.Sprintf("branch=%s", r.Spec.GitHub.Branch),
+		fmt.Sprintf("url=%s", block.URL),
+		fmt.Sprintf("branch=%s", block.Branch),
 		fmt.Sprintf("target=%s", r.Spec.Sync.Target),
-		fmt.Sprintf("path=%s", r.Spec.GitHub.Path),
+		fmt.Sprintf("path=%s", block.Path),
 	}
 	return fmt.Sprintf("- %s", strings.Join(parts, " | "))
 }
 
 func createProvisioningRepositoryPR(ctx context.Context, args CreateProvisioningRepositoryPRParams) (string, error) {
 	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
+	}
 
 	// Prepare URL parameters (matching TypeScript implementation)
 	params := url.Values{}
@@ -422,53 +647,14 @@ func createProvisioningRepositoryPR(ctx context.Context, args CreateProvisioning
 	params.Set("content", args.Body)
 	params.Set("ref", args.Ref)
 
-	// Construct the API URL with query parameters
 	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/pr?%s", args.RepositoryName, params.Encode())
-	requestURL := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
-
-	// Create HTTP request with no body (parameters are in URL)
-	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, nil)
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
-	}
-
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
-	}
-
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
-		}
-		client.Transport = transport
-	}
-
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return "", fmt.Errorf("repository '%s' not found or pull request creation not supported", args.RepositoryName)
-	}
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
 
-	// Parse the response
 	var response CreatePRResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+	if err := client.DoJSON(ctx, http.MethodPost, apiPath, nil, &response); err != nil {
+		if errors.Is(err, grafanahttp.ErrNotFound) {
+			return "", fmt.Errorf("repository '%s' not found or pull request creation not supported", args.RepositoryName)
+		}
+		return "", err
 	}
 
 	// Use the PR URL directly from the response
@@ -482,55 +668,28 @@ func createProvisioningRepositoryPR(ctx context.Context, args CreateProvisioning
 		args.RepositoryName,
 		args.Ref)
 
+	// Transparently enrich the PR (a follow-up comment linking back to this
+	// repository, today) using whichever Git provider credential has been
+	// stored for it via provisioning_credential_add. This never fails PR
+	// creation itself -- a missing credential just appends a note.
+	if repo, found, err := lookupRepository(ctx, cfg, args.RepositoryName); err == nil && found {
+		result += enrichProvisioningPR(ctx, cfg, repo, response.PullRequest.Number)
+		result += applyProvisioningPRMetadata(ctx, repo, response.PullRequest.Number, args.Labels, args.Assignees, args.Reviewers, args.Milestone)
+	}
+
 	return result, nil
 }
 
 func listProvisioningRepositories(ctx context.Context, args ListProvisioningRepositoriesParams) (string, error) {
 	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
-
-	// Construct the API URL
-	apiPath := "/apis/provisioning.grafana.app/v0alpha1/namespaces/default/settings"
-	url := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
-	}
-
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
-	}
-
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
-		}
-		client.Transport = transport
-	}
-
-	// Make the request
-	resp, err := client.Do(req)
+	client, err := grafanahttp.New(cfg)
 	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return "", err
 	}
 
-	// Parse the response
 	var response ProvisioningResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+	if err := client.GetJSON(ctx, "/apis/provisioning.grafana.app/v0alpha1/namespaces/default/settings", &response); err != nil {
+		return "", err
 	}
 
 	// If no repositories found
@@ -604,65 +763,51 @@ func listProvisioningRepositories(ctx context.Context, args ListProvisioningRepo
 	return strings.Join(rows, "\n"), nil
 }
 
-func listProvisioningRepositoryBranches(ctx context.Context, args ListProvisioningRepositoryBranchesParams) (string, error) {
-	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
-
-	// Construct the API URL
-	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/refs", args.RepositoryName)
-	url := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+// fetchRepositoryBranches retrieves every branch/ref known for a
+// repository. found is false (with a nil error) when the repository
+// itself doesn't exist, which callers surface as a plain message rather
+// than a Go error to match this tool's existing convention.
+func fetchRepositoryBranches(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName string) (branches []RepositoryBranch, found bool, err error) {
+	if branches, ok := tryLocalBranchesCache(ctx, cfg, repositoryName); ok {
+		return branches, true, nil
+	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	client, err := grafanahttp.New(cfg)
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return nil, false, err
 	}
 
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
-	}
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/refs", repositoryName)
 
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
+	var response ProvisioningBranchesResponse
+	if err := client.GetJSON(ctx, apiPath, &response); err != nil {
+		if errors.Is(err, grafanahttp.ErrNotFound) {
+			return nil, false, nil
 		}
-		client.Transport = transport
+		return nil, false, err
 	}
 
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
-	}
-	defer resp.Body.Close()
+	return response.Items, true, nil
+}
 
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Sprintf("Repository '%s' not found or does not exist.", args.RepositoryName), nil
-	}
+func listProvisioningRepositoryBranches(ctx context.Context, args ListProvisioningRepositoryBranchesParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	branches, found, err := fetchRepositoryBranches(ctx, cfg, args.RepositoryName)
+	if err != nil {
+		return "", err
 	}
-
-	// Parse the response
-	var response ProvisioningBranchesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+	if !found {
+		return fmt.Sprintf("Repository '%s' not found or does not exist.", args.RepositoryName), nil
 	}
 
 	// If no branches found
-	if len(response.Items) == 0 {
+	if len(branches) == 0 {
 		return "Repository has no branches or refs", nil
 	}
 
 	// Apply branch name filter if provided
-	filtered := response.Items
+	filtered := branches
 
 	if args.BranchName != "" {
 		var branchFiltered []RepositoryBranch
@@ -704,56 +849,92 @@ func listProvisioningRepositoryBranches(ctx context.Context, args ListProvisioni
 	return strings.Join(rows, "\n"), nil
 }
 
-func getProvisioningRepository(ctx context.Context, args GetProvisioningRepositoryParams) (string, error) {
-	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+// revisionSHAPattern matches a full or abbreviated hex commit SHA (Git
+// accepts abbreviations down to 4 characters, but anything shorter is too
+// likely to collide to be useful here).
+var revisionSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// normalizeRevision strips the "refs/heads/" prefix and a trailing
+// "^{commit}" peel expression from a revision string, leaving the bare
+// branch name or SHA to match against.
+func normalizeRevision(revision string) string {
+	name := strings.TrimPrefix(revision, "refs/heads/")
+	name = strings.TrimSuffix(name, "^{commit}")
+	return name
+}
 
-	// Construct the API URL
-	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s", args.RepositoryName)
-	url := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+func resolveProvisioningRepositoryRevision(ctx context.Context, args ResolveProvisioningRepositoryRevisionParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	branches, found, err := fetchRepositoryBranches(ctx, cfg, args.RepositoryName)
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("Repository '%s' not found or does not exist.", args.RepositoryName), nil
 	}
 
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
+	name := normalizeRevision(args.Revision)
+
+	for _, b := range branches {
+		if b.Name == name {
+			return formatRepositoryRevision(RepositoryRevision{
+				SHA:     b.Hash,
+				RefKind: "branch",
+				Ref:     "refs/heads/" + b.Name,
+			}), nil
+		}
 	}
 
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
+	if revisionSHAPattern.MatchString(name) {
+		for _, b := range branches {
+			if strings.HasPrefix(strings.ToLower(b.Hash), strings.ToLower(name)) {
+				return formatRepositoryRevision(RepositoryRevision{
+					SHA:     b.Hash,
+					RefKind: "commit",
+				}), nil
+			}
 		}
-		client.Transport = transport
 	}
 
-	// Make the request
-	resp, err := client.Do(req)
+	return "", fmt.Errorf("revision %q not found in repository %q", args.Revision, args.RepositoryName)
+}
+
+func provisioningRepositoryHasBranch(ctx context.Context, args ProvisioningRepositoryHasBranchParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	branches, found, err := fetchRepositoryBranches(ctx, cfg, args.RepositoryName)
 	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("Repository '%s' not found or does not exist.", args.RepositoryName), nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Sprintf("Repository '%s' not found.", args.RepositoryName), nil
+	for _, b := range branches {
+		if b.Name == args.BranchName {
+			return fmt.Sprintf("Repository '%s' has branch '%s': true", args.RepositoryName, args.BranchName), nil
+		}
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return fmt.Sprintf("Repository '%s' has branch '%s': false", args.RepositoryName, args.BranchName), nil
+}
+
+func getProvisioningRepository(ctx context.Context, args GetProvisioningRepositoryParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
 	}
 
-	// Parse the response
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s", args.RepositoryName)
+
 	var response RepositoryDetail
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+	if err := client.GetJSON(ctx, apiPath, &response); err != nil {
+		if errors.Is(err, grafanahttp.ErrNotFound) {
+			return fmt.Sprintf("Repository '%s' not found.", args.RepositoryName), nil
+		}
+		return "", err
 	}
 
 	return formatRepositoryDetail(response), nil
@@ -761,73 +942,30 @@ func getProvisioningRepository(ctx context.Context, args GetProvisioningReposito
 
 func getProvisioningRepositoryFileContent(ctx context.Context, args GetProvisioningRepositoryFileContentParams) (string, error) {
 	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
-
-	// Encode the path for URL safety
-	encodedPath := url.QueryEscape(args.Path)
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
+	}
 
 	// Build the API path
+	encodedPath := url.QueryEscape(args.Path)
 	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/files/%s", args.RepositoryName, encodedPath)
 	if args.Ref != "" {
 		apiPath += fmt.Sprintf("?ref=%s", url.QueryEscape(args.Ref))
 	}
 
-	url := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
-	}
-
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
-	}
-
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
-		}
-		client.Transport = transport
-	}
-
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Sprintf("File '%s' not found in repository '%s'.", args.Path, args.RepositoryName), nil
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	// Parse the response
 	var response FileContentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+	if err := client.GetJSON(ctx, apiPath, &response); err != nil {
+		if errors.Is(err, grafanahttp.ErrNotFound) {
+			return fmt.Sprintf("File '%s' not found in repository '%s'.", args.Path, args.RepositoryName), nil
+		}
+		return "", err
 	}
 
 	if response.Resource.File == nil {
 		return fmt.Sprintf("No file content found for path: %s", args.Path), nil
 	}
 
-	// Format the JSON nicely for display
-	fileContent, err := json.MarshalIndent(response.Resource.File, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("marshaling file content: %w", err)
-	}
-
 	// Add metadata about the file
 	var metadata []string
 	metadata = append(metadata, fmt.Sprintf("File: %s", args.Path))
@@ -841,6 +979,49 @@ func getProvisioningRepositoryFileContent(ctx context.Context, args GetProvision
 	if response.URLs.SourceURL != "" {
 		metadata = append(metadata, fmt.Sprintf("Source URL: %s", response.URLs.SourceURL))
 	}
+
+	// A Git LFS-tracked path comes back as its pointer stub, not the real
+	// content, whenever the provisioning API can't parse it as structured
+	// JSON (it isn't -- it's plain text); surface that to the caller and,
+	// unless disabled, resolve it via the LFS Batch API.
+	if pointerText, isString := response.Resource.File.(string); isString {
+		if pointer, ok := parseLFSPointer(pointerText); ok {
+			metadata = append(metadata, fmt.Sprintf("LFS pointer: oid=%s size=%d", pointer.OID, pointer.Size))
+
+			resolveLFS := args.ResolveLFS == nil || *args.ResolveLFS
+			if !resolveLFS {
+				metadata = append(metadata, "", "Pointer Content (resolve_lfs=false):", "```", pointerText, "```")
+				return strings.Join(metadata, "\n"), nil
+			}
+
+			repo, found, err := lookupRepository(ctx, cfg, args.RepositoryName)
+			if err != nil || !found {
+				metadata = append(metadata, "Note: could not resolve repository to find its LFS server -- showing the raw pointer.", "", "Pointer Content:", "```", pointerText, "```")
+				return strings.Join(metadata, "\n"), nil
+			}
+
+			resolved, err := resolveLFSPointer(ctx, repo, pointer)
+			if err != nil {
+				metadata = append(metadata, fmt.Sprintf("Note: failed to resolve LFS pointer: %v -- showing the raw pointer.", err), "", "Pointer Content:", "```", pointerText, "```")
+				return strings.Join(metadata, "\n"), nil
+			}
+
+			if !utf8.ValidString(resolved) {
+				metadata = append(metadata, fmt.Sprintf("Note: resolved LFS object (oid=%s, %d bytes) is binary or not valid UTF-8 -- not embedding its content.", pointer.OID, len(resolved)))
+				return strings.Join(metadata, "\n"), nil
+			}
+
+			metadata = append(metadata, "", "Resolved Content:", "```", resolved, "```")
+			return strings.Join(metadata, "\n"), nil
+		}
+	}
+
+	// Format the JSON nicely for display
+	fileContent, err := json.MarshalIndent(response.Resource.File, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling file content: %w", err)
+	}
+
 	metadata = append(metadata, "")
 	metadata = append(metadata, "File Content:")
 	metadata = append(metadata, "```json")
@@ -850,63 +1031,168 @@ func getProvisioningRepositoryFileContent(ctx context.Context, args GetProvision
 	return strings.Join(metadata, "\n"), nil
 }
 
-func getProvisioningRepositoryFileHistory(ctx context.Context, args GetProvisioningRepositoryFileHistoryParams) (string, error) {
-	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+// lfsPointerSignature is the first line of every Git LFS pointer file
+// (https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md#the-pointer).
+const lfsPointerSignature = "version https://git-lfs.github.com/spec/v1"
 
-	// Encode the path for URL safety
-	encodedPath := url.QueryEscape(args.Path)
+// lfsPointer is the decoded form of a Git LFS pointer file: the small text
+// stub Git stores in place of a large file's real content when its path is
+// tracked via `.gitattributes filter=lfs`.
+type lfsPointer struct {
+	OID  string
+	Size int64
+}
 
-	// Build the API path
-	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/history/%s", args.RepositoryName, encodedPath)
-	if args.Ref != "" {
-		apiPath += fmt.Sprintf("?ref=%s", url.QueryEscape(args.Ref))
+// parseLFSPointer parses content as a Git LFS pointer file, reporting
+// ok=false for anything that isn't one.
+func parseLFSPointer(content string) (lfsPointer, bool) {
+	if !strings.HasPrefix(content, lfsPointerSignature) {
+		return lfsPointer{}, false
+	}
+
+	var pointer lfsPointer
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case strings.HasPrefix(line, "oid sha256:"):
+			pointer.OID = strings.TrimPrefix(line, "oid sha256:")
+		case strings.HasPrefix(line, "size "):
+			size, err := strconv.ParseInt(strings.TrimPrefix(line, "size "), 10, 64)
+			if err != nil {
+				return lfsPointer{}, false
+			}
+			pointer.Size = size
+		}
 	}
+	if pointer.OID == "" || pointer.Size == 0 {
+		return lfsPointer{}, false
+	}
+	return pointer, true
+}
 
-	url := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+// lfsServerURL derives a repository's LFS server endpoint from its clone
+// URL, following the convention Git LFS clients fall back to when a repo
+// doesn't advertise a dedicated lfs.url in its config: "<repo>.git/info/lfs".
+func lfsServerURL(repoURL string) string {
+	trimmed := strings.TrimSuffix(strings.TrimRight(repoURL, "/"), ".git")
+	return trimmed + ".git/info/lfs"
+}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// resolveLFSPointer fetches the real content behind pointer via the LFS
+// Batch API, authenticating with whatever credential has been stored for
+// repo via provisioning_credential_add.
+func resolveLFSPointer(ctx context.Context, repo Repository, pointer lfsPointer) (string, error) {
+	reqBody, err := json.Marshal(map[string]any{
+		"operation": "download",
+		"transfers": []string{"basic"},
+		"objects":   []map[string]any{{"oid": pointer.OID, "size": pointer.Size}},
+	})
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", fmt.Errorf("encoding LFS batch request: %w", err)
 	}
 
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
-	}
-
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
+	req, err := http.NewRequestWithContext(ctx, "POST", lfsServerURL(repo.URL)+"/objects/batch", bytes.NewReader(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("creating LFS batch request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.git-lfs+json")
+	req.Header.Set("Content-Type", "application/vnd.git-lfs+json")
+	if key, err := credentialKeyForRepository(repo, ""); err == nil {
+		if cred, found, err := defaultCredentialStore.Get(key); err == nil && found {
+			if token, ok := cred.(TokenCredential); ok {
+				req.Header.Set("Authorization", "Bearer "+token.Token)
+			}
 		}
-		client.Transport = transport
 	}
 
-	// Make the request
-	resp, err := client.Do(req)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
+		return "", fmt.Errorf("making LFS batch request: %w", err)
 	}
 	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Sprintf("File '%s' not found in repository '%s' or no history available.", args.Path, args.RepositoryName), nil
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("LFS batch API returned status %d", resp.StatusCode)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	var batch struct {
+		Objects []struct {
+			Actions struct {
+				Download struct {
+					Href   string            `json:"href"`
+					Header map[string]string `json:"header"`
+				} `json:"download"`
+			} `json:"actions"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"objects"`
 	}
-
-	// Parse the response
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return "", fmt.Errorf("decoding LFS batch response: %w", err)
+	}
+	if len(batch.Objects) == 0 {
+		return "", fmt.Errorf("LFS batch API returned no objects for oid %s", pointer.OID)
+	}
+
+	obj := batch.Objects[0]
+	if obj.Error != nil {
+		return "", fmt.Errorf("LFS server: %s", obj.Error.Message)
+	}
+	if obj.Actions.Download.Href == "" {
+		return "", fmt.Errorf("LFS batch API returned no download action for oid %s", pointer.OID)
+	}
+
+	downloadReq, err := http.NewRequestWithContext(ctx, "GET", obj.Actions.Download.Href, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating LFS download request: %w", err)
+	}
+	for k, v := range obj.Actions.Download.Header {
+		downloadReq.Header.Set(k, v)
+	}
+
+	downloadResp, err := http.DefaultClient.Do(downloadReq)
+	if err != nil {
+		return "", fmt.Errorf("downloading LFS object: %w", err)
+	}
+	defer downloadResp.Body.Close()
+	if downloadResp.StatusCode < 200 || downloadResp.StatusCode >= 300 {
+		return "", fmt.Errorf("downloading LFS object: unexpected status code %d", downloadResp.StatusCode)
+	}
+
+	// pointer.Size is known up front from the parsed pointer, so cap the read
+	// at one byte past it: a misbehaving or malicious LFS server can't make
+	// this buffer an unbounded amount of memory, and a response that's still
+	// too long after the cap is caught by the length check below rather than
+	// silently truncated and treated as complete.
+	content, err := io.ReadAll(io.LimitReader(downloadResp.Body, pointer.Size+1))
+	if err != nil {
+		return "", fmt.Errorf("reading LFS object content: %w", err)
+	}
+	if int64(len(content)) != pointer.Size {
+		return "", fmt.Errorf("LFS object size mismatch: pointer declared %d bytes, server returned %d", pointer.Size, len(content))
+	}
+	return string(content), nil
+}
+
+func getProvisioningRepositoryFileHistory(ctx context.Context, args GetProvisioningRepositoryFileHistoryParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	// Build the API path
+	encodedPath := url.QueryEscape(args.Path)
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/history/%s", args.RepositoryName, encodedPath)
+	if args.Ref != "" {
+		apiPath += fmt.Sprintf("?ref=%s", url.QueryEscape(args.Ref))
+	}
+
 	var response ProvisioningFileHistoryResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+	if err := client.GetJSON(ctx, apiPath, &response); err != nil {
+		if errors.Is(err, grafanahttp.ErrNotFound) {
+			return fmt.Sprintf("File '%s' not found in repository '%s' or no history available.", args.Path, args.RepositoryName), nil
+		}
+		return "", err
 	}
 
 	if len(response.Items) == 0 {
@@ -926,78 +1212,271 @@ func getProvisioningRepositoryFileHistory(ctx context.Context, args GetProvision
 	return strings.Join(rows, "\n"), nil
 }
 
-func manageProvisioningRepositoryFile(ctx context.Context, args ManageProvisioningRepositoryFileParams) (string, error) {
-	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+// fetchFileHistory retrieves path's commit history in repositoryName up to
+// ref, the same data getProvisioningRepositoryFileHistory formats for
+// display. found is false (with a nil error) when the provisioning API has
+// no history for path at all.
+func fetchFileHistory(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, path, ref string) (items []RepositoryFileHistory, found bool, err error) {
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return nil, false, err
+	}
 
-	// Encode the path for URL safety
-	encodedPath := url.QueryEscape(args.Path)
+	encodedPath := url.QueryEscape(path)
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/history/%s", repositoryName, encodedPath)
+	if ref != "" {
+		apiPath += fmt.Sprintf("?ref=%s", url.QueryEscape(ref))
+	}
 
-	// Prepare query parameters
-	params := url.Values{}
-	params.Set("message", args.Message)
-	if args.Branch != "" {
-		params.Set("ref", args.Branch)
+	var response ProvisioningFileHistoryResponse
+	if err := client.GetJSON(ctx, apiPath, &response); err != nil {
+		if errors.Is(err, grafanahttp.ErrNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if len(response.Items) == 0 {
+		return nil, false, nil
 	}
+	return response.Items, true, nil
+}
 
-	// Build the API path
-	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/files/%s?%s", args.RepositoryName, encodedPath, params.Encode())
+// errProvisioningFileNotText is returned by fetchProvisioningFileText when
+// path exists at ref but isn't usable as text: an LFS pointer that couldn't
+// be resolved (repository lookup or LFS object download failed), or content
+// -- pointer or resolved -- that isn't valid UTF-8. Diff and blame report
+// this to the caller instead of silently comparing or attributing the raw
+// 3-line pointer stub or binary bytes as if they were the file's real text.
+var errProvisioningFileNotText = errors.New("file content is binary or an unresolved LFS pointer, not text")
+
+// fetchProvisioningFileText retrieves path's content at ref as plain text,
+// resolving Git LFS pointers the same way getProvisioningRepositoryFileContent
+// does (returning errProvisioningFileNotText rather than that tool's
+// descriptive notes when resolution isn't possible or the result isn't
+// text). Unlike that tool, it returns the bare content with no metadata
+// prefix, since callers (diff, blame) compare or attribute it line by line
+// rather than display it directly. It checks the local shallow-clone cache
+// first, the same as fetchRepositoryFileContent. found is false (with a nil
+// error) when path doesn't exist at ref.
+func fetchProvisioningFileText(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, path, ref string) (content string, found bool, err error) {
+	raw, existed, ok := tryLocalFileContentCache(ctx, cfg, repositoryName, path, ref)
+	if !ok {
+		client, err := grafanahttp.New(cfg)
+		if err != nil {
+			return "", false, err
+		}
 
-	url := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+		encodedPath := url.QueryEscape(path)
+		apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/files/%s", repositoryName, encodedPath)
+		if ref != "" {
+			apiPath += fmt.Sprintf("?ref=%s", url.QueryEscape(ref))
+		}
 
-	var req *http.Request
-	var err error
+		var response FileContentResponse
+		if err := client.GetJSON(ctx, apiPath, &response); err != nil {
+			if errors.Is(err, grafanahttp.ErrNotFound) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		if response.Resource.File == nil {
+			return "", false, nil
+		}
 
-	switch args.Operation {
-	case "create":
-		req, err = http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(args.Content))
-	case "update":
-		req, err = http.NewRequestWithContext(ctx, "PUT", url, strings.NewReader(args.Content))
-	case "delete":
-		req, err = http.NewRequestWithContext(ctx, "DELETE", url, nil)
-	default:
-		return "", fmt.Errorf("invalid operation: %s. Must be create, update, or delete", args.Operation)
+		if pointerText, isString := response.Resource.File.(string); isString {
+			raw, existed = pointerText, true
+		} else {
+			encoded, err := json.MarshalIndent(response.Resource.File, "", "  ")
+			if err != nil {
+				return "", false, fmt.Errorf("encoding file content: %w", err)
+			}
+			return string(encoded), true, nil
+		}
+	}
+	if !existed {
+		return "", false, nil
+	}
+
+	pointer, isLFS := parseLFSPointer(raw)
+	if !isLFS {
+		if !utf8.ValidString(raw) {
+			return "", true, errProvisioningFileNotText
+		}
+		return raw, true, nil
 	}
+	repo, repoFound, err := lookupRepository(ctx, cfg, repositoryName)
+	if err != nil || !repoFound {
+		return "", true, errProvisioningFileNotText
+	}
+	resolved, err := resolveLFSPointer(ctx, repo, pointer)
+	if err != nil || !utf8.ValidString(resolved) {
+		return "", true, errProvisioningFileNotText
+	}
+	return resolved, true, nil
+}
+
+func getProvisioningRepositoryFileDiff(ctx context.Context, args GetProvisioningRepositoryFileDiffParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
 
+	fromContent, fromFound, err := fetchProvisioningFileText(ctx, cfg, args.RepositoryName, args.Path, args.FromRef)
+	if errors.Is(err, errProvisioningFileNotText) {
+		return fmt.Sprintf("Cannot diff '%s': content at %s is %v.", args.Path, args.FromRef, err), nil
+	}
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", fmt.Errorf("fetching %s at %s: %w", args.Path, args.FromRef, err)
+	}
+	toContent, toFound, err := fetchProvisioningFileText(ctx, cfg, args.RepositoryName, args.Path, args.ToRef)
+	if errors.Is(err, errProvisioningFileNotText) {
+		return fmt.Sprintf("Cannot diff '%s': content at %s is %v.", args.Path, args.ToRef, err), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("fetching %s at %s: %w", args.Path, args.ToRef, err)
+	}
+	if !fromFound && !toFound {
+		return fmt.Sprintf("File '%s' not found in repository '%s' at either ref.", args.Path, args.RepositoryName), nil
 	}
 
-	// Set content type for create/update operations
-	if args.Operation == "create" || args.Operation == "update" {
-		req.Header.Set("Content-Type", "application/json")
+	fromLabel := fmt.Sprintf("%s@%s", args.Path, args.FromRef)
+	if !fromFound {
+		fromLabel = "/dev/null"
+	}
+	toLabel := fmt.Sprintf("%s@%s", args.Path, args.ToRef)
+	if !toFound {
+		toLabel = "/dev/null"
 	}
 
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
+	diff := unifiedDiffContext(fromLabel, fromContent, toLabel, toContent, args.ContextLines)
+	if diff == "" {
+		return fmt.Sprintf("No differences in '%s' between %s and %s.", args.Path, args.FromRef, args.ToRef), nil
 	}
+	return diff, nil
+}
 
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
+// maxBlameHistoryRevisions bounds how many historical revisions
+// getProvisioningRepositoryFileBlame re-fetches and re-diffs to attribute
+// every line of the current content, since each revision costs a
+// fetchProvisioningFileText round trip. A file with a longer history than
+// this attributes its oldest surviving lines to the oldest revision this
+// limit still reaches, noted in the result rather than silently.
+const maxBlameHistoryRevisions = 50
+
+// blameLine is one line of a blamed file, annotated with the most recent
+// revision (from the oldest-to-newest walk in getProvisioningRepositoryFileBlame)
+// that introduced or last changed it.
+type blameLine struct {
+	line      string
+	ref       string
+	authors   []string
+	createdAt time.Time
+}
+
+func getProvisioningRepositoryFileBlame(ctx context.Context, args GetProvisioningRepositoryFileBlameParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	history, found, err := fetchFileHistory(ctx, cfg, args.RepositoryName, args.Path, args.Ref)
+	if err != nil {
+		return "", fmt.Errorf("fetching history for %s: %w", args.Path, err)
+	}
+	if !found {
+		return fmt.Sprintf("File '%s' not found in repository '%s' or no history available.", args.Path, args.RepositoryName), nil
+	}
+
+	// history is newest-first, matching get_provisioning_repository_file_history;
+	// blame needs to replay it oldest-first, re-diffing each successive
+	// revision against the last to see what it changed.
+	oldestFirst := make([]RepositoryFileHistory, len(history))
+	for i, h := range history {
+		oldestFirst[len(history)-1-i] = h
+	}
+	truncated := false
+	if len(oldestFirst) > maxBlameHistoryRevisions {
+		oldestFirst = oldestFirst[len(oldestFirst)-maxBlameHistoryRevisions:]
+		truncated = true
+	}
+
+	var blame []blameLine
+	var prevContent string
+	for _, h := range oldestFirst {
+		content, contentFound, err := fetchProvisioningFileText(ctx, cfg, args.RepositoryName, args.Path, h.Ref)
+		if err != nil && !errors.Is(err, errProvisioningFileNotText) {
+			return "", fmt.Errorf("fetching %s at %s: %w", args.Path, h.Ref, err)
+		}
+		if !contentFound || errors.Is(err, errProvisioningFileNotText) {
+			// Deleted, or binary/unresolved-LFS, at this revision; nothing
+			// to attribute until it's text again (or the walk ends) at a
+			// later revision.
+			prevContent = ""
+			blame = nil
+			continue
 		}
-		client.Transport = transport
+		blame = attributeLines(blame, prevContent, content, h)
+		prevContent = content
 	}
 
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
+	if len(blame) == 0 {
+		return fmt.Sprintf("File '%s' has no content to blame at the requested ref.", args.Path), nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Sprintf("Repository '%s' not found or file operation not supported.", args.RepositoryName), fmt.Errorf("repository not found")
+	var rows []string
+	header := fmt.Sprintf("Blame for '%s' (%d lines)", args.Path, len(blame))
+	if truncated {
+		header += fmt.Sprintf(", attribution limited to the most recent %d of %d history entries", maxBlameHistoryRevisions, len(history))
 	}
+	rows = append(rows, header)
+	for i, bl := range blame {
+		rows = append(rows, fmt.Sprintf("%4d | ref=%s | authors=%s | createdAt=%s | %s",
+			i+1, bl.ref, strings.Join(bl.authors, ","), bl.createdAt.Format(time.RFC3339), bl.line))
+	}
+	return strings.Join(rows, "\n"), nil
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// attributeLines re-diffs prevContent against content and returns content's
+// lines as blameLines: a line equal to one in prevContent keeps its
+// existing attribution from prevBlame (prevBlame is indexed the same way
+// prevContent's lines are), and any new or changed line is attributed to h,
+// the history entry whose commit produced content.
+func attributeLines(prevBlame []blameLine, prevContent, content string, h RepositoryFileHistory) []blameLine {
+	ops := diffLineOps(splitDiffLines(prevContent), splitDiffLines(content))
+
+	next := make([]blameLine, 0, len(splitDiffLines(content)))
+	prevIdx := 0
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			if prevIdx < len(prevBlame) {
+				next = append(next, prevBlame[prevIdx])
+			} else {
+				next = append(next, blameLine{line: op.line, ref: h.Ref, authors: h.Authors, createdAt: h.CreatedAt})
+			}
+			prevIdx++
+		case diffDelete:
+			prevIdx++
+		case diffInsert:
+			next = append(next, blameLine{line: op.line, ref: h.Ref, authors: h.Authors, createdAt: h.CreatedAt})
+		}
+	}
+	return next
+}
+
+func manageProvisioningRepositoryFile(ctx context.Context, args ManageProvisioningRepositoryFileParams) (string, error) {
+	switch args.Operation {
+	case "create", "update", "delete":
+	default:
+		return "", fmt.Errorf("invalid operation: %s. Must be create, update, or delete", args.Operation)
+	}
+
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	if args.DryRun {
+		return dryRunFileChange(ctx, cfg, args.RepositoryName, args.Branch, args.Operation, args.Path, args.Content)
+	}
+
+	hash, err := commitRepositoryFile(ctx, cfg, args.RepositoryName, args.Branch, args.Message, args.Operation, args.Path, args.Content)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return fmt.Sprintf("Repository '%s' not found or file operation not supported.", args.RepositoryName), err
+		}
+		return "", err
 	}
 
 	// Format success message
@@ -1016,115 +1495,578 @@ func manageProvisioningRepositoryFile(ctx context.Context, args ManageProvisioni
 		result += fmt.Sprintf(" on branch %s", args.Branch)
 	}
 	result += fmt.Sprintf("\n\nCommit message: %s", args.Message)
+	if hash != "" {
+		result += fmt.Sprintf("\nCommit hash: %s", hash)
+	}
 
 	return result, nil
 }
 
-// openURL opens the specified URL in the user's default browser
-func openURL(url string) error {
-	var cmd string
-	var args []string
+// dryRunFileChange previews a manageProvisioningRepositoryFile mutation
+// without writing anything: it diffs the file's current content at ref
+// against the proposed content, and summarizes the resource the change
+// would provision.
+func dryRunFileChange(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, ref, operation, path, content string) (string, error) {
+	oldContent, existed, err := fetchRepositoryFileContent(ctx, cfg, repositoryName, path, ref)
+	if err != nil {
+		return "", fmt.Errorf("reading current content: %w", err)
+	}
+	if !existed {
+		oldContent = ""
+	}
 
-	switch runtime.GOOS {
-	case "windows":
-		cmd = "cmd"
-		args = []string{"/c", "start"}
-	case "darwin":
-		cmd = "open"
-	default: // "linux", "freebsd", "openbsd", "netbsd"
-		cmd = "xdg-open"
+	oldLabel, newLabel, newContent := path, path, content
+	switch operation {
+	case "create":
+		oldLabel, oldContent = "/dev/null", ""
+	case "delete":
+		newLabel, newContent = "/dev/null", ""
 	}
-	args = append(args, url)
-	return exec.Command(cmd, args...).Start()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dry run: %s %s in repository '%s' (no changes written)\n\n", operation, path, repositoryName)
+	writeDiffPreview(&b, oldLabel, oldContent, newLabel, newContent)
+
+	return b.String(), nil
 }
 
-func manualSubmitGithubPullRequest(ctx context.Context, args ManualSubmitGithubPullRequestParams) (string, error) {
-	// Validate that the repository exists in the provisioning configuration
+// dryRunFileChanges previews a manageProvisioningRepositoryFiles commit
+// without writing anything, diffing and summarizing each change in the
+// order it would have been applied.
+func dryRunFileChanges(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, ref string, changes []FileChange) (string, error) {
+	var sections []string
+	for _, change := range changes {
+		readPath := change.Path
+		if change.Operation == "move" {
+			readPath = change.FromPath
+		}
+
+		oldContent, existed, err := fetchRepositoryFileContent(ctx, cfg, repositoryName, readPath, ref)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", readPath, err)
+		}
+		if !existed {
+			oldContent = ""
+		}
+
+		oldLabel, newLabel, newContent := change.Path, change.Path, change.Content
+		switch change.Operation {
+		case "create":
+			oldLabel, oldContent = "/dev/null", ""
+		case "delete":
+			newLabel, newContent = "/dev/null", ""
+		case "move":
+			oldLabel = change.FromPath
+			if newContent == "" {
+				newContent = oldContent
+			}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s %s:\n", change.Operation, change.Path)
+		writeDiffPreview(&b, oldLabel, oldContent, newLabel, newContent)
+		sections = append(sections, b.String())
+	}
+
+	header := fmt.Sprintf("Dry run: previewing %d file change(s) for repository '%s' (no changes written)\n", len(changes), repositoryName)
+	return header + "\n" + strings.Join(sections, "\n"), nil
+}
+
+// writeDiffPreview appends a unified diff between oldContent and
+// newContent to b, followed by a one-line summary of the resource
+// newContent would provision, if any.
+func writeDiffPreview(b *strings.Builder, oldLabel, oldContent, newLabel, newContent string) {
+	diff := unifiedDiff(oldLabel, oldContent, newLabel, newContent)
+	if diff == "" {
+		b.WriteString("No differences.\n")
+	} else {
+		b.WriteString("```diff\n")
+		b.WriteString(diff)
+		b.WriteString("```\n")
+	}
+
+	if summary := summarizeFileMutation(newLabel, newContent); summary != "" {
+		b.WriteString(summary)
+		b.WriteString("\n")
+	}
+
+	if resourceKindForPath(newLabel) == "dashboard" || resourceKindForPath(oldLabel) == "dashboard" {
+		if panelSummary, ok := dashboardPanelDiff(oldContent, newContent); ok && panelSummary != "" {
+			b.WriteString(panelSummary)
+			b.WriteString("\n")
+		}
+	}
+}
+
+const previewProvisioningRepositoryPRToolPrompt = `Preview a file change in a provisioning repository before writing it, without creating a commit or PR. Given a repository, path, and proposed content, this returns a unified diff against the file's current content at the given ref (or against /dev/null if the file doesn't exist yet), a one-line summary of the resource the change would provision, and -- for dashboard JSON files -- a structured summary of which panels were added, removed, or edited, keyed by panel id.
+
+Use this before manage_provisioning_repository_file or create_provisioning_repository_pr so the user can review exactly what would change. This tool never writes anything; it's equivalent to manage_provisioning_repository_file's dry_run mode but standalone, so previewing doesn't require deciding on a commit message or operation up front.`
+
+type PreviewProvisioningRepositoryPRParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Name of the provisioning repository to preview the change against"`
+	Path           string `json:"path" jsonschema:"required,description=Repository file path (e.g. \"dashboards/my-dashboard.json\")"`
+	Content        string `json:"content" jsonschema:"required,description=Proposed new content for the file. Pass an empty string to preview a deletion."`
+	Ref            string `json:"ref,omitempty" jsonschema:"description=Git reference (branch\\, tag\\, or commit hash) to diff against; defaults to the repository's configured branch"`
+}
+
+func previewProvisioningRepositoryPR(ctx context.Context, args PreviewProvisioningRepositoryPRParams) (string, error) {
 	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
 
-	// Construct the API URL
-	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s", args.RepositoryName)
-	requestURL := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+	oldContent, existed, err := fetchRepositoryFileContent(ctx, cfg, args.RepositoryName, args.Path, args.Ref)
+	if err != nil {
+		return "", fmt.Errorf("reading current content: %w", err)
+	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	oldLabel, newLabel := args.Path, args.Path
+	if !existed {
+		oldLabel, oldContent = "/dev/null", ""
+	}
+	if args.Content == "" {
+		newLabel = "/dev/null"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Preview: %s in repository '%s' (no changes written)\n\n", args.Path, args.RepositoryName)
+	writeDiffPreview(&b, oldLabel, oldContent, newLabel, args.Content)
+
+	return b.String(), nil
+}
+
+var PreviewProvisioningRepositoryPR = mcpgrafana.MustTool(
+	"preview_provisioning_repository_pr",
+	previewProvisioningRepositoryPRToolPrompt,
+	previewProvisioningRepositoryPR,
+	mcp.WithTitleAnnotation("Preview Provisioning Repository Change"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+// commitRepositoryFile performs a single create/update/delete call against
+// Grafana's per-file provisioning endpoint. It's the primitive both
+// manageProvisioningRepositoryFile and manageProvisioningRepositoryFiles
+// build on; the latter applies it once per FileChange to simulate an
+// atomic multi-file commit, since this endpoint has no bulk/tree-commit
+// equivalent.
+// commitRepositoryFile writes a single file change and returns the hash of
+// the commit it produced, if the provisioning API reported one (it doesn't
+// for every repository type, e.g. "local" repositories report an empty
+// hash).
+func commitRepositoryFile(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, branch, message, operation, path, content string) (hash string, err error) {
+	client, err := grafanahttp.New(cfg)
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", err
 	}
 
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
+	// Encode the path for URL safety
+	encodedPath := url.QueryEscape(path)
+
+	// Prepare query parameters
+	params := url.Values{}
+	params.Set("message", message)
+	if branch != "" {
+		params.Set("ref", branch)
 	}
 
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
-		}
-		client.Transport = transport
+	// Build the API path
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/files/%s?%s", repositoryName, encodedPath, params.Encode())
+
+	requestURL := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+
+	var req *http.Request
+
+	switch operation {
+	case "create":
+		req, err = http.NewRequest("POST", requestURL, strings.NewReader(content))
+	case "update":
+		req, err = http.NewRequest("PUT", requestURL, strings.NewReader(content))
+	case "delete":
+		req, err = http.NewRequest("DELETE", requestURL, nil)
+	default:
+		return "", fmt.Errorf("invalid operation: %s. Must be create, update, or delete", operation)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	// Set content type for create/update operations
+	if operation == "create" || operation == "update" {
+		req.Header.Set("Content-Type", "application/json")
 	}
 
 	// Make the request
-	resp, err := client.Do(req)
+	resp, err := client.Do(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return "", fmt.Errorf("repository '%s' not found", args.RepositoryName)
+		return "", fmt.Errorf("repository '%s' not found or file operation not supported", repositoryName)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Parse the response
-	var response RepositoryDetail
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+	// The write response mirrors FileContentResponse's shape; decoding it
+	// is best-effort since some repository types (e.g. "local") don't
+	// report a hash at all, and a delete's response body may be empty.
+	var written FileContentResponse
+	_ = json.NewDecoder(resp.Body).Decode(&written)
+
+	return written.Hash, nil
+}
+
+func validateFileChange(change FileChange) error {
+	if change.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	switch change.Operation {
+	case "create", "update":
+		if change.Content == "" {
+			return fmt.Errorf("content is required for %s operation", change.Operation)
+		}
+	case "delete":
+	case "move":
+		if change.FromPath == "" {
+			return fmt.Errorf("from_path is required for move operation")
+		}
+	default:
+		return fmt.Errorf("invalid operation: %s. Must be create, update, delete, or move", change.Operation)
+	}
+	return nil
+}
+
+// fetchRepositoryFileContent returns the raw content of a file at ref, and
+// whether it exists, so callers can snapshot it before mutating it and
+// restore it again on rollback.
+func fetchRepositoryFileContent(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, path, ref string) (content string, existed bool, err error) {
+	if content, existed, ok := tryLocalFileContentCache(ctx, cfg, repositoryName, path, ref); ok {
+		return content, existed, nil
+	}
+
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", false, err
+	}
+
+	encodedPath := url.QueryEscape(path)
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/files/%s", repositoryName, encodedPath)
+	if ref != "" {
+		apiPath += fmt.Sprintf("?ref=%s", url.QueryEscape(ref))
+	}
+
+	var response FileContentResponse
+	if err := client.GetJSON(ctx, apiPath, &response); err != nil {
+		if errors.Is(err, grafanahttp.ErrNotFound) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	if response.Resource.File == nil {
+		return "", false, nil
+	}
+
+	raw, err := json.Marshal(response.Resource.File)
+	if err != nil {
+		return "", false, fmt.Errorf("marshaling file content: %w", err)
+	}
+	return string(raw), true, nil
+}
+
+// applyFileChange performs one change of an atomic multi-file commit and
+// returns the FileChange that would undo it (so the caller can roll back a
+// partially-applied batch if a later change fails) plus the hash of the
+// commit it produced, if the provisioning API reported one.
+func applyFileChange(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, ref, message string, change FileChange) (FileChange, string, error) {
+	switch change.Operation {
+	case "create":
+		hash, err := commitRepositoryFile(ctx, cfg, repositoryName, ref, message, "create", change.Path, change.Content)
+		if err != nil {
+			return FileChange{}, "", err
+		}
+		return FileChange{Path: change.Path, Operation: "delete"}, hash, nil
+
+	case "update":
+		before, existed, err := fetchRepositoryFileContent(ctx, cfg, repositoryName, change.Path, ref)
+		if err != nil {
+			return FileChange{}, "", fmt.Errorf("reading current content: %w", err)
+		}
+		hash, err := commitRepositoryFile(ctx, cfg, repositoryName, ref, message, "update", change.Path, change.Content)
+		if err != nil {
+			return FileChange{}, "", err
+		}
+		if !existed {
+			return FileChange{Path: change.Path, Operation: "delete"}, hash, nil
+		}
+		return FileChange{Path: change.Path, Operation: "update", Content: before}, hash, nil
+
+	case "delete":
+		before, existed, err := fetchRepositoryFileContent(ctx, cfg, repositoryName, change.Path, ref)
+		if err != nil {
+			return FileChange{}, "", fmt.Errorf("reading current content: %w", err)
+		}
+		hash, err := commitRepositoryFile(ctx, cfg, repositoryName, ref, message, "delete", change.Path, "")
+		if err != nil {
+			return FileChange{}, "", err
+		}
+		if !existed {
+			return FileChange{}, hash, nil
+		}
+		return FileChange{Path: change.Path, Operation: "create", Content: before}, hash, nil
+
+	case "move":
+		before, _, err := fetchRepositoryFileContent(ctx, cfg, repositoryName, change.FromPath, ref)
+		if err != nil {
+			return FileChange{}, "", fmt.Errorf("reading source content: %w", err)
+		}
+		content := change.Content
+		if content == "" {
+			content = before
+		}
+		createHash, err := commitRepositoryFile(ctx, cfg, repositoryName, ref, message, "create", change.Path, content)
+		if err != nil {
+			return FileChange{}, "", err
+		}
+		if _, err := commitRepositoryFile(ctx, cfg, repositoryName, ref, message, "delete", change.FromPath, ""); err != nil {
+			// The destination was created but the source couldn't be removed;
+			// undo the create so the move doesn't half-apply.
+			if _, cleanupErr := commitRepositoryFile(ctx, cfg, repositoryName, ref, message, "delete", change.Path, ""); cleanupErr != nil {
+				return FileChange{}, "", fmt.Errorf("moving %s to %s: %w (cleanup of %s also failed: %v)", change.FromPath, change.Path, err, change.Path, cleanupErr)
+			}
+			return FileChange{}, "", fmt.Errorf("moving %s to %s: %w", change.FromPath, change.Path, err)
+		}
+		return FileChange{Path: change.FromPath, Operation: "create", Content: before}, createHash, nil
+
+	default:
+		return FileChange{}, "", fmt.Errorf("invalid operation: %s. Must be create, update, delete, or move", change.Operation)
 	}
+}
 
-	// Extract the GitHub URL from the repository configuration
-	githubURL := response.Spec.GitHub.URL
+// rollbackFileChanges undoes previously applied changes in reverse order,
+// best-effort, and reports every undo that failed rather than stopping at
+// the first one so the caller knows exactly what still needs manual cleanup.
+func rollbackFileChanges(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, ref, message string, applied []FileChange) error {
+	var errs []string
+	for i := len(applied) - 1; i >= 0; i-- {
+		undo := applied[i]
+		if undo.Path == "" {
+			continue
+		}
+		rollbackMessage := fmt.Sprintf("Rollback: %s", message)
+		if _, err := commitRepositoryFile(ctx, cfg, repositoryName, ref, rollbackMessage, undo.Operation, undo.Path, undo.Content); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", undo.Path, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("rollback incomplete for: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
 
-	// Validate that this is a GitHub repository
-	if !strings.Contains(strings.ToLower(githubURL), "github") {
-		return "", fmt.Errorf("repository '%s' is not a GitHub repository (URL: %s)", args.RepositoryName, githubURL)
+// tryServerSideBatchCommit attempts the provisioning API's bulk file-commit
+// endpoint, if this Grafana instance exposes one. It reports ok=false
+// (never an error) when the endpoint doesn't exist (404/405), so the
+// caller can fall back to the per-file sequence below -- most provisioning
+// backends, and every one this tool has been tested against so far, don't
+// implement it yet.
+func tryServerSideBatchCommit(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName, ref, message string, changes []FileChange) (hash string, ok bool, err error) {
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", false, err
 	}
 
-	// Create URL parameters for GitHub's compare page
 	params := url.Values{}
-	params.Set("expand", "1")
-	params.Set("title", args.Title)
-	params.Set("body", args.Body)
+	params.Set("message", message)
+	if ref != "" {
+		params.Set("ref", ref)
+	}
 
-	// Construct the GitHub compare URL
-	prURL := fmt.Sprintf("%s/compare/%s...%s?%s",
-		strings.TrimRight(githubURL, "/"),
-		args.BaseBranch,
-		args.HeadBranch,
-		params.Encode())
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/files:batch?%s", repositoryName, params.Encode())
+	requestURL := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+
+	body, err := json.Marshal(struct {
+		Changes []FileChange `json:"changes"`
+	}{Changes: changes})
+	if err != nil {
+		return "", false, fmt.Errorf("encoding batch request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", requestURL, bytes.NewReader(body))
+	if err != nil {
+		return "", false, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return "", false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", true, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var written FileContentResponse
+	_ = json.NewDecoder(resp.Body).Decode(&written)
+	return written.Hash, true, nil
+}
+
+// manageProvisioningRepositoryFiles applies a batch of file changes as a
+// single logical commit. It first tries the provisioning API's bulk
+// files:batch endpoint, and only falls back to the batched-sequence path
+// below if this instance doesn't expose one: changes are applied one at a
+// time, and the moment one fails every change applied so far in this call
+// is rolled back so the repository is never left half-migrated.
+func manageProvisioningRepositoryFiles(ctx context.Context, args ManageProvisioningRepositoryFilesParams) (string, error) {
+	if len(args.Changes) == 0 {
+		return "", fmt.Errorf("at least one change is required")
+	}
+	for i, change := range args.Changes {
+		if err := validateFileChange(change); err != nil {
+			return "", fmt.Errorf("change %d (%s): %w", i, change.Path, err)
+		}
+	}
+
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	if args.DryRun {
+		return dryRunFileChanges(ctx, cfg, args.RepositoryName, args.Ref, args.Changes)
+	}
+
+	if hash, ok, err := tryServerSideBatchCommit(ctx, cfg, args.RepositoryName, args.Ref, args.Message, args.Changes); ok {
+		if err != nil {
+			return "", fmt.Errorf("batch commit to repository '%s': %w", args.RepositoryName, err)
+		}
+		header := fmt.Sprintf("Committed %d file change(s) to repository '%s' via the bulk endpoint", len(args.Changes), args.RepositoryName)
+		if args.Ref != "" {
+			header += fmt.Sprintf(" on ref %s", args.Ref)
+		}
+		header += fmt.Sprintf("\n\nCommit message: %s\n", args.Message)
+		results := make([]string, len(args.Changes))
+		for i, change := range args.Changes {
+			results[i] = formatFileChangeResult(change, "applied", hash)
+		}
+		return header + strings.Join(results, "\n"), nil
+	}
+
+	var applied []FileChange
+	var results []string
+	for _, change := range args.Changes {
+		undo, hash, err := applyFileChange(ctx, cfg, args.RepositoryName, args.Ref, args.Message, change)
+		if err != nil {
+			results = append(results, formatFileChangeResult(change, fmt.Sprintf("failed: %s", err), ""))
+			header := fmt.Sprintf("Atomic commit to repository '%s' failed; rolling back %d prior change(s):", args.RepositoryName, len(applied))
+			if rollbackErr := rollbackFileChanges(ctx, cfg, args.RepositoryName, args.Ref, args.Message, applied); rollbackErr != nil {
+				return strings.Join(append([]string{header}, results...), "\n"), fmt.Errorf("applying change for %s: %w (rollback error: %v)", change.Path, err, rollbackErr)
+			}
+			return strings.Join(append([]string{header}, results...), "\n"), fmt.Errorf("applying change for %s: %w", change.Path, err)
+		}
+		applied = append(applied, undo)
+		results = append(results, formatFileChangeResult(change, "applied", hash))
+	}
+
+	header := fmt.Sprintf("Committed %d file change(s) to repository '%s'", len(args.Changes), args.RepositoryName)
+	if args.Ref != "" {
+		header += fmt.Sprintf(" on ref %s", args.Ref)
+	}
+	header += fmt.Sprintf("\n\nCommit message: %s\n", args.Message)
+
+	return header + strings.Join(results, "\n"), nil
+}
+
+// noBrowserMode is set at startup via --no-browser (or the
+// MCP_GRAFANA_NO_BROWSER environment variable). When true,
+// manualSubmitPullRequestImpl returns the pre-filled PR URL in its response
+// instead of trying to launch a browser, so headless/remote deployments
+// (SSE transport, containers, WSL without an X session) can still use the
+// manual-submit flow.
+var noBrowserMode bool
+
+// SetNoBrowserMode sets the process-wide no-browser flag. Called once at
+// startup from the --no-browser flag.
+func SetNoBrowserMode(noBrowser bool) {
+	noBrowserMode = noBrowser
+}
+
+func manualSubmitPullRequest(ctx context.Context, args ManualSubmitPullRequestParams) (string, error) {
+	return recoverHandlerFunc("manual_submit_pull_request", func() (string, error) {
+		return manualSubmitPullRequestImpl(ctx, args)
+	})
+}
+
+func manualSubmitPullRequestImpl(ctx context.Context, args ManualSubmitPullRequestParams) (string, error) {
+	// Validate that the repository exists in the provisioning configuration
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+
+	// Construct the API URL
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s", args.RepositoryName)
+
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	var response RepositoryDetail
+	if err := client.GetJSON(ctx, apiPath, &response); err != nil {
+		if errors.Is(err, grafanahttp.ErrNotFound) {
+			return "", fmt.Errorf("repository '%s' not found", args.RepositoryName)
+		}
+		return "", err
+	}
+
+	// Extract the repository URL from whichever VCS block the repository's
+	// Spec.Type actually populated (github, gitlab, bitbucket, azuredevops)
+	// and pick the provider that handles it, honoring an explicit override
+	// for self-hosted instances whose hostname doesn't name the forge.
+	repoURL := response.vcsBlock().URL
+
+	provider, err := resolvePRProvider(repoURL, args.Provider)
+	if err != nil {
+		return "", fmt.Errorf("repository '%s': %w", args.RepositoryName, err)
+	}
+
+	prURL := provider.BuildURL(PRProviderRequest{
+		RepoURL:    repoURL,
+		BaseBranch: args.BaseBranch,
+		HeadBranch: args.HeadBranch,
+		Title:      args.Title,
+		Body:       args.Body,
+	})
+
+	if noBrowserMode {
+		result := fmt.Sprintf("--no-browser is set; not opening a browser.\n\n🔗 %s pull request page: %s\n\n📋 Pre-filled Details:\n- Repository: %s\n- Title: %s\n- Body: %s\n- Base Branch: %s\n- Head Branch: %s\n\nOpen the URL above to review and submit the pull request manually on %s.",
+			provider.DisplayName(),
+			prURL,
+			args.RepositoryName,
+			args.Title,
+			args.Body,
+			args.BaseBranch,
+			args.HeadBranch,
+			provider.DisplayName())
+		return result, nil
+	}
 
 	// Open the URL in the default browser
-	if err := openURL(prURL); err != nil {
+	if err := browser.Open(prURL); err != nil {
 		return "", fmt.Errorf("failed to open browser: %w", err)
 	}
 
 	// Return success message with the URL
-	result := fmt.Sprintf("GitHub pull request page opened in your browser!\n\n🔗 PR URL: %s\n\n📋 Pre-filled Details:\n- Repository: %s\n- Title: %s\n- Body: %s\n- Base Branch: %s\n- Head Branch: %s\n\nYou can now review and submit the pull request manually on GitHub.",
+	result := fmt.Sprintf("%s pull request page opened in your browser!\n\n🔗 PR URL: %s\n\n📋 Pre-filled Details:\n- Repository: %s\n- Title: %s\n- Body: %s\n- Base Branch: %s\n- Head Branch: %s\n\nYou can now review and submit the pull request manually on %s.",
+		provider.DisplayName(),
 		prURL,
 		args.RepositoryName,
 		args.Title,
 		args.Body,
 		args.BaseBranch,
-		args.HeadBranch)
+		args.HeadBranch,
+		provider.DisplayName())
 
 	return result, nil
 }
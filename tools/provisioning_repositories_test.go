@@ -345,6 +345,102 @@ func TestProvisioningRepositoriesTools(t *testing.T) {
 		}
 	})
 
+	t.Run("resolve provisioning repository revision - branch name", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := resolveProvisioningRepositoryRevision(ctx, ResolveProvisioningRepositoryRevisionParams{
+			RepositoryName: "test-repo",
+			Revision:       "main",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "refKind=branch")
+		assert.Contains(t, result, "ref=refs/heads/main")
+	})
+
+	t.Run("resolve provisioning repository revision - fully qualified ref and peel expression", func(t *testing.T) {
+		ctx := newTestContext()
+
+		fullyQualified, err := resolveProvisioningRepositoryRevision(ctx, ResolveProvisioningRepositoryRevisionParams{
+			RepositoryName: "test-repo",
+			Revision:       "refs/heads/main",
+		})
+		require.NoError(t, err)
+
+		peeled, err := resolveProvisioningRepositoryRevision(ctx, ResolveProvisioningRepositoryRevisionParams{
+			RepositoryName: "test-repo",
+			Revision:       "main^{commit}",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, fullyQualified, peeled)
+	})
+
+	t.Run("resolve provisioning repository revision - unknown revision", func(t *testing.T) {
+		ctx := newTestContext()
+
+		_, err := resolveProvisioningRepositoryRevision(ctx, ResolveProvisioningRepositoryRevisionParams{
+			RepositoryName: "test-repo",
+			Revision:       "does-not-exist-branch-12345",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("resolve provisioning repository revision - nonexistent repository", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := resolveProvisioningRepositoryRevision(ctx, ResolveProvisioningRepositoryRevisionParams{
+			RepositoryName: "nonexistent-repo-12345",
+			Revision:       "main",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "not found")
+	})
+
+	t.Run("provisioning repository has branch - existing and missing branch", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := provisioningRepositoryHasBranch(ctx, ProvisioningRepositoryHasBranchParams{
+			RepositoryName: "test-repo",
+			BranchName:     "main",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "true")
+
+		result, err = provisioningRepositoryHasBranch(ctx, ProvisioningRepositoryHasBranchParams{
+			RepositoryName: "test-repo",
+			BranchName:     "does-not-exist-branch-12345",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "false")
+	})
+
+	t.Run("provisioning repository has branch - nonexistent repository", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := provisioningRepositoryHasBranch(ctx, ProvisioningRepositoryHasBranchParams{
+			RepositoryName: "nonexistent-repo-12345",
+			BranchName:     "main",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "not found")
+	})
+
+	t.Run("format repository revision function", func(t *testing.T) {
+		branchRevision := formatRepositoryRevision(RepositoryRevision{
+			SHA:     "abc123def456",
+			RefKind: "branch",
+			Ref:     "refs/heads/main",
+		})
+		assert.Equal(t, "- sha=abc123def456 | refKind=branch | ref=refs/heads/main", branchRevision)
+
+		commitRevision := formatRepositoryRevision(RepositoryRevision{
+			SHA:     "abc123def456",
+			RefKind: "commit",
+		})
+		assert.Equal(t, "- sha=abc123def456 | refKind=commit", commitRevision)
+	})
+
 	t.Run("get provisioning repository - nonexistent repository", func(t *testing.T) {
 		ctx := newTestContext()
 
@@ -438,6 +534,289 @@ func TestProvisioningRepositoriesTools(t *testing.T) {
 		}
 	})
 
+	t.Run("manage provisioning repository files - invalid operation in one change", func(t *testing.T) {
+		ctx := newTestContext()
+
+		_, err := manageProvisioningRepositoryFiles(ctx, ManageProvisioningRepositoryFilesParams{
+			RepositoryName: "test-repo",
+			Message:        "Test atomic commit",
+			Changes: []FileChange{
+				{Path: "dashboards/a.json", Operation: "create", Content: "{}"},
+				{Path: "dashboards/b.json", Operation: "invalid-operation"},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid operation")
+	})
+
+	t.Run("manage provisioning repository files - move requires from_path", func(t *testing.T) {
+		ctx := newTestContext()
+
+		_, err := manageProvisioningRepositoryFiles(ctx, ManageProvisioningRepositoryFilesParams{
+			RepositoryName: "test-repo",
+			Message:        "Test atomic commit",
+			Changes: []FileChange{
+				{Path: "dashboards/renamed.json", Operation: "move"},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "from_path is required")
+	})
+
+	t.Run("manage provisioning repository files - nonexistent repository", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := manageProvisioningRepositoryFiles(ctx, ManageProvisioningRepositoryFilesParams{
+			RepositoryName: "nonexistent-repo-12345",
+			Message:        "Test atomic commit",
+			Changes: []FileChange{
+				{Path: "test-file.json", Operation: "create", Content: `{"test": "content"}`},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, result, "failed")
+	})
+
+	t.Run("manage provisioning repository files - partial failure rolls back prior changes", func(t *testing.T) {
+		ctx := newTestContext()
+
+		// The first change targets a real repository and should apply
+		// cleanly; the second targets a repository that doesn't exist and
+		// should fail, forcing the first change to be rolled back.
+		result, err := manageProvisioningRepositoryFiles(ctx, ManageProvisioningRepositoryFilesParams{
+			RepositoryName: "test-repo",
+			Message:        "Test atomic commit with rollback",
+			Changes: []FileChange{
+				{Path: "dashboards/rollback-test.json", Operation: "create", Content: `{"test": "content"}`},
+				{Path: "dashboards/rollback-test.json", Operation: "update", Content: `{"test": "should not apply"}`},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, result, "rolling back")
+
+		// The rolled-back file should no longer exist.
+		history, histErr := getProvisioningRepositoryFileHistory(ctx, GetProvisioningRepositoryFileHistoryParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/rollback-test.json",
+		})
+		require.NoError(t, histErr)
+		assert.True(t,
+			assert.Contains(t, history, "not found") ||
+				assert.Contains(t, history, "No history found"),
+			"rolled-back file should not exist")
+	})
+
+	t.Run("manage provisioning repository files - move semantics", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := manageProvisioningRepositoryFiles(ctx, ManageProvisioningRepositoryFilesParams{
+			RepositoryName: "test-repo",
+			Message:        "Rename dashboard",
+			Changes: []FileChange{
+				{Path: "dashboards/new-name.json", Operation: "move", FromPath: "dashboards/old-name.json"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "path=dashboards/new-name.json")
+		assert.Contains(t, result, "from=dashboards/old-name.json")
+		assert.Contains(t, result, "op=move")
+		assert.Contains(t, result, "status=applied")
+	})
+
+	t.Run("manage provisioning repository file - dry run create", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := manageProvisioningRepositoryFile(ctx, ManageProvisioningRepositoryFileParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/dry-run-create.json",
+			Operation:      "create",
+			Content:        "{\"uid\": \"dry-run-create\", \"title\": \"Dry Run Create\"}\n",
+			Message:        "Test dry run create",
+			DryRun:         true,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "Dry run")
+		assert.Contains(t, result, "--- /dev/null")
+		assert.Contains(t, result, "+++ dashboards/dry-run-create.json")
+		assert.Contains(t, result, `+{"uid": "dry-run-create", "title": "Dry Run Create"}`)
+		assert.Contains(t, result, "Would provision dashboard")
+
+		// The file should not actually have been created.
+		history, histErr := getProvisioningRepositoryFileHistory(ctx, GetProvisioningRepositoryFileHistoryParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/dry-run-create.json",
+		})
+		require.NoError(t, histErr)
+		assert.True(t,
+			assert.Contains(t, history, "not found") ||
+				assert.Contains(t, history, "No history found"),
+			"dry run should not have created the file")
+	})
+
+	t.Run("manage provisioning repository file - dry run update and delete", func(t *testing.T) {
+		ctx := newTestContext()
+
+		// Create the file for real first, so the update/delete dry runs
+		// have something to diff against.
+		_, err := manageProvisioningRepositoryFile(ctx, ManageProvisioningRepositoryFileParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/dry-run-update.json",
+			Operation:      "create",
+			Content:        "{\"uid\": \"dry-run-update\", \"title\": \"Before\"}\n",
+			Message:        "Seed file for dry run update/delete test",
+		})
+		require.NoError(t, err)
+
+		updateResult, err := manageProvisioningRepositoryFile(ctx, ManageProvisioningRepositoryFileParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/dry-run-update.json",
+			Operation:      "update",
+			Content:        "{\"uid\": \"dry-run-update\", \"title\": \"After\"}\n",
+			Message:        "Test dry run update",
+			DryRun:         true,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, updateResult, "--- dashboards/dry-run-update.json")
+		assert.Contains(t, updateResult, "+++ dashboards/dry-run-update.json")
+		assert.Contains(t, updateResult, "-{\"uid\": \"dry-run-update\", \"title\": \"Before\"}")
+		assert.Contains(t, updateResult, "+{\"uid\": \"dry-run-update\", \"title\": \"After\"}")
+
+		deleteResult, err := manageProvisioningRepositoryFile(ctx, ManageProvisioningRepositoryFileParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/dry-run-update.json",
+			Operation:      "delete",
+			Message:        "Test dry run delete",
+			DryRun:         true,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, deleteResult, "+++ /dev/null")
+		assert.Contains(t, deleteResult, "-{\"uid\": \"dry-run-update\", \"title\": \"Before\"}")
+
+		// Neither dry run should have changed the file: it should still
+		// read back with its original content.
+		content, err := getProvisioningRepositoryFileContent(ctx, GetProvisioningRepositoryFileContentParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/dry-run-update.json",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, content, "Before")
+	})
+
+	t.Run("manage provisioning repository files - dry run batch", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := manageProvisioningRepositoryFiles(ctx, ManageProvisioningRepositoryFilesParams{
+			RepositoryName: "test-repo",
+			Message:        "Test dry run batch",
+			DryRun:         true,
+			Changes: []FileChange{
+				{Path: "dashboards/dry-run-batch-a.json", Operation: "create", Content: "{\"uid\": \"dry-run-batch-a\", \"title\": \"A\"}\n"},
+				{Path: "dashboards/dry-run-batch-b.json", Operation: "delete"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "Dry run")
+		assert.Contains(t, result, "create dashboards/dry-run-batch-a.json")
+		assert.Contains(t, result, "delete dashboards/dry-run-batch-b.json")
+		assert.Contains(t, result, "--- /dev/null")
+		assert.Contains(t, result, "+++ dashboards/dry-run-batch-a.json")
+
+		// Nothing in the batch should actually have been committed.
+		history, histErr := getProvisioningRepositoryFileHistory(ctx, GetProvisioningRepositoryFileHistoryParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/dry-run-batch-a.json",
+		})
+		require.NoError(t, histErr)
+		assert.True(t,
+			assert.Contains(t, history, "not found") ||
+				assert.Contains(t, history, "No history found"),
+			"dry run batch should not have created any file")
+	})
+
+	t.Run("preview provisioning repository pr", func(t *testing.T) {
+		ctx := newTestContext()
+
+		_, err := manageProvisioningRepositoryFile(ctx, ManageProvisioningRepositoryFileParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/preview-pr.json",
+			Operation:      "create",
+			Content:        `{"uid": "preview-pr", "title": "Before", "panels": [{"id": 1, "title": "CPU"}]}` + "\n",
+			Message:        "Seed file for preview PR test",
+		})
+		require.NoError(t, err)
+
+		result, err := previewProvisioningRepositoryPR(ctx, PreviewProvisioningRepositoryPRParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/preview-pr.json",
+			Content:        `{"uid": "preview-pr", "title": "After", "panels": [{"id": 1, "title": "CPU"}, {"id": 2, "title": "Memory"}]}` + "\n",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "Preview:")
+		assert.Contains(t, result, "--- dashboards/preview-pr.json")
+		assert.Contains(t, result, "+++ dashboards/preview-pr.json")
+		assert.Contains(t, result, "Would provision dashboard")
+		assert.Contains(t, result, "Panel changes: 1 added, 0 removed, 0 edited")
+		assert.Contains(t, result, `added: panel 2 ("Memory")`)
+
+		// Previewing never writes anything.
+		content, err := getProvisioningRepositoryFileContent(ctx, GetProvisioningRepositoryFileContentParams{
+			RepositoryName: "test-repo",
+			Path:           "dashboards/preview-pr.json",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, content, "Before")
+	})
+
+	t.Run("unified diff function", func(t *testing.T) {
+		diff := unifiedDiff("old.json", "{\"a\": 1}\n", "new.json", "{\"a\": 2}\n")
+		assert.Contains(t, diff, "--- old.json")
+		assert.Contains(t, diff, "+++ new.json")
+		assert.Contains(t, diff, `-{"a": 1}`)
+		assert.Contains(t, diff, `+{"a": 2}`)
+
+		// Identical content has no diff at all.
+		assert.Equal(t, "", unifiedDiff("a", "same\n", "b", "same\n"))
+	})
+
+	t.Run("summarize file mutation function", func(t *testing.T) {
+		summary := summarizeFileMutation("dashboards/example.json", `{"uid": "example", "title": "Example", "folderTitle": "General"}`)
+		assert.Equal(t, `Would provision dashboard "Example" (uid=example) in folder "General"`, summary)
+
+		assert.Equal(t, "", summarizeFileMutation("README.md", "not a resource"))
+	})
+
+	t.Run("dashboard panel diff function", func(t *testing.T) {
+		before := `{"uid": "d1", "panels": [{"id": 1, "title": "CPU"}, {"id": 2, "title": "Memory"}]}`
+		after := `{"uid": "d1", "panels": [{"id": 1, "title": "CPU (renamed)"}, {"id": 3, "title": "Disk"}]}`
+
+		summary, ok := dashboardPanelDiff(before, after)
+		require.True(t, ok)
+		assert.Contains(t, summary, "Panel changes: 1 added, 1 removed, 1 edited")
+		assert.Contains(t, summary, `added: panel 3 ("Disk")`)
+		assert.Contains(t, summary, `removed: panel 2 ("Memory")`)
+		assert.Contains(t, summary, `edited: panel 1 ("CPU (renamed)")`)
+
+		// Identical panels produce no summary.
+		same, ok := dashboardPanelDiff(before, before)
+		require.True(t, ok)
+		assert.Equal(t, "", same)
+
+		// Non-dashboard content isn't diffed at the panel level.
+		_, ok = dashboardPanelDiff("not json", after)
+		assert.False(t, ok)
+	})
+
+	t.Run("format file change result function", func(t *testing.T) {
+		created := formatFileChangeResult(FileChange{Path: "dashboards/a.json", Operation: "create"}, "applied", "")
+		assert.Equal(t, "- path=dashboards/a.json | op=create | status=applied", created)
+
+		moved := formatFileChangeResult(FileChange{Path: "dashboards/b.json", Operation: "move", FromPath: "dashboards/a.json"}, "applied", "")
+		assert.Equal(t, "- path=dashboards/b.json | op=move | from=dashboards/a.json | status=applied", moved)
+
+		withHash := formatFileChangeResult(FileChange{Path: "dashboards/a.json", Operation: "create"}, "applied", "abc123")
+		assert.Equal(t, "- path=dashboards/a.json | op=create | status=applied | hash=abc123", withHash)
+	})
+
 	t.Run("format repository branch function", func(t *testing.T) {
 		// Test the formatRepositoryBranch helper function directly
 		branch := RepositoryBranch{
@@ -470,24 +849,19 @@ func TestProvisioningRepositoriesTools(t *testing.T) {
 				Name: "test-repo",
 			},
 			Spec: struct {
-				Title  string `json:"title"`
-				Type   string `json:"type"`
-				GitHub struct {
-					URL    string `json:"url"`
-					Branch string `json:"branch"`
-					Path   string `json:"path"`
-				} `json:"github"`
-				Sync struct {
+				Title       string             `json:"title"`
+				Type        string             `json:"type"`
+				GitHub      repositoryVCSBlock `json:"github"`
+				GitLab      repositoryVCSBlock `json:"gitlab"`
+				Bitbucket   repositoryVCSBlock `json:"bitbucket"`
+				AzureDevOps repositoryVCSBlock `json:"azuredevops"`
+				Sync        struct {
 					Target string `json:"target"`
 				} `json:"sync"`
 			}{
 				Title: "Test Repository",
 				Type:  "git",
-				GitHub: struct {
-					URL    string `json:"url"`
-					Branch string `json:"branch"`
-					Path   string `json:"path"`
-				}{
+				GitHub: repositoryVCSBlock{
 					URL:    "https://github.com/test/repo",
 					Branch: "main",
 					Path:   "grafana",
@@ -514,6 +888,29 @@ func TestProvisioningRepositoriesTools(t *testing.T) {
 		// Should use pipe separator
 		assert.Contains(t, result, " | ")
 	})
+
+	t.Run("vcsBlock resolves the block matching Spec.Type", func(t *testing.T) {
+		gitlabDetail := RepositoryDetail{}
+		gitlabDetail.Spec.Type = "gitlab"
+		gitlabDetail.Spec.GitLab = repositoryVCSBlock{URL: "https://gitlab.com/test/repo", Branch: "main", Path: "grafana"}
+		gitlabDetail.Spec.GitHub = repositoryVCSBlock{URL: "https://github.com/should-not-be-used/repo"}
+
+		assert.Equal(t, "https://gitlab.com/test/repo", gitlabDetail.vcsBlock().URL)
+
+		azureDetail := RepositoryDetail{}
+		azureDetail.Spec.Type = "azuredevops"
+		azureDetail.Spec.AzureDevOps = repositoryVCSBlock{URL: "https://dev.azure.com/test/repo", Branch: "main"}
+
+		assert.Equal(t, "https://dev.azure.com/test/repo", azureDetail.vcsBlock().URL)
+	})
+
+	t.Run("vcsBlock falls back to whichever block has a URL when Type is unrecognized", func(t *testing.T) {
+		detail := RepositoryDetail{}
+		detail.Spec.Type = "git"
+		detail.Spec.Bitbucket = repositoryVCSBlock{URL: "https://bitbucket.org/test/repo", Branch: "main"}
+
+		assert.Equal(t, "https://bitbucket.org/test/repo", detail.vcsBlock().URL)
+	})
 }
 
 func TestManualSubmitGithubPullRequest(t *testing.T) {
@@ -554,7 +951,7 @@ func TestManualSubmitGithubPullRequest(t *testing.T) {
 			ctx := context.Background()
 
 			// Call the function
-			_, err := manualSubmitGithubPullRequest(ctx, tt.args)
+			_, err := manualSubmitPullRequest(ctx, tt.args)
 
 			// Check error conditions
 			if tt.wantErr {
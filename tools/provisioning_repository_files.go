@@ -1,10 +1,14 @@
 package tools
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -12,10 +16,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 
 	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/grafana/mcp-grafana/internal/grafanahttp"
 )
 
-func AddProvisioningRepositoryFilesTool(mcp *server.MCPServer) {
-	ListProvisioningRepositoryFiles.Register(mcp)
+// AddProvisioningRepositoryFilesTool registers the read-only provisioning
+// repository file tools. Write tools (create/update/delete) are only
+// registered when enableWriteTools is true, so read-only deployments can't
+// mutate GitOps-managed repositories through the MCP server. Both groups
+// are further filtered by allowed, the same as every other AddXxxTools
+// function.
+func AddProvisioningRepositoryFilesTool(mcp *server.MCPServer, enableWriteTools bool, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	mcpgrafana.RegisterTool(mcp, allowed, read, ListProvisioningRepositoryFiles)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetProvisioningRepositoryFile)
+	mcpgrafana.RegisterTool(mcp, allowed, read, GetProvisioningRepositoryStatus)
+
+	if enableWriteTools {
+		write := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryWrite}
+		destructive := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryWrite | mcpgrafana.ToolCategoryDestructive}
+		mcpgrafana.RegisterTool(mcp, allowed, write, CreateOrUpdateProvisioningRepositoryFile)
+		mcpgrafana.RegisterTool(mcp, allowed, destructive, DeleteProvisioningRepositoryFile)
+	}
 }
 
 const listProvisioningRepositoryFilesToolPrompt = `List files within a specific Git repository configured for this Grafana instance. This tool shows all files present in the repository at the configured path and branch, including dashboards and folders. IMPORTANT: If any files are found, it means this Grafana instance IS managed by Git (GitOps). If no files are found, the repository may be empty or the instance is NOT Git-managed. Repository files are used for managing Grafana configuration as code (dashboards, datasources, etc.) through Git version control. Requires a repository_name parameter for exact matching, and optionally supports filtering by file path using regex patterns.`
@@ -29,11 +50,81 @@ var ListProvisioningRepositoryFiles = mcpgrafana.MustTool(
 	mcp.WithReadOnlyHintAnnotation(true),
 )
 
+const getProvisioningRepositoryFileToolPrompt = `Retrieve a single file from a Git repository configured for this Grafana instance, including its content, Git hash, and source URL. The returned hash can be passed as expected_hash to create_or_update_provisioning_repository_file or delete_provisioning_repository_file to guard against overwriting concurrent changes (optimistic concurrency via If-Match). Supports an optional ref parameter to read the file from a specific branch, tag, or commit instead of the repository's default branch.`
+
+var GetProvisioningRepositoryFile = mcpgrafana.MustTool(
+	"get_provisioning_repository_file",
+	getProvisioningRepositoryFileToolPrompt,
+	getProvisioningRepositoryFile,
+	mcp.WithTitleAnnotation("Get Provisioning Repository File"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
+const createOrUpdateProvisioningRepositoryFileToolPrompt = `Create or update a file in a Git repository configured for this Grafana instance, committing the change to version control. Dashboard and other JSON configuration should be passed as raw JSON in content; for binary files, base64-encode the data and set content_is_base64. Supports an optional ref to write to a specific branch instead of the repository's default branch, and an optional expected_hash (from get_provisioning_repository_file or list_provisioning_repository_files) sent as an If-Match header so the write is rejected if the file changed since it was last read.`
+
+var CreateOrUpdateProvisioningRepositoryFile = mcpgrafana.MustTool(
+	"create_or_update_provisioning_repository_file",
+	createOrUpdateProvisioningRepositoryFileToolPrompt,
+	createOrUpdateProvisioningRepositoryFile,
+	mcp.WithTitleAnnotation("Create or Update Provisioning Repository File"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+const deleteProvisioningRepositoryFileToolPrompt = `Delete a file from a Git repository configured for this Grafana instance, committing the removal to version control. Supports an optional ref to delete from a specific branch instead of the repository's default branch, and an optional expected_hash sent as an If-Match header so the delete is rejected if the file changed since it was last read.`
+
+var DeleteProvisioningRepositoryFile = mcpgrafana.MustTool(
+	"delete_provisioning_repository_file",
+	deleteProvisioningRepositoryFileToolPrompt,
+	deleteProvisioningRepositoryFile,
+	mcp.WithTitleAnnotation("Delete Provisioning Repository File"),
+	mcp.WithDestructiveHintAnnotation(true),
+)
+
+const getProvisioningRepositoryStatusToolPrompt = `Retrieve the sync status of a Git repository configured for this Grafana instance: whether the last sync succeeded, the ref and hash it synced to, and the health of the repository's background sync job. Use this to check whether recent file changes have been picked up by Grafana's provisioning/Git Sync controllers yet.`
+
+var GetProvisioningRepositoryStatus = mcpgrafana.MustTool(
+	"get_provisioning_repository_status",
+	getProvisioningRepositoryStatusToolPrompt,
+	getProvisioningRepositoryStatus,
+	mcp.WithTitleAnnotation("Get Provisioning Repository Status"),
+	mcp.WithIdempotentHintAnnotation(true),
+	mcp.WithReadOnlyHintAnnotation(true),
+)
+
 type ListProvisioningRepositoryFilesParams struct {
 	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
 	Path           string `json:"path,omitempty" jsonschema:"description=Repository file path (can be a javascript regex pattern)"`
 }
 
+type GetProvisioningRepositoryFileParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+	Path           string `json:"path" jsonschema:"required,description=Repository file path relative to the repository root (e.g. \"dashboards/my-dashboard.json\")"`
+	Ref            string `json:"ref,omitempty" jsonschema:"description=Git reference (branch\\, tag\\, or commit hash) to read the file from. Defaults to the repository's default branch"`
+}
+
+type CreateOrUpdateProvisioningRepositoryFileParams struct {
+	RepositoryName  string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+	Path            string `json:"path" jsonschema:"required,description=Repository file path relative to the repository root (e.g. \"dashboards/my-dashboard.json\")"`
+	Ref             string `json:"ref,omitempty" jsonschema:"description=Git branch\\, tag\\, or commit to write to. If not specified\\, the change is pushed to the repository's default branch"`
+	Content         string `json:"content" jsonschema:"required,description=File content. For dashboards and other JSON configuration\\, provide raw JSON text. For binary files\\, base64-encode the data and set content_is_base64"`
+	ContentIsBase64 bool   `json:"content_is_base64,omitempty" jsonschema:"description=Set to true if content is base64-encoded binary data rather than raw JSON text"`
+	Message         string `json:"message" jsonschema:"required,description=Commit message describing the change"`
+	ExpectedHash    string `json:"expected_hash,omitempty" jsonschema:"description=Expected current file hash\\, as returned by get_provisioning_repository_file. When set\\, sent as an If-Match header so the write fails instead of silently overwriting a concurrent change"`
+}
+
+type DeleteProvisioningRepositoryFileParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+	Path           string `json:"path" jsonschema:"required,description=Repository file path relative to the repository root"`
+	Ref            string `json:"ref,omitempty" jsonschema:"description=Git branch\\, tag\\, or commit to delete from. If not specified\\, the change is pushed to the repository's default branch"`
+	Message        string `json:"message" jsonschema:"required,description=Commit message describing the deletion"`
+	ExpectedHash   string `json:"expected_hash,omitempty" jsonschema:"description=Expected current file hash\\, as returned by get_provisioning_repository_file. When set\\, sent as an If-Match header so the delete fails instead of silently removing a concurrent change"`
+}
+
+type GetProvisioningRepositoryStatusParams struct {
+	RepositoryName string `json:"repository_name" jsonschema:"required,description=Repository name for exact matching"`
+}
+
 type RepositoryFile struct {
 	Path string `json:"path"`
 	Size int64  `json:"size"`
@@ -44,6 +135,20 @@ type ProvisioningRepositoryFilesResponse struct {
 	Items []RepositoryFile `json:"items"`
 }
 
+type RepositoryStatus struct {
+	Sync struct {
+		State      string `json:"state"`
+		Message    string `json:"message,omitempty"`
+		Ref        string `json:"ref,omitempty"`
+		Hash       string `json:"hash,omitempty"`
+		Incomplete bool   `json:"incomplete,omitempty"`
+	} `json:"sync"`
+	Health struct {
+		Healthy bool     `json:"healthy"`
+		Message []string `json:"message,omitempty"`
+	} `json:"health"`
+}
+
 func formatRepositoryFile(f RepositoryFile) string {
 	// Format size in a human-readable way
 	sizeStr := formatFileSize(f.Size)
@@ -63,65 +168,96 @@ func formatFileSize(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-func listProvisioningRepositoryFiles(ctx context.Context, args ListProvisioningRepositoryFilesParams) (string, error) {
-	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+func formatRepositoryStatus(repositoryName string, s RepositoryStatus) string {
+	parts := []string{
+		fmt.Sprintf("repository=%s", repositoryName),
+		fmt.Sprintf("sync.state=%s", s.Sync.State),
+	}
+	if s.Sync.Ref != "" {
+		parts = append(parts, fmt.Sprintf("sync.ref=%s", s.Sync.Ref))
+	}
+	if s.Sync.Hash != "" {
+		parts = append(parts, fmt.Sprintf("sync.hash=%s", s.Sync.Hash))
+	}
+	if s.Sync.Message != "" {
+		parts = append(parts, fmt.Sprintf("sync.message=%s", s.Sync.Message))
+	}
+	parts = append(parts, fmt.Sprintf("health.healthy=%t", s.Health.Healthy))
+	if len(s.Health.Message) > 0 {
+		parts = append(parts, fmt.Sprintf("health.message=%s", strings.Join(s.Health.Message, "; ")))
+	}
+	return fmt.Sprintf("- %s", strings.Join(parts, " | "))
+}
 
-	// Construct the API URL with the repository name
-	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/files", args.RepositoryName)
-	url := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+// provisioningRequest builds and issues a request against the provisioning
+// API for the given repository sub-resource path (e.g.
+// "files/dashboards%2Ffoo.json" or "files/dashboards%2Ffoo.json/status"),
+// applying query params and any extra headers on top of client's cached,
+// retrying transport and auth headers, so repeated calls across tool
+// invocations share one connection pool instead of each building its own.
+func provisioningRequest(ctx context.Context, cfg mcpgrafana.GrafanaConfig, client *grafanahttp.Client, method, repositoryName, subPath string, query url.Values, headers map[string]string, body io.Reader) (*http.Response, error) {
+	apiPath := fmt.Sprintf("/apis/provisioning.grafana.app/v0alpha1/namespaces/default/repositories/%s/%s", repositoryName, subPath)
+	reqURL := fmt.Sprintf("%s%s", strings.TrimRight(cfg.URL, "/"), apiPath)
+	if len(query) > 0 {
+		reqURL = fmt.Sprintf("%s?%s", reqURL, query.Encode())
+	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := http.NewRequest(method, reqURL, body)
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
 	}
-
-	// Add authorization header
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-	} else if cfg.AccessToken != "" && cfg.IDToken != "" {
-		req.Header.Set("X-Access-Token", cfg.AccessToken)
-		req.Header.Set("X-Grafana-Id", cfg.IDToken)
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
 
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport, err := tlsConfig.HTTPTransport(http.DefaultTransport.(*http.Transport))
-		if err != nil {
-			return "", fmt.Errorf("failed to create custom transport: %w", err)
-		}
-		client.Transport = transport
+	return client.Do(ctx, req)
+}
+
+// fetchRepositoryFiles retrieves the file inventory of a single
+// provisioning repository. found is false (with a nil error) when the
+// repository itself doesn't exist.
+func fetchRepositoryFiles(ctx context.Context, cfg mcpgrafana.GrafanaConfig, repositoryName string) (files []RepositoryFile, found bool, err error) {
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return nil, false, err
 	}
 
-	// Make the request
-	resp, err := client.Do(req)
+	resp, err := provisioningRequest(ctx, cfg, client, "GET", repositoryName, "files", nil, nil, nil)
 	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
+		return nil, false, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Sprintf("Repository '%s' not found or does not exist.", args.RepositoryName), nil
+		return nil, false, nil
 	}
-
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Parse the response
 	var response ProvisioningRepositoryFilesResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+		return nil, false, fmt.Errorf("decoding response: %w", err)
+	}
+	return response.Items, true, nil
+}
+
+func listProvisioningRepositoryFiles(ctx context.Context, args ListProvisioningRepositoryFilesParams) (string, error) {
+	files, found, err := fetchRepositoryFiles(ctx, mcpgrafana.GrafanaConfigFromContext(ctx), args.RepositoryName)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("Repository '%s' not found or does not exist.", args.RepositoryName), nil
 	}
 
 	// If no files found
-	if len(response.Items) == 0 {
+	if len(files) == 0 {
 		return "Repository is empty", nil
 	}
 
 	// Apply path filter if provided
-	filtered := response.Items
+	filtered := files
 
 	if args.Path != "" {
 		var pathFiltered []RepositoryFile
@@ -162,3 +298,173 @@ func listProvisioningRepositoryFiles(ctx context.Context, args ListProvisioningR
 
 	return strings.Join(rows, "\n"), nil
 }
+
+func getProvisioningRepositoryFile(ctx context.Context, args GetProvisioningRepositoryFileParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	if args.Ref != "" {
+		query.Set("ref", args.Ref)
+	}
+
+	subPath := fmt.Sprintf("files/%s", url.QueryEscape(args.Path))
+	resp, err := provisioningRequest(ctx, cfg, client, "GET", args.RepositoryName, subPath, query, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Sprintf("File '%s' not found in repository '%s'.", args.Path, args.RepositoryName), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response FileContentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+
+	content, err := json.Marshal(response.Resource.File)
+	if err != nil {
+		return "", fmt.Errorf("encoding file content: %w", err)
+	}
+
+	return fmt.Sprintf("path=%s | ref=%s | hash=%s | sourceURL=%s\n\n%s",
+		args.Path, response.Ref, response.Hash, response.URLs.SourceURL, string(content)), nil
+}
+
+func createOrUpdateProvisioningRepositoryFile(ctx context.Context, args CreateOrUpdateProvisioningRepositoryFileParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	var bodyBytes []byte
+	contentType := "application/json"
+	if args.ContentIsBase64 {
+		bodyBytes, err = base64.StdEncoding.DecodeString(args.Content)
+		if err != nil {
+			return "", fmt.Errorf("decoding base64 content: %w", err)
+		}
+		contentType = "application/octet-stream"
+	} else {
+		bodyBytes = []byte(args.Content)
+	}
+
+	query := url.Values{}
+	query.Set("message", args.Message)
+	if args.Ref != "" {
+		query.Set("ref", args.Ref)
+	}
+
+	headers := map[string]string{"Content-Type": contentType}
+	if args.ExpectedHash != "" {
+		headers["If-Match"] = args.ExpectedHash
+	}
+
+	subPath := fmt.Sprintf("files/%s", url.QueryEscape(args.Path))
+	resp, err := provisioningRequest(ctx, cfg, client, "PUT", args.RepositoryName, subPath, query, headers, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed || resp.StatusCode == http.StatusConflict {
+		return "", fmt.Errorf("file '%s' was modified since expected_hash was read; re-fetch it and retry", args.Path)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	result := fmt.Sprintf("File %s written successfully", args.Path)
+	if args.Ref != "" {
+		result += fmt.Sprintf(" on ref %s", args.Ref)
+	}
+	result += fmt.Sprintf("\n\nCommit message: %s", args.Message)
+
+	return result, nil
+}
+
+func deleteProvisioningRepositoryFile(ctx context.Context, args DeleteProvisioningRepositoryFileParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("message", args.Message)
+	if args.Ref != "" {
+		query.Set("ref", args.Ref)
+	}
+
+	var headers map[string]string
+	if args.ExpectedHash != "" {
+		headers = map[string]string{"If-Match": args.ExpectedHash}
+	}
+
+	subPath := fmt.Sprintf("files/%s", url.QueryEscape(args.Path))
+	resp, err := provisioningRequest(ctx, cfg, client, "DELETE", args.RepositoryName, subPath, query, headers, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Sprintf("File '%s' not found in repository '%s'.", args.Path, args.RepositoryName), nil
+	}
+
+	if resp.StatusCode == http.StatusPreconditionFailed || resp.StatusCode == http.StatusConflict {
+		return "", fmt.Errorf("file '%s' was modified since expected_hash was read; re-fetch it and retry", args.Path)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	result := fmt.Sprintf("File %s deleted successfully", args.Path)
+	if args.Ref != "" {
+		result += fmt.Sprintf(" on ref %s", args.Ref)
+	}
+	result += fmt.Sprintf("\n\nCommit message: %s", args.Message)
+
+	return result, nil
+}
+
+func getProvisioningRepositoryStatus(ctx context.Context, args GetProvisioningRepositoryStatusParams) (string, error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	client, err := grafanahttp.New(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := provisioningRequest(ctx, cfg, client, "GET", args.RepositoryName, "status", nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Sprintf("Repository '%s' not found or does not exist.", args.RepositoryName), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var status RepositoryStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+
+	return formatRepositoryStatus(args.RepositoryName, status), nil
+}
@@ -0,0 +1,89 @@
+// Requires a Grafana instance running on localhost:3000,
+// with provisioning repositories configured (if testing Git-managed instance).
+// Run with `go test -tags integration`.
+//go:build integration
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvisioningRepositoryFilesTools(t *testing.T) {
+	t.Run("get provisioning repository file - nonexistent repository", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := getProvisioningRepositoryFile(ctx, GetProvisioningRepositoryFileParams{
+			RepositoryName: "nonexistent-repo-12345",
+			Path:           "dashboards/does-not-exist.json",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "not found")
+	})
+
+	t.Run("get provisioning repository status - nonexistent repository", func(t *testing.T) {
+		ctx := newTestContext()
+
+		result, err := getProvisioningRepositoryStatus(ctx, GetProvisioningRepositoryStatusParams{
+			RepositoryName: "nonexistent-repo-12345",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, result, "not found")
+	})
+
+	t.Run("create or update provisioning repository file - nonexistent repository", func(t *testing.T) {
+		ctx := newTestContext()
+
+		_, err := createOrUpdateProvisioningRepositoryFile(ctx, CreateOrUpdateProvisioningRepositoryFileParams{
+			RepositoryName: "nonexistent-repo-12345",
+			Path:           "dashboards/test-file.json",
+			Content:        `{"test": "content"}`,
+			Message:        "Test commit",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("create or update provisioning repository file - invalid base64 content", func(t *testing.T) {
+		ctx := newTestContext()
+
+		_, err := createOrUpdateProvisioningRepositoryFile(ctx, CreateOrUpdateProvisioningRepositoryFileParams{
+			RepositoryName:  "nonexistent-repo-12345",
+			Path:            "assets/logo.png",
+			Content:         "not-valid-base64!!!",
+			ContentIsBase64: true,
+			Message:         "Test commit",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decoding base64 content")
+	})
+
+	t.Run("delete provisioning repository file - nonexistent repository", func(t *testing.T) {
+		ctx := newTestContext()
+
+		_, err := deleteProvisioningRepositoryFile(ctx, DeleteProvisioningRepositoryFileParams{
+			RepositoryName: "nonexistent-repo-12345",
+			Path:           "dashboards/test-file.json",
+			Message:        "Test deletion",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("format repository status function", func(t *testing.T) {
+		status := RepositoryStatus{}
+		status.Sync.State = "success"
+		status.Sync.Ref = "main"
+		status.Sync.Hash = "abc123"
+		status.Health.Healthy = true
+
+		result := formatRepositoryStatus("test-repo", status)
+
+		assert.Contains(t, result, "repository=test-repo")
+		assert.Contains(t, result, "sync.state=success")
+		assert.Contains(t, result, "sync.ref=main")
+		assert.Contains(t, result, "sync.hash=abc123")
+		assert.Contains(t, result, "health.healthy=true")
+	})
+}
@@ -3,27 +3,57 @@ package tools
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	mcpgrafana "github.com/grafana/mcp-grafana"
+	"github.com/grafana/mcp-grafana/internal/proxy"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// ProxyConfig holds configuration for proxy handlers
+// ProxyTypeConfig holds the proxy settings for a single datasource type
+// (whether it's enabled, how often it's polled for discovery, and how
+// callMCP retries transient failures against it).
+type ProxyTypeConfig struct {
+	Enabled         bool
+	PollingInterval time.Duration
+	RetryPolicy     RetryPolicy
+	// MaxResponseBytes caps a proxied MCP response body for this type. Zero
+	// or less uses DefaultMaxResponseBytes.
+	MaxResponseBytes int64
+	// ToolsCacheDuration bounds how long ensureSession reuses a cached
+	// tools/list result before re-issuing it upstream (with an
+	// If-None-Match revalidation). Zero or less caches indefinitely,
+	// until CleanupStaleSessions evicts the session or the datasource's
+	// URL changes.
+	ToolsCacheDuration time.Duration
+}
+
+// ProxyConfig holds configuration for proxy handlers, keyed by datasource
+// type (e.g. "tempo", "loki"). Use ForType to read a type's settings; it
+// falls back to that type's registered environment-derived defaults (see
+// RegisterProxyDatasourceType) for any type not explicitly present here.
 type ProxyConfig struct {
-	// Tempo-specific configuration
-	TempoEnabled       bool
-	TempoPollingInterval time.Duration
+	Types map[string]ProxyTypeConfig
+}
+
+// ForType returns dsType's proxy configuration, falling back to the
+// defaults registered for it via RegisterProxyDatasourceType (or
+// enabled-with-a-5-minute-poll, for a type that never registered any).
+func (c ProxyConfig) ForType(dsType string) ProxyTypeConfig {
+	if cfg, ok := c.Types[dsType]; ok {
+		return cfg
+	}
+	return defaultProxyTypeConfig(dsType)
 }
 
 // proxyConfigKey is the context key for proxy configuration
@@ -38,11 +68,10 @@ func WithProxyConfig(ctx context.Context, config ProxyConfig) context.Context {
 func ProxyConfigFromContext(ctx context.Context) ProxyConfig {
 	config, ok := ctx.Value(proxyConfigKey{}).(ProxyConfig)
 	if !ok {
-		// Return default configuration
-		return ProxyConfig{
-			TempoEnabled:         os.Getenv("TEMPO_PROXY_ENABLED") != "false",
-			TempoPollingInterval: 5 * time.Minute,
-		}
+		// No config was installed (e.g. a direct call outside
+		// AddProxiedTools); resolve every known type from its registered
+		// environment defaults.
+		return ProxyConfig{Types: defaultProxyConfigTypes()}
 	}
 	return config
 }
@@ -53,12 +82,52 @@ type ProxyHandler interface {
 	Initialize(ctx context.Context, mcp *server.MCPServer)
 	// Shutdown cleans up resources for this datasource type
 	Shutdown()
+	// OnDatasourceAdded is called by the legacy discovery reconciler (see
+	// reconcileDatasourceType) when a datasource of this handler's type is
+	// seen for the first time, so the handler can register its tools
+	// immediately rather than waiting out its own next poll. Takes only
+	// the UID/ID rather than the full ProxyDatasource so implementations
+	// outside this package (which can't import it without a cycle) can
+	// satisfy the interface.
+	OnDatasourceAdded(ctx context.Context, datasourceUID string, datasourceID int64)
+	// OnDatasourceRemoved is called when a previously seen datasource of
+	// this handler's type disappears from Grafana, after its session has
+	// already been torn down.
+	OnDatasourceRemoved(ctx context.Context, datasourceUID string)
+}
+
+// ProxyDatasourceTypeOptions describes how a datasource type's proxy
+// settings (enabled/polling interval) are derived from the environment
+// when a caller doesn't install an explicit ProxyConfig. A backend
+// adapter registers these alongside its ProxyHandler so new datasource
+// types become configurable without any change here.
+type ProxyDatasourceTypeOptions struct {
+	// EnvPrefix is the env var prefix for this type's settings, e.g.
+	// "TEMPO" for TEMPO_PROXY_ENABLED / TEMPO_POLLING_INTERVAL. Empty
+	// means always-enabled with DefaultPollingInterval.
+	EnvPrefix string
+	// DefaultPollingInterval is used when {EnvPrefix}_POLLING_INTERVAL is
+	// unset, empty, or fails to parse. Zero means 5 minutes.
+	DefaultPollingInterval time.Duration
+	// RetryPolicy is the retry budget callMCP applies to calls against this
+	// datasource type. The zero value uses DefaultRetryPolicy.
+	RetryPolicy RetryPolicy
+	// DefaultMaxResponseBytes caps a proxied MCP response body for this
+	// type, overridable per deployment via {EnvPrefix}_MAX_RESPONSE_BYTES.
+	// Zero or less uses DefaultMaxResponseBytes, for a backend whose
+	// responses don't warrant a higher ceiling than the global default.
+	DefaultMaxResponseBytes int64
+	// DefaultToolsCacheDuration is used when {EnvPrefix}_TOOLS_CACHE_DURATION
+	// is unset, empty, or fails to parse. Zero or less caches tools/list
+	// results indefinitely.
+	DefaultToolsCacheDuration time.Duration
 }
 
-// Registry of proxy handlers by datasource type
+// Registry of proxy handlers and their type options, keyed by datasource type
 var (
-	proxyHandlers = make(map[string]ProxyHandler)
-	handlersMutex sync.RWMutex
+	proxyHandlers    = make(map[string]ProxyHandler)
+	proxyTypeOptions = make(map[string]ProxyDatasourceTypeOptions)
+	handlersMutex    sync.RWMutex
 )
 
 // RegisterProxyHandler registers a handler for a specific datasource type
@@ -68,19 +137,119 @@ func RegisterProxyHandler(datasourceType string, handler ProxyHandler) {
 	proxyHandlers[datasourceType] = handler
 }
 
-// AddProxiedTools initializes all registered proxy handlers
-func AddProxiedTools(mcp *server.MCPServer) {
+// RegisterProxyDatasourceType declares how datasourceType's proxy settings
+// are read from the environment, so the default ProxyConfig built by
+// AddProxiedTools (and ProxyConfigFromContext, when no config was
+// installed) covers it without touching either. Backend adapters call
+// this from their package init alongside RegisterProxyHandler.
+func RegisterProxyDatasourceType(datasourceType string, opts ProxyDatasourceTypeOptions) {
+	handlersMutex.Lock()
+	defer handlersMutex.Unlock()
+	proxyTypeOptions[datasourceType] = opts
+}
+
+// envPrefixForDatasourceType returns datasourceType's registered EnvPrefix
+// (see RegisterProxyDatasourceType), or "" if it registered no options or
+// left EnvPrefix unset.
+func envPrefixForDatasourceType(datasourceType string) string {
 	handlersMutex.RLock()
 	defer handlersMutex.RUnlock()
-	
-	// Create a context with proxy configuration from environment
-	ctx := context.Background()
-	config := ProxyConfig{
-		TempoEnabled:         os.Getenv("TEMPO_PROXY_ENABLED") != "false",
-		TempoPollingInterval: parsePollingInterval(os.Getenv("TEMPO_POLLING_INTERVAL")),
+	return proxyTypeOptions[datasourceType].EnvPrefix
+}
+
+// defaultProxyTypeConfig resolves datasourceType's ProxyTypeConfig from its
+// registered ProxyDatasourceTypeOptions and the environment, or an
+// always-enabled 5-minute default if no options were registered for it.
+func defaultProxyTypeConfig(datasourceType string) ProxyTypeConfig {
+	handlersMutex.RLock()
+	opts, ok := proxyTypeOptions[datasourceType]
+	handlersMutex.RUnlock()
+
+	interval := opts.DefaultPollingInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
 	}
-	ctx = WithProxyConfig(ctx, config)
-	
+	maxResponseBytes := opts.DefaultMaxResponseBytes
+	toolsCacheDuration := opts.DefaultToolsCacheDuration
+	retryPolicy := opts.RetryPolicy
+	enabled := true
+	if ok && opts.EnvPrefix != "" {
+		if v := os.Getenv(opts.EnvPrefix + "_PROXY_ENABLED"); v != "" {
+			enabled = v != "false"
+		}
+		if v := os.Getenv(opts.EnvPrefix + "_POLLING_INTERVAL"); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				slog.Warn("invalid polling interval, using default", "datasource_type", datasourceType, "value", v, "error", err)
+			} else {
+				interval = parsed
+			}
+		}
+		if v := os.Getenv(opts.EnvPrefix + "_MAX_RESPONSE_BYTES"); v != "" {
+			parsed, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || parsed <= 0 {
+				slog.Warn("invalid max response bytes, using default", "datasource_type", datasourceType, "value", v, "error", err)
+			} else {
+				maxResponseBytes = parsed
+			}
+		}
+		if v := os.Getenv(opts.EnvPrefix + "_TOOLS_CACHE_DURATION"); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				slog.Warn("invalid tools cache duration, using default", "datasource_type", datasourceType, "value", v, "error", err)
+			} else {
+				toolsCacheDuration = parsed
+			}
+		}
+		if v := os.Getenv(opts.EnvPrefix + "_MAX_RETRY_ATTEMPTS"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed <= 0 {
+				slog.Warn("invalid max retry attempts, using default", "datasource_type", datasourceType, "value", v, "error", err)
+			} else {
+				retryPolicy.MaxAttempts = parsed
+			}
+		}
+	}
+	return ProxyTypeConfig{
+		Enabled:            enabled,
+		PollingInterval:    interval,
+		RetryPolicy:        retryPolicy.normalized(),
+		MaxResponseBytes:   maxResponseBytes,
+		ToolsCacheDuration: toolsCacheDuration,
+	}
+}
+
+// defaultProxyConfigTypes resolves every registered datasource type's
+// ProxyTypeConfig from the environment.
+func defaultProxyConfigTypes() map[string]ProxyTypeConfig {
+	handlersMutex.RLock()
+	types := make([]string, 0, len(proxyTypeOptions))
+	for dsType := range proxyTypeOptions {
+		types = append(types, dsType)
+	}
+	handlersMutex.RUnlock()
+
+	cfg := make(map[string]ProxyTypeConfig, len(types))
+	for _, dsType := range types {
+		cfg[dsType] = defaultProxyTypeConfig(dsType)
+	}
+	return cfg
+}
+
+// AddProxiedTools initializes all registered proxy handlers, gating
+// ProxyBatchCall on allowed the same way the other AddXxxTools functions
+// do. The tools each proxy handler discovers and registers dynamically
+// (handler.Initialize below) aren't declared as fixed MustTool values, so
+// they aren't yet covered by ToolCapabilities filtering -- gating those
+// would mean threading allowed into the ProxyHandler/Registry discovery
+// path, which is out of scope here.
+func AddProxiedTools(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	// Create a context with proxy configuration from environment. Resolved
+	// before taking handlersMutex for iteration below, since it takes the
+	// same lock itself to read proxyTypeOptions.
+	ctx := context.Background()
+	ctx = WithProxyConfig(ctx, ProxyConfig{Types: defaultProxyConfigTypes()})
+
 	// Also need Grafana config for the proxy handlers
 	grafanaURL := os.Getenv("GRAFANA_URL")
 	grafanaAPIKey := os.Getenv("GRAFANA_API_KEY")
@@ -90,29 +259,22 @@ func AddProxiedTools(mcp *server.MCPServer) {
 			APIKey: grafanaAPIKey,
 		}
 		ctx = mcpgrafana.WithGrafanaConfig(ctx, gc)
-		
+
 		// Create Grafana client
 		client := mcpgrafana.NewGrafanaClient(ctx, grafanaURL, grafanaAPIKey)
 		ctx = mcpgrafana.WithGrafanaClient(ctx, client)
 	}
-	
+
+	handlersMutex.RLock()
+	defer handlersMutex.RUnlock()
+
 	for dsType, handler := range proxyHandlers {
 		slog.Info("Initializing proxy handler", "datasource_type", dsType)
 		handler.Initialize(ctx, mcp)
 	}
-}
 
-// parsePollingInterval parses a duration string with a default fallback
-func parsePollingInterval(intervalStr string) time.Duration {
-	if intervalStr == "" {
-		return 5 * time.Minute
-	}
-	interval, err := time.ParseDuration(intervalStr)
-	if err != nil {
-		slog.Warn("Invalid polling interval, using default", "value", intervalStr, "error", err)
-		return 5 * time.Minute
-	}
-	return interval
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	mcpgrafana.RegisterTool(mcp, allowed, read, ProxyBatchCall)
 }
 
 // StopProxiedTools shuts down all registered proxy handlers
@@ -133,6 +295,18 @@ type ProxyDatasource struct {
 	Name string
 	URL  string
 	Type string
+
+	// Region, Country, and Continent are optional location labels, read
+	// from the underlying Grafana datasource's jsonData during discovery.
+	// They're used by SelectDatasource to prefer the closest candidate
+	// when multiple datasources of the same type are configured.
+	Region    string
+	Country   string
+	Continent string
+	// LoadScore is the datasource's most recently measured tools/list
+	// latency in seconds, refreshed by refreshLoadScores. Lower is
+	// preferred; the zero value (never measured) sorts first.
+	LoadScore float64
 }
 
 // JSON-RPC structures for MCP communication
@@ -180,6 +354,12 @@ type MCPContent struct {
 	Text string `json:"text"`
 }
 
+// notificationBufferSize bounds Notifications so a session that never
+// drains it (no caller currently reads server-initiated notifications)
+// can't grow without bound; dispatchNotification drops and logs once full
+// rather than blocking the JSON-RPC call that received the frame.
+const notificationBufferSize = 32
+
 // ProxySession represents a session with a specific datasource
 type ProxySession struct {
 	ID           string
@@ -187,6 +367,37 @@ type ProxySession struct {
 	Tools        []MCPTool
 	Initialized  bool
 	LastUsed     time.Time
+
+	// Notifications receives id-less JSON-RPC messages (e.g.
+	// "notifications/tools/list_changed", progress updates) seen while
+	// decoding an SSE response on this session.
+	Notifications chan *JSONRPCNotification
+
+	// LastEventID is the most recent SSE "id:" field seen on this
+	// session's stream, used to resume via the Last-Event-ID header if
+	// callMCPStream has to reconnect.
+	LastEventID string
+
+	// ToolsCachedAt is when Tools was last fetched (or revalidated via a
+	// 304) from the upstream tools/list call.
+	ToolsCachedAt time.Time
+	// ToolsETag is a hash of Tools, sent back as If-None-Match on the
+	// next tools/list call so an upstream that supports conditional
+	// requests can reply 304 instead of re-sending an unchanged list.
+	ToolsETag string
+	// ToolsSourceURL is the ProxyDatasource.URL Tools was fetched from.
+	// ensureSession forces a refresh, ignoring any cache TTL, when this
+	// no longer matches the datasource's current URL (it may have been
+	// repointed at a different backend without its UID changing).
+	ToolsSourceURL string
+
+	// LastError is the error message from this session's most recent
+	// failed callMCP attempt, or "" if its last attempt succeeded (or none
+	// has been made yet). Retries is how many consecutive attempts have
+	// failed since the last success. Together they let a caller observe
+	// transient degradation without tailing logs.
+	LastError string
+	Retries   int
 }
 
 // SessionManager manages sessions for multiple datasources
@@ -227,15 +438,30 @@ func (sm *SessionManager) GetSession(datasourceUID string, datasourceID int64) *
 	}
 	
 	session = &ProxySession{
-		DatasourceID: datasourceID,
-		Initialized:  false,
-		LastUsed:     time.Now(),
+		DatasourceID:  datasourceID,
+		Initialized:   false,
+		LastUsed:      time.Now(),
+		Notifications: make(chan *JSONRPCNotification, notificationBufferSize),
 	}
 	sm.sessions[datasourceUID] = session
-	
+	proxy.IncActiveSessions()
+
 	return session
 }
 
+// SessionID returns datasourceUID's current session ID, or "" if it has
+// no session yet (e.g. before its first successful "initialize" call) or
+// no session at all.
+func (sm *SessionManager) SessionID(datasourceUID string) string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		return session.ID
+	}
+	return ""
+}
+
 // SetSessionID updates the session ID for a datasource
 func (sm *SessionManager) SetSessionID(datasourceUID string, sessionID string) {
 	sm.mu.Lock()
@@ -246,14 +472,96 @@ func (sm *SessionManager) SetSessionID(datasourceUID string, sessionID string) {
 	}
 }
 
+// LastEventID returns datasourceUID's most recently recorded SSE event ID,
+// or "" if it has no session or no event ID has been seen yet.
+func (sm *SessionManager) LastEventID(datasourceUID string) string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		return session.LastEventID
+	}
+	return ""
+}
+
+// SetLastEventID records the most recent SSE event ID seen on
+// datasourceUID's session, for a later reconnect's Last-Event-ID header.
+func (sm *SessionManager) SetLastEventID(datasourceUID string, eventID string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		session.LastEventID = eventID
+	}
+}
+
+// LastError returns the error message from datasourceUID's most recent
+// failed callMCP attempt, or "" if it has no session or its last attempt
+// succeeded.
+func (sm *SessionManager) LastError(datasourceUID string) string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		return session.LastError
+	}
+	return ""
+}
+
+// Retries returns how many consecutive callMCP attempts have failed
+// against datasourceUID's session since its last success.
+func (sm *SessionManager) Retries(datasourceUID string) int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		return session.Retries
+	}
+	return 0
+}
+
+// RecordCallError records a failed callMCP attempt against datasourceUID's
+// session: err's message becomes LastError and Retries increments. Mirrors
+// the retry-count-on-status pattern the Grafana Operator's dashboard
+// reconciler uses to surface degraded-but-not-yet-failed state.
+func (sm *SessionManager) RecordCallError(datasourceUID string, err error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		session.LastError = err.Error()
+		session.Retries++
+	}
+}
+
+// RecordCallSuccess clears datasourceUID's session's LastError/Retries
+// after a successful callMCP attempt.
+func (sm *SessionManager) RecordCallSuccess(datasourceUID string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		session.LastError = ""
+		session.Retries = 0
+	}
+}
+
 // SetTools updates the tools for a datasource session
-func (sm *SessionManager) SetTools(datasourceUID string, tools []MCPTool) {
+// SetTools also records sourceURL and an ETag hash of tools as the
+// session's tools-list cache metadata, for ToolsCacheValid/fetchToolsList
+// to use on the next ensureSession call.
+func (sm *SessionManager) SetTools(datasourceUID string, tools []MCPTool, sourceURL string) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
-	
+
 	if session, exists := sm.sessions[datasourceUID]; exists {
 		session.Tools = tools
 		session.Initialized = true
+		session.ToolsCachedAt = time.Now()
+		session.ToolsSourceURL = sourceURL
+		if etag, err := toolsETag(tools); err == nil {
+			session.ToolsETag = etag
+		}
 	}
 }
 
@@ -266,65 +574,215 @@ func (sm *SessionManager) CleanupStaleSessions(maxAge time.Duration) {
 	for uid, session := range sm.sessions {
 		if now.Sub(session.LastUsed) > maxAge {
 			delete(sm.sessions, uid)
+			proxy.DecActiveSessions()
 		}
 	}
 }
 
+// RemoveSession discards datasourceUID's session, if any, e.g. because the
+// reconciler (see reconcileDatasourceType) observed the datasource itself
+// was removed from Grafana. A later call for the same UID just creates a
+// fresh session, same as it would for one never seen before.
+func (sm *SessionManager) RemoveSession(datasourceUID string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if _, exists := sm.sessions[datasourceUID]; exists {
+		delete(sm.sessions, datasourceUID)
+		proxy.DecActiveSessions()
+	}
+}
+
 // Global variables for session and datasource management
 var (
-	proxyDatasources     map[string]map[string]ProxyDatasource // Maps type -> UID -> datasource info
-	datasourcesLock      sync.RWMutex
-	sessionManager       = NewSessionManager()
+	proxyDatasources        map[string]map[string]ProxyDatasource // Maps type -> UID -> datasource info
+	datasourceTypeIndex     map[string]string                     // Maps datasource UID -> datasource type, populated during discovery
+	datasourcesLock         sync.RWMutex
+	sessionManager          = NewSessionManager()
 	jsonrpcRequestIDCounter int64 // Atomic counter for JSON-RPC request IDs
-	discoveryStopChan    chan struct{} // Channel to stop the discovery goroutine
-	discoveryRunning     bool
-	discoveryMutex       sync.Mutex
+
+	// discoveryStopChans holds one stop channel per datasource type
+	// currently ticking under startPeriodicDiscovery, keyed by type, so
+	// each type's reconciler can run on its own ProxyConfig-derived
+	// interval instead of a single shared one.
+	discoveryStopChans map[string]chan struct{}
+	discoveryRunning   bool
+	discoveryMutex     sync.Mutex
+
+	// callRecorder is the process-wide proxy.Recorder for callMCP sessions,
+	// lazily opened from proxy.RecordEnvVar the first time callMCP runs. It
+	// stays nil (recording disabled) unless that env var is set.
+	callRecorder     *proxy.Recorder
+	callRecorderOnce sync.Once
 )
 
+// recorderForCalls returns the process-wide call recorder, opening the
+// segment file named by proxy.RecordEnvVar on first use. Returns nil if
+// the env var isn't set or the file couldn't be opened (logged, not
+// fatal: recording is a debugging aid, not something a live call should
+// fail over).
+func recorderForCalls() *proxy.Recorder {
+	callRecorderOnce.Do(func() {
+		path := os.Getenv(proxy.RecordEnvVar)
+		if path == "" {
+			return
+		}
+		rec, err := proxy.NewRecorder(path)
+		if err != nil {
+			slog.Error("failed to open proxy session recording; continuing without it", "path", path, "error", err)
+			return
+		}
+		slog.Info("recording proxied MCP sessions", "path", path)
+		callRecorder = rec
+	})
+	return callRecorder
+}
+
 // Initialize package-level variables
 func init() {
 	proxyDatasources = make(map[string]map[string]ProxyDatasource)
+	datasourceTypeIndex = make(map[string]string)
 }
 
-// startPeriodicDiscovery starts a background goroutine that periodically discovers datasources
-func startPeriodicDiscovery(ctx context.Context, interval time.Duration) {
+// startPeriodicDiscovery starts one reconciliation ticker per registered
+// datasource type, each running at that type's ProxyConfig-derived polling
+// interval (see ForType) rather than a single shared one. Each tick
+// reconciles that type's datasource list (see reconcileDatasourceType) and
+// cleans up stale sessions.
+func startPeriodicDiscovery(ctx context.Context) {
 	discoveryMutex.Lock()
 	if discoveryRunning {
 		discoveryMutex.Unlock()
 		return
 	}
 	discoveryRunning = true
-	discoveryStopChan = make(chan struct{})
+	discoveryStopChans = make(map[string]chan struct{})
+
+	handlersMutex.RLock()
+	dsTypes := make([]string, 0, len(proxyHandlers))
+	for dsType := range proxyHandlers {
+		dsTypes = append(dsTypes, dsType)
+	}
+	handlersMutex.RUnlock()
+
+	config := ProxyConfigFromContext(ctx)
+	for _, dsType := range dsTypes {
+		stop := make(chan struct{})
+		discoveryStopChans[dsType] = stop
+
+		interval := config.ForType(dsType).PollingInterval
+		if interval <= 0 {
+			interval = 5 * time.Minute
+		}
+		go runDiscoveryTicker(ctx, dsType, interval, stop)
+	}
 	discoveryMutex.Unlock()
-	
-	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-		
-		for {
-			select {
-			case <-ticker.C:
-				// Clean up stale sessions (older than 1 hour)
-				sessionManager.CleanupStaleSessions(time.Hour)
-				
-			case <-discoveryStopChan:
-				return
-			case <-ctx.Done():
-				return
+}
+
+// runDiscoveryTicker reconciles dsType's datasources and sweeps stale
+// sessions every interval, until stop closes or ctx is done.
+func runDiscoveryTicker(ctx context.Context, dsType string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := reconcileDatasourceType(ctx, dsType); err != nil {
+				slog.Error("periodic discovery failed", "datasource_type", dsType, "error", err)
 			}
+			sessionManager.CleanupStaleSessions(time.Hour)
+
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
 		}
-	}()
+	}
 }
 
-// stopPeriodicDiscovery stops the background discovery goroutine
+// reconcileDatasourceType re-discovers dsType's datasources and diffs the
+// result against the cached proxyDatasources entry for it, notifying
+// dsType's registered ProxyHandler of anything that appeared or
+// disappeared so it can register or unregister per-datasource tools
+// without waiting out its own next poll. A datasource that disappears
+// also has its session torn down, since its UID no longer resolves to
+// anything. A discovery error (e.g. no datasources of this type exist
+// right now) is logged and treated as a no-op for this tick, matching
+// discoverAndUpdateTools's handling of the same condition.
+func reconcileDatasourceType(ctx context.Context, dsType string) error {
+	handlersMutex.RLock()
+	handler := proxyHandlers[dsType]
+	handlersMutex.RUnlock()
+
+	datasourcesLock.RLock()
+	previous := proxyDatasources[dsType]
+	datasourcesLock.RUnlock()
+
+	discovered, err := discoverDatasources(ctx, dsType)
+	if err != nil {
+		proxy.RecordDiscoveryError(dsType)
+		slog.Warn("no datasources found during periodic discovery", "datasource_type", dsType, "error", err)
+		return nil
+	}
+
+	datasourcesLock.Lock()
+	proxyDatasources[dsType] = discovered
+	datasourcesLock.Unlock()
+
+	if handler == nil {
+		return nil
+	}
+
+	for uid, ds := range discovered {
+		if _, ok := previous[uid]; !ok {
+			handler.OnDatasourceAdded(ctx, uid, ds.ID)
+		}
+	}
+	for uid := range previous {
+		if _, ok := discovered[uid]; !ok {
+			sessionManager.RemoveSession(uid)
+			handler.OnDatasourceRemoved(ctx, uid)
+		}
+	}
+	return nil
+}
+
+// TriggerDiscovery immediately reconciles every registered datasource type
+// against Grafana's current datasource list, instead of waiting out each
+// type's next polling tick. It's the on-demand counterpart to
+// startPeriodicDiscovery's tickers, exposed via the trigger_discovery
+// admin tool for operators who just changed Grafana's datasources and
+// don't want to wait for the next poll.
+func TriggerDiscovery(ctx context.Context) error {
+	handlersMutex.RLock()
+	dsTypes := make([]string, 0, len(proxyHandlers))
+	for dsType := range proxyHandlers {
+		dsTypes = append(dsTypes, dsType)
+	}
+	handlersMutex.RUnlock()
+
+	for _, dsType := range dsTypes {
+		if err := reconcileDatasourceType(ctx, dsType); err != nil {
+			return fmt.Errorf("reconcile %s datasources: %w", dsType, err)
+		}
+	}
+	return nil
+}
+
+// stopPeriodicDiscovery stops every per-type discovery goroutine started
+// by startPeriodicDiscovery.
 func stopPeriodicDiscovery() {
 	discoveryMutex.Lock()
 	defer discoveryMutex.Unlock()
-	
-	if discoveryRunning && discoveryStopChan != nil {
-		close(discoveryStopChan)
-		discoveryRunning = false
+
+	if !discoveryRunning {
+		return
+	}
+	for _, stop := range discoveryStopChans {
+		close(stop)
 	}
+	discoveryStopChans = nil
+	discoveryRunning = false
 }
 
 // getNextRequestID returns the next JSON-RPC request ID
@@ -346,27 +804,81 @@ func discoverDatasources(ctx context.Context, datasourceType string) (map[string
 		return nil, fmt.Errorf("failed to list datasources: %w", err)
 	}
 	
+	// Carry over any load score already measured for a UID, so a routine
+	// rediscovery pass doesn't reset it to "never measured" between
+	// refreshLoadScores ticks.
+	datasourcesLock.RLock()
+	previous := proxyDatasources[datasourceType]
+	datasourcesLock.RUnlock()
+
 	// Filter for datasources of the specified type and build map
 	datasources := make(map[string]ProxyDatasource)
 	for _, ds := range resp.Payload {
 		if strings.EqualFold(ds.Type, datasourceType) {
-			datasources[ds.UID] = ProxyDatasource{
-				ID:   ds.ID,
-				UID:  ds.UID,
-				Name: ds.Name,
-				URL:  ds.URL,
-				Type: ds.Type,
+			region, country, continent := locationLabelsFromJSONData(ds)
+			pd := ProxyDatasource{
+				ID:        ds.ID,
+				UID:       ds.UID,
+				Name:      ds.Name,
+				URL:       ds.URL,
+				Type:      ds.Type,
+				Region:    region,
+				Country:   country,
+				Continent: continent,
 			}
+			if prev, ok := previous[ds.UID]; ok {
+				pd.LoadScore = prev.LoadScore
+			}
+			datasources[ds.UID] = pd
 		}
 	}
 	
 	if len(datasources) == 0 {
 		return nil, fmt.Errorf("no %s datasources found", datasourceType)
 	}
-	
+
+	datasourcesLock.Lock()
+	for uid := range datasources {
+		datasourceTypeIndex[uid] = datasourceType
+	}
+	datasourcesLock.Unlock()
+
 	return datasources, nil
 }
 
+// resolveDatasource finds datasourceUID's type via the per-UID index
+// populated by discoverDatasources, then resolves it through the usual
+// getDatasource cache/discovery path. If the index doesn't know about
+// datasourceUID yet (e.g. no discovery pass has seen it), it falls back to
+// trying every registered proxy datasource type rather than assuming
+// "tempo".
+func resolveDatasource(ctx context.Context, datasourceUID string) (*ProxyDatasource, error) {
+	datasourcesLock.RLock()
+	dsType, known := datasourceTypeIndex[datasourceUID]
+	datasourcesLock.RUnlock()
+
+	if known {
+		return getDatasource(ctx, dsType, datasourceUID)
+	}
+
+	handlersMutex.RLock()
+	candidateTypes := make([]string, 0, len(proxyHandlers))
+	for dsType := range proxyHandlers {
+		candidateTypes = append(candidateTypes, dsType)
+	}
+	handlersMutex.RUnlock()
+
+	var lastErr error
+	for _, dsType := range candidateTypes {
+		ds, err := getDatasource(ctx, dsType, datasourceUID)
+		if err == nil {
+			return ds, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to resolve datasource %s against any registered proxy type: %w", datasourceUID, lastErr)
+}
+
 // getDatasource retrieves a datasource by UID and type
 func getDatasource(ctx context.Context, datasourceType, uid string) (*ProxyDatasource, error) {
 	datasourcesLock.RLock()
@@ -404,35 +916,100 @@ func getDatasource(ctx context.Context, datasourceType, uid string) (*ProxyDatas
 	return &ds, nil
 }
 
-// callMCP makes a JSON-RPC call to an MCP server through Grafana proxy
+// callMCP makes a JSON-RPC call to an MCP server through Grafana proxy,
+// retrying transient failures (stale sessions, connection errors, HTTP
+// 5xx/429 from the proxy, JSON-RPC server-error codes, decode failures on
+// what's probably a 5xx error page) per the datasource type's RetryPolicy,
+// with exponential backoff plus full jitter between attempts (a 429's
+// Retry-After, if present, overrides the computed backoff). Every
+// attempt's outcome is recorded on the session via
+// SessionManager.RecordCallError/RecordCallSuccess. If proxy.RecordEnvVar
+// is set, every attempt of every invocation (discovery and tool-call
+// alike) is appended to the named segment file via proxy.Recorder, for
+// later replay through proxy.Replayer. Every invocation, retries included,
+// is recorded once against
+// mcp_proxy_requests_total/mcp_proxy_request_duration_seconds and
+// audit-logged; see auditProxyCall.
 func callMCP(ctx context.Context, datasourceUID string, method string, params interface{}) (*JSONRPCResponse, error) {
+	policy := retryPolicyForDatasource(ctx, datasourceUID)
+	start := time.Now()
+
+	jsonResp, err := callMCPWithRetry(ctx, policy, datasourceUID, method, func() (*JSONRPCResponse, error) {
+		jsonResp, err := callMCPOnce(ctx, datasourceUID, method, params)
+
+		if rec := recorderForCalls(); rec != nil {
+			var result interface{}
+			if jsonResp != nil {
+				result = jsonResp.Result
+			}
+			if recErr := rec.Record(datasourceUID, method, params, result, err); recErr != nil {
+				slog.Error("failed to write proxy session recording", "error", recErr)
+			}
+		}
+
+		return jsonResp, err
+	})
+
+	auditProxyCall(datasourceUID, method, start, jsonResp, err)
+	return jsonResp, err
+}
+
+// auditProxyCall records one callMCP invocation (every retry attempt it
+// made counted as a single call) against the mcp_proxy_* metrics and
+// emits a structured slog record carrying the fields an operator needs to
+// correlate a slow or failing proxied call with the datasource and
+// session behind it, without instrumenting every call site by hand.
+func auditProxyCall(datasourceUID, method string, start time.Time, resp *JSONRPCResponse, err error) {
+	duration := time.Since(start)
+
+	datasourcesLock.RLock()
+	dsType := datasourceTypeIndex[datasourceUID]
+	datasourcesLock.RUnlock()
+
+	proxy.RecordRequest(dsType, datasourceUID, method, duration, err)
+
+	requestID := 0
+	if resp != nil {
+		requestID = resp.ID
+	}
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+
+	attrs := []any{
+		"request_id", requestID,
+		"method", method,
+		"datasource_type", dsType,
+		"datasource_uid", datasourceUID,
+		"session_id", sessionManager.SessionID(datasourceUID),
+		"duration_ms", duration.Milliseconds(),
+		"outcome", outcome,
+	}
+	if err != nil {
+		slog.Warn("proxy jsonrpc call", append(attrs, "error", err.Error())...)
+		return
+	}
+	slog.Info("proxy jsonrpc call", attrs...)
+}
+
+// callMCPOnce is the actual JSON-RPC round trip through the Grafana
+// datasource proxy; callMCP wraps it with retries and optional session
+// recording.
+func callMCPOnce(ctx context.Context, datasourceUID string, method string, params interface{}) (*JSONRPCResponse, error) {
 	// Extract Grafana configuration from context
 	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
 	if cfg.URL == "" {
 		return nil, fmt.Errorf("grafana URL not found in context")
 	}
 	
-	// Get the datasource information - we need to determine the type from the UID
-	// For now, we'll check all known types
-	var ds *ProxyDatasource
-	var err error
-	
-	// Try each known type
-	for dsType := range proxyDatasources {
-		ds, err = getDatasource(ctx, dsType, datasourceUID)
-		if err == nil {
-			break
-		}
-	}
-	
-	if ds == nil {
-		// Try to discover from Tempo (default for now)
-		ds, err = getDatasource(ctx, "tempo", datasourceUID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get datasource: %w", err)
-		}
+	// Resolve the datasource's type via the per-UID index populated during
+	// discovery, rather than guessing across every known type.
+	ds, err := resolveDatasource(ctx, datasourceUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource: %w", err)
 	}
-	
+
 	// Get or create session for this datasource
 	session := sessionManager.GetSession(datasourceUID, ds.ID)
 	
@@ -465,63 +1042,44 @@ func callMCP(ctx context.Context, datasourceUID string, method string, params in
 		req.Header.Set("Mcp-Session-Id", session.ID)
 	}
 	
-	// Add authentication based on configuration
-	if cfg.APIKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.APIKey))
-	}
-	
-	// Create HTTP client with TLS configuration if available
-	client := &http.Client{
-		Timeout: 30 * time.Second, // Add timeout to prevent hanging
-	}
-	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
-		transport := &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: tlsConfig.SkipVerify,
-			},
-		}
-		
-		// Create proper TLS config if certificates are provided
-		if tlsConfig.CertFile != "" || tlsConfig.KeyFile != "" || tlsConfig.CAFile != "" {
-			tlsCfg, err := tlsConfig.CreateTLSConfig()
-			if err != nil {
-				return nil, fmt.Errorf("failed to create TLS config: %w", err)
-			}
-			transport.TLSClientConfig = tlsCfg
-		}
-		
-		client.Transport = transport
+	// Add authentication based on configuration. API key takes priority;
+	// basic auth credentials are used as a fallback for deployments behind
+	// a reverse proxy that doesn't support service account tokens. Also
+	// propagates the caller's tenant to the datasource proxy, the standard
+	// multi-tenancy contract for Mimir/Loki/Tempo behind Grafana
+	// Enterprise/GEL.
+	applyProxyAuthHeaders(req, cfg)
+
+	// Create HTTP client with TLS configuration if available, wrapped in a
+	// retry transport so transient 5xx/429 responses from the proxy don't
+	// immediately bubble up as MCP tool failures. The JSON-RPC call below
+	// is a POST, so by default it isn't retried; only GET/HEAD calls
+	// through this client benefit until non-idempotent opt-in lands.
+	client, err := newProxyHTTPClient(cfg)
+	if err != nil {
+		return nil, err
 	}
-	
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close() //nolint:errcheck
-	
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-	
-	// Check if this is a text error response instead of JSON
-	bodyStr := string(body)
-	if strings.HasPrefix(bodyStr, "Invalid session ID") || strings.HasPrefix(bodyStr, "No session") {
-		// Session expired, clear it and retry
-		sessionManager.SetSessionID(datasourceUID, "")
-		session.Initialized = false
-		return nil, fmt.Errorf("session expired, please retry: %s", bodyStr)
+	limitResponseBody(ctx, resp, datasourceUID)
+
+	if statusErr := classifyHTTPStatus(resp); statusErr != nil {
+		return nil, statusErr
 	}
-	
-	var jsonResp JSONRPCResponse
-	if err := json.Unmarshal(body, &jsonResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response (body: %s): %w", bodyStr, err)
+
+	jsonResp, err := decodeMCPResponse(resp, session, datasourceUID, request.ID)
+	if err != nil {
+		return nil, responseSizeLimitError(err, ds.Type)
 	}
-	
+
 	if jsonResp.Error != nil {
-		return nil, fmt.Errorf("MCP error: %v", jsonResp.Error)
+		return nil, newRPCError(jsonResp.Error)
 	}
-	
+
 	// Extract session ID from Set-Cookie or response headers
 	if method == "initialize" {
 		// Check Mcp-Session-Id header (used by Tempo)
@@ -542,48 +1100,29 @@ func callMCP(ctx context.Context, datasourceUID string, method string, params in
 		}
 	}
 	
-	return &jsonResp, nil
+	return jsonResp, nil
 }
 
 // ensureSession initializes the MCP session if not already done
 func ensureSession(ctx context.Context, datasourceUID string) error {
-	// Get the datasource information - we need to determine the type
-	var ds *ProxyDatasource
-	var err error
-	
-	// Try each known type
-	for dsType := range proxyDatasources {
-		ds, err = getDatasource(ctx, dsType, datasourceUID)
-		if err == nil {
-			break
-		}
-	}
-	
-	if ds == nil {
-		// Try Tempo as default
-		ds, err = getDatasource(ctx, "tempo", datasourceUID)
-		if err != nil {
-			return fmt.Errorf("failed to get datasource: %w", err)
-		}
+	// Resolve the datasource's type via the per-UID index populated during
+	// discovery, rather than guessing across every known type.
+	ds, err := resolveDatasource(ctx, datasourceUID)
+	if err != nil {
+		return fmt.Errorf("failed to get datasource: %w", err)
 	}
-	
+
 	session := sessionManager.GetSession(datasourceUID, ds.ID)
-	
-	if session.Initialized {
+	ttl := ProxyConfigFromContext(ctx).ForType(ds.Type).ToolsCacheDuration
+
+	if sessionManager.ToolsCacheValid(datasourceUID, ds.URL, ttl) {
 		return nil
 	}
-	
-	// Initialize the session with retry logic
-	const maxRetries = 3
-	var lastErr error
-	
-	for retry := 0; retry < maxRetries; retry++ {
-		if retry > 0 {
-			// Exponential backoff
-			time.Sleep(time.Duration(retry*retry) * time.Second)
-		}
-		
-		// Initialize the session
+
+	if !session.Initialized {
+		// callMCP retries transient failures (including a stale/expired
+		// session) on its own, per the datasource type's RetryPolicy, so a
+		// single call here is enough.
 		initParams := MCPInitializeParams{
 			ProtocolVersion: "2024-11-05",
 			Capabilities:    map[string]interface{}{},
@@ -592,60 +1131,106 @@ func ensureSession(ctx context.Context, datasourceUID string) error {
 				"version": "1.0",
 			},
 		}
-		
-		_, err = callMCP(ctx, datasourceUID, "initialize", initParams)
-		if err != nil {
-			lastErr = err
-			// Check if it's a session error, if so, retry
-			if strings.Contains(err.Error(), "session expired") {
-				continue
-			}
-			// For other errors, don't retry
+
+		if _, err := callMCP(ctx, datasourceUID, "initialize", initParams); err != nil {
 			return fmt.Errorf("failed to initialize session: %w", err)
 		}
-		
-		// List tools
-		resp, err := callMCP(ctx, datasourceUID, "tools/list", nil)
-		if err != nil {
-			lastErr = err
-			if strings.Contains(err.Error(), "session expired") {
-				continue
-			}
-			return fmt.Errorf("failed to list tools: %w", err)
-		}
-		
-		// Parse tools response
-		resultBytes, err := json.Marshal(resp.Result)
-		if err != nil {
-			return fmt.Errorf("failed to marshal tools result: %w", err)
-		}
-		
-		var toolsResult MCPListToolsResult
-		if err := json.Unmarshal(resultBytes, &toolsResult); err != nil {
-			return fmt.Errorf("failed to unmarshal tools result: %w", err)
-		}
-		
-		sessionManager.SetTools(datasourceUID, toolsResult.Tools)
-		
-		// Success!
+	}
+
+	// session.Initialized but past its TTL (or pointed at a new URL):
+	// revalidate with the cached ETag instead of re-initializing, so an
+	// upstream that supports conditional requests can reply 304 for an
+	// unchanged tool list.
+	tools, notModified, err := fetchToolsList(ctx, datasourceUID, ds, sessionManager.ToolsETag(datasourceUID))
+	if err != nil {
+		return fmt.Errorf("failed to list tools: %w", err)
+	}
+	if notModified {
+		sessionManager.TouchToolsCache(datasourceUID, ds.URL)
 		return nil
 	}
-	
-	return fmt.Errorf("failed to initialize session after %d retries: %w", maxRetries, lastErr)
+
+	sessionManager.SetTools(datasourceUID, tools, ds.URL)
+	return nil
 }
 
-// StartProxyDiscovery starts the periodic discovery of datasources
-// This should be called after the server has been initialized with Grafana configuration
-func StartProxyDiscovery(ctx context.Context, interval time.Duration) {
-	// Start periodic discovery with default interval of 5 minutes if not specified
-	if interval == 0 {
-		interval = 5 * time.Minute
-	}
-	
-	startPeriodicDiscovery(ctx, interval)
+// StartProxyDiscovery starts the periodic discovery of datasources. This
+// should be called after the server has been initialized with Grafana
+// configuration. Each registered datasource type ticks on its own
+// ProxyConfig-derived polling interval rather than a shared one; see
+// startPeriodicDiscovery.
+func StartProxyDiscovery(ctx context.Context) {
+	startPeriodicDiscovery(ctx)
 }
 
 // StopProxyDiscovery stops the periodic discovery of datasources
 func StopProxyDiscovery() {
 	stopPeriodicDiscovery()
-} 
+}
+
+// convertProxyDatasources converts the tools package's ProxyDatasource map
+// into the shape internal/proxy's backend-agnostic Registry operates on.
+func convertProxyDatasources(in map[string]ProxyDatasource) map[string]proxy.Datasource {
+	out := make(map[string]proxy.Datasource, len(in))
+	for uid, ds := range in {
+		out[uid] = proxy.Datasource{ID: ds.ID, UID: ds.UID, Name: ds.Name, URL: ds.URL, Type: ds.Type}
+	}
+	return out
+}
+
+// convertMCPTools converts a proxied session's wire-format tool list
+// (MCPTool, decoded from the datasource's tools/list response) into
+// mcp.Tool, the type internal/proxy's discovery and dedup pipeline
+// operates on. The conversion goes through JSON rather than a field-by-field
+// copy since the two types' schema representations aren't guaranteed to
+// match exactly.
+func convertMCPTools(tools []MCPTool) []mcp.Tool {
+	converted := make([]mcp.Tool, 0, len(tools))
+	for _, t := range tools {
+		raw, err := json.Marshal(t)
+		if err != nil {
+			continue
+		}
+		var mt mcp.Tool
+		if err := json.Unmarshal(raw, &mt); err != nil {
+			continue
+		}
+		converted = append(converted, mt)
+	}
+	return converted
+}
+
+// callProxiedMCPTool calls originalToolName on datasourceUID's proxied MCP
+// session with arguments and returns its rendered text result. It's the
+// proxy.Hooks.Call implementation shared by every datasource-proxied
+// backend (Tempo, and any future one with the same session/JSON-RPC shape).
+func callProxiedMCPTool(ctx context.Context, datasourceUID, originalToolName string, arguments map[string]interface{}) (string, error) {
+	callParams := mcp.CallToolParams{
+		Name:      originalToolName,
+		Arguments: arguments,
+	}
+
+	resp, err := callMCP(ctx, datasourceUID, "tools/call", callParams)
+	if err != nil {
+		return "", err
+	}
+
+	resultBytes, err := json.Marshal(resp.Result)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal call result: %w", err)
+	}
+
+	var callResult mcp.CallToolResult
+	if err := json.Unmarshal(resultBytes, &callResult); err != nil {
+		return "", fmt.Errorf("failed to unmarshal call result: %w", err)
+	}
+
+	if len(callResult.Content) > 0 {
+		// Type assertion needed since Content is []mcp.Content (interface).
+		if textContent, ok := callResult.Content[0].(mcp.TextContent); ok {
+			return textContent.Text, nil
+		}
+	}
+
+	return "", nil
+}
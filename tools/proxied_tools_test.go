@@ -5,8 +5,12 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -224,6 +228,109 @@ func TestCallMCP_SessionIDHeader(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// registerRetryTestDatasource registers datasourceUID as a "tempo"
+// datasource at serverURL, including the datasourceTypeIndex entry
+// retryPolicyForDatasource needs to resolve policy (rather than always
+// falling back to DefaultRetryPolicy), and returns a context carrying
+// both the Grafana config and a ProxyConfig overriding "tempo"'s
+// RetryPolicy so these tests don't run at DefaultRetryPolicy's real
+// backoff/timeout durations.
+func registerRetryTestDatasource(t *testing.T, datasourceUID, serverURL string, policy RetryPolicy) context.Context {
+	t.Helper()
+	datasourcesLock.Lock()
+	if proxyDatasources["tempo"] == nil {
+		proxyDatasources["tempo"] = make(map[string]ProxyDatasource)
+	}
+	proxyDatasources["tempo"][datasourceUID] = ProxyDatasource{
+		ID:   1,
+		UID:  datasourceUID,
+		Name: "Test",
+		URL:  serverURL,
+		Type: "tempo",
+	}
+	if datasourceTypeIndex == nil {
+		datasourceTypeIndex = make(map[string]string)
+	}
+	datasourceTypeIndex[datasourceUID] = "tempo"
+	datasourcesLock.Unlock()
+
+	cfg := mcpgrafana.GrafanaConfig{URL: serverURL}
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), cfg)
+	return WithProxyConfig(ctx, ProxyConfig{
+		Types: map[string]ProxyTypeConfig{"tempo": {RetryPolicy: policy}},
+	})
+}
+
+func TestCallMCP_RetriesOn429WithRetryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: 1, Result: "ok"})
+	}))
+	defer server.Close()
+
+	ctx := registerRetryTestDatasource(t, "retry-429-uid", server.URL, RetryPolicy{
+		MaxAttempts: 3, InitialBackoff: 5 * time.Millisecond, MaxBackoff: 10 * time.Millisecond, RetryTimeout: time.Second,
+	})
+
+	resp, err := callMCP(ctx, "retry-429-uid", "test", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Result)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	assert.Equal(t, 0, sessionManager.Retries("retry-429-uid"))
+	assert.Empty(t, sessionManager.LastError("retry-429-uid"))
+}
+
+func TestCallMCP_Retries5xxExhausted(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "upstream unavailable")
+	}))
+	defer server.Close()
+
+	ctx := registerRetryTestDatasource(t, "retry-5xx-uid", server.URL, RetryPolicy{
+		MaxAttempts: 3, InitialBackoff: 5 * time.Millisecond, MaxBackoff: 10 * time.Millisecond, RetryTimeout: time.Second,
+	})
+
+	_, err := callMCP(ctx, "retry-5xx-uid", "test", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Equal(t, 3, sessionManager.Retries("retry-5xx-uid"))
+	assert.NotEmpty(t, sessionManager.LastError("retry-5xx-uid"))
+}
+
+func TestCallMCP_RecoversAfterTransientFailures(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: 1, Result: "recovered"})
+	}))
+	defer server.Close()
+
+	ctx := registerRetryTestDatasource(t, "retry-recover-uid", server.URL, RetryPolicy{
+		MaxAttempts: 4, InitialBackoff: 5 * time.Millisecond, MaxBackoff: 10 * time.Millisecond, RetryTimeout: time.Second,
+	})
+
+	resp, err := callMCP(ctx, "retry-recover-uid", "test", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "recovered", resp.Result)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Equal(t, 0, sessionManager.Retries("retry-recover-uid"))
+	assert.Empty(t, sessionManager.LastError("retry-recover-uid"))
+}
+
 func TestSessionManager(t *testing.T) {
 	t.Run("create and retrieve session", func(t *testing.T) {
 		sm := NewSessionManager()
@@ -264,7 +371,7 @@ func TestSessionManager(t *testing.T) {
 			{Name: "tool1", Description: "Tool 1"},
 			{Name: "tool2", Description: "Tool 2"},
 		}
-		sm.SetTools("datasource-1", tools)
+		sm.SetTools("datasource-1", tools, "http://grafana.example.com")
 		
 		// Verify
 		assert.True(t, session.Initialized)
@@ -296,6 +403,200 @@ func TestSessionManager(t *testing.T) {
 	})
 }
 
+func TestSessionManager_ToolsCache(t *testing.T) {
+	t.Run("cache hit inside TTL", func(t *testing.T) {
+		sm := NewSessionManager()
+		sm.GetSession("datasource-1", 123)
+		sm.SetTools("datasource-1", []MCPTool{{Name: "tool1"}}, "http://grafana.example.com")
+
+		assert.True(t, sm.ToolsCacheValid("datasource-1", "http://grafana.example.com", time.Hour))
+		assert.True(t, sm.ToolsCacheValid("datasource-1", "http://grafana.example.com", 0)) // ttl<=0 means forever
+	})
+
+	t.Run("cache miss after TTL elapses", func(t *testing.T) {
+		sm := NewSessionManager()
+		session := sm.GetSession("datasource-1", 123)
+		sm.SetTools("datasource-1", []MCPTool{{Name: "tool1"}}, "http://grafana.example.com")
+		session.ToolsCachedAt = time.Now().Add(-2 * time.Hour)
+
+		assert.False(t, sm.ToolsCacheValid("datasource-1", "http://grafana.example.com", time.Hour))
+	})
+
+	t.Run("forced refresh when datasource URL changes", func(t *testing.T) {
+		sm := NewSessionManager()
+		sm.GetSession("datasource-1", 123)
+		sm.SetTools("datasource-1", []MCPTool{{Name: "tool1"}}, "http://old.example.com")
+
+		assert.False(t, sm.ToolsCacheValid("datasource-1", "http://new.example.com", time.Hour))
+	})
+
+	t.Run("eviction when datasource's session is removed", func(t *testing.T) {
+		sm := NewSessionManager()
+		sm.GetSession("datasource-1", 123)
+		sm.SetTools("datasource-1", []MCPTool{{Name: "tool1"}}, "http://grafana.example.com")
+		require.True(t, sm.ToolsCacheValid("datasource-1", "http://grafana.example.com", time.Hour))
+
+		sm.RemoveSession("datasource-1")
+
+		assert.False(t, sm.ToolsCacheValid("datasource-1", "http://grafana.example.com", time.Hour))
+		assert.Empty(t, sm.ToolsETag("datasource-1"))
+	})
+
+	t.Run("ETag changes with tool list content", func(t *testing.T) {
+		sm := NewSessionManager()
+		sm.GetSession("datasource-1", 123)
+		sm.SetTools("datasource-1", []MCPTool{{Name: "tool1"}}, "http://grafana.example.com")
+		firstETag := sm.ToolsETag("datasource-1")
+		require.NotEmpty(t, firstETag)
+
+		sm.SetTools("datasource-1", []MCPTool{{Name: "tool1"}, {Name: "tool2"}}, "http://grafana.example.com")
+		assert.NotEqual(t, firstETag, sm.ToolsETag("datasource-1"))
+	})
+}
+
+// newToolsListServer returns an httptest server that answers "initialize"
+// with a bare success and "tools/list" by recording the call in callCount
+// and the request's If-None-Match header, replying 304 when it matches
+// servedETag, or a fresh tools list (and updating servedETag) otherwise.
+func newToolsListServer(t *testing.T, callCount *int32, servedETag *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req JSONRPCRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch req.Method {
+		case "initialize":
+			json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]interface{}{}})
+		case "tools/list":
+			atomic.AddInt32(callCount, 1)
+			tools := []MCPTool{{Name: "tool1", Description: "Tool 1"}}
+			etag, err := toolsETag(tools)
+			require.NoError(t, err)
+
+			if *servedETag != "" && r.Header.Get("If-None-Match") == *servedETag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			*servedETag = etag
+			json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: MCPListToolsResult{Tools: tools}})
+		default:
+			t.Fatalf("unexpected method %q", req.Method)
+		}
+	}))
+}
+
+func TestEnsureSession_ToolsCacheRevalidation(t *testing.T) {
+	var callCount int32
+	var servedETag string
+	server := newToolsListServer(t, &callCount, &servedETag)
+	defer server.Close()
+
+	cfg := mcpgrafana.GrafanaConfig{URL: server.URL}
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), cfg)
+	registerStreamTestDatasource(t, "ensure-session-uid", server.URL)
+	datasourcesLock.Lock()
+	datasourceTypeIndex["ensure-session-uid"] = "tempo"
+	datasourcesLock.Unlock()
+
+	require.NoError(t, ensureSession(ctx, "ensure-session-uid"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&callCount))
+
+	// A second call within the (infinite, since no ProxyConfig was
+	// installed) TTL must not hit tools/list again.
+	require.NoError(t, ensureSession(ctx, "ensure-session-uid"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&callCount))
+
+	// Force a TTL miss directly: the next ensureSession call should
+	// re-issue tools/list, sending back the cached ETag, and get a 304.
+	sessionManager.TouchToolsCache("ensure-session-uid", "this-is-not-the-cached-url")
+	require.NoError(t, ensureSession(ctx, "ensure-session-uid"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&callCount), "URL mismatch should force a revalidation call")
+}
+
+func TestCallMCPBatch_SendsArrayAndDemuxesOutOfOrder(t *testing.T) {
+	var capturedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		capturedBody = body
+
+		var batch []JSONRPCRequest
+		require.NoError(t, json.Unmarshal(body, &batch))
+		require.Len(t, batch, 3)
+
+		// Reply out of order and with the middle call failing, to verify
+		// demuxing is by id rather than response position.
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]JSONRPCResponse{
+			{JSONRPC: "2.0", ID: batch[2].ID, Result: "third"},
+			{JSONRPC: "2.0", ID: batch[0].ID, Result: "first"},
+			{JSONRPC: "2.0", ID: batch[1].ID, Error: map[string]interface{}{"message": "boom"}},
+		})
+	}))
+	defer server.Close()
+
+	cfg := mcpgrafana.GrafanaConfig{URL: server.URL}
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), cfg)
+	registerStreamTestDatasource(t, "batch-uid", server.URL)
+
+	results, err := callMCPBatch(ctx, "batch-uid", []MCPCall{
+		{Method: "tools/call", Params: map[string]string{"name": "one"}},
+		{Method: "tools/call", Params: map[string]string{"name": "two"}},
+		{Method: "tools/call", Params: map[string]string{"name": "three"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	// The captured body must be a JSON array, not a single JSON-RPC object.
+	var asArray []json.RawMessage
+	require.NoError(t, json.Unmarshal(capturedBody, &asArray))
+	assert.Len(t, asArray, 3)
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "first", results[0].Response.Result)
+
+	require.Error(t, results[1].Err)
+	assert.Contains(t, results[1].Err.Error(), "boom")
+
+	require.NoError(t, results[2].Err)
+	assert.Equal(t, "third", results[2].Response.Result)
+}
+
+func TestCallMCPBatch_MissingResponseIsPerCallError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var batch []JSONRPCRequest
+		require.NoError(t, json.Unmarshal(body, &batch))
+
+		w.Header().Set("Content-Type", "application/json")
+		// Only answer the first call.
+		json.NewEncoder(w).Encode([]JSONRPCResponse{
+			{JSONRPC: "2.0", ID: batch[0].ID, Result: "ok"},
+		})
+	}))
+	defer server.Close()
+
+	cfg := mcpgrafana.GrafanaConfig{URL: server.URL}
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), cfg)
+	registerStreamTestDatasource(t, "batch-uid-missing", server.URL)
+
+	results, err := callMCPBatch(ctx, "batch-uid-missing", []MCPCall{
+		{Method: "tools/call", Params: map[string]string{"name": "one"}},
+		{Method: "tools/call", Params: map[string]string{"name": "two"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.NoError(t, results[0].Err)
+	require.Error(t, results[1].Err)
+	assert.Contains(t, results[1].Err.Error(), "no response received")
+}
+
 func TestJSONRPCRequestIDCounter(t *testing.T) {
 	t.Run("sequential IDs", func(t *testing.T) {
 		// Reset counter for test
@@ -338,4 +639,167 @@ func TestJSONRPCRequestIDCounter(t *testing.T) {
 
 func TestGetDatasource(t *testing.T) {
 	// TODO: Add tests for getDatasource function
-} 
+}
+
+// registerStreamTestDatasource makes datasourceUID resolvable through
+// resolveDatasource's "tempo" type bucket, same convention the other
+// tests in this file use.
+func registerStreamTestDatasource(t *testing.T, datasourceUID, serverURL string) {
+	t.Helper()
+	datasourcesLock.Lock()
+	if proxyDatasources["tempo"] == nil {
+		proxyDatasources["tempo"] = make(map[string]ProxyDatasource)
+	}
+	proxyDatasources["tempo"][datasourceUID] = ProxyDatasource{
+		ID:   1,
+		UID:  datasourceUID,
+		Name: "Test",
+		URL:  serverURL,
+		Type: "tempo",
+	}
+	datasourcesLock.Unlock()
+}
+
+// requestIDFromBody unmarshals an incoming JSON-RPC request's id, so a
+// test SSE server can echo it back in its final response frame.
+func requestIDFromBody(t *testing.T, r *http.Request) int {
+	t.Helper()
+	body, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	var req JSONRPCRequest
+	require.NoError(t, json.Unmarshal(body, &req))
+	return req.ID
+}
+
+func writeSSEFrame(w http.ResponseWriter, flusher http.Flusher, eventID string, v interface{}) {
+	b, _ := json.Marshal(v)
+	if eventID != "" {
+		fmt.Fprintf(w, "id: %s\n", eventID)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", b)
+	flusher.Flush()
+}
+
+func TestCallMCPStream_OrderedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := requestIDFromBody(t, r)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+
+		writeSSEFrame(w, flusher, "", JSONRPCNotification{JSONRPC: "2.0", Method: "notifications/progress"})
+		writeSSEFrame(w, flusher, "evt-1", JSONRPCResponse{JSONRPC: "2.0", ID: requestID, Result: "done"})
+	}))
+	defer server.Close()
+
+	cfg := mcpgrafana.GrafanaConfig{URL: server.URL}
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), cfg)
+	registerStreamTestDatasource(t, "stream-ordered", server.URL)
+
+	var mu sync.Mutex
+	var events []StreamEvent
+	err := callMCPStream(ctx, "stream-ordered", "test", nil, func(e StreamEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	require.NotNil(t, events[0].Notification)
+	assert.Equal(t, "notifications/progress", events[0].Notification.Method)
+	require.NotNil(t, events[1].Response)
+	assert.Equal(t, "done", events[1].Response.Result)
+	assert.Equal(t, "evt-1", sessionManager.LastEventID("stream-ordered"))
+}
+
+func TestCallMCPStream_CancellationStopsDelivery(t *testing.T) {
+	blockUntilCanceled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+
+		writeSSEFrame(w, flusher, "", JSONRPCNotification{JSONRPC: "2.0", Method: "notifications/progress"})
+
+		select {
+		case <-r.Context().Done():
+		case <-blockUntilCanceled:
+		}
+	}))
+	defer server.Close()
+	defer close(blockUntilCanceled)
+
+	cfg := mcpgrafana.GrafanaConfig{URL: server.URL}
+	baseCtx := mcpgrafana.WithGrafanaConfig(context.Background(), cfg)
+	ctx, cancel := context.WithCancel(baseCtx)
+	registerStreamTestDatasource(t, "stream-cancel", server.URL)
+
+	var mu sync.Mutex
+	var events []StreamEvent
+	done := make(chan error, 1)
+	go func() {
+		done <- callMCPStream(ctx, "stream-cancel", "test", nil, func(e StreamEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		})
+	}()
+
+	// Give the notification a chance to arrive, then cancel before the
+	// server ever sends a final response.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("callMCPStream did not return after context cancellation")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, events, 1)
+	assert.NotNil(t, events[0].Notification)
+}
+
+func TestCallMCPStream_ReconnectsWithLastEventID(t *testing.T) {
+	var attempt int32
+	var capturedLastEventID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := requestIDFromBody(t, r)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+
+		if atomic.AddInt32(&attempt, 1) == 1 {
+			// First attempt: emit one frame with an event ID, then drop the
+			// connection before the response arrives.
+			writeSSEFrame(w, flusher, "evt-reconnect-1", JSONRPCNotification{JSONRPC: "2.0", Method: "notifications/progress"})
+			return
+		}
+
+		capturedLastEventID = r.Header.Get("Last-Event-ID")
+		writeSSEFrame(w, flusher, "evt-reconnect-2", JSONRPCResponse{JSONRPC: "2.0", ID: requestID, Result: "done"})
+	}))
+	defer server.Close()
+
+	cfg := mcpgrafana.GrafanaConfig{URL: server.URL}
+	ctx := mcpgrafana.WithGrafanaConfig(context.Background(), cfg)
+	registerStreamTestDatasource(t, "stream-reconnect", server.URL)
+
+	var mu sync.Mutex
+	var events []StreamEvent
+	err := callMCPStream(ctx, "stream-reconnect", "test", nil, func(e StreamEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "evt-reconnect-1", capturedLastEventID)
+	require.NotEmpty(t, events)
+	assert.NotNil(t, events[len(events)-1].Response)
+}
@@ -0,0 +1,281 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// MCPCall is one call in a callMCPBatch request: the JSON-RPC method and
+// params callMCP would otherwise send as its own standalone HTTP round
+// trip.
+type MCPCall struct {
+	Method string
+	Params interface{}
+}
+
+// MCPResult is callMCPBatch's per-call outcome. Response is nil and Err is
+// set both for a transport-level demux failure (the batch response never
+// contained this call's id) and for an MCP-level error the upstream
+// returned for this call specifically; either way, one call's failure
+// doesn't affect the others' Response/Err in the same batch.
+type MCPResult struct {
+	Response *JSONRPCResponse
+	Err      error
+}
+
+// callMCPBatch sends every call in calls as a single JSON-RPC 2.0 batch
+// (a JSON array of request objects) through the Grafana datasource proxy,
+// one HTTP round trip for all of them, and demultiplexes the array of
+// responses back to callers by id — regardless of the order the upstream
+// server returned them in. Like callMCPOnce, it expects the caller (the
+// proxy registry's EnsureSession hook, or an explicit ensureSession call)
+// to have already established the session; callMCPBatch itself issues no
+// retries, since a batch mid-retry could duplicate the calls that already
+// succeeded.
+//
+// The returned []MCPResult is always len(calls) long, in the same order
+// as calls, even when the error return is non-nil for the handful of
+// calls a transport failure affected.
+func callMCPBatch(ctx context.Context, datasourceUID string, calls []MCPCall) ([]MCPResult, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("grafana URL not found in context")
+	}
+
+	ds, err := resolveDatasource(ctx, datasourceUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource: %w", err)
+	}
+	session := sessionManager.GetSession(datasourceUID, ds.ID)
+
+	ids := make([]int, len(calls))
+	batch := make([]JSONRPCRequest, len(calls))
+	for i, call := range calls {
+		id := int(getNextRequestID())
+		ids[i] = id
+		batch[i] = JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: call.Method, Params: call.Params}
+	}
+
+	reqBody, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch request: %w", err)
+	}
+
+	proxyURL := fmt.Sprintf("%s/api/datasources/proxy/%d/api/mcp", strings.TrimRight(cfg.URL, "/"), ds.ID)
+	req, err := http.NewRequestWithContext(ctx, "POST", proxyURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream, application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	if session.ID != "" {
+		req.Header.Set("Mcp-Session-Id", session.ID)
+	}
+	applyProxyAuthHeaders(req, cfg)
+
+	client, err := newProxyHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	limitResponseBody(ctx, resp, datasourceUID)
+
+	var responses []JSONRPCResponse
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		responses, err = decodeBatchSSEResponses(resp.Body)
+	} else {
+		responses, err = decodeBatchJSONResponses(resp.Body)
+	}
+	if err != nil {
+		return nil, responseSizeLimitError(err, ds.Type)
+	}
+
+	byID := make(map[int]JSONRPCResponse, len(responses))
+	for _, r := range responses {
+		byID[r.ID] = r
+	}
+
+	results := make([]MCPResult, len(calls))
+	for i, id := range ids {
+		r, ok := byID[id]
+		if !ok {
+			results[i] = MCPResult{Err: fmt.Errorf("no response received for request id %d (method %s)", id, calls[i].Method)}
+			continue
+		}
+		if r.Error != nil {
+			results[i] = MCPResult{Response: &r, Err: fmt.Errorf("MCP error: %v", r.Error)}
+			continue
+		}
+		respCopy := r
+		results[i] = MCPResult{Response: &respCopy}
+	}
+	return results, nil
+}
+
+// decodeBatchJSONResponses parses a non-streaming batch response body: a
+// JSON array of JSONRPCResponse, per JSON-RPC 2.0 batch semantics. A
+// server that doesn't support batching and replied with a single object
+// is also accepted, as a one-element batch.
+func decodeBatchJSONResponses(body io.Reader) ([]JSONRPCResponse, error) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch response: %w", err)
+	}
+
+	var batch []JSONRPCResponse
+	if err := json.Unmarshal(raw, &batch); err == nil {
+		return batch, nil
+	}
+
+	var single JSONRPCResponse
+	if err := json.Unmarshal(raw, &single); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch response: %w", err)
+	}
+	return []JSONRPCResponse{single}, nil
+}
+
+// decodeBatchSSEResponses parses a streamed batch response: each SSE
+// "data:" frame carries either one JSONRPCResponse or a JSON array of
+// them (an upstream may choose to emit the whole batch as a single frame,
+// or one frame per call).
+func decodeBatchSSEResponses(body io.Reader) ([]JSONRPCResponse, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var data strings.Builder
+	var responses []JSONRPCResponse
+	flush := func() error {
+		if data.Len() == 0 {
+			return nil
+		}
+		frame := data.String()
+		data.Reset()
+
+		var batch []JSONRPCResponse
+		if err := json.Unmarshal([]byte(frame), &batch); err == nil {
+			responses = append(responses, batch...)
+			return nil
+		}
+		var single JSONRPCResponse
+		if err := json.Unmarshal([]byte(frame), &single); err != nil {
+			return fmt.Errorf("failed to unmarshal SSE batch frame (data: %s): %w", frame, err)
+		}
+		responses = append(responses, single)
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read SSE batch stream: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// ProxyBatchCallItem is one tool invocation inside a ProxyBatchCallRequest.
+type ProxyBatchCallItem struct {
+	ToolName  string                 `json:"tool_name" jsonschema:"required,description=The proxied tool's original name (as seen in its upstream tools/list\\, not its disambiguated MCP name)"`
+	Arguments map[string]interface{} `json:"arguments,omitempty" jsonschema:"description=Tool-specific arguments"`
+}
+
+// ProxyBatchCallRequest represents a request to invoke several proxied
+// MCP tools against the same datasource in a single HTTP round trip.
+type ProxyBatchCallRequest struct {
+	DatasourceUID string               `json:"datasource_uid" jsonschema:"required,description=The UID of the proxied datasource to call"`
+	Calls         []ProxyBatchCallItem `json:"calls" jsonschema:"required,description=The tool calls to batch together"`
+}
+
+// proxyBatchCall ensures datasourceUID's session, then issues every entry
+// in req.Calls as one JSON-RPC batch via callMCPBatch, rendering each
+// call's outcome (or per-call error) back as a single numbered report.
+func proxyBatchCall(ctx context.Context, req ProxyBatchCallRequest) (string, error) {
+	if len(req.Calls) == 0 {
+		return "", fmt.Errorf("calls must not be empty")
+	}
+	if err := ensureSession(ctx, req.DatasourceUID); err != nil {
+		return "", fmt.Errorf("failed to ensure session: %w", err)
+	}
+
+	calls := make([]MCPCall, len(req.Calls))
+	for i, c := range req.Calls {
+		calls[i] = MCPCall{
+			Method: "tools/call",
+			Params: MCPCallToolParams{Name: c.ToolName, Arguments: c.Arguments},
+		}
+	}
+
+	results, err := callMCPBatch(ctx, req.DatasourceUID, calls)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, result := range results {
+		fmt.Fprintf(&b, "## Call %d: %s\n", i+1, req.Calls[i].ToolName)
+		if result.Err != nil {
+			fmt.Fprintf(&b, "Error: %s\n\n", result.Err)
+			continue
+		}
+		resultBytes, err := json.Marshal(result.Response.Result)
+		if err != nil {
+			fmt.Fprintf(&b, "Error: failed to marshal result: %s\n\n", err)
+			continue
+		}
+		var toolResult MCPCallToolResult
+		if err := json.Unmarshal(resultBytes, &toolResult); err != nil {
+			fmt.Fprintf(&b, "Error: failed to unmarshal result: %s\n\n", err)
+			continue
+		}
+		for _, content := range toolResult.Content {
+			fmt.Fprintf(&b, "%s\n", content.Text)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// ProxyBatchCall lets a single agent turn invoke several proxied MCP tool
+// calls against the same datasource (e.g. Tempo, and any future
+// datasource-proxied backend with the same session/JSON-RPC shape) as one
+// HTTP round trip through the Grafana proxy, instead of one per call.
+var ProxyBatchCall = mcpgrafana.MustTool(
+	"proxy_call_batch",
+	"Call several proxied MCP tools (as listed by their datasource's tools/list) against the same datasource in a single JSON-RPC batch request, rather than one network round trip per call.",
+	proxyBatchCall,
+	mcp.WithTitleAnnotation("Batch call proxied MCP tools"),
+)
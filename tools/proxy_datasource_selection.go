@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultLoadRefreshInterval is how often StartLoadRefresh pings each known
+// datasource's tools/list latency to update its LoadScore, absent an
+// explicit interval.
+const defaultLoadRefreshInterval = time.Minute
+
+// datasourceJSONData is the subset of a Grafana datasource list item this
+// package reads location labels from. Decoding through JSON (rather than a
+// field-by-field copy) keeps this independent of the exact Go type the
+// Grafana client returns for an item's jsonData.
+type datasourceJSONData struct {
+	JSONData map[string]interface{} `json:"jsonData"`
+}
+
+// locationLabelsFromJSONData extracts the "region", "country", and
+// "continent" string fields from a Grafana datasource's jsonData, if
+// present. Any of the three may come back empty: most datasources don't
+// set these, and SelectDatasource treats an empty hint or label as "no
+// preference" rather than an error.
+func locationLabelsFromJSONData(ds interface{}) (region, country, continent string) {
+	raw, err := json.Marshal(ds)
+	if err != nil {
+		return "", "", ""
+	}
+
+	var parsed datasourceJSONData
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return "", "", ""
+	}
+
+	asString := func(key string) string {
+		if v, ok := parsed.JSONData[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	return asString("region"), asString("country"), asString("continent")
+}
+
+// DatasourceSelectionHints narrows SelectDatasource's choice among multiple
+// datasources of the same type. A zero-value field means "no preference"
+// for that dimension.
+type DatasourceSelectionHints struct {
+	Region    string
+	Country   string
+	Continent string
+}
+
+// SelectDatasource picks the best candidate of datasourceType for hints,
+// preferring (in order) a region match, a country match, a continent
+// match, and finally the lowest LoadScore. Ties and an all-zero-value
+// hints both fall through to "any datasource of the requested type",
+// ordered solely by LoadScore. Datasources are discovered fresh if none of
+// datasourceType are cached yet.
+func SelectDatasource(ctx context.Context, datasourceType string, hints DatasourceSelectionHints) (*ProxyDatasource, error) {
+	candidates, err := candidateDatasources(ctx, datasourceType)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no %s datasources found", datasourceType)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+
+		if matchRank := compareMatch(hints.Region, a.Region, b.Region); matchRank != 0 {
+			return matchRank > 0
+		}
+		if matchRank := compareMatch(hints.Country, a.Country, b.Country); matchRank != 0 {
+			return matchRank > 0
+		}
+		if matchRank := compareMatch(hints.Continent, a.Continent, b.Continent); matchRank != 0 {
+			return matchRank > 0
+		}
+		return a.LoadScore < b.LoadScore
+	})
+
+	best := candidates[0]
+	return &best, nil
+}
+
+// compareMatch reports whether a or b more closely matches hint: +1 if a
+// matches and b doesn't, -1 if b matches and a doesn't, 0 if they agree
+// (both match, neither matches, or hint is empty).
+func compareMatch(hint, a, b string) int {
+	if hint == "" {
+		return 0
+	}
+	aMatch, bMatch := a == hint, b == hint
+	switch {
+	case aMatch && !bMatch:
+		return 1
+	case bMatch && !aMatch:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// candidateDatasources returns every known datasource of datasourceType,
+// discovering them if the cache doesn't have any yet.
+func candidateDatasources(ctx context.Context, datasourceType string) ([]ProxyDatasource, error) {
+	datasourcesLock.RLock()
+	typeDatasources := proxyDatasources[datasourceType]
+	candidates := make([]ProxyDatasource, 0, len(typeDatasources))
+	for _, ds := range typeDatasources {
+		candidates = append(candidates, ds)
+	}
+	datasourcesLock.RUnlock()
+
+	if len(candidates) > 0 {
+		return candidates, nil
+	}
+
+	discovered, err := discoverDatasources(ctx, datasourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover %s datasources: %w", datasourceType, err)
+	}
+
+	datasourcesLock.Lock()
+	proxyDatasources[datasourceType] = discovered
+	datasourcesLock.Unlock()
+
+	candidates = make([]ProxyDatasource, 0, len(discovered))
+	for _, ds := range discovered {
+		candidates = append(candidates, ds)
+	}
+	return candidates, nil
+}
+
+// Global state for the load-refresh goroutine, mirroring the discovery
+// goroutine's start/stop pattern in proxied_tools.go.
+var (
+	loadRefreshStopChan chan struct{}
+	loadRefreshRunning  bool
+	loadRefreshMutex    sync.Mutex
+)
+
+// StartLoadRefresh starts a background goroutine that periodically pings
+// tools/list against every known proxied datasource and records its
+// latency as LoadScore, so SelectDatasource's least-loaded ordering
+// reflects current conditions rather than a one-time snapshot. interval
+// defaults to defaultLoadRefreshInterval if zero.
+func StartLoadRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultLoadRefreshInterval
+	}
+
+	loadRefreshMutex.Lock()
+	if loadRefreshRunning {
+		loadRefreshMutex.Unlock()
+		return
+	}
+	loadRefreshRunning = true
+	loadRefreshStopChan = make(chan struct{})
+	loadRefreshMutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				refreshLoadScores(ctx)
+			case <-loadRefreshStopChan:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// StopLoadRefresh stops the background load-refresh goroutine.
+func StopLoadRefresh() {
+	loadRefreshMutex.Lock()
+	defer loadRefreshMutex.Unlock()
+
+	if loadRefreshRunning && loadRefreshStopChan != nil {
+		close(loadRefreshStopChan)
+		loadRefreshRunning = false
+	}
+}
+
+// refreshLoadScores pings tools/list against every known datasource and
+// records its round-trip latency, in seconds, as its new LoadScore. A
+// failed ping leaves the previous LoadScore in place rather than
+// penalizing a datasource for one transient hiccup.
+func refreshLoadScores(ctx context.Context) {
+	type target struct {
+		dsType string
+		uid    string
+	}
+
+	datasourcesLock.RLock()
+	targets := make([]target, 0)
+	for dsType, typeDatasources := range proxyDatasources {
+		for uid := range typeDatasources {
+			targets = append(targets, target{dsType: dsType, uid: uid})
+		}
+	}
+	datasourcesLock.RUnlock()
+
+	for _, t := range targets {
+		start := time.Now()
+		_, err := callMCP(ctx, t.uid, "tools/list", nil)
+		if err != nil {
+			slog.Debug("load-refresh ping failed, keeping previous load score", "datasource_uid", t.uid, "error", err)
+			continue
+		}
+		elapsed := time.Since(start).Seconds()
+
+		datasourcesLock.Lock()
+		if typeDatasources, ok := proxyDatasources[t.dsType]; ok {
+			if ds, ok := typeDatasources[t.uid]; ok {
+				ds.LoadScore = elapsed
+				typeDatasources[t.uid] = ds
+			}
+		}
+		datasourcesLock.Unlock()
+	}
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxResponseBytes bounds a proxied MCP response body for a
+// datasource type that registered no MaxResponseBytes override via
+// ProxyDatasourceTypeOptions (or whose ProxyConfig entry left
+// MaxResponseBytes unset). Tempo/Loki trace and log payloads are the
+// largest responses callMCP sees in practice; 16 MiB comfortably covers
+// them without leaving an unbounded reader on a misbehaving proxy.
+const DefaultMaxResponseBytes int64 = 16 * 1024 * 1024
+
+// maxResponseBytesForDatasource resolves the MaxResponseBytes ceiling to
+// apply for datasourceUID: its datasource type's configured value, if the
+// type is known via the per-UID index and configured above zero, or
+// DefaultMaxResponseBytes otherwise.
+func maxResponseBytesForDatasource(ctx context.Context, datasourceUID string) int64 {
+	datasourcesLock.RLock()
+	dsType, known := datasourceTypeIndex[datasourceUID]
+	datasourcesLock.RUnlock()
+
+	if known {
+		if max := ProxyConfigFromContext(ctx).ForType(dsType).MaxResponseBytes; max > 0 {
+			return max
+		}
+	}
+	return DefaultMaxResponseBytes
+}
+
+// limitResponseBody wraps resp.Body in an http.MaxBytesReader capped at
+// datasourceUID's configured MaxResponseBytes, so decodeMCPResponse can't
+// be made to buffer an unbounded payload from a misbehaving or malicious
+// proxy target. Passing a nil ResponseWriter is safe: MaxBytesReader only
+// calls back into it to report a too-large request body on the server
+// side, which doesn't apply to this client-side read.
+func limitResponseBody(ctx context.Context, resp *http.Response, datasourceUID string) {
+	resp.Body = http.MaxBytesReader(nil, resp.Body, maxResponseBytesForDatasource(ctx, datasourceUID))
+}
+
+// responseSizeLimitError rewrites err into a message naming the env var
+// that raises datasourceType's response size ceiling, if err is an
+// http.MaxBytesReader rejection from a body limited by limitResponseBody.
+// Any other error (including one from decodeMCPResponse wrapping a
+// MaxBytesReader error, since errors.Is traverses wrapped chains) passes
+// through unchanged.
+func responseSizeLimitError(err error, datasourceType string) error {
+	if err == nil || !errors.Is(err, http.ErrBodyTooLarge) {
+		return err
+	}
+	prefix := envPrefixForDatasourceType(datasourceType)
+	if prefix == "" {
+		prefix = strings.ToUpper(datasourceType)
+	}
+	return fmt.Errorf("MCP response exceeds configured limit; increase %s_MAX_RESPONSE_BYTES", prefix)
+}
@@ -0,0 +1,321 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how callMCP retries a proxied JSON-RPC call against
+// a single datasource. Modeled on goss's Validate retry loop (sleep,
+// retry, and bail out once either the attempt budget or the wall-clock
+// retry timeout is exhausted, whichever comes first).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Zero or less uses DefaultRetryPolicy's value.
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt; it doubles
+	// after each subsequent retry, capped at MaxBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff delay between attempts.
+	MaxBackoff time.Duration
+	// RetryTimeout is the total wall-clock budget across all attempts,
+	// measured from the first attempt. A retry that would start after the
+	// deadline stops early even if MaxAttempts hasn't been reached.
+	RetryTimeout time.Duration
+	// RetryableError reports whether err, from one failed attempt, should
+	// be retried. A nil func uses defaultRetryableCallError.
+	RetryableError func(err error) bool
+}
+
+// DefaultRetryPolicy is used for a datasource type that registered no
+// RetryPolicy override via ProxyDatasourceTypeOptions.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		RetryTimeout:   30 * time.Second,
+	}
+}
+
+// normalized fills in zero-valued fields of p with DefaultRetryPolicy's,
+// so a caller-supplied RetryPolicy only needs to set the fields it cares
+// about overriding.
+func (p RetryPolicy) normalized() RetryPolicy {
+	d := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = d.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = d.MaxBackoff
+	}
+	if p.RetryTimeout <= 0 {
+		p.RetryTimeout = d.RetryTimeout
+	}
+	if p.RetryableError == nil {
+		p.RetryableError = defaultRetryableCallError
+	}
+	return p
+}
+
+// httpStatusError wraps a non-2xx HTTP response from the datasource proxy
+// itself, as opposed to a JSON-RPC-level error inside an otherwise
+// successful 200 response. callMCPOnce returns one of these instead of
+// trying to decode a body that, for a 5xx or 429, almost certainly isn't a
+// JSON-RPC response at all (an error page, a rate-limit notice).
+type httpStatusError struct {
+	StatusCode int
+	// RetryAfter is parsed from the response's Retry-After header, for a
+	// 429; zero if absent or not the delay-seconds form.
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *httpStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("proxy returned HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("proxy returned HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// retryable reports whether StatusCode is transient: any 5xx (the proxy or
+// upstream is unhealthy, likely briefly) or a 429 (rate limited; worth
+// retrying once RetryAfter, if any, has elapsed).
+func (e *httpStatusError) retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// classifyHTTPStatus returns an *httpStatusError for resp's status if it
+// isn't 2xx, reading (and discarding, bounded) its body for the error
+// message; nil for a successful response, in which case the caller
+// proceeds to decode the body as JSON-RPC as usual.
+func classifyHTTPStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+	return &httpStatusError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
+
+// parseRetryAfter parses the HTTP Retry-After header's delay-seconds form
+// (the only form any proxy in this codebase emits). Returns 0 for an
+// empty, negative, or HTTP-date-formatted value, leaving the caller to
+// fall back to its own backoff.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// RPCError wraps a JSON-RPC error object (the "error" member of a
+// JSONRPCResponse) so the retry layer can decide whether it's transient
+// without every caller re-parsing the raw interface{} itself.
+type RPCError struct {
+	Code    int
+	Message string
+	Raw     interface{}
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("MCP error: %v", e.Raw)
+}
+
+// retryable reports whether Code falls in the JSON-RPC "server error"
+// range (-32000 to -32099, reserved for implementation-defined transient
+// failures) or is -32603 Internal error; every other standard code (parse
+// error, invalid request, method not found, invalid params) indicates a
+// malformed or permanently unsupported call that retrying can't fix.
+func (e *RPCError) retryable() bool {
+	if e.Code == -32603 {
+		return true
+	}
+	return e.Code <= -32000 && e.Code >= -32099
+}
+
+// newRPCError builds an *RPCError from a JSONRPCResponse.Error value. That
+// field decodes from JSON as a map[string]interface{} for a
+// spec-compliant {"code": ..., "message": ...} object, but stays typed
+// interface{} since some MCP servers send something else; Code is left 0
+// (not retryable) when it doesn't.
+func newRPCError(raw interface{}) *RPCError {
+	e := &RPCError{Raw: raw}
+	if m, ok := raw.(map[string]interface{}); ok {
+		if code, ok := m["code"].(float64); ok {
+			e.Code = int(code)
+		}
+		if msg, ok := m["message"].(string); ok {
+			e.Message = msg
+		}
+	}
+	return e
+}
+
+// defaultRetryableCallError reports whether err, as returned by
+// callMCPOnce, represents a transient failure worth retrying: an HTTP-level
+// 5xx/429 from the proxy, a JSON-RPC error object in the server-error code
+// range, a network-level failure (connection reset, timeout, ...), a
+// stale/expired session, or a decode failure on a body that probably came
+// back from a transient 5xx error page rather than a real JSON-RPC
+// response.
+func defaultRetryableCallError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.retryable()
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.retryable()
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "session expired"):
+		return true
+	case strings.Contains(msg, "failed to make request"):
+		return true
+	case strings.Contains(msg, "failed to unmarshal response"):
+		return true
+	default:
+		return false
+	}
+}
+
+// retryPolicyForDatasource resolves the RetryPolicy to apply for
+// datasourceUID: its datasource type's configured policy, if the type is
+// known via the per-UID index, or DefaultRetryPolicy otherwise.
+func retryPolicyForDatasource(ctx context.Context, datasourceUID string) RetryPolicy {
+	datasourcesLock.RLock()
+	dsType, known := datasourceTypeIndex[datasourceUID]
+	datasourcesLock.RUnlock()
+
+	if !known {
+		return DefaultRetryPolicy()
+	}
+	return ProxyConfigFromContext(ctx).ForType(dsType).RetryPolicy.normalized()
+}
+
+// callMCPWithRetry retries attempt (one callMCP round trip) against
+// policy's attempt/backoff/timeout budget, logging a structured slog event
+// per attempt so operators can diagnose flaky proxies, and recording each
+// attempt's outcome on datasourceUID's session (see
+// SessionManager.RecordCallError/RecordCallSuccess) so a caller can
+// observe transient degradation without tailing logs. attempt is called at
+// least once even if policy.RetryTimeout is somehow already in the past.
+func callMCPWithRetry(ctx context.Context, policy RetryPolicy, datasourceUID, method string, attempt func() (*JSONRPCResponse, error)) (*JSONRPCResponse, error) {
+	policy = policy.normalized()
+	deadline := time.Now().Add(policy.RetryTimeout)
+	backoff := policy.InitialBackoff
+
+	var lastErr error
+	for n := 1; n <= policy.MaxAttempts; n++ {
+		start := time.Now()
+		resp, err := attempt()
+		elapsed := time.Since(start)
+
+		slog.Info("proxy JSON-RPC call attempt",
+			"datasource_uid", datasourceUID,
+			"method", method,
+			"attempt", n,
+			"max_attempts", policy.MaxAttempts,
+			"elapsed", elapsed,
+			"error", errString(err))
+
+		if err == nil {
+			sessionManager.RecordCallSuccess(datasourceUID)
+			return resp, nil
+		}
+		lastErr = err
+		sessionManager.RecordCallError(datasourceUID, err)
+
+		if !policy.RetryableError(err) {
+			return nil, err
+		}
+		if n == policy.MaxAttempts {
+			break
+		}
+
+		wait := backoffWithJitter(backoff)
+		var statusErr *httpStatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			wait = statusErr.RetryAfter
+		}
+
+		if time.Now().Add(wait).After(deadline) {
+			slog.Warn("proxy retry budget exhausted before next attempt",
+				"datasource_uid", datasourceUID, "method", method, "attempts", n, "error", err)
+			break
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	return nil, lastErr
+}
+
+// backoffWithJitter applies full jitter to backoff (a random delay in
+// [0, backoff)) so a burst of calls that all start retrying at once, e.g.
+// after a proxy restart, don't all land on the same retry schedule and
+// re-overload it in lockstep.
+func backoffWithJitter(backoff time.Duration) time.Duration {
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff)))
+}
+
+// sleepContext waits for d or ctx's cancellation, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// errString renders err for structured logging, since slog logs a nil
+// error interface value as the string "<nil>" otherwise.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
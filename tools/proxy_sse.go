@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// JSONRPCNotification is an id-less JSON-RPC message a server sends
+// without waiting for a request, e.g. "notifications/tools/list_changed"
+// or a progress update. decodeSSEResponse dispatches these to the
+// session's Notifications channel instead of returning them to the
+// caller, which is waiting for the response matching its own request ID.
+type JSONRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcMessageProbe is decoded first to tell a notification (no "id") apart
+// from a response, without committing to either shape.
+type rpcMessageProbe struct {
+	ID *int `json:"id"`
+}
+
+// decodeMCPResponse reads resp's body and returns the JSONRPCResponse for
+// requestID, branching on Content-Type: a "text/event-stream" response is
+// parsed incrementally as SSE frames (see decodeSSEResponse); anything
+// else is read in full and unmarshaled directly as JSON-RPC.
+func decodeMCPResponse(resp *http.Response, session *ProxySession, datasourceUID string, requestID int) (*JSONRPCResponse, error) {
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return decodeSSEResponse(resp.Body, session, datasourceUID, requestID)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Check if this is a text error response instead of JSON
+	bodyStr := string(body)
+	if strings.HasPrefix(bodyStr, "Invalid session ID") || strings.HasPrefix(bodyStr, "No session") {
+		// Session expired, clear it and retry
+		sessionManager.SetSessionID(datasourceUID, "")
+		session.Initialized = false
+		return nil, fmt.Errorf("session expired, please retry: %s", bodyStr)
+	}
+
+	var jsonResp JSONRPCResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response (body: %s): %w", bodyStr, err)
+	}
+
+	return &jsonResp, nil
+}
+
+// decodeSSEResponse reads body as a "text/event-stream", accumulating
+// each event's "data:" lines and, on the blank line that ends an event,
+// deciding whether the event is a notification (no "id") or a response.
+// Notifications are dispatched to session's Notifications channel and
+// scanning continues; a response whose id doesn't match requestID is
+// skipped (the stream may carry frames for other in-flight calls on the
+// same session); the first response matching requestID is returned. If
+// the stream ends without one, that's an error: the server closed the
+// connection before answering this call.
+func decodeSSEResponse(body io.Reader, session *ProxySession, datasourceUID string, requestID int) (*JSONRPCResponse, error) {
+	scanner := bufio.NewScanner(body)
+	// MCP tool results can be large (query results, logs, traces);
+	// match the JSON path's lack of a size cap by growing the buffer
+	// well past bufio.Scanner's 64KiB default.
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var data strings.Builder
+	flush := func() (*JSONRPCResponse, bool, error) {
+		if data.Len() == 0 {
+			return nil, false, nil
+		}
+		frame := data.String()
+		data.Reset()
+
+		if strings.HasPrefix(frame, "Invalid session ID") || strings.HasPrefix(frame, "No session") {
+			sessionManager.SetSessionID(datasourceUID, "")
+			session.Initialized = false
+			return nil, false, fmt.Errorf("session expired, please retry: %s", frame)
+		}
+
+		var probe rpcMessageProbe
+		if err := json.Unmarshal([]byte(frame), &probe); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal SSE frame (data: %s): %w", frame, err)
+		}
+
+		if probe.ID == nil {
+			var notification JSONRPCNotification
+			if err := json.Unmarshal([]byte(frame), &notification); err != nil {
+				return nil, false, fmt.Errorf("failed to unmarshal SSE notification (data: %s): %w", frame, err)
+			}
+			dispatchNotification(session, &notification)
+			return nil, false, nil
+		}
+
+		if *probe.ID != requestID {
+			return nil, false, nil
+		}
+
+		var jsonResp JSONRPCResponse
+		if err := json.Unmarshal([]byte(frame), &jsonResp); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal SSE response (data: %s): %w", frame, err)
+		}
+		return &jsonResp, true, nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "":
+			resp, done, err := flush()
+			if err != nil {
+				return nil, err
+			}
+			if done {
+				return resp, nil
+			}
+			// Any other line (event:, id:, retry:, a comment) is
+			// part of SSE framing this caller doesn't need.
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read SSE stream: %w", err)
+	}
+
+	// The server may close the connection right after its last data
+	// line without a trailing blank line; flush whatever's buffered.
+	resp, done, err := flush()
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("SSE stream ended without a response for request id %d", requestID)
+}
+
+// dispatchNotification delivers notification to session's Notifications
+// channel without blocking the in-flight call that received it. A full
+// channel (no one reading notifications) drops the oldest interest
+// instead of stalling the JSON-RPC response: callers should drain
+// Notifications to actually act on "notifications/tools/list_changed"
+// and progress updates.
+func dispatchNotification(session *ProxySession, notification *JSONRPCNotification) {
+	if session.Notifications == nil {
+		return
+	}
+	select {
+	case session.Notifications <- notification:
+	default:
+		slog.Warn("dropping MCP notification, session channel full",
+			"method", notification.Method, "datasource_id", session.DatasourceID)
+	}
+}
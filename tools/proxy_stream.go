@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/mcp-grafana/internal/health"
+	"github.com/grafana/mcp-grafana/internal/retry"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// maxStreamReconnectAttempts bounds how many times callMCPStream
+// reconnects, with Last-Event-ID, after the stream drops before a response
+// for the call's request ID arrived.
+const maxStreamReconnectAttempts = 3
+
+// StreamEvent is one message callMCPStream delivers to its onEvent
+// callback as a proxied MCP Streamable HTTP / SSE call progresses.
+// Exactly one of Response or Notification is set: Response is the final
+// JSONRPCResponse for the call (delivery of it ends the stream, same as a
+// non-streaming callMCP); Notification is a server-initiated, id-less
+// message (e.g. a progress update) seen along the way.
+type StreamEvent struct {
+	Response     *JSONRPCResponse
+	Notification *JSONRPCNotification
+}
+
+// newProxyHTTPClient builds the http.Client callMCPOnce and callMCPStream
+// both use to reach the Grafana datasource proxy: cfg's TLS settings
+// (falling back to Go's default transport when unset), wrapped with the
+// retry-on-5xx/429 transport and request instrumentation every proxied
+// call gets.
+func newProxyHTTPClient(cfg mcpgrafana.GrafanaConfig) (*http.Client, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	if tlsConfig := cfg.TLSConfig; tlsConfig != nil {
+		transport := &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: tlsConfig.SkipVerify},
+		}
+		if tlsConfig.CertFile != "" || tlsConfig.KeyFile != "" || tlsConfig.CAFile != "" {
+			tlsCfg, err := tlsConfig.CreateTLSConfig()
+			if err != nil {
+				return nil, fmt.Errorf("failed to create TLS config: %w", err)
+			}
+			transport.TLSClientConfig = tlsCfg
+		}
+		client.Transport = transport
+	}
+
+	client.Transport = retry.NewTransport(client.Transport, retry.DefaultConfig())
+	client.Transport = health.NewInstrumentedTransport(client.Transport, "datasource_proxy")
+	return client, nil
+}
+
+// applyProxyAuthHeaders sets the authentication and tenancy headers every
+// proxied MCP call needs, mirroring the Grafana config's auth precedence:
+// API key first, then basic auth for deployments behind a reverse proxy
+// that doesn't support service account tokens.
+func applyProxyAuthHeaders(req *http.Request, cfg mcpgrafana.GrafanaConfig) {
+	switch {
+	case cfg.APIKey != "":
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.APIKey))
+	case cfg.Password != "":
+		req.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	if cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", cfg.TenantID)
+	}
+}
+
+// callMCPStream is the Streamable HTTP / SSE counterpart to callMCP: instead
+// of waiting for and returning a single JSONRPCResponse, it delivers every
+// event on the stream (partial responses along the way, and
+// server-initiated notifications) to onEvent as they arrive, honoring
+// ctx.Done() for cancellation. If the connection drops before the
+// response for this call arrives, it reconnects using the session's last
+// received SSE event ID, up to maxStreamReconnectAttempts times.
+func callMCPStream(ctx context.Context, datasourceUID string, method string, params interface{}, onEvent func(StreamEvent)) error {
+	ds, err := resolveDatasource(ctx, datasourceUID)
+	if err != nil {
+		return fmt.Errorf("failed to get datasource: %w", err)
+	}
+	session := sessionManager.GetSession(datasourceUID, ds.ID)
+	requestID := int(getNextRequestID())
+
+	var lastErr error
+	for attempt := 0; attempt <= maxStreamReconnectAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := streamMCPOnce(ctx, datasourceUID, ds, method, params, session, requestID, onEvent)
+		if err == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		lastErr = err
+		slog.Warn("MCP stream disconnected, reconnecting",
+			"datasource_uid", datasourceUID, "attempt", attempt+1, "last_event_id", sessionManager.LastEventID(datasourceUID), "error", err)
+	}
+
+	return fmt.Errorf("MCP stream failed after %d attempt(s): %w", maxStreamReconnectAttempts+1, lastErr)
+}
+
+// streamMCPOnce performs a single Streamable HTTP / SSE round trip for the
+// call, returning nil once the response for requestID has been delivered
+// to onEvent. Any other outcome (transport error, or the stream ending
+// without that response) is returned as an error for callMCPStream to
+// retry.
+func streamMCPOnce(ctx context.Context, datasourceUID string, ds *ProxyDatasource, method string, params interface{}, session *ProxySession, requestID int, onEvent func(StreamEvent)) error {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	if cfg.URL == "" {
+		return fmt.Errorf("grafana URL not found in context")
+	}
+
+	proxyURL := fmt.Sprintf("%s/api/datasources/proxy/%d/api/mcp", strings.TrimRight(cfg.URL, "/"), ds.ID)
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: requestID, Method: method, Params: params}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", proxyURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream, application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	if session.ID != "" {
+		req.Header.Set("Mcp-Session-Id", session.ID)
+	}
+	if lastEventID := sessionManager.LastEventID(datasourceUID); lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	applyProxyAuthHeaders(req, cfg)
+
+	client, err := newProxyHTTPClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	limitResponseBody(ctx, resp, datasourceUID)
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		body, err := decodeMCPResponse(resp, session, datasourceUID, requestID)
+		if err != nil {
+			return responseSizeLimitError(err, ds.Type)
+		}
+		onEvent(StreamEvent{Response: body})
+		return nil
+	}
+
+	return decodeSSEStream(resp.Body, session, datasourceUID, requestID, onEvent)
+}
+
+// decodeSSEStream is decodeSSEResponse's streaming counterpart: instead of
+// discarding every frame but the one matching requestID, it forwards each
+// notification and the matching response to onEvent as soon as it's
+// parsed, and records every frame's SSE "id:" as the session's
+// Last-Event-ID so a reconnect can resume with it.
+func decodeSSEStream(body io.Reader, session *ProxySession, datasourceUID string, requestID int, onEvent func(StreamEvent)) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var data strings.Builder
+	flush := func() (bool, error) {
+		if data.Len() == 0 {
+			return false, nil
+		}
+		frame := data.String()
+		data.Reset()
+
+		if strings.HasPrefix(frame, "Invalid session ID") || strings.HasPrefix(frame, "No session") {
+			sessionManager.SetSessionID(datasourceUID, "")
+			session.Initialized = false
+			return false, fmt.Errorf("session expired, please retry: %s", frame)
+		}
+
+		var probe rpcMessageProbe
+		if err := json.Unmarshal([]byte(frame), &probe); err != nil {
+			return false, fmt.Errorf("failed to unmarshal SSE frame (data: %s): %w", frame, err)
+		}
+
+		if probe.ID == nil {
+			var notification JSONRPCNotification
+			if err := json.Unmarshal([]byte(frame), &notification); err != nil {
+				return false, fmt.Errorf("failed to unmarshal SSE notification (data: %s): %w", frame, err)
+			}
+			dispatchNotification(session, &notification)
+			onEvent(StreamEvent{Notification: &notification})
+			return false, nil
+		}
+
+		if *probe.ID != requestID {
+			return false, nil
+		}
+
+		var jsonResp JSONRPCResponse
+		if err := json.Unmarshal([]byte(frame), &jsonResp); err != nil {
+			return false, fmt.Errorf("failed to unmarshal SSE response (data: %s): %w", frame, err)
+		}
+		onEvent(StreamEvent{Response: &jsonResp})
+		return true, nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case strings.HasPrefix(line, "id:"):
+			sessionManager.SetLastEventID(datasourceUID, strings.TrimSpace(strings.TrimPrefix(line, "id:")))
+		case line == "":
+			done, err := flush()
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read SSE stream: %w", err)
+	}
+
+	done, err := flush()
+	if err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	return fmt.Errorf("SSE stream ended without a response for request id %d", requestID)
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	mcpgrafana "github.com/grafana/mcp-grafana"
+)
+
+// toolsETag hashes tools' wire representation into a stable ETag
+// ensureSession can send back as If-None-Match on its next tools/list
+// call, so an upstream MCP server that supports conditional requests can
+// reply 304 instead of re-sending an unchanged tool list.
+func toolsETag(tools []MCPTool) (string, error) {
+	b, err := json.Marshal(tools)
+	if err != nil {
+		return "", fmt.Errorf("hashing tools list: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ToolsCacheValid reports whether datasourceUID's cached tool list is
+// still usable: the session must be initialized, against the same
+// ProxyDatasource URL it was cached from (a datasource can be
+// repointed at a different backend without its UID changing, the same
+// "don't reuse the cache if the URL changed" rule the Grafana operator's
+// dashboard pipeline applies to its own content cache), and within ttl of
+// when it was last fetched or revalidated. ttl <= 0 means cache forever
+// once fetched.
+func (sm *SessionManager) ToolsCacheValid(datasourceUID string, datasourceURL string, ttl time.Duration) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	session, exists := sm.sessions[datasourceUID]
+	if !exists || !session.Initialized {
+		return false
+	}
+	if session.ToolsSourceURL != datasourceURL {
+		return false
+	}
+	if ttl <= 0 {
+		return true
+	}
+	return time.Since(session.ToolsCachedAt) < ttl
+}
+
+// ToolsETag returns datasourceUID's cached tools-list ETag, or "" if it
+// has no session or no tools cached yet.
+func (sm *SessionManager) ToolsETag(datasourceUID string) string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		return session.ToolsETag
+	}
+	return ""
+}
+
+// TouchToolsCache records that datasourceUID's already-cached tool list
+// was revalidated (a 304 from the upstream tools/list call) against
+// datasourceURL, resetting its TTL clock without changing the cached
+// tools themselves.
+func (sm *SessionManager) TouchToolsCache(datasourceUID string, datasourceURL string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if session, exists := sm.sessions[datasourceUID]; exists {
+		session.ToolsSourceURL = datasourceURL
+		session.ToolsCachedAt = time.Now()
+	}
+}
+
+// fetchToolsList issues a tools/list JSON-RPC call directly (rather than
+// through callMCP) so it can set an If-None-Match request header from
+// etag and observe a 304 response: the MCP Streamable HTTP transport
+// carries JSON-RPC over plain HTTP, so a conditional-request-aware
+// upstream can short-circuit an unchanged tools/list the same way it
+// would any other cacheable HTTP resource. notModified is true only on a
+// 304 response, in which case toolsList is nil and the caller should keep
+// using its previously cached list.
+func fetchToolsList(ctx context.Context, datasourceUID string, ds *ProxyDatasource, etag string) (toolsList []MCPTool, notModified bool, err error) {
+	cfg := mcpgrafana.GrafanaConfigFromContext(ctx)
+	if cfg.URL == "" {
+		return nil, false, fmt.Errorf("grafana URL not found in context")
+	}
+
+	session := sessionManager.GetSession(datasourceUID, ds.ID)
+	proxyURL := fmt.Sprintf("%s/api/datasources/proxy/%d/api/mcp", strings.TrimRight(cfg.URL, "/"), ds.ID)
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: int(getNextRequestID()), Method: "tools/list"}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", proxyURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream, application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	if session.ID != "" {
+		req.Header.Set("Mcp-Session-Id", session.ID)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	applyProxyAuthHeaders(req, cfg)
+
+	client, err := newProxyHTTPClient(cfg)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, true, nil
+	}
+
+	limitResponseBody(ctx, resp, datasourceUID)
+	jsonResp, err := decodeMCPResponse(resp, session, datasourceUID, request.ID)
+	if err != nil {
+		return nil, false, responseSizeLimitError(err, ds.Type)
+	}
+	if jsonResp.Error != nil {
+		return nil, false, fmt.Errorf("MCP error: %v", jsonResp.Error)
+	}
+
+	resultBytes, err := json.Marshal(jsonResp.Result)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal tools result: %w", err)
+	}
+	var toolsResult MCPListToolsResult
+	if err := json.Unmarshal(resultBytes, &toolsResult); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal tools result: %w", err)
+	}
+	return toolsResult.Tools, false, nil
+}
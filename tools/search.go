@@ -51,6 +51,7 @@ var SearchDashboards = mcpgrafana.MustTool(
 	searchDashboards,
 )
 
-func AddSearchTools(mcp *server.MCPServer) {
-	SearchDashboards.Register(mcp)
+func AddSearchTools(mcp *server.MCPServer, allowed mcpgrafana.ToolCapabilities) {
+	read := mcpgrafana.ToolCapabilities{Categories: mcpgrafana.ToolCategoryRead}
+	mcpgrafana.RegisterTool(mcp, allowed, read, SearchDashboards)
 }
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/grafana/mcp-grafana/internal/proxy"
+)
+
+// tempoDiscoveryEnvVar names the environment variable operators use to
+// plug in service-discovery backends beyond Grafana for Tempo datasources,
+// e.g. "grafana,file:/etc/mcp/tempo.json,consul://consul:8500/service/tempo".
+// Backends are comma-separated and merged in the order listed, with later
+// backends overriding earlier ones on a UID conflict (see
+// proxy.MergeDiscoverers). An unset or empty value preserves the original
+// Grafana-only behavior.
+const tempoDiscoveryEnvVar = "TEMPO_DISCOVERY"
+
+// buildTempoDiscoverer returns the Discoverer the Tempo proxy handler
+// should use: Grafana alone unless TEMPO_DISCOVERY configures additional
+// (or alternative) backends, in which case it merges all of them.
+func buildTempoDiscoverer() proxy.Discoverer {
+	spec := os.Getenv(tempoDiscoveryEnvVar)
+	if spec == "" {
+		return proxy.DiscovererFunc(discoverTempoProxyDatasources)
+	}
+
+	discoverers, err := parseTempoDiscoverySpec(spec)
+	if err != nil {
+		// Fall back to Grafana-only rather than leave the proxy with no
+		// datasources at all because of a config typo.
+		return proxy.DiscovererFunc(discoverTempoProxyDatasources)
+	}
+
+	return proxy.MergeDiscoverers(discoverers...)
+}
+
+// parseTempoDiscoverySpec parses a TEMPO_DISCOVERY value into the ordered
+// list of Discoverers it names. Supported backends:
+//
+//	grafana                                           - the existing Grafana datasource list
+//	file:<path>                                       - a JSON file of datasource entries, hot-reloaded on change
+//	dns_srv://<domain>/<service>.<proto>[?scheme=s]    - an SRV lookup, default scheme "http"
+//	consul://<addr>/service/<name>[?tag=t&scheme=s]    - a Consul catalog service query, default scheme "http"
+func parseTempoDiscoverySpec(spec string) ([]proxy.Discoverer, error) {
+	var discoverers []proxy.Discoverer
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		switch {
+		case part == "grafana":
+			discoverers = append(discoverers, proxy.DiscovererFunc(discoverTempoProxyDatasources))
+
+		case strings.HasPrefix(part, "file:"):
+			path := strings.TrimPrefix(part, "file:")
+			discoverers = append(discoverers, proxy.NewFileDiscoverer(path))
+
+		case strings.HasPrefix(part, "dns_srv://"):
+			u, err := url.Parse(part)
+			if err != nil {
+				return nil, fmt.Errorf("parse dns_srv discovery spec %q: %w", part, err)
+			}
+			serviceProto := strings.TrimPrefix(u.Path, "/")
+			service, proto, ok := strings.Cut(serviceProto, ".")
+			if !ok {
+				return nil, fmt.Errorf("dns_srv discovery spec %q: path must be <service>.<proto>", part)
+			}
+			scheme := u.Query().Get("scheme")
+			if scheme == "" {
+				scheme = "http"
+			}
+			discoverers = append(discoverers, proxy.NewDNSSRVDiscoverer(service, proto, u.Host, scheme, "tempo"))
+
+		case strings.HasPrefix(part, "consul://"):
+			u, err := url.Parse(part)
+			if err != nil {
+				return nil, fmt.Errorf("parse consul discovery spec %q: %w", part, err)
+			}
+			service := strings.TrimPrefix(u.Path, "/service/")
+			if service == "" || service == u.Path {
+				return nil, fmt.Errorf("consul discovery spec %q: path must be /service/<name>", part)
+			}
+			scheme := u.Query().Get("scheme")
+			if scheme == "" {
+				scheme = "http"
+			}
+			addr := fmt.Sprintf("http://%s", u.Host)
+			discoverers = append(discoverers, proxy.NewConsulDiscoverer(addr, service, u.Query().Get("tag"), scheme, "tempo", nil))
+
+		default:
+			return nil, fmt.Errorf("unrecognized discovery backend %q", part)
+		}
+	}
+
+	if len(discoverers) == 0 {
+		return nil, fmt.Errorf("%s set but no valid backends parsed", tempoDiscoveryEnvVar)
+	}
+
+	return discoverers, nil
+}
+
+// tempoDiscover is the Discover hook installed on tempoHandler: it's
+// context-independent (built once from the environment at package init),
+// consistent with how Discoverers are meant to be composed ahead of time
+// rather than re-parsed on every poll.
+func tempoDiscover(ctx context.Context) (map[string]proxy.Datasource, error) {
+	return tempoDiscoverer.Discover(ctx)
+}
+
+var tempoDiscoverer = buildTempoDiscoverer()
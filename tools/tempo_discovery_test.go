@@ -0,0 +1,46 @@
+//go:build unit
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTempoDiscoverySpec(t *testing.T) {
+	t.Run("grafana and file", func(t *testing.T) {
+		discoverers, err := parseTempoDiscoverySpec("grafana,file:/etc/mcp/tempo.json")
+		require.NoError(t, err)
+		assert.Len(t, discoverers, 2)
+	})
+
+	t.Run("consul with tag and scheme", func(t *testing.T) {
+		discoverers, err := parseTempoDiscoverySpec("consul://consul:8500/service/tempo?tag=prod&scheme=https")
+		require.NoError(t, err)
+		assert.Len(t, discoverers, 1)
+	})
+
+	t.Run("dns_srv", func(t *testing.T) {
+		discoverers, err := parseTempoDiscoverySpec("dns_srv://example.com/tempo.tcp")
+		require.NoError(t, err)
+		assert.Len(t, discoverers, 1)
+	})
+
+	t.Run("unrecognized backend", func(t *testing.T) {
+		_, err := parseTempoDiscoverySpec("bogus://nope")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unrecognized discovery backend")
+	})
+
+	t.Run("malformed consul spec", func(t *testing.T) {
+		_, err := parseTempoDiscoverySpec("consul://consul:8500/not-a-service-path")
+		require.Error(t, err)
+	})
+
+	t.Run("empty spec yields no backends", func(t *testing.T) {
+		_, err := parseTempoDiscoverySpec("")
+		require.Error(t, err)
+	})
+}
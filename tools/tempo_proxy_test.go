@@ -3,93 +3,12 @@
 package tools
 
 import (
-	"context"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestComputeToolHash(t *testing.T) {
-	t.Run("identical tools produce same hash", func(t *testing.T) {
-		tool1 := MCPTool{
-			Name:        "test-tool",
-			Description: "A test tool",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"param1": map[string]interface{}{"type": "string"},
-				},
-			},
-		}
-		
-		tool2 := MCPTool{
-			Name:        "test-tool",
-			Description: "A test tool",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"param1": map[string]interface{}{"type": "string"},
-				},
-			},
-		}
-		
-		hash1 := computeToolHash(tool1)
-		hash2 := computeToolHash(tool2)
-		
-		assert.Equal(t, hash1, hash2)
-	})
-	
-	t.Run("different descriptions produce different hashes", func(t *testing.T) {
-		tool1 := MCPTool{
-			Name:        "test-tool",
-			Description: "A test tool",
-			InputSchema: map[string]interface{}{},
-		}
-		
-		tool2 := MCPTool{
-			Name:        "test-tool",
-			Description: "A different test tool",
-			InputSchema: map[string]interface{}{},
-		}
-		
-		hash1 := computeToolHash(tool1)
-		hash2 := computeToolHash(tool2)
-		
-		assert.NotEqual(t, hash1, hash2)
-	})
-	
-	t.Run("different schemas produce different hashes", func(t *testing.T) {
-		tool1 := MCPTool{
-			Name:        "test-tool",
-			Description: "A test tool",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"param1": map[string]interface{}{"type": "string"},
-				},
-			},
-		}
-		
-		tool2 := MCPTool{
-			Name:        "test-tool",
-			Description: "A test tool",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"param1": map[string]interface{}{"type": "number"},
-				},
-			},
-		}
-		
-		hash1 := computeToolHash(tool1)
-		hash2 := computeToolHash(tool2)
-		
-		assert.NotEqual(t, hash1, hash2)
-	})
-}
-
 func TestNormalizeTempoToolName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -117,7 +36,7 @@ func TestNormalizeTempoToolName(t *testing.T) {
 			expected: "tempo_search",
 		},
 	}
-	
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := normalizeTempoToolName(tt.input)
@@ -158,7 +77,7 @@ func TestMakeUniqueToolName(t *testing.T) {
 			expected:       "tempo_trace_search_tempo_prod",
 		},
 	}
-	
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := makeUniqueToolName(tt.baseName, tt.datasourceName)
@@ -170,95 +89,23 @@ func TestMakeUniqueToolName(t *testing.T) {
 func TestCreateTempoToolHandler(t *testing.T) {
 	t.Run("requires datasource_uid", func(t *testing.T) {
 		handler := createTempoToolHandler("tempo_test_tool", []string{"ds1", "ds2"})
-		
-		// Test empty datasource_uid
+
 		_, err := handler(nil, DynamicTempoToolParams{
 			DatasourceUID: "",
 		})
-		
+
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "datasource_uid is required")
 	})
-	
+
 	t.Run("validates allowed datasources", func(t *testing.T) {
 		handler := createTempoToolHandler("tempo_test_tool", []string{"ds1", "ds2"})
-		
-		// Test invalid datasource
+
 		_, err := handler(nil, DynamicTempoToolParams{
 			DatasourceUID: "ds3",
 		})
-		
+
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "datasource ds3 does not provide tool tempo_test_tool")
 	})
-} 
-
-func TestTempoRegistry_PollingLifecycle(t *testing.T) {
-	t.Run("start and stop polling", func(t *testing.T) {
-		registry := &tempoToolRegistry{
-			registeredTools:   make(map[string]*registeredTool),
-			datasourceTools:   make(map[string][]string),
-			toolToDatasources: make(map[string][]string),
-			stopPoller:        make(chan struct{}),
-		}
-		
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-		
-		// Start polling
-		registry.startPolling(ctx, 100*time.Millisecond)
-		assert.True(t, registry.pollerRunning)
-		
-		// Stop polling
-		registry.stopPolling()
-		assert.False(t, registry.pollerRunning)
-	})
-	
-	t.Run("context cancellation stops polling", func(t *testing.T) {
-		registry := &tempoToolRegistry{
-			registeredTools:   make(map[string]*registeredTool),
-			datasourceTools:   make(map[string][]string),
-			toolToDatasources: make(map[string][]string),
-			stopPoller:        make(chan struct{}),
-		}
-		
-		ctx, cancel := context.WithCancel(context.Background())
-		
-		// Start polling
-		registry.startPolling(ctx, 100*time.Millisecond)
-		
-		// Cancel context
-		cancel()
-		
-		// Give it time to react - polling might take a moment to stop
-		time.Sleep(500 * time.Millisecond)
-		
-		// The polling goroutine should eventually stop, but it might not update
-		// pollerRunning immediately. Let's check if we can stop it without panic
-		registry.stopPolling() // Should be safe even if already stopped
-		
-		// Now it should definitely be stopped
-		assert.False(t, registry.pollerRunning)
-	})
-	
-	t.Run("multiple stop calls are safe", func(t *testing.T) {
-		registry := &tempoToolRegistry{
-			registeredTools:   make(map[string]*registeredTool),
-			datasourceTools:   make(map[string][]string),
-			toolToDatasources: make(map[string][]string),
-			stopPoller:        make(chan struct{}),
-		}
-		
-		ctx := context.Background()
-		
-		// Start polling
-		registry.startPolling(ctx, 100*time.Millisecond)
-		
-		// Stop multiple times - should not panic
-		registry.stopPolling()
-		registry.stopPolling()
-		registry.stopPolling()
-		
-		assert.False(t, registry.pollerRunning)
-	})
-} 
+}